@@ -853,6 +853,8 @@ func (a *App) GetSSHHostStatus() []SSHHostStatus {
 			HostID:    s.HostID,
 			Connected: s.Connected,
 			LastError: lastError,
+			Resources: s.Resources.Summary(),
+			Warnings:  s.Resources.Warnings(),
 		}
 	}
 	return result
@@ -875,6 +877,10 @@ type SSHHostStatus struct {
 	HostID    string `json:"hostId"`
 	Connected bool   `json:"connected"`
 	LastError string `json:"lastError,omitempty"`
+	// Resources summarizes load, memory, disk and running agents when the
+	// pool has collected them; Warnings is set when the host is overloaded
+	Resources string   `json:"resources,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
 }
 
 // SSHHostInfo represents SSH host configuration for the frontend.
@@ -889,6 +895,8 @@ type SSHHostInfo struct {
 	AutoDiscover bool   `json:"autoDiscover"`
 	TmuxPath     string `json:"tmuxPath"`
 	JumpHost     string `json:"jumpHost"`
+	// SSHConfigAlias is the ~/.ssh/config Host entry this host resolves from
+	SSHConfigAlias string `json:"sshConfigAlias"`
 }
 
 // GetSSHHosts returns all configured SSH hosts.
@@ -896,17 +904,22 @@ func (a *App) GetSSHHosts() []SSHHostInfo {
 	hosts := a.sshBridge.GetAllHosts()
 	result := make([]SSHHostInfo, 0, len(hosts))
 	for hostID, def := range hosts {
+		host := def.Host
+		if host == "" {
+			host = def.SSHConfigAlias
+		}
 		result = append(result, SSHHostInfo{
-			HostID:       hostID,
-			Host:         def.Host,
-			User:         def.User,
-			Port:         def.Port,
-			IdentityFile: def.IdentityFile,
-			Description:  def.Description,
-			GroupName:    def.GroupName,
-			AutoDiscover: def.AutoDiscover,
-			TmuxPath:     def.TmuxPath,
-			JumpHost:     def.JumpHost,
+			HostID:         hostID,
+			Host:           host,
+			User:           def.User,
+			Port:           def.Port,
+			IdentityFile:   def.IdentityFile,
+			Description:    def.Description,
+			GroupName:      def.GroupName,
+			AutoDiscover:   def.AutoDiscover,
+			TmuxPath:       def.TmuxPath,
+			JumpHost:       def.JumpHost,
+			SSHConfigAlias: def.SSHConfigAlias,
 		})
 	}
 	return result
@@ -922,6 +935,11 @@ func (a *App) UpdateSSHHost(hostID, host, user string, port int, identityFile, d
 	return a.sshBridge.UpdateHost(hostID, host, user, port, identityFile, description, groupName, autoDiscover, tmuxPath, jumpHost)
 }
 
+// ListSSHConfigAliases returns Host aliases from ~/.ssh/config for the host field.
+func (a *App) ListSSHConfigAliases() []string {
+	return a.sshBridge.ListSSHConfigAliases()
+}
+
 // RemoveSSHHost removes an SSH host configuration.
 func (a *App) RemoveSSHHost(hostID string) error {
 	return a.sshBridge.RemoveHost(hostID)
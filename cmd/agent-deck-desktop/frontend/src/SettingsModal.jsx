@@ -1,7 +1,7 @@
 import { useState, useEffect, useCallback, useRef } from 'react';
 import './SettingsModal.css';
 import LaunchConfigEditor from './LaunchConfigEditor';
-import { GetLaunchConfigs, DeleteLaunchConfig, GetSoftNewlineMode, SetSoftNewlineMode, SetFontSize, GetScrollSpeed, SetScrollSpeed, ResetGroupSettings, GetAutoCopyOnSelectEnabled, SetAutoCopyOnSelectEnabled, GetShowActivityRibbon, SetShowActivityRibbon, GetShowContextMeter, SetShowContextMeter, GetFileBasedActivityDetection, SetFileBasedActivityDetection, GetScanPaths, AddScanPath, RemoveScanPath, GetScanMaxDepth, SetScanMaxDepth, BrowseLocalDirectory, GetSSHHosts, AddSSHHost, UpdateSSHHost, RemoveSSHHost, TestSSHConnection } from '../wailsjs/go/main/App';
+import { GetLaunchConfigs, DeleteLaunchConfig, GetSoftNewlineMode, SetSoftNewlineMode, SetFontSize, GetScrollSpeed, SetScrollSpeed, ResetGroupSettings, GetAutoCopyOnSelectEnabled, SetAutoCopyOnSelectEnabled, GetShowActivityRibbon, SetShowActivityRibbon, GetShowContextMeter, SetShowContextMeter, GetFileBasedActivityDetection, SetFileBasedActivityDetection, GetScanPaths, AddScanPath, RemoveScanPath, GetScanMaxDepth, SetScanMaxDepth, BrowseLocalDirectory, GetSSHHosts, AddSSHHost, UpdateSSHHost, RemoveSSHHost, TestSSHConnection, ListSSHConfigAliases } from '../wailsjs/go/main/App';
 import { createLogger } from './logger';
 import { TOOLS } from './utils/tools';
 import ToolIcon from './ToolIcon';
@@ -28,6 +28,7 @@ export default function SettingsModal({ onClose, fontSize = DEFAULT_FONT_SIZE, o
     const [scanPaths, setScanPaths] = useState([]);
     const [scanMaxDepth, setScanMaxDepth] = useState(2);
     const [sshHosts, setSSHHosts] = useState([]);
+    const [sshConfigAliases, setSSHConfigAliases] = useState([]);
     const [editingSSHHost, setEditingSSHHost] = useState(null);
     const [sshHostForm, setSSHHostForm] = useState({
         hostId: '',
@@ -284,6 +285,13 @@ export default function SettingsModal({ onClose, fontSize = DEFAULT_FONT_SIZE, o
         } catch (err) {
             logger.error('Failed to load SSH hosts:', err);
         }
+        try {
+            // Aliases from ~/.ssh/config: picking one reuses its HostName, User, Port, IdentityFile, ProxyJump
+            const aliases = await ListSSHConfigAliases();
+            setSSHConfigAliases(aliases || []);
+        } catch (err) {
+            logger.error('Failed to load ~/.ssh/config aliases:', err);
+        }
     };
 
     const resetSSHHostForm = () => {
@@ -799,8 +807,14 @@ export default function SettingsModal({ onClose, fontSize = DEFAULT_FONT_SIZE, o
                                             type="text"
                                             value={sshHostForm.host}
                                             onChange={(e) => setSSHHostForm(prev => ({ ...prev, host: e.target.value }))}
-                                            placeholder="192.168.1.100"
+                                            placeholder="192.168.1.100 or ~/.ssh/config alias"
+                                            list="settings-ssh-config-aliases"
                                         />
+                                        <datalist id="settings-ssh-config-aliases">
+                                            {sshConfigAliases.map(alias => (
+                                                <option key={alias} value={alias} />
+                                            ))}
+                                        </datalist>
                                         {sshHostErrors.host && (
                                             <span className="settings-ssh-error">{sshHostErrors.host}</span>
                                         )}
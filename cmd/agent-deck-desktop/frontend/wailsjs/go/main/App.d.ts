@@ -108,6 +108,8 @@ export function IsGitWorktree(arg1:string):Promise<boolean>;
 
 export function IsPrimaryWindow():Promise<boolean>;
 
+export function ListSSHConfigAliases():Promise<Array<string>>;
+
 export function ListSSHHosts():Promise<Array<string>>;
 
 export function ListSessions():Promise<Array<main.SessionInfo>>;
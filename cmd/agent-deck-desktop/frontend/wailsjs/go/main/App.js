@@ -214,6 +214,10 @@ export function IsPrimaryWindow() {
   return window['go']['main']['App']['IsPrimaryWindow']();
 }
 
+export function ListSSHConfigAliases() {
+  return window['go']['main']['App']['ListSSHConfigAliases']();
+}
+
 export function ListSSHHosts() {
   return window['go']['main']['App']['ListSSHHosts']();
 }
@@ -199,6 +199,7 @@ export namespace main {
 	    autoDiscover: boolean;
 	    tmuxPath: string;
 	    jumpHost: string;
+	    sshConfigAlias: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new SSHHostInfo(source);
@@ -216,6 +217,7 @@ export namespace main {
 	        this.autoDiscover = source["autoDiscover"];
 	        this.tmuxPath = source["tmuxPath"];
 	        this.jumpHost = source["jumpHost"];
+	        this.sshConfigAlias = source["sshConfigAlias"];
 	    }
 	}
 	export class SSHHostStatus {
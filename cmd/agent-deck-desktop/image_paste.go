@@ -179,17 +179,11 @@ func generateRemotePathWithExt(ext string) string {
 }
 
 // StreamToRemoteFile streams data to a file on the remote host using SSH.
-// Uses 'umask 077; cat > path' for secure file creation with 0600 permissions.
+// The file is created with 0600 permissions.
 func StreamToRemoteFile(conn *ssh.Connection, data []byte, remotePath string) error {
-	// Use cat with umask for secure file creation (permissions 0600)
-	command := fmt.Sprintf("umask 077; cat > %q", remotePath)
-
-	reader := bytes.NewReader(data)
-	_, err := conn.RunCommandWithStdin(command, reader)
-	if err != nil {
+	if err := conn.WriteFile(remotePath, bytes.NewReader(data)); err != nil {
 		return fmt.Errorf("failed to stream file to remote: %w", err)
 	}
-
 	return nil
 }
 
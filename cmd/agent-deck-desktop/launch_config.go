@@ -17,6 +17,7 @@ type LaunchConfigInfo struct {
 	DangerousMode bool     `json:"dangerousMode"`
 	MCPConfigPath string   `json:"mcpConfigPath"`
 	MCPNames      []string `json:"mcpNames,omitempty"`
+	MCPBundles    []string `json:"mcpBundles,omitempty"`
 	ExtraArgs     []string `json:"extraArgs"`
 	IsDefault     bool     `json:"isDefault"`
 }
@@ -42,6 +43,7 @@ func (m *LaunchConfigManager) GetLaunchConfigs() ([]LaunchConfigInfo, error) {
 			Description:   cfg.Description,
 			DangerousMode: cfg.DangerousMode,
 			MCPConfigPath: cfg.MCPConfigPath,
+			MCPBundles:    cfg.MCPBundles,
 			ExtraArgs:     cfg.ExtraArgs,
 			IsDefault:     cfg.IsDefault,
 		}
@@ -99,6 +101,7 @@ func (m *LaunchConfigManager) GetLaunchConfig(key string) (*LaunchConfigInfo, er
 		Description:   cfg.Description,
 		DangerousMode: cfg.DangerousMode,
 		MCPConfigPath: cfg.MCPConfigPath,
+		MCPBundles:    cfg.MCPBundles,
 		ExtraArgs:     cfg.ExtraArgs,
 		IsDefault:     cfg.IsDefault,
 	}
@@ -168,12 +171,14 @@ func (m *LaunchConfigManager) SaveLaunchConfig(
 	}
 
 	// Create or update the config
+	// MCP bundles are edited in config.toml, so preserve them on update
 	config.LaunchConfigs[key] = session.LaunchConfig{
 		Name:          name,
 		Tool:          tool,
 		Description:   description,
 		DangerousMode: dangerousMode,
 		MCPConfigPath: mcpConfigPath,
+		MCPBundles:    config.LaunchConfigs[key].MCPBundles,
 		ExtraArgs:     extraArgs,
 		IsDefault:     isDefault,
 	}
@@ -254,6 +259,7 @@ func (m *LaunchConfigManager) GetDefaultLaunchConfig(tool string) (*LaunchConfig
 				Description:   cfg.Description,
 				DangerousMode: cfg.DangerousMode,
 				MCPConfigPath: cfg.MCPConfigPath,
+				MCPBundles:    cfg.MCPBundles,
 				ExtraArgs:     cfg.ExtraArgs,
 				IsDefault:     cfg.IsDefault,
 			}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"runtime"
 
+	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/wailsapp/wails/v2"
 	"github.com/wailsapp/wails/v2/pkg/logger"
 	"github.com/wailsapp/wails/v2/pkg/menu"
@@ -104,6 +105,8 @@ func main() {
 		}
 	}
 
+	session.SetAuditSource("desktop")
+
 	// Create an instance of the app structure
 	app, err := NewApp()
 	if err != nil {
@@ -1,12 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"sync"
 	"time"
 
+	"github.com/asheshgoplani/agent-deck/internal/ssh"
 	"github.com/creack/pty"
 )
 
@@ -15,8 +17,15 @@ type PTY struct {
 	cmd  *exec.Cmd
 	file *os.File
 	mu   sync.Mutex
+
+	// remote replaces cmd and file for hosts using the native SSH
+	// transport, whose PTY lives on the server
+	remote ssh.InteractiveSession
 }
 
+// errNoReadDeadline is returned by SetReadDeadline on a remote PTY
+var errNoReadDeadline = errors.New("read deadlines are not supported on native SSH sessions")
+
 // SpawnPTY creates a new PTY running the specified shell.
 func SpawnPTY(shell string) (*PTY, error) {
 	if shell == "" {
@@ -91,17 +100,26 @@ func SpawnPTYWithCommandAndSize(cols, rows int, name string, args ...string) (*P
 
 // Read reads from the PTY.
 func (p *PTY) Read(buf []byte) (int, error) {
+	if p.remote != nil {
+		return p.remote.Read(buf)
+	}
 	return p.file.Read(buf)
 }
 
 // SetReadDeadline sets a deadline for future Read calls.
 // A zero value clears the deadline.
 func (p *PTY) SetReadDeadline(t time.Time) error {
+	if p.remote != nil {
+		return errNoReadDeadline
+	}
 	return p.file.SetReadDeadline(t)
 }
 
 // Write writes to the PTY.
 func (p *PTY) Write(data []byte) (int, error) {
+	if p.remote != nil {
+		return p.remote.Write(data)
+	}
 	return p.file.Write(data)
 }
 
@@ -109,6 +127,9 @@ func (p *PTY) Write(data []byte) (int, error) {
 func (p *PTY) Resize(cols, rows uint16) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	if p.remote != nil {
+		return p.remote.Resize(int(cols), int(rows))
+	}
 	return pty.Setsize(p.file, &pty.Winsize{
 		Cols: cols,
 		Rows: rows,
@@ -120,6 +141,9 @@ func (p *PTY) Close() error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
+	if p.remote != nil {
+		return p.remote.Close()
+	}
 	if p.cmd.Process != nil {
 		p.cmd.Process.Kill()
 	}
@@ -135,7 +159,8 @@ func (p *PTY) Close() error {
 //   - tmuxSession: tmux session name to attach on the remote host
 //   - sshBridge: SSH bridge for connection management
 //
-// Returns a PTY wrapping the local ssh process.
+// Returns a PTY wrapping the local ssh process, or the remote PTY session
+// for hosts using the native transport.
 func SpawnSSHPTY(hostID, tmuxSession string, sshBridge *SSHBridge) (*PTY, error) {
 	// Get the SSH connection (establishes ControlMaster if not already)
 	conn, err := sshBridge.GetConnection(hostID)
@@ -149,6 +174,16 @@ func SpawnSSHPTY(hostID, tmuxSession string, sshBridge *SSHBridge) (*PTY, error)
 	// Build the attach command with proper quoting to prevent shell injection
 	attachCmd := fmt.Sprintf("%s attach-session -t %q", tmuxPath, tmuxSession)
 
+	// The native transport opens the PTY on the server over its own client;
+	// the caller resizes it to the terminal right after
+	if conn.IsNative() {
+		remote, err := conn.OpenInteractive(attachCmd, 80, 24)
+		if err != nil {
+			return nil, err
+		}
+		return &PTY{remote: remote}, nil
+	}
+
 	// Use StartInteractiveSession which builds the proper SSH command with -t for PTY
 	sshCmd, err := conn.StartInteractiveSession(attachCmd)
 	if err != nil {
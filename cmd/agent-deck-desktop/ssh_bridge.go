@@ -120,6 +120,7 @@ func (b *SSHBridge) AddHost(hostID, host, user string, port int, identityFile, d
 		TmuxPath:     tmuxPath,
 		JumpHost:     jumpHost,
 	}
+	applySSHConfigAlias(&def)
 
 	return session.SetSSHHost(hostID, def)
 }
@@ -142,10 +143,35 @@ func (b *SSHBridge) UpdateHost(hostID, host, user string, port int, identityFile
 		TmuxPath:     tmuxPath,
 		JumpHost:     jumpHost,
 	}
+	applySSHConfigAlias(&def)
 
 	return session.SetSSHHost(hostID, def)
 }
 
+// applySSHConfigAlias records host as ssh_config_alias when it names an entry
+// in ~/.ssh/config, so HostName, User, Port, IdentityFile and ProxyJump are
+// taken from there instead of having to be retyped.
+func applySSHConfigAlias(def *session.SSHHostDef) {
+	sshCfg, err := ssh.LoadSSHConfig("")
+	if err != nil || !sshCfg.HasAlias(def.Host) {
+		return
+	}
+	def.SSHConfigAlias = def.Host
+	// The settings form always submits port 22; let the ssh config's Port apply
+	if def.Port == 22 {
+		def.Port = 0
+	}
+}
+
+// ListSSHConfigAliases returns the Host aliases defined in ~/.ssh/config.
+func (b *SSHBridge) ListSSHConfigAliases() []string {
+	sshCfg, err := ssh.LoadSSHConfig("")
+	if err != nil {
+		return []string{}
+	}
+	return sshCfg.Aliases()
+}
+
 // RemoveHost removes an SSH host configuration from config.toml.
 func (b *SSHBridge) RemoveHost(hostID string) error {
 	return session.RemoveSSHHost(hostID)
@@ -164,5 +190,12 @@ func (b *SSHBridge) ValidateHost(hostID, host string) (bool, string) {
 		return false, "Host/IP address is required"
 	}
 
+	// A host naming a ~/.ssh/config alias must resolve
+	def := session.SSHHostDef{Host: host}
+	applySSHConfigAlias(&def)
+	if errMsg := session.ValidateSSHHostDef(def); errMsg != "" {
+		return false, errMsg
+	}
+
 	return true, ""
 }
@@ -14,6 +14,7 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"github.com/asheshgoplani/agent-deck/internal/session"
 	"github.com/wailsapp/wails/v2/pkg/runtime"
 )
 
@@ -666,6 +667,7 @@ func (t *Terminal) StartRemoteTmuxSession(hostID, tmuxSession, projectPath, tool
 			return fmt.Errorf("failed to restart remote session: %w", err)
 		}
 		t.debugLog("[REMOTE] Remote session restarted successfully")
+		recordSessionAudit(session.AuditRestart, t.sessionID, "", "remote tmux session "+tmuxSession+" was gone")
 		// Give the session a moment to start
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -27,27 +27,29 @@ var shellUnsafePattern = regexp.MustCompile(`[;&|$` + "`" + `\\(){}[\]<>!*?#\n\r
 
 // SessionInfo represents an Agent Deck session for the frontend.
 type SessionInfo struct {
-	ID                    string    `json:"id"`
-	Title                 string    `json:"title"`
-	CustomLabel           string    `json:"customLabel,omitempty"`
-	ProjectPath           string    `json:"projectPath"`
-	GroupPath             string    `json:"groupPath"`
-	Tool                  string    `json:"tool"`
-	Status                string    `json:"status"`
-	TmuxSession           string    `json:"tmuxSession"`
-	IsRemote              bool      `json:"isRemote"`
-	RemoteHost            string    `json:"remoteHost,omitempty"`
-	RemoteHostDisplayName string    `json:"remoteHostDisplayName,omitempty"` // Friendly name from config (group_name)
-	GitBranch             string    `json:"gitBranch,omitempty"`
-	IsWorktree            bool      `json:"isWorktree,omitempty"`
-	GitDirty              bool      `json:"gitDirty,omitempty"`
-	GitAhead              int       `json:"gitAhead,omitempty"`
-	GitBehind             int       `json:"gitBehind,omitempty"`
-	LastAccessedAt        time.Time `json:"lastAccessedAt,omitempty"`
-	WaitingSince          time.Time `json:"waitingSince,omitempty"` // When session entered waiting status
-	LaunchConfigName      string    `json:"launchConfigName,omitempty"`
-	LoadedMCPs            []string  `json:"loadedMcps,omitempty"`
-	DangerousMode         bool      `json:"dangerousMode,omitempty"`
+	ID                    string            `json:"id"`
+	Title                 string            `json:"title"`
+	CustomLabel           string            `json:"customLabel,omitempty"`
+	ProjectPath           string            `json:"projectPath"`
+	GroupPath             string            `json:"groupPath"`
+	Tool                  string            `json:"tool"`
+	Status                string            `json:"status"`
+	TmuxSession           string            `json:"tmuxSession"`
+	IsRemote              bool              `json:"isRemote"`
+	RemoteHost            string            `json:"remoteHost,omitempty"`
+	RemoteHostDisplayName string            `json:"remoteHostDisplayName,omitempty"` // Friendly name from config (group_name)
+	GitBranch             string            `json:"gitBranch,omitempty"`
+	IsWorktree            bool              `json:"isWorktree,omitempty"`
+	GitDirty              bool              `json:"gitDirty,omitempty"`
+	GitAhead              int               `json:"gitAhead,omitempty"`
+	GitBehind             int               `json:"gitBehind,omitempty"`
+	LastAccessedAt        time.Time         `json:"lastAccessedAt,omitempty"`
+	WaitingSince          time.Time         `json:"waitingSince,omitempty"` // When session entered waiting status
+	LaunchConfigName      string            `json:"launchConfigName,omitempty"`
+	LoadedMCPs            []string          `json:"loadedMcps,omitempty"`
+	DangerousMode         bool              `json:"dangerousMode,omitempty"`
+	Tags                  []string          `json:"tags,omitempty"`
+	Meta                  map[string]string `json:"meta,omitempty"`
 }
 
 // SessionMetadata represents runtime metadata for a session's status bar.
@@ -247,6 +249,8 @@ func (tm *TmuxManager) PersistSession(s SessionInfo) error {
 		LaunchConfigName: s.LaunchConfigName,
 		LoadedMCPNames:   s.LoadedMCPs,
 		DangerousMode:    s.DangerousMode,
+		Tags:             s.Tags,
+		Meta:             s.Meta,
 	}
 
 	// Append and save
@@ -657,6 +661,8 @@ func (tm *TmuxManager) convertInstancesToSessionInfos(instances []*session.Insta
 			LaunchConfigName:      inst.LaunchConfigName,
 			LoadedMCPs:            inst.LoadedMCPNames,
 			DangerousMode:         inst.DangerousMode,
+			Tags:                  inst.Tags,
+			Meta:                  inst.Meta,
 		})
 	}
 
@@ -1082,6 +1088,7 @@ type toolCommandResult struct {
 	cmdArgs          []string
 	launchConfigName string
 	loadedMCPs       []string
+	mcpBundles       []string
 	dangerousMode    bool
 }
 
@@ -1146,6 +1153,9 @@ func buildToolCommand(tool, configKey string, forRemote bool) toolCommandResult
 				}
 			}
 
+			// MCP bundles are attached by the caller (they write project config files)
+			result.mcpBundles = cfg.MCPBundles
+
 			// Add extra args
 			result.cmdArgs = append(result.cmdArgs, cfg.ExtraArgs...)
 		}
@@ -1208,6 +1218,16 @@ func (tm *TmuxManager) CreateSession(projectPath, title, tool, configKey string)
 	// Build tool command with optional launch config settings
 	tcr := buildToolCommand(tool, configKey, false /* forRemote */)
 
+	// Attach MCP bundles from the launch config before the tool starts so it
+	// picks them up from .mcp.json / settings.json on launch
+	if len(tcr.mcpBundles) > 0 {
+		if _, err := session.AttachMCPBundles(tool, projectPath, tcr.mcpBundles); err != nil {
+			fmt.Printf("Warning: failed to attach MCP bundles: %v\n", err)
+		} else if members, err := session.ResolveMCPBundles(tcr.mcpBundles); err == nil {
+			tcr.loadedMCPs = session.MergeMCPNames(tcr.loadedMCPs, members)
+		}
+	}
+
 	// Build the full command string
 	fullCmd := tcr.toolCmd
 	if len(tcr.cmdArgs) > 0 {
@@ -1250,6 +1270,7 @@ func (tm *TmuxManager) CreateSession(projectPath, title, tool, configKey string)
 		// Log warning but don't fail - session is still usable
 		fmt.Printf("Warning: failed to persist session: %v\n", err)
 	}
+	recordSessionAudit(session.AuditCreate, sessionInfo.ID, title, "")
 
 	return sessionInfo, nil
 }
@@ -1370,6 +1391,7 @@ func (tm *TmuxManager) CreateRemoteSession(hostID, projectPath, title, tool, con
 	// Register the session on the remote host so its TUI/desktop app can see it
 	// This calls `agent-deck register-session` on the remote machine
 	tm.registerSessionOnRemote(hostID, sessionName, projectPath, tool, title, sshBridge)
+	recordSessionAudit(session.AuditCreate, sessionInfo.ID, title, "on "+hostID)
 
 	return sessionInfo, nil
 }
@@ -1493,12 +1515,14 @@ func (tm *TmuxManager) DeleteSession(sessionID string, sshBridge *SSHBridge) err
 	found := false
 	var tmuxSession string
 	var remoteHost string
+	var title string
 	newInstances := make([]*session.InstanceData, 0, len(data.Instances))
 	for _, inst := range data.Instances {
 		if inst.ID == sessionID {
 			found = true
 			tmuxSession = inst.TmuxSession
 			remoteHost = inst.RemoteHost
+			title = inst.Title
 			// Skip this session (don't add to newInstances)
 			continue
 		}
@@ -1531,7 +1555,17 @@ func (tm *TmuxManager) DeleteSession(sessionID string, sshBridge *SSHBridge) err
 
 	// Save with the session removed
 	data.Instances = newInstances
-	return tm.adapter.SaveStorageData(data)
+	if err := tm.adapter.SaveStorageData(data); err != nil {
+		return err
+	}
+	recordSessionAudit(session.AuditDelete, sessionID, title, "")
+	return nil
+}
+
+// recordSessionAudit records an action taken in the desktop app in the
+// profile's audit log
+func recordSessionAudit(action, sessionID, title, detail string) {
+	session.RecordAuditEntry("", session.AuditEntry{Action: action, SessionID: sessionID, Title: title, Detail: detail})
 }
 
 // UpdateSessionCustomLabel updates the custom_label field for a session.
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleArchive dispatches archive subcommands
+func handleArchive(profile string, args []string) {
+	if len(args) == 0 {
+		printArchiveHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleArchiveList(profile, args[1:])
+	case "restore":
+		handleArchiveRestore(profile, args[1:])
+	case "purge":
+		handleArchivePurge(profile, args[1:])
+	case "help", "-h", "--help":
+		printArchiveHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown archive command '%s'\n", args[0])
+		printArchiveHelp()
+		os.Exit(1)
+	}
+}
+
+// printArchiveHelp prints help for archive commands
+func printArchiveHelp() {
+	fmt.Println("Usage: agent-deck archive <command> [options]")
+	fmt.Println()
+	fmt.Println("Deleted sessions are kept in the profile's archive until restored or")
+	fmt.Println("purged ([archive] retention_days in config.toml, default 30).")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                List archived sessions")
+	fmt.Println("  restore <id|title>  Restore a session and restart it")
+	fmt.Println("  purge [id|title]... Permanently delete archived sessions (--all for every one)")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck archive list")
+	fmt.Println("  agent-deck archive restore my-project")
+	fmt.Println("  agent-deck archive purge --all")
+}
+
+// archivedSessionJSON is the JSON form of an archived session
+type archivedSessionJSON struct {
+	ID              string    `json:"id"`
+	Title           string    `json:"title"`
+	Path            string    `json:"path"`
+	Group           string    `json:"group"`
+	Tool            string    `json:"tool"`
+	RemoteHost      string    `json:"remote_host,omitempty"`
+	ClaudeSessionID string    `json:"claude_session_id,omitempty"`
+	WorktreeBranch  string    `json:"worktree_branch,omitempty"`
+	ArchivedAt      time.Time `json:"archived_at"`
+	Reason          string    `json:"reason,omitempty"`
+}
+
+func newArchivedSessionJSON(s *session.ArchivedSession) archivedSessionJSON {
+	return archivedSessionJSON{
+		ID:              s.Session.ID,
+		Title:           s.Session.Title,
+		Path:            s.Session.ProjectPath,
+		Group:           s.Session.GroupPath,
+		Tool:            s.Session.Tool,
+		RemoteHost:      s.Session.RemoteHost,
+		ClaudeSessionID: s.Session.ClaudeSessionID,
+		WorktreeBranch:  s.Session.WorktreeBranch,
+		ArchivedAt:      s.ArchivedAt,
+		Reason:          s.Reason,
+	}
+}
+
+// handleArchiveList lists archived sessions, most recent first
+func handleArchiveList(profile string, args []string) {
+	fs := flag.NewFlagSet("archive list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Only print session IDs")
+	quietShort := fs.Bool("q", false, "Only print session IDs (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck archive list [options]")
+		fmt.Println()
+		fmt.Println("List deleted sessions kept in the archive, most recent first.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+	archive, err := session.OpenArchive(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	archived, err := archive.List()
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		list := make([]archivedSessionJSON, 0, len(archived))
+		for _, s := range archived {
+			list = append(list, newArchivedSessionJSON(s))
+		}
+		out.Print("", map[string]interface{}{
+			"sessions": list,
+		})
+		return
+	}
+
+	if quietMode {
+		for _, s := range archived {
+			fmt.Println(s.Session.ID)
+		}
+		return
+	}
+
+	if len(archived) == 0 {
+		fmt.Println("Archive is empty.")
+		return
+	}
+
+	fmt.Printf("%-20s %-10s %-8s %-17s %-24s %s\n", "TITLE", "ID", "TOOL", "ARCHIVED", "REASON", "PATH")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, s := range archived {
+		path := s.Session.ProjectPath
+		if s.Session.RemoteHost != "" {
+			path = s.Session.RemoteHost + ":" + path
+		}
+		fmt.Printf("%-20s %-10s %-8s %-17s %-24s %s\n",
+			truncate(s.Session.Title, 20), truncate(s.Session.ID, 10), s.Session.Tool,
+			s.ArchivedAt.Local().Format("2006-01-02 15:04"), truncate(s.Reason, 24), FormatPath(path))
+	}
+	if retention := session.GetArchiveSettings().Retention(); retention > 0 {
+		fmt.Printf("\nTotal: %d archived (purged after %d days)\n", len(archived), int(retention.Hours()/24))
+	} else {
+		fmt.Printf("\nTotal: %d archived\n", len(archived))
+	}
+}
+
+// handleArchiveRestore moves an archived session back into the profile and
+// recreates its tmux session
+func handleArchiveRestore(profile string, args []string) {
+	fs := flag.NewFlagSet("archive restore", flag.ExitOnError)
+	noStart := fs.Bool("no-start", false, "Restore without starting the session")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck archive restore <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Restore an archived session with its group, Claude session link and")
+		fmt.Println("worktree, then recreate its tmux session, resuming the conversation")
+		fmt.Println("when the session ID is known.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderArchiveArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	identifier := fs.Arg(0)
+	if identifier == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, groupsData, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	archive, err := session.OpenArchive(storage.Profile())
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	entry, err := archive.Find(identifier)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	inst, err := session.RestoreArchivedSession(entry, instances)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to restore: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	instances = append(instances, inst)
+	groupTree := session.NewGroupTreeWithGroups(instances, groupsData)
+	if inst.GroupPath != "" {
+		groupTree.CreateGroup(inst.GroupPath)
+	}
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if _, err := archive.Take(inst.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: restored session is still in the archive: %v\n", err)
+	}
+	session.RecordAudit(storage.Profile(), session.AuditCreate, inst, "restored from archive")
+
+	started := false
+	startErr := ""
+	if !*noStart {
+		if err := inst.Revive(); err != nil {
+			startErr = err.Error()
+		} else {
+			started = true
+			session.RecordAudit(storage.Profile(), session.AuditStart, inst, "")
+			if err := saveSessionData(storage, instances); err != nil {
+				out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+	}
+
+	message := fmt.Sprintf("Restored session: %s", inst.Title)
+	switch {
+	case started:
+		message += " (restarted)"
+	case startErr != "":
+		message += fmt.Sprintf(" (not started: %s)", startErr)
+	}
+	out.Success(message, map[string]interface{}{
+		"success":     true,
+		"id":          inst.ID,
+		"title":       inst.Title,
+		"group":       inst.GroupPath,
+		"started":     started,
+		"start_error": startErr,
+	})
+}
+
+// handleArchivePurge permanently deletes archived sessions
+func handleArchivePurge(profile string, args []string) {
+	fs := flag.NewFlagSet("archive purge", flag.ExitOnError)
+	all := fs.Bool("all", false, "Purge every archived session")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck archive purge <id|title>... [options]")
+		fmt.Println("       agent-deck archive purge --all")
+		fmt.Println()
+		fmt.Println("Permanently delete archived sessions. This cannot be undone.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderArchiveArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() == 0 && !*all {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if fs.NArg() > 0 && *all {
+		out.Error("use either session identifiers or --all", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	archive, err := session.OpenArchive(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	var ids []string
+	for _, identifier := range fs.Args() {
+		entry, err := archive.Find(identifier)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		ids = append(ids, entry.Session.ID)
+	}
+
+	purged, err := archive.Purge(ids)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Purged %d archived session(s)", purged), map[string]interface{}{
+		"success": true,
+		"purged":  purged,
+	})
+}
+
+// reorderArchiveArgs reorders arguments so flags come before positional args
+// e.g., "my-project --no-start" becomes "--no-start my-project"
+func reorderArchiveArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--reason": true, "-reason": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
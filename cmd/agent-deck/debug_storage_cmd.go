@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleDebugStorage prints the profile's stored sessions and groups as one
+// JSON object, read through the configured backend. Remote discovery runs it
+// on SSH hosts so it works whether they use sessions.json or sessions.db.
+func handleDebugStorage(profile string) {
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() { _ = storage.Close() }()
+
+	data, err := storage.LoadStorageData()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// handleDebugChanges lists the SQLite backend's change journal
+func handleDebugChanges(profile string, args []string) {
+	fs := flag.NewFlagSet("debug changes", flag.ExitOnError)
+	since := fs.Int64("since", 0, "Only show changes after this sequence number")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck debug changes [options]")
+		fmt.Println()
+		fmt.Println("Show the change journal of the SQLite storage backend (the last 1000")
+		fmt.Println("session writes: which session, which fields, when).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, false)
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	defer func() { _ = storage.Close() }()
+
+	changes, err := storage.ChangesSince(*since)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if changes == nil {
+		changes = []session.StorageChange{}
+	}
+
+	var b strings.Builder
+	if len(changes) == 0 {
+		b.WriteString("No changes\n")
+	}
+	for _, c := range changes {
+		fmt.Fprintf(&b, "%6d  %s  %-8s %s", c.Seq, c.At.Local().Format("2006-01-02 15:04:05"), c.Op, c.InstanceID)
+		if len(c.Fields) > 0 {
+			fmt.Fprintf(&b, "  %s", strings.Join(c.Fields, ","))
+		}
+		b.WriteString("\n")
+	}
+	out.Print(b.String(), map[string]interface{}{"changes": changes})
+}
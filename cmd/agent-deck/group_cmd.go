@@ -619,6 +619,7 @@ func handleGroupMove(profile string, args []string) {
 	if toGroup == "" {
 		toGroup = session.DefaultGroupPath
 	}
+	session.RecordAudit(storage.Profile(), session.AuditMove, inst, fromGroup+" -> "+toGroup)
 
 	out.Success(fmt.Sprintf("Moved %s to %s", inst.Title, toGroup), map[string]interface{}{
 		"success": true,
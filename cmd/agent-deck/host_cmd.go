@@ -0,0 +1,1181 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// handleHost dispatches host subcommands
+func handleHost(profile string, args []string) {
+	if len(args) == 0 {
+		printHostHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		handleHostList(args[1:])
+	case "add":
+		handleHostAdd(args[1:])
+	case "update":
+		handleHostUpdate(args[1:])
+	case "remove", "rm":
+		handleHostRemove(args[1:])
+	case "test":
+		handleHostTest(args[1:])
+	case "status":
+		handleHostStatus(profile, args[1:])
+	case "discover":
+		handleHostDiscover(profile, args[1:])
+	case "doctor":
+		handleHostDoctor(args[1:])
+	case "import":
+		handleHostImport(args[1:])
+	case "validate":
+		handleHostValidate(args[1:])
+	case "help", "-h", "--help":
+		printHostHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown host command '%s'\n", args[0])
+		printHostHelp()
+		os.Exit(1)
+	}
+}
+
+// printHostHelp prints help for host commands
+func printHostHelp() {
+	fmt.Println("Usage: agent-deck host <command> [options]")
+	fmt.Println()
+	fmt.Println("Manage SSH hosts ([ssh_hosts.X] in config.toml).")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                List configured hosts")
+	fmt.Println("  add <host-id>       Add a host")
+	fmt.Println("  update <host-id>    Change settings of a host")
+	fmt.Println("  remove <host-id>    Remove a host")
+	fmt.Println("  test <host-id>      Test the SSH connection to a host")
+	fmt.Println("  status              Show connection status of all hosts")
+	fmt.Println("  discover [host-id]  Discover agent-deck sessions on hosts")
+	fmt.Println("  doctor <host-id>    Check tmux, agent-deck and agent CLIs on a host")
+	fmt.Println("  import [alias...]   Import hosts from ~/.ssh/config as ssh_config_alias entries")
+	fmt.Println("  validate <host-id>  Show the effective settings a host connects with")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck host add dev --host 10.0.0.5 --user me --auto-discover")
+	fmt.Println("  agent-deck host update dev --port 2222 --transport native")
+	fmt.Println("  agent-deck host test dev --json")
+	fmt.Println("  agent-deck host discover dev               # Add dev's sessions to this profile")
+	fmt.Println("  agent-deck host doctor dev --install       # Check prerequisites, upload agent-deck")
+	fmt.Println("  agent-deck host import --dry-run           # Preview hosts in ~/.ssh/config")
+	fmt.Println("  agent-deck host import gpu-box -y          # Import a single alias")
+	fmt.Println("  agent-deck host validate gpu-box           # Show resolved host/user/port/jump")
+}
+
+// hostJSON is the --json representation of an [ssh_hosts.X] entry
+type hostJSON struct {
+	HostID         string   `json:"host_id"`
+	Host           string   `json:"host,omitempty"`
+	SSHConfigAlias string   `json:"ssh_config_alias,omitempty"`
+	User           string   `json:"user,omitempty"`
+	Port           int      `json:"port,omitempty"`
+	IdentityFile   string   `json:"identity_file,omitempty"`
+	JumpHost       string   `json:"jump_host,omitempty"`
+	Transport      string   `json:"transport,omitempty"`
+	TmuxPath       string   `json:"tmux_path,omitempty"`
+	GroupName      string   `json:"group_name,omitempty"`
+	SessionPrefix  string   `json:"session_prefix,omitempty"`
+	Description    string   `json:"description,omitempty"`
+	AutoDiscover   bool     `json:"auto_discover"`
+	ForwardMCPs    []string `json:"forward_mcps,omitempty"`
+}
+
+func newHostJSON(hostID string, def session.SSHHostDef) hostJSON {
+	return hostJSON{
+		HostID:         hostID,
+		Host:           def.Host,
+		SSHConfigAlias: def.SSHConfigAlias,
+		User:           def.User,
+		Port:           def.Port,
+		IdentityFile:   def.IdentityFile,
+		JumpHost:       def.JumpHost,
+		Transport:      def.Transport,
+		TmuxPath:       def.TmuxPath,
+		GroupName:      def.GroupName,
+		SessionPrefix:  def.SessionPrefix,
+		Description:    def.Description,
+		AutoDiscover:   def.AutoDiscover,
+		ForwardMCPs:    def.ForwardMCPs,
+	}
+}
+
+// hostAddress renders user@host:port for display, using the ssh config alias
+// when no host is set
+func hostAddress(def session.SSHHostDef) string {
+	addr := def.Host
+	if addr == "" {
+		addr = def.SSHConfigAlias
+	}
+	if def.User != "" {
+		addr = def.User + "@" + addr
+	}
+	if def.Port != 0 && def.Port != 22 {
+		addr = fmt.Sprintf("%s:%d", addr, def.Port)
+	}
+	return addr
+}
+
+// handleHostList lists configured SSH hosts
+func handleHostList(args []string) {
+	fs := flag.NewFlagSet("host list", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Only print host IDs")
+	quietShort := fs.Bool("q", false, "Only print host IDs (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host list [options]")
+		fmt.Println()
+		fmt.Println("List SSH hosts from config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+	hosts := session.GetAvailableSSHHosts()
+	names := session.GetAvailableSSHHostNames()
+
+	if *jsonOutput {
+		list := make([]hostJSON, 0, len(names))
+		for _, name := range names {
+			list = append(list, newHostJSON(name, hosts[name]))
+		}
+		out.Print("", map[string]interface{}{
+			"hosts": list,
+		})
+		return
+	}
+
+	if quietMode {
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No SSH hosts configured.")
+		fmt.Println()
+		fmt.Println("Add one with:")
+		fmt.Println("  agent-deck host add dev --host 192.168.1.100 --user developer")
+		fmt.Println("  agent-deck host import      # from ~/.ssh/config")
+		return
+	}
+
+	configPath, _ := session.GetUserConfigPath()
+	fmt.Printf("SSH hosts (from %s):\n\n", FormatPath(configPath))
+
+	maxID := 8
+	for _, name := range names {
+		if len(name) > maxID {
+			maxID = len(name)
+		}
+	}
+	if maxID > 24 {
+		maxID = 24
+	}
+	fmt.Printf("%-*s %-36s %-9s %-9s %s\n", maxID, "HOST ID", "ADDRESS", "TRANSPORT", "DISCOVER", "DESCRIPTION")
+	fmt.Println(strings.Repeat("-", maxID+70))
+	for _, name := range names {
+		def := hosts[name]
+		transport := def.Transport
+		if transport == "" {
+			transport = sshpkg.TransportSSH
+		}
+		discover := "no"
+		if def.AutoDiscover {
+			discover = "yes"
+		}
+		idDisplay := name
+		if len(idDisplay) > maxID {
+			idDisplay = idDisplay[:maxID-3] + "..."
+		}
+		fmt.Printf("%-*s %-36s %-9s %-9s %s\n", maxID, idDisplay, hostAddress(def), transport, discover, def.Description)
+	}
+	fmt.Printf("\nTotal: %d hosts\n", len(names))
+}
+
+// hostDefFlags holds the flags shared by 'host add' and 'host update'
+type hostDefFlags struct {
+	host          *string
+	alias         *string
+	user          *string
+	port          *int
+	identityFile  *string
+	jumpHost      *string
+	transport     *string
+	tmuxPath      *string
+	groupName     *string
+	sessionPrefix *string
+	description   *string
+	autoDiscover  *bool
+	forwardMCPs   *string
+}
+
+func registerHostDefFlags(fs *flag.FlagSet) *hostDefFlags {
+	return &hostDefFlags{
+		host:          fs.String("host", "", "Hostname or IP address"),
+		alias:         fs.String("ssh-config-alias", "", "Host entry in ~/.ssh/config to take settings from"),
+		user:          fs.String("user", "", "SSH username"),
+		port:          fs.Int("port", 0, "SSH port"),
+		identityFile:  fs.String("identity-file", "", "Path to SSH private key"),
+		jumpHost:      fs.String("jump-host", "", "Bastion: ssh_hosts name or [user@]host[:port], comma-separated"),
+		transport:     fs.String("transport", "", "Transport: ssh or native"),
+		tmuxPath:      fs.String("tmux-path", "", "Full path to tmux on the remote host"),
+		groupName:     fs.String("group-name", "", "Display name for the host's group in the TUI"),
+		sessionPrefix: fs.String("session-prefix", "", "Prefix shown before remote session titles"),
+		description:   fs.String("description", "", "Help text shown in the host selector"),
+		autoDiscover:  fs.Bool("auto-discover", false, "Discover agent-deck sessions on this host"),
+		forwardMCPs:   fs.String("forward-mcps", "", "Comma-separated pooled MCPs to forward to the host"),
+	}
+}
+
+// apply copies the flags that were given on the command line into def
+func (f *hostDefFlags) apply(fs *flag.FlagSet, def *session.SSHHostDef) {
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "host":
+			def.Host = *f.host
+		case "ssh-config-alias":
+			def.SSHConfigAlias = *f.alias
+		case "user":
+			def.User = *f.user
+		case "port":
+			def.Port = *f.port
+		case "identity-file":
+			def.IdentityFile = *f.identityFile
+		case "jump-host":
+			def.JumpHost = *f.jumpHost
+		case "transport":
+			def.Transport = *f.transport
+		case "tmux-path":
+			def.TmuxPath = *f.tmuxPath
+		case "group-name":
+			def.GroupName = *f.groupName
+		case "session-prefix":
+			def.SessionPrefix = *f.sessionPrefix
+		case "description":
+			def.Description = *f.description
+		case "auto-discover":
+			def.AutoDiscover = *f.autoDiscover
+		case "forward-mcps":
+			def.ForwardMCPs = nil
+			for _, name := range strings.Split(*f.forwardMCPs, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					def.ForwardMCPs = append(def.ForwardMCPs, name)
+				}
+			}
+		}
+	})
+}
+
+// validateHostDef checks a host definition before it is saved
+func validateHostDef(hostID string, def session.SSHHostDef) string {
+	if errMsg := session.ValidateSSHHostID(hostID); errMsg != "" {
+		return errMsg
+	}
+	switch def.Transport {
+	case "", sshpkg.TransportSSH, sshpkg.TransportNative:
+	default:
+		return fmt.Sprintf("unknown transport '%s' (use %s or %s)", def.Transport, sshpkg.TransportSSH, sshpkg.TransportNative)
+	}
+	if def.Port < 0 || def.Port > 65535 {
+		return fmt.Sprintf("invalid port %d", def.Port)
+	}
+	return session.ValidateSSHHostDef(def)
+}
+
+// handleHostAdd adds a new [ssh_hosts.X] entry
+func handleHostAdd(args []string) {
+	fs := flag.NewFlagSet("host add", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	defFlags := registerHostDefFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host add <host-id> --host <address> [options]")
+		fmt.Println("       agent-deck host add <host-id> --ssh-config-alias <alias> [options]")
+		fmt.Println()
+		fmt.Println("Add an SSH host to config.toml. <host-id> is the [ssh_hosts.X] key.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hostID := fs.Arg(0)
+	if hostID == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if session.GetSSHHostDef(hostID) != nil {
+		out.Error(fmt.Sprintf("SSH host '%s' already exists (use 'agent-deck host update')", hostID), ErrCodeAlreadyExists)
+		os.Exit(1)
+	}
+
+	var def session.SSHHostDef
+	defFlags.apply(fs, &def)
+	if errMsg := validateHostDef(hostID, def); errMsg != "" {
+		out.Error(errMsg, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if err := session.SetSSHHost(hostID, def); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Added SSH host %s (%s)", hostID, hostAddress(def)), map[string]interface{}{
+		"success": true,
+		"host":    newHostJSON(hostID, def),
+	})
+}
+
+// handleHostUpdate changes the given fields of an existing host
+func handleHostUpdate(args []string) {
+	fs := flag.NewFlagSet("host update", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	defFlags := registerHostDefFlags(fs)
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host update <host-id> [options]")
+		fmt.Println()
+		fmt.Println("Change settings of an SSH host. Only the given options are modified;")
+		fmt.Println("pass an empty value (e.g. --jump-host \"\") to clear a setting.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hostID := fs.Arg(0)
+	if hostID == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	existing := session.GetSSHHostDef(hostID)
+	if existing == nil {
+		out.Error(fmt.Sprintf("SSH host '%s' not found in config.toml", hostID), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if fs.NFlag() == 0 || (fs.NFlag() == 1 && *jsonOutput) {
+		out.Error("nothing to update (pass at least one option)", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	def := *existing
+	defFlags.apply(fs, &def)
+	if errMsg := validateHostDef(hostID, def); errMsg != "" {
+		out.Error(errMsg, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	// Drop the old connection so the next use picks up the new settings
+	sshpkg.DefaultPool().Close(hostID)
+	if err := session.SetSSHHost(hostID, def); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Updated SSH host %s (%s)", hostID, hostAddress(def)), map[string]interface{}{
+		"success": true,
+		"host":    newHostJSON(hostID, def),
+	})
+}
+
+// handleHostRemove removes an [ssh_hosts.X] entry
+func handleHostRemove(args []string) {
+	fs := flag.NewFlagSet("host remove", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host remove <host-id> [options]")
+		fmt.Println()
+		fmt.Println("Remove an SSH host from config.toml. Sessions on the host are kept")
+		fmt.Println("but can't be reached until the host is added again.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hostID := fs.Arg(0)
+	if hostID == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if session.GetSSHHostDef(hostID) == nil {
+		out.Error(fmt.Sprintf("SSH host '%s' not found in config.toml", hostID), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	session.InitSSHPool()
+	if err := session.RemoveSSHHost(hostID); err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Removed SSH host %s", hostID), map[string]interface{}{
+		"success": true,
+		"host_id": hostID,
+	})
+}
+
+// handleHostTest checks that a host is reachable over SSH
+func handleHostTest(args []string) {
+	fs := flag.NewFlagSet("host test", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host test <host-id> [options]")
+		fmt.Println()
+		fmt.Println("Open an SSH connection to the host and run a trivial command.")
+		fmt.Println("Exits 1 if the host is unreachable.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hostID := fs.Arg(0)
+	if hostID == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	def := session.GetSSHHostDef(hostID)
+	if def == nil {
+		out.Error(fmt.Sprintf("SSH host '%s' not found in config.toml", hostID), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	session.InitSSHPool()
+	start := time.Now()
+	err := sshpkg.DefaultPool().TestConnection(hostID)
+	elapsed := time.Since(start)
+
+	if *jsonOutput {
+		result := map[string]interface{}{
+			"host_id":    hostID,
+			"address":    hostAddress(*def),
+			"connected":  err == nil,
+			"latency_ms": elapsed.Milliseconds(),
+		}
+		if err != nil {
+			result["error"] = err.Error()
+		}
+		out.Print("", result)
+		if err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err != nil {
+		out.Error(fmt.Sprintf("%s (%s): %v", hostID, hostAddress(*def), err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("%s (%s) reachable in %s", hostID, hostAddress(*def), elapsed.Round(time.Millisecond)), nil)
+}
+
+// handleHostStatus shows the connection status of every configured host
+func handleHostStatus(profile string, args []string) {
+	fs := flag.NewFlagSet("host status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host status [options]")
+		fmt.Println()
+		fmt.Println("Connect to every configured host and show whether it is reachable, with")
+		fmt.Println("its load average, free memory and disk, and number of running agents.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	session.InitSSHPool()
+	statuses := sshpkg.DefaultPool().Status()
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].HostID < statuses[j].HostID })
+	collectHostResources(statuses)
+
+	if *jsonOutput {
+		type statusJSON struct {
+			HostID    string     `json:"host_id"`
+			Connected bool       `json:"connected"`
+			Error     string     `json:"error,omitempty"`
+			LastCheck *time.Time `json:"last_check,omitempty"`
+			// From the offline snapshot of an unreachable host
+			LastContact    *time.Time `json:"last_contact,omitempty"`
+			CachedSessions int        `json:"cached_sessions,omitempty"`
+
+			Resources *sshpkg.HostResources `json:"resources,omitempty"`
+			Warnings  []string              `json:"warnings,omitempty"`
+		}
+		list := make([]statusJSON, 0, len(statuses))
+		for _, st := range statuses {
+			item := statusJSON{HostID: st.HostID, Connected: st.Connected}
+			if st.Connected {
+				item.Resources = st.Resources
+				item.Warnings = st.Resources.Warnings()
+			}
+			if st.LastError != nil {
+				item.Error = st.LastError.Error()
+			}
+			if !st.LastCheck.IsZero() {
+				lastCheck := st.LastCheck
+				item.LastCheck = &lastCheck
+			}
+			if cache := session.GetRemoteHostCache(profile, st.HostID); cache != nil && !st.Connected {
+				item.LastContact = &cache.UpdatedAt
+				item.CachedSessions = len(cache.Sessions)
+			}
+			list = append(list, item)
+		}
+		out.Print("", map[string]interface{}{
+			"hosts": list,
+		})
+		return
+	}
+
+	if len(statuses) == 0 {
+		fmt.Println("No SSH hosts configured.")
+		return
+	}
+
+	connected := 0
+	for _, st := range statuses {
+		if st.Connected {
+			connected++
+			fmt.Printf("%s %s\n", successSymbol, st.HostID)
+			if st.Resources != nil {
+				fmt.Printf("    %s\n", st.Resources.Summary())
+				for _, w := range st.Resources.Warnings() {
+					fmt.Printf("    ! overloaded: %s\n", w)
+				}
+			}
+			continue
+		}
+		msg := "not connected"
+		if st.LastError != nil {
+			msg = st.LastError.Error()
+		}
+		fmt.Printf("%s %s: %s\n", errorSymbol, st.HostID, msg)
+		if cache := session.GetRemoteHostCache(profile, st.HostID); cache != nil {
+			fmt.Printf("    last contact %s, %d session(s) in snapshot\n",
+				cache.UpdatedAt.Local().Format("2006-01-02 15:04"), len(cache.Sessions))
+		}
+	}
+	fmt.Printf("\n%d/%d hosts connected\n", connected, len(statuses))
+}
+
+// collectHostResources fills in the resources of connected hosts, in parallel
+func collectHostResources(statuses []sshpkg.Status) {
+	var wg sync.WaitGroup
+	for i := range statuses {
+		if !statuses[i].Connected {
+			continue
+		}
+		wg.Add(1)
+		go func(st *sshpkg.Status) {
+			defer wg.Done()
+			if r, err := sshpkg.DefaultPool().CollectResources(st.HostID); err == nil {
+				st.Resources = r
+			}
+		}(&statuses[i])
+	}
+	wg.Wait()
+}
+
+// handleHostDiscover imports agent-deck sessions from remote hosts into the
+// profile, like the TUI's periodic remote discovery
+func handleHostDiscover(profile string, args []string) {
+	fs := flag.NewFlagSet("host discover", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host discover [host-id...] [options]")
+		fmt.Println()
+		fmt.Println("Discover agentdeck_* tmux sessions on SSH hosts and add them to the")
+		fmt.Println("profile under the remote/<group_name> group. Sessions that no longer")
+		fmt.Println("exist on the host are removed. Without host IDs, every host with")
+		fmt.Println("auto_discover = true is scanned.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hosts := session.GetAvailableSSHHosts()
+
+	hostIDs := fs.Args()
+	if len(hostIDs) == 0 {
+		for _, name := range session.GetAvailableSSHHostNames() {
+			if hosts[name].AutoDiscover {
+				hostIDs = append(hostIDs, name)
+			}
+		}
+		if len(hostIDs) == 0 {
+			out.Error("no hosts with auto_discover = true (pass host IDs to scan)", ErrCodeNotFound)
+			os.Exit(1)
+		}
+	}
+	for _, hostID := range hostIDs {
+		if _, ok := hosts[hostID]; !ok {
+			out.Error(fmt.Sprintf("SSH host '%s' not found in config.toml", hostID), ErrCodeNotFound)
+			os.Exit(2)
+		}
+	}
+
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to initialize storage: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	instances, groups, err := storage.LoadWithGroups()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load sessions: %v", err), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	groupTree := session.NewGroupTreeWithGroups(instances, groups)
+	session.InitSSHPool()
+
+	type discoverJSON struct {
+		HostID     string   `json:"host_id"`
+		Discovered []string `json:"discovered"`
+		Updated    int      `json:"updated"`
+		Removed    int      `json:"removed"`
+		Error      string   `json:"error,omitempty"`
+		// Reconciliation with the host's last-known snapshot
+		Disappeared []string   `json:"disappeared,omitempty"`
+		Unreachable int        `json:"unreachable,omitempty"`
+		LastContact *time.Time `json:"last_contact,omitempty"`
+	}
+	results := make([]discoverJSON, 0, len(hostIDs))
+	changed := false
+	failed := false
+
+	for _, hostID := range hostIDs {
+		result := discoverJSON{HostID: hostID, Discovered: []string{}}
+		discovered, updated, staleIDs, remoteGroups, err := session.DiscoverRemoteSessionsForHost(hostID, instances)
+		if err != nil {
+			report := session.ReconcileRemoteHost(profile, hostID, instances, nil, err)
+			result.Error = err.Error()
+			result.Unreachable = len(report.Unreachable)
+			if !report.LastContact.IsZero() {
+				result.LastContact = &report.LastContact
+			}
+			results = append(results, result)
+			failed = true
+			continue
+		}
+
+		for _, rg := range remoteGroups {
+			if _, exists := groupTree.Groups[rg.Path]; !exists {
+				groupTree.EnsureGroupExists(rg.Path, rg.Name, rg.Order, rg.Expanded)
+				changed = true
+			}
+		}
+
+		if len(staleIDs) > 0 {
+			stale := make(map[string]bool, len(staleIDs))
+			for _, id := range staleIDs {
+				stale[id] = true
+			}
+			kept := instances[:0]
+			for _, inst := range instances {
+				if !stale[inst.ID] {
+					kept = append(kept, inst)
+				}
+			}
+			result.Removed = len(instances) - len(kept)
+			instances = kept
+		}
+
+		merged, newCount := session.MergeDiscoveredSessions(instances, discovered)
+		instances = merged
+		if newCount > 0 {
+			for _, inst := range discovered {
+				result.Discovered = append(result.Discovered, inst.Title)
+			}
+		}
+		result.Updated = len(updated)
+		report := session.ReconcileRemoteHost(profile, hostID, instances, staleIDs, nil)
+		if !report.LastContact.IsZero() {
+			result.LastContact = &report.LastContact
+		}
+		for _, snap := range report.Disappeared {
+			result.Disappeared = append(result.Disappeared, snap.Title)
+		}
+		if newCount > 0 || result.Updated > 0 || result.Removed > 0 {
+			changed = true
+		}
+		results = append(results, result)
+	}
+
+	if changed {
+		groupTree.SyncWithInstances(instances)
+		groupTree.RemoveEmptyRemoteGroups()
+		if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+			out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"hosts": results,
+		})
+	} else {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Printf("%s %s: %s\n", errorSymbol, r.HostID, r.Error)
+				if r.Unreachable > 0 && r.LastContact != nil {
+					fmt.Printf("    %d session(s) kept from last contact %s (unreachable, not removed)\n",
+						r.Unreachable, r.LastContact.Local().Format("2006-01-02 15:04"))
+				}
+				continue
+			}
+			fmt.Printf("%s %s: %d new, %d moved, %d removed\n", successSymbol, r.HostID, len(r.Discovered), r.Updated, r.Removed)
+			for _, title := range r.Discovered {
+				fmt.Printf("    + %s\n", title)
+			}
+			for _, title := range r.Disappeared {
+				fmt.Printf("    - %s (gone since last contact)\n", title)
+			}
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// handleHostDoctor checks the prerequisites for remote sessions on a host
+// and optionally installs agent-deck there
+func handleHostDoctor(args []string) {
+	fs := flag.NewFlagSet("host doctor", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	install := fs.Bool("install", false, "Upload agent-deck to ~/.local/bin if it is missing or a different version")
+	binary := fs.String("binary", "", "agent-deck binary to upload (default: this executable, if the host's OS/arch match)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host doctor <host-id> [options]")
+		fmt.Println()
+		fmt.Println("Check that an SSH host has what remote sessions need: tmux (or tmux_path),")
+		fmt.Println("agent-deck (for register-session and remote-agent), the agent CLIs")
+		fmt.Println("(claude, gemini, codex, opencode), git, and nc -U when forward_mcps is set.")
+		fmt.Println("Exits 1 if a required check fails.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hostID := fs.Arg(0)
+	if hostID == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	if session.GetSSHHostDef(hostID) == nil {
+		out.Error(fmt.Sprintf("SSH host '%s' not found in config.toml", hostID), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	session.InitSSHPool()
+	report := session.RunHostDoctor(hostID, Version)
+
+	var installed, installErr string
+	if check := report.Check("agent-deck"); *install && check != nil && (check.Status != session.HostCheckOK || *binary != "") {
+		if !*jsonOutput {
+			fmt.Printf("Uploading agent-deck to %s...\n", hostID)
+		}
+		path, err := session.InstallAgentDeckOnHost(hostID, *binary, report)
+		if err != nil {
+			installErr = err.Error()
+		} else {
+			installed = path
+			report = session.RunHostDoctor(hostID, Version)
+		}
+	}
+
+	if *jsonOutput {
+		result := map[string]interface{}{
+			"healthy": !report.Failed() && installErr == "",
+			"report":  report,
+		}
+		if installed != "" {
+			result["installed"] = installed
+		}
+		if installErr != "" {
+			result["install_error"] = installErr
+		}
+		out.Print("", result)
+		if report.Failed() || installErr != "" {
+			os.Exit(1)
+		}
+		return
+	}
+
+	platform := ""
+	if report.OS != "" {
+		platform = fmt.Sprintf(" (%s/%s)", report.OS, report.Arch)
+	}
+	fmt.Printf("Host %s%s:\n\n", hostID, platform)
+	for _, c := range report.Checks {
+		symbol := successSymbol
+		switch c.Status {
+		case session.HostCheckWarn:
+			symbol = "!"
+		case session.HostCheckFail:
+			symbol = errorSymbol
+		}
+		info := c.Version
+		if c.Detail != "" {
+			if info != "" {
+				info += " - "
+			}
+			info += c.Detail
+		}
+		fmt.Printf("  %s %-11s %s\n", symbol, c.Name, info)
+	}
+	fmt.Println()
+
+	if installed != "" {
+		out.Success(fmt.Sprintf("Installed agent-deck at %s", installed), nil)
+	}
+	if installErr != "" {
+		out.Error("install failed: "+installErr, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if report.Failed() {
+		os.Exit(1)
+	}
+	out.Success("Host is ready for remote sessions", nil)
+}
+
+// handleHostImport imports Host entries from ~/.ssh/config
+func handleHostImport(args []string) {
+	fs := flag.NewFlagSet("host import", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	file := fs.String("file", "", "ssh config file to read (default: ~/.ssh/config)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without writing config.toml")
+	yes := fs.Bool("yes", false, "Import without confirmation")
+	yesShort := fs.Bool("y", false, "Import without confirmation (short)")
+	overwrite := fs.Bool("overwrite", false, "Replace existing [ssh_hosts.X] entries with the same ID")
+	autoDiscover := fs.Bool("auto-discover", false, "Enable auto_discover on imported hosts")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host import [alias...] [options]")
+		fmt.Println()
+		fmt.Println("Import Host entries from ~/.ssh/config (following Include) into config.toml.")
+		fmt.Println("Imported hosts reference the alias via ssh_config_alias, so HostName, User,")
+		fmt.Println("Port, IdentityFile and ProxyJump stay in ~/.ssh/config.")
+		fmt.Println("Without aliases, every literal Host entry is offered.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	autoConfirm := *yes || *yesShort
+
+	path := *file
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		path = home + path[1:]
+	}
+	candidates, err := session.ScanSSHConfigHosts(path)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if path == "" {
+		path = sshpkg.DefaultSSHConfigPath()
+	}
+
+	if fs.NArg() > 0 {
+		byAlias := make(map[string]session.SSHConfigImport, len(candidates))
+		for _, c := range candidates {
+			byAlias[c.Resolved.Alias] = c
+		}
+		var selected []session.SSHConfigImport
+		for _, alias := range fs.Args() {
+			c, ok := byAlias[alias]
+			if !ok {
+				out.Error(fmt.Sprintf("Host '%s' not found in %s", alias, FormatPath(path)), ErrCodeNotFound)
+				os.Exit(2)
+			}
+			selected = append(selected, c)
+		}
+		candidates = selected
+	}
+
+	toImport := 0
+	for _, c := range candidates {
+		if !c.Exists || *overwrite {
+			toImport++
+		}
+	}
+
+	if *jsonOutput {
+		if candidates == nil {
+			candidates = []session.SSHConfigImport{}
+		}
+		var imported []string
+		if !*dryRun && toImport > 0 {
+			imported, err = session.ImportSSHConfigHosts(candidates, *autoDiscover, *overwrite)
+			if err != nil {
+				out.Error(err.Error(), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		if imported == nil {
+			imported = []string{}
+		}
+		out.Print("", map[string]interface{}{
+			"candidates": candidates,
+			"imported":   imported,
+			"dry_run":    *dryRun,
+		})
+		return
+	}
+
+	if len(candidates) == 0 {
+		fmt.Printf("No Host entries found in %s.\n", FormatPath(path))
+		return
+	}
+
+	fmt.Printf("Hosts in %s:\n\n", FormatPath(path))
+	for _, c := range candidates {
+		switch {
+		case c.Exists && !*overwrite:
+			fmt.Printf("= [ssh_hosts.%s] already in config.toml (skipped, use --overwrite to replace)\n", c.HostID)
+		case c.Exists:
+			fmt.Printf("~ [ssh_hosts.%s] ssh_config_alias = %q (replaces existing entry)\n", c.HostID, c.Resolved.Alias)
+		default:
+			fmt.Printf("+ [ssh_hosts.%s] ssh_config_alias = %q\n", c.HostID, c.Resolved.Alias)
+		}
+		printSSHConfigHost(c.Resolved)
+		fmt.Println()
+	}
+
+	if toImport == 0 {
+		fmt.Println("Nothing to import.")
+		return
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: %d hosts would be imported.\n", toImport)
+		return
+	}
+
+	if !autoConfirm {
+		fmt.Printf("Import %d hosts into config.toml? [y/N] ", toImport)
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Cancelled.")
+			return
+		}
+	}
+
+	imported, err := session.ImportSSHConfigHosts(candidates, *autoDiscover, *overwrite)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Imported %d hosts: %s", len(imported), strings.Join(imported, ", ")), nil)
+}
+
+// printSSHConfigHost prints the effective connection settings of an ssh config alias
+func printSSHConfigHost(h sshpkg.SSHConfigHost) {
+	fmt.Printf("    hostname: %s\n", h.HostName)
+	if h.User != "" {
+		fmt.Printf("    user:     %s\n", h.User)
+	}
+	if h.Port != 0 {
+		fmt.Printf("    port:     %d\n", h.Port)
+	}
+	if h.IdentityFile != "" {
+		fmt.Printf("    identity: %s\n", FormatPath(h.IdentityFile))
+	}
+	if h.ProxyJump != "" {
+		fmt.Printf("    jump:     %s\n", h.ProxyJump)
+	}
+	if h.ProxyCommand != "" {
+		fmt.Printf("    proxy:    %s (ssh transport only)\n", h.ProxyCommand)
+	}
+}
+
+// handleHostValidate shows the effective settings of a configured host
+func handleHostValidate(args []string) {
+	fs := flag.NewFlagSet("host validate", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck host validate <host-id> [options]")
+		fmt.Println()
+		fmt.Println("Check an [ssh_hosts.X] entry and show the effective host, user, port,")
+		fmt.Println("identity file and jump host after applying ssh_config_alias.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderHostArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	hostID := fs.Arg(0)
+	if hostID == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	def := session.GetSSHHostDef(hostID)
+	if def == nil {
+		out.Error(fmt.Sprintf("SSH host '%s' not found in config.toml", hostID), ErrCodeNotFound)
+		os.Exit(2)
+	}
+
+	problem := session.ValidateSSHHostDef(*def)
+	eff, _ := session.ResolveSSHHostDef(*def)
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"host_id":          hostID,
+			"valid":            problem == "",
+			"error":            problem,
+			"ssh_config_alias": def.SSHConfigAlias,
+			"host":             eff.Host,
+			"user":             eff.User,
+			"port":             eff.Port,
+			"identity_file":    eff.IdentityFile,
+			"jump_host":        eff.JumpHost,
+			"transport":        eff.Transport,
+		})
+		if problem != "" {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("[ssh_hosts.%s]\n", hostID)
+	if def.SSHConfigAlias != "" {
+		fmt.Printf("  ssh_config_alias: %s\n", def.SSHConfigAlias)
+	}
+	port := eff.Port
+	if port == 0 {
+		port = 22
+	}
+	user := eff.User
+	if user == "" {
+		user = "(current user)"
+	}
+	transport := eff.Transport
+	if transport == "" {
+		transport = sshpkg.TransportSSH
+	}
+	fmt.Printf("  host:      %s\n", eff.Host)
+	fmt.Printf("  user:      %s\n", user)
+	fmt.Printf("  port:      %d\n", port)
+	if eff.IdentityFile != "" {
+		fmt.Printf("  identity:  %s\n", FormatPath(eff.IdentityFile))
+	}
+	if eff.JumpHost != "" {
+		fmt.Printf("  jump_host: %s\n", eff.JumpHost)
+	}
+	fmt.Printf("  transport: %s\n", transport)
+
+	if problem != "" {
+		out.Error(problem, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success("Configuration is valid", nil)
+}
+
+// reorderHostArgs reorders arguments so flags come before positional args
+// e.g., "dev --host 10.0.0.5" becomes "--host 10.0.0.5 dev"
+func reorderHostArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--file": true, "-file": true,
+		"--host": true, "-host": true,
+		"--ssh-config-alias": true, "-ssh-config-alias": true,
+		"--user": true, "-user": true,
+		"--port": true, "-port": true,
+		"--identity-file": true, "-identity-file": true,
+		"--jump-host": true, "-jump-host": true,
+		"--transport": true, "-transport": true,
+		"--tmux-path": true, "-tmux-path": true,
+		"--group-name": true, "-group-name": true,
+		"--session-prefix": true, "-session-prefix": true,
+		"--description": true, "-description": true,
+		"--forward-mcps": true, "-forward-mcps": true,
+		"--binary": true, "-binary": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
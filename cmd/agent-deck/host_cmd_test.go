@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+func TestReorderHostArgs(t *testing.T) {
+	got := reorderHostArgs([]string{"dev", "--host", "10.0.0.5", "--auto-discover", "--port=2222", "--json"})
+	want := []string{"--host", "10.0.0.5", "--auto-discover", "--port=2222", "--json", "dev"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reorderHostArgs = %v, want %v", got, want)
+	}
+}
+
+func TestHostDefFlags_ApplyOnlyGivenFlags(t *testing.T) {
+	fs := flag.NewFlagSet("host update", flag.ContinueOnError)
+	defFlags := registerHostDefFlags(fs)
+	if err := fs.Parse([]string{"--port", "2222", "--jump-host", "", "--auto-discover=false", "--forward-mcps", "exa, github,"}); err != nil {
+		t.Fatal(err)
+	}
+
+	def := session.SSHHostDef{
+		Host:         "10.0.0.5",
+		User:         "dev",
+		JumpHost:     "bastion",
+		AutoDiscover: true,
+		Description:  "keep",
+	}
+	defFlags.apply(fs, &def)
+
+	want := session.SSHHostDef{
+		Host:        "10.0.0.5",
+		User:        "dev",
+		Port:        2222,
+		Description: "keep",
+		ForwardMCPs: []string{"exa", "github"},
+	}
+	if !reflect.DeepEqual(def, want) {
+		t.Errorf("def = %+v, want %+v", def, want)
+	}
+}
+
+func TestValidateHostDef(t *testing.T) {
+	tests := []struct {
+		name   string
+		hostID string
+		def    session.SSHHostDef
+		valid  bool
+	}{
+		{"valid", "dev", session.SSHHostDef{Host: "10.0.0.5"}, true},
+		{"native", "dev", session.SSHHostDef{Host: "10.0.0.5", Transport: "native"}, true},
+		{"bad id", "dev box", session.SSHHostDef{Host: "10.0.0.5"}, false},
+		{"no host", "dev", session.SSHHostDef{User: "me"}, false},
+		{"bad transport", "dev", session.SSHHostDef{Host: "x", Transport: "mosh"}, false},
+		{"bad port", "dev", session.SSHHostDef{Host: "x", Port: 70000}, false},
+	}
+	for _, tt := range tests {
+		if got := validateHostDef(tt.hostID, tt.def) == ""; got != tt.valid {
+			t.Errorf("%s: valid = %v, want %v", tt.name, got, tt.valid)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleLog shows the profile's audit log of session actions
+func handleLog(profile string, args []string) {
+	fs := flag.NewFlagSet("log", flag.ExitOnError)
+	sessionRef := fs.String("session", "", "Only actions on this session (title, ID or ID prefix; deleted sessions by ID)")
+	since := fs.String("since", "", "Only actions since a duration ago (30m, 2h, 7d) or a date (2006-01-02, RFC 3339)")
+	actions := fs.String("action", "", "Only these actions, comma-separated: "+strings.Join(session.AuditActions, ", "))
+	source := fs.String("source", "", "Only actions from this source (tui, cli, desktop)")
+	limit := fs.Int("n", 50, "Show the most recent N entries (0 = all)")
+	verbose := fs.Bool("v", false, "Also show the command (subcommand and flag names) and user of each action")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck log [session] [options]")
+		fmt.Println()
+		fmt.Println("Show who created, started, stopped, restarted, forked, messaged, deleted or")
+		fmt.Println("moved sessions and attached/detached their MCPs: the TUI, the CLI, the")
+		fmt.Println("desktop app or a script, with the process ID and command. Only the")
+		fmt.Println("subcommand and flag names are recorded, not flag values or arguments.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck log --session my-project --since 2h")
+		fmt.Println("  agent-deck log --action stop,restart,delete -v")
+		fmt.Println("  agent-deck -p work log --since 2024-06-01 --json")
+	}
+
+	if err := fs.Parse(reorderLogArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	if *sessionRef == "" {
+		*sessionRef = fs.Arg(0)
+	}
+	filter := session.AuditFilter{Source: *source, Limit: *limit}
+
+	if *since != "" {
+		t, err := parseSince(*since, time.Now())
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		filter.Since = t
+	}
+
+	if *actions != "" {
+		valid := make(map[string]bool, len(session.AuditActions))
+		for _, a := range session.AuditActions {
+			valid[a] = true
+		}
+		for _, a := range strings.Split(*actions, ",") {
+			a = strings.TrimSpace(a)
+			if !valid[a] {
+				out.Error(fmt.Sprintf("unknown action '%s' (valid: %s)", a, strings.Join(session.AuditActions, ", ")), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+			filter.Actions = append(filter.Actions, a)
+		}
+	}
+
+	if *sessionRef != "" {
+		// Deleted sessions can't be resolved; match their ID prefix or title
+		filter.SessionID, filter.Title = *sessionRef, *sessionRef
+		if _, instances, _, err := loadSessionData(profile); err == nil {
+			if inst, _, _ := ResolveSession(*sessionRef, instances); inst != nil {
+				filter.SessionID, filter.Title = inst.ID, ""
+			}
+		}
+	}
+
+	entries, err := session.ReadAudit(profile, filter)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		if entries == nil {
+			entries = []session.AuditEntry{}
+		}
+		out.Print("", map[string]interface{}{
+			"profile": session.GetEffectiveProfile(profile),
+			"entries": entries,
+		})
+		return
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No matching actions.")
+		return
+	}
+
+	fmt.Printf("%-19s %-10s %-20s %-10s %-26s %s\n", "TIME", "ACTION", "SESSION", "ID", "BY", "DETAIL")
+	fmt.Println(strings.Repeat("-", 100))
+	for _, e := range entries {
+		fmt.Printf("%-19s %-10s %-20s %-10s %-26s %s\n",
+			e.Time.Local().Format("2006-01-02 15:04:05"), e.Action, truncate(e.Title, 20),
+			truncate(e.SessionID, 10), truncate(auditActor(e), 26), e.Detail)
+		if *verbose {
+			fmt.Printf("    $ %s", e.Command)
+			if e.User != "" {
+				fmt.Printf("  (user %s)", e.User)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// auditActor describes the process behind an entry, e.g. "cli pid 4242 (bash)"
+func auditActor(e session.AuditEntry) string {
+	actor := e.Source + " pid " + strconv.Itoa(e.PID)
+	if e.Parent != "" {
+		actor += " (" + e.Parent + ")"
+	}
+	return actor
+}
+
+// parseSince parses --since: a duration before now (with d for days) or a
+// date or RFC 3339 time
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q (use e.g. 30m, 2h, 7d or 2006-01-02)", s)
+}
+
+// reorderLogArgs moves flags before positional arguments so that
+// "log my-project --since 2h" parses
+func reorderLogArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--session": true, "-session": true,
+		"--since": true, "-since": true,
+		"--action": true, "-action": true,
+		"--source": true, "-source": true,
+		"-n": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
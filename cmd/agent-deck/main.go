@@ -143,6 +143,15 @@ func main() {
 
 	// Handle subcommands
 	if len(args) > 0 {
+		// Push what a subcommand saved once it returns (the TUI below syncs
+		// on its own)
+		handled := true
+		defer func() {
+			if handled {
+				pushSavedProfiles(args[0])
+			}
+		}()
+
 		switch args[0] {
 		case "version", "--version", "-v":
 			fmt.Printf("Agent Deck v%s\n", Version)
@@ -159,11 +168,20 @@ func main() {
 		case "remove", "rm":
 			handleRemove(profile, args[1:])
 			return
+		case "archive":
+			handleArchive(profile, args[1:])
+			return
 		case "status":
 			handleStatus(profile, args[1:])
 			return
 		case "profile":
-			handleProfile(args[1:])
+			handleProfile(profile, args[1:])
+			return
+		case "sync":
+			handleSync(profile, args[1:])
+			return
+		case "log":
+			handleLog(profile, args[1:])
 			return
 		case "update":
 			handleUpdate(args[1:])
@@ -192,7 +210,17 @@ func main() {
 		case "register-session":
 			handleRegisterSession(profile, args[1:])
 			return
+		case "host":
+			handleHost(profile, args[1:])
+			return
+		case "remote-agent":
+			handleRemoteAgent(args[1:])
+			return
+		case "remote":
+			handleRemote(profile, args[1:])
+			return
 		}
+		handled = false
 	}
 
 	// Set version for UI update checking
@@ -272,6 +300,9 @@ func main() {
 		log.SetOutput(io.Discard)
 	}
 
+	// Actions taken from here on are the TUI's in the audit log
+	session.SetAuditSource("tui")
+
 	// Start TUI with the specified profile
 	// Pass isPrimaryInstance to control notification bar management
 	p := tea.NewProgram(
@@ -401,6 +432,7 @@ func reorderArgsForFlagParsing(args []string) []string {
 		"-c": true, "--cmd": true,
 		"-p": true, "--parent": true,
 		"--mcp": true,
+		"--tag": true, "--meta": true,
 		"-w": true, "--worktree": true,
 		"-H": true, "--host": true,
 	}
@@ -513,6 +545,20 @@ func handleAdd(profile string, args []string) {
 		return nil
 	})
 
+	// Tag and metadata flags - can be specified multiple times
+	var tagFlags, metaFlags []string
+	fs.Func("tag", "Tag the session (can specify multiple times)", func(s string) error {
+		tagFlags = append(tagFlags, s)
+		return nil
+	})
+	fs.Func("meta", "Metadata key=value (can specify multiple times)", func(s string) error {
+		if _, _, err := session.ParseMetaAssignment(s); err != nil {
+			return err
+		}
+		metaFlags = append(metaFlags, s)
+		return nil
+	})
+
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck add [path] [options]")
 		fmt.Println()
@@ -533,6 +579,7 @@ func handleAdd(profile string, args []string) {
 		fmt.Println("  agent-deck -p work add               # Add to 'work' profile")
 		fmt.Println("  agent-deck add -t \"Sub-task\" --parent \"Main Project\"  # Create sub-session")
 		fmt.Println("  agent-deck add -t \"Research\" -c claude --mcp memory --mcp sequential-thinking /tmp/x")
+		fmt.Println("  agent-deck add --tag urgent --meta ticket=ENG-42 .")
 		fmt.Println()
 		fmt.Println("Worktree Examples:")
 		fmt.Println("  agent-deck add -w feature/login .    # Create worktree for existing branch")
@@ -777,6 +824,12 @@ func handleAdd(profile string, args []string) {
 		newInstance.WorktreeBranch = wtBranch
 	}
 
+	newInstance.AddTags(tagFlags...)
+	for _, m := range metaFlags {
+		key, value, _ := session.ParseMetaAssignment(m)
+		newInstance.SetMeta(key, value)
+	}
+
 	// Add to instances
 	instances = append(instances, newInstance)
 
@@ -791,6 +844,7 @@ func handleAdd(profile string, args []string) {
 		fmt.Printf("Error: failed to save session: %v\n", err)
 		os.Exit(1)
 	}
+	session.RecordAudit(storage.Profile(), session.AuditCreate, newInstance, "")
 
 	// Attach MCPs if specified
 	if len(mcpFlags) > 0 {
@@ -825,6 +879,12 @@ func handleAdd(profile string, args []string) {
 	if len(mcpFlags) > 0 {
 		fmt.Printf("  MCPs:    %s\n", strings.Join(mcpFlags, ", "))
 	}
+	if len(newInstance.Tags) > 0 {
+		fmt.Printf("  Tags:    %s\n", session.FormatTags(newInstance.Tags))
+	}
+	if len(newInstance.Meta) > 0 {
+		fmt.Printf("  Meta:    %s\n", strings.Join(session.FormatMeta(newInstance.Meta), ", "))
+	}
 	if parentInstance != nil {
 		fmt.Printf("  Parent:  %s (%s)\n", parentInstance.Title, parentInstance.ID[:8])
 	}
@@ -845,6 +905,15 @@ func handleList(profile string, args []string) {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
 	jsonOutput := fs.Bool("json", false, "Output as JSON")
 	allProfiles := fs.Bool("all", false, "List sessions from all profiles")
+	var tagFilters, metaFilters []string
+	fs.Func("tag", "Only sessions with this tag (can specify multiple times)", func(s string) error {
+		tagFilters = append(tagFilters, s)
+		return nil
+	})
+	fs.Func("meta", "Only sessions with metadata key=value, or key for any value (can specify multiple times)", func(s string) error {
+		metaFilters = append(metaFilters, s)
+		return nil
+	})
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck list [options]")
@@ -858,14 +927,22 @@ func handleList(profile string, args []string) {
 		fmt.Println("  agent-deck list                    # List from default profile")
 		fmt.Println("  agent-deck -p work list            # List from 'work' profile")
 		fmt.Println("  agent-deck list --all              # List from all profiles")
+		fmt.Println("  agent-deck list --tag urgent       # Sessions tagged urgent")
+		fmt.Println("  agent-deck list --meta client=acme # Sessions with client=acme")
 	}
 
 	if err := fs.Parse(args); err != nil {
 		os.Exit(1)
 	}
+	filterSessions := func(instances []*session.Instance) []*session.Instance {
+		if len(tagFilters) == 0 && len(metaFilters) == 0 {
+			return instances
+		}
+		return session.FilterByTagsAndMeta(instances, tagFilters, metaFilters)
+	}
 
 	if *allProfiles {
-		handleListAllProfiles(*jsonOutput)
+		handleListAllProfiles(*jsonOutput, filterSessions)
 		return
 	}
 
@@ -880,6 +957,7 @@ func handleList(profile string, args []string) {
 		fmt.Printf("Error: failed to load sessions: %v\n", err)
 		os.Exit(1)
 	}
+	instances = filterSessions(instances)
 
 	if len(instances) == 0 {
 		fmt.Printf("No sessions found in profile '%s'.\n", storage.Profile())
@@ -889,14 +967,16 @@ func handleList(profile string, args []string) {
 	if *jsonOutput {
 		// JSON output for scripting
 		type sessionJSON struct {
-			ID        string    `json:"id"`
-			Title     string    `json:"title"`
-			Path      string    `json:"path"`
-			Group     string    `json:"group"`
-			Tool      string    `json:"tool"`
-			Command   string    `json:"command,omitempty"`
-			Profile   string    `json:"profile"`
-			CreatedAt time.Time `json:"created_at"`
+			ID        string            `json:"id"`
+			Title     string            `json:"title"`
+			Path      string            `json:"path"`
+			Group     string            `json:"group"`
+			Tool      string            `json:"tool"`
+			Command   string            `json:"command,omitempty"`
+			Profile   string            `json:"profile"`
+			CreatedAt time.Time         `json:"created_at"`
+			Tags      []string          `json:"tags,omitempty"`
+			Meta      map[string]string `json:"meta,omitempty"`
 		}
 		sessions := make([]sessionJSON, len(instances))
 		for i, inst := range instances {
@@ -909,6 +989,8 @@ func handleList(profile string, args []string) {
 				Command:   inst.Command,
 				Profile:   storage.Profile(),
 				CreatedAt: inst.CreatedAt,
+				Tags:      inst.Tags,
+				Meta:      inst.Meta,
 			}
 		}
 		output, err := json.MarshalIndent(sessions, "", "  ")
@@ -938,7 +1020,7 @@ func handleList(profile string, args []string) {
 		if len(idDisplay) > tableColIDDisplay {
 			idDisplay = idDisplay[:tableColIDDisplay]
 		}
-		fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay)
+		fmt.Printf("%-*s %-*s %-*s %s%s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay, tagSuffix(inst))
 	}
 	fmt.Printf("\nTotal: %d sessions\n", len(instances))
 
@@ -947,7 +1029,7 @@ func handleList(profile string, args []string) {
 }
 
 // handleListAllProfiles lists sessions from all profiles
-func handleListAllProfiles(jsonOutput bool) {
+func handleListAllProfiles(jsonOutput bool, filterSessions func([]*session.Instance) []*session.Instance) {
 	profiles, err := session.ListProfiles()
 	if err != nil {
 		fmt.Printf("Error: failed to list profiles: %v\n", err)
@@ -961,14 +1043,16 @@ func handleListAllProfiles(jsonOutput bool) {
 
 	if jsonOutput {
 		type sessionJSON struct {
-			ID        string    `json:"id"`
-			Title     string    `json:"title"`
-			Path      string    `json:"path"`
-			Group     string    `json:"group"`
-			Tool      string    `json:"tool"`
-			Command   string    `json:"command,omitempty"`
-			Profile   string    `json:"profile"`
-			CreatedAt time.Time `json:"created_at"`
+			ID        string            `json:"id"`
+			Title     string            `json:"title"`
+			Path      string            `json:"path"`
+			Group     string            `json:"group"`
+			Tool      string            `json:"tool"`
+			Command   string            `json:"command,omitempty"`
+			Profile   string            `json:"profile"`
+			CreatedAt time.Time         `json:"created_at"`
+			Tags      []string          `json:"tags,omitempty"`
+			Meta      map[string]string `json:"meta,omitempty"`
 		}
 		var allSessions []sessionJSON
 
@@ -981,7 +1065,7 @@ func handleListAllProfiles(jsonOutput bool) {
 			if err != nil {
 				continue
 			}
-			for _, inst := range instances {
+			for _, inst := range filterSessions(instances) {
 				allSessions = append(allSessions, sessionJSON{
 					ID:        inst.ID,
 					Title:     inst.Title,
@@ -991,6 +1075,8 @@ func handleListAllProfiles(jsonOutput bool) {
 					Command:   inst.Command,
 					Profile:   profileName,
 					CreatedAt: inst.CreatedAt,
+					Tags:      inst.Tags,
+					Meta:      inst.Meta,
 				})
 			}
 		}
@@ -1015,6 +1101,7 @@ func handleListAllProfiles(jsonOutput bool) {
 		if err != nil {
 			continue
 		}
+		instances = filterSessions(instances)
 
 		if len(instances) == 0 {
 			continue
@@ -1032,7 +1119,7 @@ func handleListAllProfiles(jsonOutput bool) {
 			if len(idDisplay) > tableColIDDisplay {
 				idDisplay = idDisplay[:tableColIDDisplay]
 			}
-			fmt.Printf("%-*s %-*s %-*s %s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay)
+			fmt.Printf("%-*s %-*s %-*s %s%s\n", tableColTitle, title, tableColGroup, group, tableColPath, path, idDisplay, tagSuffix(inst))
 		}
 		fmt.Printf("(%d sessions)\n", len(instances))
 		totalSessions += len(instances)
@@ -1042,21 +1129,36 @@ func handleListAllProfiles(jsonOutput bool) {
 	fmt.Printf("Total: %d sessions across %d profiles\n", totalSessions, len(profiles))
 }
 
+// tagSuffix returns a session's tags for the end of a list row
+func tagSuffix(inst *session.Instance) string {
+	if len(inst.Tags) == 0 {
+		return ""
+	}
+	return "  " + session.FormatTags(inst.Tags)
+}
+
 // handleRemove removes a session by ID or title
 func handleRemove(profile string, args []string) {
 	fs := flag.NewFlagSet("remove", flag.ExitOnError)
+	permanent := fs.Bool("permanent", false, "Delete without keeping the session in the archive")
+	reason := fs.String("reason", "removed with agent-deck rm", "Reason recorded in the archive")
 	fs.Usage = func() {
-		fmt.Println("Usage: agent-deck remove <id|title>")
+		fmt.Println("Usage: agent-deck remove <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Remove a session by ID or title. The session is moved to the archive")
+		fmt.Println("and can be brought back with 'agent-deck archive restore'.")
 		fmt.Println()
-		fmt.Println("Remove a session by ID or title.")
+		fmt.Println("Options:")
+		fs.PrintDefaults()
 		fmt.Println()
 		fmt.Println("Examples:")
 		fmt.Println("  agent-deck remove abc12345")
 		fmt.Println("  agent-deck remove \"My Project\"")
+		fmt.Println("  agent-deck remove abc12345 --permanent")
 		fmt.Println("  agent-deck -p work remove abc12345   # Remove from 'work' profile")
 	}
 
-	if err := fs.Parse(args); err != nil {
+	if err := fs.Parse(reorderArchiveArgs(args)); err != nil {
 		os.Exit(1)
 	}
 
@@ -1079,14 +1181,34 @@ func handleRemove(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	var archive *session.Archive
+	if !*permanent {
+		if archive, err = session.OpenArchive(storage.Profile()); err != nil {
+			fmt.Printf("Error: failed to open archive: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Find and remove the session
 	found := false
 	var removedTitle string
+	var removedIDs []string
+	var removed []*session.Instance
 	newInstances := make([]*session.Instance, 0, len(instances))
 	for _, inst := range instances {
 		if inst.ID == identifier || strings.HasPrefix(inst.ID, identifier) || inst.Title == identifier {
 			found = true
 			removedTitle = inst.Title
+			removedIDs = append(removedIDs, inst.ID)
+			removed = append(removed, inst)
+			// Archive before killing so a failed archive leaves the session intact
+			if archive != nil {
+				if err := archive.Add(inst, *reason); err != nil {
+					fmt.Printf("Error: failed to archive session: %v\n", err)
+					fmt.Println("Use --permanent to delete it without archiving")
+					os.Exit(1)
+				}
+			}
 			// Kill tmux session if it exists
 			if inst.Exists() {
 				if err := inst.Kill(); err != nil {
@@ -1111,8 +1233,21 @@ func handleRemove(profile string, args []string) {
 		fmt.Printf("Error: failed to save: %v\n", err)
 		os.Exit(1)
 	}
+	for _, inst := range removed {
+		session.RecordAudit(storage.Profile(), session.AuditDelete, inst, "")
+	}
+
+	// Archived sessions keep their notes until purged
+	if archive == nil {
+		if err := session.DeleteNotes(storage.Profile(), removedIDs...); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+	}
 
 	fmt.Printf("✓ Removed session: %s (from profile '%s')\n", removedTitle, storage.Profile())
+	if archive != nil {
+		fmt.Printf("  Restore it with: agent-deck archive restore %q\n", removedTitle)
+	}
 }
 
 // statusCounts holds session counts by status
@@ -1260,8 +1395,8 @@ func handleStatus(profile string, args []string) {
 	}
 }
 
-// handleProfile manages profiles (list, create, delete, default)
-func handleProfile(args []string) {
+// handleProfile manages profiles (list, create, delete, default, export, import)
+func handleProfile(profile string, args []string) {
 	if len(args) == 0 {
 		// Default to list
 		handleProfileList()
@@ -1297,6 +1432,10 @@ func handleProfile(args []string) {
 			return
 		}
 		handleProfileSetDefault(args[1])
+	case "export":
+		handleProfileExport(profile, args[1:])
+	case "import":
+		handleProfileImport(args[1:])
 	default:
 		fmt.Printf("Unknown profile command: %s\n", args[0])
 		fmt.Println()
@@ -1307,6 +1446,8 @@ func handleProfile(args []string) {
 		fmt.Println("  create <name>     Create a new profile")
 		fmt.Println("  delete <name>     Delete a profile")
 		fmt.Println("  default [name]    Show or set default profile")
+		fmt.Println("  export [name]     Export a profile to a bundle file")
+		fmt.Println("  import <file>     Import a profile from a bundle file")
 		os.Exit(1)
 	}
 }
@@ -1456,13 +1597,18 @@ func printHelp() {
 	fmt.Println("  add <path>       Add a new session")
 	fmt.Println("  try <name>       Quick experiment (create/find dated folder + session)")
 	fmt.Println("  list, ls         List all sessions")
-	fmt.Println("  remove, rm       Remove a session")
+	fmt.Println("  remove, rm       Remove a session (kept in the archive)")
+	fmt.Println("  archive          List, restore or purge removed sessions")
 	fmt.Println("  status           Show session status summary")
 	fmt.Println("  session          Manage session lifecycle")
 	fmt.Println("  mcp              Manage MCP servers")
 	fmt.Println("  group            Manage groups")
+	fmt.Println("  host             Manage SSH hosts")
+	fmt.Println("  remote           Copy files to/from remote sessions")
 	fmt.Println("  worktree, wt     Manage git worktrees")
 	fmt.Println("  profile          Manage profiles")
+	fmt.Println("  sync             Sync profiles between machines through git")
+	fmt.Println("  log              Show who acted on sessions (audit log)")
 	fmt.Println("  update           Check for and install updates")
 	fmt.Println("  uninstall        Uninstall Agent Deck")
 	fmt.Println("  version          Show version")
@@ -1475,7 +1621,11 @@ func printHelp() {
 	fmt.Println("  session fork <id>         Fork Claude session with context")
 	fmt.Println("  session attach <id>       Attach to session interactively")
 	fmt.Println("  session show [id]         Show session details")
+	fmt.Println("  session tag <id> [tag...] Tag a session / set key=value metadata")
+	fmt.Println("  session notes <id>        Show/edit private session notes")
+	fmt.Println("  session move-profile <id> <profile>  Move/copy (--copy) a session to another profile")
 	fmt.Println("  register-session          Register an existing tmux session (for remote use)")
+	fmt.Println("  remote-agent              Serve tmux operations over ssh (started automatically)")
 	fmt.Println()
 	fmt.Println("MCP Commands:")
 	fmt.Println("  mcp list                  List available MCPs from config.toml")
@@ -1483,6 +1633,27 @@ func printHelp() {
 	fmt.Println("  mcp attach <id> <mcp>     Attach MCP to session")
 	fmt.Println("  mcp detach <id> <mcp>     Detach MCP from session")
 	fmt.Println()
+	fmt.Println("Host Commands:")
+	fmt.Println("  host list                 List SSH hosts")
+	fmt.Println("  host add <id> --host <h>  Add an SSH host")
+	fmt.Println("  host update <id> [opts]   Change an SSH host")
+	fmt.Println("  host remove <id>          Remove an SSH host")
+	fmt.Println("  host test <id>            Test the connection to a host")
+	fmt.Println("  host status               Show connection status of all hosts")
+	fmt.Println("  host discover [id]        Discover sessions on remote hosts")
+	fmt.Println("  host doctor <id>          Check a host's prerequisites (--install uploads agent-deck)")
+	fmt.Println("  host import [alias...]    Import hosts from ~/.ssh/config")
+	fmt.Println("  host validate <host-id>   Show a host's effective SSH settings")
+	fmt.Println()
+	fmt.Println("Remote Commands:")
+	fmt.Println("  remote push <id> <path>   Copy local files to a remote session's host")
+	fmt.Println("  remote pull <id> <path>   Copy files from a remote session's host")
+	fmt.Println()
+	fmt.Println("Archive Commands:")
+	fmt.Println("  archive list              List removed sessions")
+	fmt.Println("  archive restore <id>      Restore and restart a removed session")
+	fmt.Println("  archive purge <id>|--all  Permanently delete archived sessions")
+	fmt.Println()
 	fmt.Println("Group Commands:")
 	fmt.Println("  group list                List all groups")
 	fmt.Println("  group create <name>       Create a new group")
@@ -1499,6 +1670,13 @@ func printHelp() {
 	fmt.Println("  profile create <name>     Create a new profile")
 	fmt.Println("  profile delete <name>     Delete a profile")
 	fmt.Println("  profile default [name]    Show or set default profile")
+	fmt.Println("  profile export [name] -o <file>  Export sessions, config and layouts to a bundle")
+	fmt.Println("  profile import <file>     Import a bundle (--as, --merge, --map from=to)")
+	fmt.Println()
+	fmt.Println("Sync Commands:")
+	fmt.Println("  sync init <remote>        Sync profiles through a git repository")
+	fmt.Println("  sync [now]                Pull, merge and push the current profile")
+	fmt.Println("  sync status               Show sync settings and the last sync")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  agent-deck                            # Start TUI with default profile")
@@ -1510,18 +1688,23 @@ func printHelp() {
 	fmt.Println("  agent-deck mcp list --json            # List MCPs as JSON")
 	fmt.Println("  agent-deck mcp attach my-app exa      # Attach MCP to session")
 	fmt.Println("  agent-deck group move my-app work     # Move session to group")
+	fmt.Println("  agent-deck log my-app --since 2h      # Who started/stopped my-app")
 	fmt.Println()
 	fmt.Println("Environment Variables:")
 	fmt.Println("  AGENTDECK_PROFILE    Default profile to use")
 	fmt.Println("  AGENTDECK_COLOR      Color mode: truecolor, 256, 16, none")
+	fmt.Println("  AGENTDECK_AUDIT_SOURCE  Name recorded as the source in the audit log")
 	fmt.Println()
 	fmt.Println("Keyboard shortcuts (in TUI):")
 	fmt.Println("  n          New session")
 	fmt.Println("  g          New group")
 	fmt.Println("  Enter      Attach to session")
 	fmt.Println("  d          Delete session/group")
+	fmt.Println("  A          Archive (restore deleted sessions)")
 	fmt.Println("  m          Move session to group")
 	fmt.Println("  R          Rename session/group")
+	fmt.Println("  E          Edit session notes")
+	fmt.Println("  Ctrl+Z/Y   Undo/redo")
 	fmt.Println("  /          Search")
 	fmt.Println("  Ctrl+Q     Detach from session")
 	fmt.Println("  q          Quit")
@@ -1746,10 +1929,7 @@ func handleUninstall(args []string) {
 			for _, entry := range entries {
 				if entry.IsDir() {
 					profileCount++
-					sessionsFile := filepath.Join(profilesDir, entry.Name(), "sessions.json")
-					if data, err := os.ReadFile(sessionsFile); err == nil {
-						sessionCount += strings.Count(string(data), `"id"`)
-					}
+					sessionCount += countProfileSessions(entry.Name())
 				}
 			}
 		}
@@ -2009,6 +2189,21 @@ func handleUninstall(args []string) {
 	fmt.Println("Feedback: https://github.com/asheshgoplani/agent-deck/issues")
 }
 
+// countProfileSessions returns the number of sessions in a profile, read
+// through the storage layer so both sessions.json and sessions.db count
+func countProfileSessions(profile string) int {
+	storage, err := session.NewStorageWithProfile(profile)
+	if err != nil {
+		return 0
+	}
+	defer func() { _ = storage.Close() }()
+	data, err := storage.LoadStorageData()
+	if err != nil {
+		return 0
+	}
+	return len(data.Instances)
+}
+
 // formatSize formats bytes into human-readable size
 func formatSize(bytes int64) string {
 	const unit = 1024
@@ -2030,12 +2225,18 @@ func handleDebug(profile string, args []string) {
 		fmt.Println()
 		fmt.Println("Subcommands:")
 		fmt.Println("  discover    Test remote session discovery")
+		fmt.Println("  storage     Print the profile's stored sessions and groups as JSON")
+		fmt.Println("  changes     Show the SQLite change journal")
 		return
 	}
 
 	switch args[0] {
 	case "discover":
 		handleDebugDiscover()
+	case "storage":
+		handleDebugStorage(profile)
+	case "changes":
+		handleDebugChanges(profile, args[1:])
 	default:
 		fmt.Printf("Unknown debug subcommand: %s\n", args[0])
 	}
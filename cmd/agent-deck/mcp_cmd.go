@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,12 +23,22 @@ func handleMCP(profile string, args []string) {
 	switch args[0] {
 	case "list", "ls":
 		handleMCPList(args[1:])
+	case "bundles":
+		handleMCPBundles(args[1:])
+	case "doctor":
+		handleMCPDoctor(profile, args[1:])
+	case "import":
+		handleMCPImport(args[1:])
+	case "exec":
+		handleMCPExec(args[1:])
 	case "attached":
 		handleMCPAttached(profile, args[1:])
 	case "attach":
 		handleMCPAttach(profile, args[1:])
 	case "detach":
 		handleMCPDetach(profile, args[1:])
+	case "unquarantine":
+		handleMCPUnquarantine(args[1:])
 	case "help", "-h", "--help":
 		printMCPHelp()
 	default:
@@ -48,6 +59,11 @@ func printMCPHelp() {
 	fmt.Println("  attached [id]       Show MCPs attached to a session")
 	fmt.Println("  attach <id> <mcp>   Attach an MCP to a session")
 	fmt.Println("  detach <id> <mcp>   Detach an MCP from a session")
+	fmt.Println("  bundles             List MCP bundles from config.toml")
+	fmt.Println("  doctor              Check for plaintext secrets and broken secret references")
+	fmt.Println("  import              Import MCPs from Claude, Gemini and Cursor configs")
+	fmt.Println("  exec <mcp>          Run an MCP with secret references resolved (used in .mcp.json)")
+	fmt.Println("  unquarantine <mcp>  Put a crash-looping pool MCP back in the pool")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  agent-deck mcp list                        # List available MCPs")
@@ -56,6 +72,8 @@ func printMCPHelp() {
 	fmt.Println("  agent-deck mcp attach my-project exa       # Attach exa to my-project (local)")
 	fmt.Println("  agent-deck mcp attach my-project exa --global     # Attach globally")
 	fmt.Println("  agent-deck mcp detach my-project exa       # Detach exa from my-project")
+	fmt.Println("  agent-deck mcp attach my-project web-research --bundle  # Attach a bundle")
+	fmt.Println("  agent-deck mcp import --dry-run            # Preview MCPs found in other tools")
 }
 
 // handleMCPList lists all available MCPs from config.toml
@@ -69,6 +87,7 @@ func handleMCPList(args []string) {
 		fmt.Println("Usage: agent-deck mcp list [options]")
 		fmt.Println()
 		fmt.Println("List all available MCPs from config.toml.")
+		fmt.Println("When the MCP pool is enabled, pool status and health (ping) are shown.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -102,25 +121,39 @@ func handleMCPList(args []string) {
 		return
 	}
 
+	// Pool health (socket probes) only when pooling is on
+	var health map[string]session.MCPHealth
+	if config, _ := session.LoadUserConfig(); config != nil && config.MCPPool.Enabled && !quietMode {
+		health = make(map[string]session.MCPHealth)
+		for _, h := range session.GetMCPPoolHealth() {
+			health[h.Name] = h
+		}
+	}
+
 	if *jsonOutput {
 		// Build JSON output
 		type mcpJSON struct {
-			Name        string            `json:"name"`
-			Command     string            `json:"command"`
-			Args        []string          `json:"args"`
-			Env         map[string]string `json:"env,omitempty"`
-			Description string            `json:"description,omitempty"`
+			Name        string             `json:"name"`
+			Command     string             `json:"command"`
+			Args        []string           `json:"args"`
+			Env         map[string]string  `json:"env,omitempty"`
+			Description string             `json:"description,omitempty"`
+			Pool        *session.MCPHealth `json:"pool,omitempty"`
 		}
 
 		mcpList := make([]mcpJSON, 0, len(mcps))
 		for name, def := range mcps {
-			mcpList = append(mcpList, mcpJSON{
+			item := mcpJSON{
 				Name:        name,
 				Command:     def.Command,
 				Args:        def.Args,
 				Env:         def.Env,
 				Description: def.Description,
-			})
+			}
+			if h, ok := health[name]; ok {
+				item.Pool = &h
+			}
+			mcpList = append(mcpList, item)
 		}
 
 		out.Print("", map[string]interface{}{
@@ -154,8 +187,13 @@ func handleMCPList(args []string) {
 		maxName = 20
 	}
 
-	fmt.Printf("%-*s %-*s %s\n", maxName, "NAME", maxCmd, "COMMAND", "DESCRIPTION")
-	fmt.Println(strings.Repeat("-", maxName+maxCmd+20))
+	if health != nil {
+		fmt.Printf("%-*s %-*s %-14s %s\n", maxName, "NAME", maxCmd, "COMMAND", "POOL", "DESCRIPTION")
+		fmt.Println(strings.Repeat("-", maxName+maxCmd+35))
+	} else {
+		fmt.Printf("%-*s %-*s %s\n", maxName, "NAME", maxCmd, "COMMAND", "DESCRIPTION")
+		fmt.Println(strings.Repeat("-", maxName+maxCmd+20))
+	}
 
 	names := session.GetAvailableMCPNames()
 	for _, name := range names {
@@ -174,10 +212,37 @@ func handleMCPList(args []string) {
 			nameDisplay = nameDisplay[:maxName-3] + "..."
 		}
 
-		fmt.Printf("%-*s %-*s %s\n", maxName, nameDisplay, maxCmd, cmdDisplay, def.Description)
+		if health != nil {
+			fmt.Printf("%-*s %-*s %-14s %s\n", maxName, nameDisplay, maxCmd, cmdDisplay, formatMCPPoolHealth(health[name]), def.Description)
+		} else {
+			fmt.Printf("%-*s %-*s %s\n", maxName, nameDisplay, maxCmd, cmdDisplay, def.Description)
+		}
 	}
 
 	fmt.Printf("\nTotal: %d MCPs\n", len(mcps))
+
+	// Explain problems below the table
+	for _, name := range names {
+		if h, ok := health[name]; ok && h.Error != "" {
+			fmt.Printf("  %s %s: %s\n", bulletSymbol, name, h.Error)
+		}
+	}
+}
+
+// formatMCPPoolHealth renders the POOL column of 'mcp list'
+func formatMCPPoolHealth(h session.MCPHealth) string {
+	switch {
+	case h.Status == session.MCPPoolStatusQuarantined:
+		return "⛔ quarantined"
+	case h.Status != "running":
+		return h.Status
+	case h.Health == "healthy":
+		return fmt.Sprintf("✓ %dms", h.LatencyMs)
+	case h.Health == "unresponsive":
+		return "✗ unresponsive"
+	default:
+		return "running"
+	}
 }
 
 // handleMCPAttached shows MCPs attached to a session
@@ -314,6 +379,7 @@ func handleMCPAttach(profile string, args []string) {
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 	global := fs.Bool("global", false, "Attach to global config instead of local .mcp.json")
 	restart := fs.Bool("restart", false, "Restart session to load MCP immediately")
+	bundle := fs.Bool("bundle", false, "Treat the name as an MCP bundle and attach all its MCPs")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck mcp attach <session-id> <mcp-name> [options]")
@@ -327,6 +393,7 @@ func handleMCPAttach(profile string, args []string) {
 		fmt.Println("  agent-deck mcp attach my-project exa           # Attach locally")
 		fmt.Println("  agent-deck mcp attach my-project exa --global  # Attach globally")
 		fmt.Println("  agent-deck mcp attach my-project exa --restart # Attach and restart")
+		fmt.Println("  agent-deck mcp attach my-project web-research --bundle")
 	}
 
 	if err := fs.Parse(args); err != nil {
@@ -368,6 +435,11 @@ func handleMCPAttach(profile string, args []string) {
 		os.Exit(2)
 	}
 
+	if *bundle {
+		handleMCPBundleChange(profile, out, inst, mcpName, *global, *restart, true, *jsonOutput, quietMode)
+		return
+	}
+
 	// Verify MCP exists in config.toml
 	availableMCPs := session.GetAvailableMCPs()
 	if _, exists := availableMCPs[mcpName]; !exists {
@@ -420,29 +492,13 @@ func handleMCPAttach(profile string, args []string) {
 			os.Exit(1)
 		}
 	}
+	session.RecordAudit(profile, session.AuditMCPAttach, inst, mcpName+" ("+scope+")")
 
 	// Clear MCP cache for this project
 	session.ClearMCPCache(inst.ProjectPath)
 
 	// Restart if requested
-	restarted := false
-	if *restart && (inst.Tool == "claude" || inst.Tool == "gemini") {
-		if err := inst.Restart(); err != nil {
-			// Don't fail the whole operation, just warn
-			if !*jsonOutput && !quietMode {
-				fmt.Fprintf(os.Stderr, "Warning: failed to restart session: %v\n", err)
-			}
-		} else {
-			restarted = true
-			// Auto-continue: wait for Claude/Gemini to initialize, then send continue message
-			time.Sleep(2 * time.Second)
-			if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil {
-				// Send "continue" and Enter to resume the conversation
-				_ = exec.Command("tmux", "send-keys", "-l", "-t", tmuxSess.Name, "continue").Run()
-				_ = exec.Command("tmux", "send-keys", "-t", tmuxSess.Name, "Enter").Run()
-			}
-		}
-	}
+	restarted := *restart && restartAfterMCPChange(profile, inst, *jsonOutput || quietMode)
 
 	// Output result
 	if *jsonOutput {
@@ -470,6 +526,7 @@ func handleMCPDetach(profile string, args []string) {
 	quietShort := fs.Bool("q", false, "Minimal output (short)")
 	global := fs.Bool("global", false, "Remove from global config instead of local .mcp.json")
 	restart := fs.Bool("restart", false, "Restart session to unload MCP immediately")
+	bundle := fs.Bool("bundle", false, "Treat the name as an MCP bundle and detach all its MCPs")
 
 	fs.Usage = func() {
 		fmt.Println("Usage: agent-deck mcp detach <session-id> <mcp-name> [options]")
@@ -483,6 +540,7 @@ func handleMCPDetach(profile string, args []string) {
 		fmt.Println("  agent-deck mcp detach my-project exa           # Detach from local")
 		fmt.Println("  agent-deck mcp detach my-project exa --global  # Detach from global")
 		fmt.Println("  agent-deck mcp detach my-project exa --restart # Detach and restart")
+		fmt.Println("  agent-deck mcp detach my-project web-research --bundle")
 	}
 
 	if err := fs.Parse(args); err != nil {
@@ -524,6 +582,11 @@ func handleMCPDetach(profile string, args []string) {
 		os.Exit(2)
 	}
 
+	if *bundle {
+		handleMCPBundleChange(profile, out, inst, mcpName, *global, *restart, false, *jsonOutput, quietMode)
+		return
+	}
+
 	scope := "local"
 	if *global {
 		scope = "global"
@@ -572,29 +635,13 @@ func handleMCPDetach(profile string, args []string) {
 			os.Exit(1)
 		}
 	}
+	session.RecordAudit(profile, session.AuditMCPDetach, inst, mcpName+" ("+scope+")")
 
 	// Clear MCP cache for this project
 	session.ClearMCPCache(inst.ProjectPath)
 
 	// Restart if requested
-	restarted := false
-	if *restart && (inst.Tool == "claude" || inst.Tool == "gemini") {
-		if err := inst.Restart(); err != nil {
-			// Don't fail the whole operation, just warn
-			if !*jsonOutput && !quietMode {
-				fmt.Fprintf(os.Stderr, "Warning: failed to restart session: %v\n", err)
-			}
-		} else {
-			restarted = true
-			// Auto-continue: wait for Claude/Gemini to initialize, then send continue message
-			time.Sleep(2 * time.Second)
-			if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil {
-				// Send "continue" and Enter to resume the conversation
-				_ = exec.Command("tmux", "send-keys", "-l", "-t", tmuxSess.Name, "continue").Run()
-				_ = exec.Command("tmux", "send-keys", "-t", tmuxSess.Name, "Enter").Run()
-			}
-		}
-	}
+	restarted := *restart && restartAfterMCPChange(profile, inst, *jsonOutput || quietMode)
 
 	// Output result
 	if *jsonOutput {
@@ -613,3 +660,720 @@ func handleMCPDetach(profile string, args []string) {
 		out.Success(message, nil)
 	}
 }
+
+// handleMCPUnquarantine lifts a pool quarantine recorded in mcp_quarantine.json
+func handleMCPUnquarantine(args []string) {
+	fs := flag.NewFlagSet("mcp unquarantine", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck mcp unquarantine <mcp> [options]")
+		fmt.Println()
+		fmt.Println("Put an MCP the pool quarantined after crash-looping back in the pool.")
+		fmt.Println("The TUI running the pool restarts it within a few seconds. Sessions")
+		fmt.Println("switched to stdio keep using stdio until the MCP is re-attached.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderArgsForFlagParsing(args)); err != nil {
+		os.Exit(1)
+	}
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if fs.NArg() != 1 {
+		out.Error("MCP name is required", ErrCodeInvalidOperation)
+		if !*jsonOutput {
+			fmt.Println("\nUsage: agent-deck mcp unquarantine <mcp> [options]")
+		}
+		os.Exit(1)
+	}
+	name := fs.Arg(0)
+
+	record, err := session.UnquarantineMCP(name)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Lifted quarantine of %s (%d crashes, %s)", name, record.Crashes, record.Reason), map[string]interface{}{
+		"success":   true,
+		"name":      name,
+		"crashes":   record.Crashes,
+		"rewritten": record.Rewritten,
+	})
+}
+
+// handleMCPBundles lists MCP bundles from config.toml
+func handleMCPBundles(args []string) {
+	fs := flag.NewFlagSet("mcp bundles", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck mcp bundles [options]")
+		fmt.Println()
+		fmt.Println("List MCP bundles from config.toml.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	bundles := session.GetMCPBundles()
+	names := session.GetMCPBundleNames()
+
+	if *jsonOutput {
+		type bundleJSON struct {
+			Name  string   `json:"name"`
+			MCPs  []string `json:"mcps"`
+			Valid bool     `json:"valid"`
+			Error string   `json:"error,omitempty"`
+		}
+		bundleList := make([]bundleJSON, 0, len(names))
+		for _, name := range names {
+			entry := bundleJSON{Name: name, MCPs: bundles[name], Valid: true}
+			if _, err := session.ResolveMCPBundles([]string{name}); err != nil {
+				entry.Valid = false
+				entry.Error = err.Error()
+			}
+			bundleList = append(bundleList, entry)
+		}
+		out.Print("", map[string]interface{}{
+			"bundles": bundleList,
+		})
+		return
+	}
+
+	if quietMode {
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No MCP bundles configured.")
+		fmt.Println()
+		fmt.Println("Define bundles in ~/.agent-deck/config.toml:")
+		fmt.Println()
+		fmt.Println("  [mcp_bundles]")
+		fmt.Println("  web-research = [\"exa\", \"firecrawl\", \"fetch\"]")
+		return
+	}
+
+	configPath, _ := session.GetUserConfigPath()
+	fmt.Printf("MCP bundles (from %s):\n\n", FormatPath(configPath))
+	for _, name := range names {
+		fmt.Printf("  %s %s: %s\n", bulletSymbol, name, strings.Join(bundles[name], ", "))
+		if _, err := session.ResolveMCPBundles([]string{name}); err != nil {
+			fmt.Printf("      %s\n", err)
+		}
+	}
+	fmt.Printf("\nTotal: %d bundles\n", len(names))
+}
+
+// handleMCPBundleChange attaches or detaches every MCP in a bundle with a single
+// config write, then optionally restarts the session
+func handleMCPBundleChange(profile string, out *CLIOutput, inst *session.Instance, bundleName string, global, restart, attach, jsonOutput, quietMode bool) {
+	if session.GetMCPBundle(bundleName) == nil {
+		out.Error(fmt.Sprintf("MCP bundle '%s' not found in config.toml", bundleName), ErrCodeMCPNotAvailable)
+		if !jsonOutput && !quietMode {
+			fmt.Println("\nAvailable bundles:")
+			for _, name := range session.GetMCPBundleNames() {
+				fmt.Printf("  %s %s\n", bulletSymbol, name)
+			}
+		}
+		os.Exit(2)
+	}
+
+	scope := "local"
+	if inst.Tool == "gemini" {
+		scope = "global"
+	}
+
+	var changed []string
+	var err error
+	if global && inst.Tool != "gemini" {
+		scope = "global"
+		var members []string
+		members, err = session.ResolveMCPBundles([]string{bundleName})
+		if err == nil {
+			current := session.GetGlobalMCPNames()
+			var updated []string
+			if attach {
+				updated = session.MergeMCPNames(current, members)
+				changed = session.SubtractMCPNames(updated, current)
+			} else {
+				updated = session.SubtractMCPNames(current, members)
+				changed = session.SubtractMCPNames(current, updated)
+			}
+			if len(changed) > 0 {
+				err = session.WriteGlobalMCP(updated)
+			}
+		}
+	} else if attach {
+		changed, err = session.AttachMCPBundles(inst.Tool, inst.ProjectPath, []string{bundleName})
+	} else {
+		changed, err = session.DetachMCPBundles(inst.Tool, inst.ProjectPath, []string{bundleName})
+	}
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if len(changed) > 0 {
+		action := session.AuditMCPAttach
+		if !attach {
+			action = session.AuditMCPDetach
+		}
+		session.RecordAudit(profile, action, inst, strings.Join(changed, ", ")+" ("+scope+", bundle "+bundleName+")")
+	}
+
+	// Clear MCP cache for this project
+	session.ClearMCPCache(inst.ProjectPath)
+
+	restarted := restart && len(changed) > 0 && restartAfterMCPChange(profile, inst, jsonOutput || quietMode)
+
+	verb := "Attached"
+	prep := "to"
+	if !attach {
+		verb = "Detached"
+		prep = "from"
+	}
+
+	if jsonOutput {
+		if changed == nil {
+			changed = []string{}
+		}
+		out.Print("", map[string]interface{}{
+			"success":   true,
+			"session":   inst.Title,
+			"bundle":    bundleName,
+			"mcps":      changed,
+			"scope":     scope,
+			"restarted": restarted,
+		})
+		return
+	}
+
+	if len(changed) == 0 {
+		out.Success(fmt.Sprintf("Nothing to do: bundle %s is already %s %s (%s)",
+			bundleName, strings.ToLower(verb), prep, scope), nil)
+		return
+	}
+	message := fmt.Sprintf("%s bundle %s (%s) %s %s (%s)",
+		verb, bundleName, strings.Join(changed, ", "), prep, inst.Title, scope)
+	if restarted {
+		message += " - session restarted"
+	}
+	out.Success(message, nil)
+}
+
+// handleMCPExec runs a stdio MCP from config.toml with its env secret references
+// (env:, file:, cmd:) resolved. Generated .mcp.json files point here instead of
+// embedding secret values. stdin/stdout are passed through untouched.
+func handleMCPExec(args []string) {
+	if len(args) != 1 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, "Usage: agent-deck mcp exec <mcp-name>")
+		os.Exit(1)
+	}
+	name := args[0]
+
+	def := session.GetMCPDef(name)
+	if def == nil {
+		fmt.Fprintf(os.Stderr, "Error: MCP '%s' not found in config.toml\n", name)
+		os.Exit(2)
+	}
+	if def.Command == "" {
+		fmt.Fprintf(os.Stderr, "Error: MCP '%s' has no command (HTTP/SSE MCPs cannot be exec'd)\n", name)
+		os.Exit(1)
+	}
+
+	env, err := def.ResolvedEnv()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: MCP '%s': %v\n", name, err)
+		os.Exit(1)
+	}
+
+	cmd := exec.Command(def.Command, def.Args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+	for k, v := range env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		fmt.Fprintf(os.Stderr, "Error: failed to run MCP '%s': %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// handleMCPDoctor reports plaintext secrets in config.toml and generated MCP
+// files, plus env:/file: secret references that cannot be resolved
+func handleMCPDoctor(profile string, args []string) {
+	fs := flag.NewFlagSet("mcp doctor", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck mcp doctor [options]")
+		fmt.Println()
+		fmt.Println("Check MCP configuration for plaintext secrets.")
+		fmt.Println()
+		fmt.Println("Scans config.toml, every session's .mcp.json, Claude's .claude.json files")
+		fmt.Println("and Gemini's settings.json. Use secret references in config.toml instead:")
+		fmt.Println("  env = { EXA_API_KEY = \"env:EXA_API_KEY\" }")
+		fmt.Println("  env = { EXA_API_KEY = \"file:~/.secrets/exa\" }")
+		fmt.Println("  env = { EXA_API_KEY = \"cmd:pass show exa\" }")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+
+	// Collect generated files to scan
+	var files []string
+	seen := make(map[string]bool)
+	addFile := func(path string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		if _, err := os.Stat(path); err == nil {
+			files = append(files, path)
+		}
+	}
+
+	if storage, err := session.NewStorageWithProfile(profile); err == nil {
+		if instances, _, err := storage.LoadWithGroups(); err == nil {
+			for _, inst := range instances {
+				if inst.ProjectPath != "" && !inst.IsRemote() {
+					addFile(filepath.Join(inst.ProjectPath, ".mcp.json"))
+				}
+			}
+		}
+	}
+	addFile(filepath.Join(session.GetClaudeConfigDir(), ".claude.json"))
+	addFile(session.GetUserMCPRootPath())
+	addFile(filepath.Join(session.GetGeminiConfigDir(), "settings.json"))
+
+	configFindings := session.FindLiteralSecretsInConfig()
+	refProblems := session.CheckSecretRefs()
+	var fileFindings []session.SecretFinding
+	var scanErrors []string
+	for _, path := range files {
+		findings, err := session.FindLiteralSecretsInMCPFile(path)
+		if err != nil {
+			scanErrors = append(scanErrors, err.Error())
+			continue
+		}
+		fileFindings = append(fileFindings, findings...)
+	}
+
+	healthy := len(configFindings) == 0 && len(refProblems) == 0 && len(fileFindings) == 0
+
+	if *jsonOutput {
+		if configFindings == nil {
+			configFindings = []session.SecretFinding{}
+		}
+		if fileFindings == nil {
+			fileFindings = []session.SecretFinding{}
+		}
+		if refProblems == nil {
+			refProblems = []session.SecretRefProblem{}
+		}
+		out.Print("", map[string]interface{}{
+			"healthy":         healthy,
+			"scanned_files":   files,
+			"config_secrets":  configFindings,
+			"generated_files": fileFindings,
+			"unresolved_refs": refProblems,
+			"scan_errors":     scanErrors,
+		})
+		if !healthy {
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("Scanned config.toml and %d generated MCP files\n\n", len(files))
+
+	if len(configFindings) > 0 {
+		fmt.Println("Plaintext secrets in config.toml (replace with env:/file:/cmd: references):")
+		for _, f := range configFindings {
+			fmt.Printf("  %s [mcps.%s] %s.%s\n", bulletSymbol, f.MCP, f.Field, f.Key)
+		}
+		fmt.Println()
+	}
+
+	if len(fileFindings) > 0 {
+		fmt.Println("Plaintext secrets in generated files:")
+		for _, f := range fileFindings {
+			location := f.MCP
+			if f.Project != "" {
+				location = fmt.Sprintf("projects[%s].%s", FormatPath(f.Project), f.MCP)
+			}
+			fmt.Printf("  %s %s: %s %s.%s\n", bulletSymbol, FormatPath(f.File), location, f.Field, f.Key)
+		}
+		fmt.Println()
+		fmt.Println("  Re-attach these MCPs after switching to secret references to regenerate the files.")
+		fmt.Println()
+	}
+
+	if len(refProblems) > 0 {
+		fmt.Println("Secret references that cannot be resolved:")
+		for _, p := range refProblems {
+			fmt.Printf("  %s [mcps.%s] %s.%s: %s\n", bulletSymbol, p.MCP, p.Field, p.Key, p.Error)
+		}
+		fmt.Println()
+	}
+
+	for _, e := range scanErrors {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", e)
+	}
+
+	if healthy {
+		out.Success("No plaintext secrets found", nil)
+		return
+	}
+	os.Exit(1)
+}
+
+// handleMCPImport imports MCP definitions from Claude, Gemini and Cursor configs
+// into config.toml's [mcps] section
+func handleMCPImport(args []string) {
+	fs := flag.NewFlagSet("mcp import", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	projectPath := fs.String("project", "", "Project directory to scan for .mcp.json / .cursor/mcp.json (default: current directory)")
+	from := fs.String("from", "", "Comma-separated sources to scan: claude,project,gemini,cursor (default: all)")
+	file := fs.String("file", "", "Additional MCP config file to scan (JSON with mcpServers)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without writing config.toml")
+	yes := fs.Bool("yes", false, "Import without confirmation")
+	yesShort := fs.Bool("y", false, "Import without confirmation (short)")
+	overwrite := fs.Bool("overwrite", false, "Replace config.toml entries that conflict with imported definitions")
+	secretRefs := fs.Bool("secret-refs", false, "Store secret-looking env values as env:KEY references instead of plaintext")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck mcp import [options]")
+		fmt.Println()
+		fmt.Println("Import MCP definitions from ~/.claude.json, project .mcp.json,")
+		fmt.Println("Gemini settings.json and Cursor mcp.json into config.toml.")
+		fmt.Println("MCPs are deduplicated by command+args (or URL for HTTP/SSE MCPs).")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck mcp import --dry-run")
+		fmt.Println("  agent-deck mcp import --from claude,cursor -y")
+		fmt.Println("  agent-deck mcp import --file ~/team/mcp.json --secret-refs")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	autoConfirm := *yes || *yesShort
+
+	project := *projectPath
+	if project == "" {
+		project, _ = os.Getwd()
+	}
+	if project != "" {
+		if abs, err := filepath.Abs(project); err == nil {
+			project = abs
+		}
+	}
+
+	sources := session.DefaultMCPImportSources(project)
+	if *from != "" {
+		kinds := make(map[string]bool)
+		for _, kind := range strings.Split(*from, ",") {
+			kind = strings.TrimSpace(kind)
+			switch kind {
+			case "claude", "project", "gemini", "cursor":
+				kinds[kind] = true
+			default:
+				out.Error(fmt.Sprintf("unknown source '%s' (use claude, project, gemini, cursor)", kind), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		filtered := sources[:0]
+		for _, src := range sources {
+			if kinds[src.Kind] {
+				filtered = append(filtered, src)
+			}
+		}
+		sources = filtered
+	}
+	if *file != "" {
+		path := *file
+		if strings.HasPrefix(path, "~/") {
+			home, _ := os.UserHomeDir()
+			path = filepath.Join(home, path[2:])
+		}
+		sources = append(sources, session.MCPImportSource{Kind: "file", Path: path})
+	}
+
+	candidates, scanErrs := session.ScanMCPImportSources(sources)
+	for _, err := range scanErrs {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+
+	converted := make(map[string][]string)
+	if *secretRefs {
+		for i := range candidates {
+			if keys := candidates[i].Def.ConvertLiteralSecretsToRefs(); len(keys) > 0 {
+				converted[candidates[i].Name] = keys
+			}
+		}
+	}
+
+	toImport := 0
+	for _, c := range candidates {
+		if c.Status == session.MCPImportNew || (*overwrite && c.Status == session.MCPImportConflict) {
+			toImport++
+		}
+	}
+
+	if *jsonOutput {
+		// Without a terminal to confirm on, only --yes writes config.toml
+		preview := *dryRun || !autoConfirm
+		list := make([]mcpImportCandidateJSON, 0, len(candidates))
+		for _, c := range candidates {
+			item := mcpImportCandidateJSON{
+				Name:         c.Name,
+				Def:          newMCPDefJSON(c.Def),
+				Sources:      c.Sources,
+				Status:       c.Status,
+				ExistingName: c.ExistingName,
+				SecretRefs:   converted[c.Name],
+			}
+			if c.Existing != nil {
+				existing := newMCPDefJSON(*c.Existing)
+				item.Existing = &existing
+			}
+			list = append(list, item)
+		}
+		var imported []string
+		if !preview && toImport > 0 {
+			var err error
+			imported, err = session.ApplyMCPImports(candidates, *overwrite)
+			if err != nil {
+				out.Error(err.Error(), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		if imported == nil {
+			imported = []string{}
+		}
+		out.Print("", map[string]interface{}{
+			"candidates": list,
+			"imported":   imported,
+			"dry_run":    preview,
+		})
+		return
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println("No MCP definitions found.")
+		fmt.Println()
+		fmt.Println("Scanned:")
+		for _, src := range sources {
+			fmt.Printf("  %s %s\n", bulletSymbol, FormatPath(src.Path))
+		}
+		return
+	}
+
+	// Show diff against config.toml
+	configPath, _ := session.GetUserConfigPath()
+	fmt.Printf("MCPs found (compared to %s):\n\n", FormatPath(configPath))
+	for _, c := range candidates {
+		switch c.Status {
+		case session.MCPImportNew:
+			printMCPDefDiff("+", c.Name, c.Def)
+		case session.MCPImportConflict:
+			if *overwrite {
+				printMCPDefDiff("-", c.Name, *c.Existing)
+				printMCPDefDiff("+", c.Name, c.Def)
+			} else {
+				fmt.Printf("~ [mcps.%s] differs from config.toml (skipped, use --overwrite to replace)\n", c.Name)
+			}
+		case session.MCPImportExists:
+			fmt.Printf("= [mcps.%s] already in config.toml\n", c.Name)
+		case session.MCPImportDuplicate:
+			fmt.Printf("= %s: same command as [mcps.%s]\n", c.Name, c.ExistingName)
+		}
+		for _, src := range c.Sources {
+			fmt.Printf("    from %s\n", FormatPath(src))
+		}
+		if keys, ok := converted[c.Name]; ok {
+			fmt.Printf("    env values stored as references: %s\n", strings.Join(keys, ", "))
+		}
+		fmt.Println()
+	}
+
+	if toImport == 0 {
+		fmt.Println("Nothing to import.")
+		return
+	}
+	if *dryRun {
+		fmt.Printf("Dry run: %d MCPs would be imported.\n", toImport)
+		return
+	}
+
+	if !autoConfirm {
+		fmt.Printf("Import %d MCPs into config.toml? [y/N] ", toImport)
+		var response string
+		_, _ = fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Cancelled.")
+			return
+		}
+	}
+
+	imported, err := session.ApplyMCPImports(candidates, *overwrite)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	out.Success(fmt.Sprintf("Imported %d MCPs: %s", len(imported), strings.Join(imported, ", ")), nil)
+	if len(converted) > 0 {
+		fmt.Println("  Export the referenced environment variables before launching sessions.")
+	} else if len(session.FindLiteralSecretsInConfig()) > 0 {
+		fmt.Println("  Tip: run 'agent-deck mcp doctor' - config.toml contains plaintext secrets.")
+	}
+}
+
+// mcpDefJSON is an MCP definition in 'mcp import --json' output, with
+// secret env and header values masked
+type mcpDefJSON struct {
+	Command     string            `json:"command,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+	Env         map[string]string `json:"env,omitempty"`
+	Description string            `json:"description,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Transport   string            `json:"transport,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+}
+
+func newMCPDefJSON(def session.MCPDef) mcpDefJSON {
+	return mcpDefJSON{
+		Command:     def.Command,
+		Args:        def.Args,
+		Env:         maskMCPSecrets(def.Env),
+		Description: def.Description,
+		URL:         def.URL,
+		Transport:   def.Transport,
+		Headers:     maskMCPSecrets(def.Headers),
+	}
+}
+
+// mcpImportCandidateJSON is one candidate in 'mcp import --json' output
+type mcpImportCandidateJSON struct {
+	Name         string      `json:"name"`
+	Def          mcpDefJSON  `json:"def"`
+	Sources      []string    `json:"sources"`
+	Status       string      `json:"status"`
+	ExistingName string      `json:"existing_name,omitempty"`
+	Existing     *mcpDefJSON `json:"existing,omitempty"`
+	// SecretRefs are env keys stored as references (--secret-refs)
+	SecretRefs []string `json:"secret_refs,omitempty"`
+}
+
+// maskMCPSecrets returns a copy of env or header values with literal
+// secrets replaced by asterisks; secret references are kept
+func maskMCPSecrets(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	masked := make(map[string]string, len(values))
+	for k, v := range values {
+		if !session.IsSecretRef(v) && session.LooksLikeLiteralSecret(k, v) {
+			v = "********"
+		}
+		masked[k] = v
+	}
+	return masked
+}
+
+// printMCPDefDiff prints an MCP definition as TOML lines prefixed with a diff marker.
+// Env and header values are masked so secrets don't end up in terminal scrollback.
+func printMCPDefDiff(marker, name string, def session.MCPDef) {
+	fmt.Printf("%s [mcps.%s]\n", marker, name)
+	if def.Command != "" {
+		fmt.Printf("%s command = %q\n", marker, def.Command)
+	}
+	if len(def.Args) > 0 {
+		quoted := make([]string, len(def.Args))
+		for i, arg := range def.Args {
+			quoted[i] = fmt.Sprintf("%q", arg)
+		}
+		fmt.Printf("%s args = [%s]\n", marker, strings.Join(quoted, ", "))
+	}
+	if def.URL != "" {
+		fmt.Printf("%s url = %q\n", marker, def.URL)
+		fmt.Printf("%s transport = %q\n", marker, def.Transport)
+	}
+	printMaskedMap := func(field string, values map[string]string) {
+		if len(values) == 0 {
+			return
+		}
+		masked := maskMCPSecrets(values)
+		keys := make([]string, 0, len(masked))
+		for k := range masked {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		parts := make([]string, len(keys))
+		for i, k := range keys {
+			parts[i] = fmt.Sprintf("%s = %q", k, masked[k])
+		}
+		fmt.Printf("%s %s = { %s }\n", marker, field, strings.Join(parts, ", "))
+	}
+	printMaskedMap("env", def.Env)
+	printMaskedMap("headers", def.Headers)
+}
+
+// restartAfterMCPChange restarts a Claude/Gemini session so MCP changes take effect,
+// then sends "continue" to resume the conversation. Returns true if restarted.
+func restartAfterMCPChange(profile string, inst *session.Instance, silent bool) bool {
+	if inst.Tool != "claude" && inst.Tool != "gemini" {
+		return false
+	}
+	if err := inst.Restart(); err != nil {
+		// Don't fail the whole operation, just warn
+		if !silent {
+			fmt.Fprintf(os.Stderr, "Warning: failed to restart session: %v\n", err)
+		}
+		return false
+	}
+	session.RecordAudit(profile, session.AuditRestart, inst, "MCP change")
+	// Auto-continue: wait for Claude/Gemini to initialize, then send continue message
+	time.Sleep(2 * time.Second)
+	if tmuxSess := inst.GetTmuxSession(); tmuxSess != nil {
+		// Send "continue" and Enter to resume the conversation
+		_ = exec.Command("tmux", "send-keys", "-l", "-t", tmuxSess.Name, "continue").Run()
+		_ = exec.Command("tmux", "send-keys", "-t", tmuxSess.Name, "Enter").Run()
+	}
+	return true
+}
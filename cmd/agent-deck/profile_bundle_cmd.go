@@ -0,0 +1,282 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleProfileExport writes a profile's sessions, groups, notes, config
+// and desktop state to a bundle file
+func handleProfileExport(profile string, args []string) {
+	fs := flag.NewFlagSet("profile export", flag.ExitOnError)
+	output := fs.String("o", "", "Bundle file to write (required, e.g. deck.tar.gz; - for stdout)")
+	noConfig := fs.Bool("no-config", false, "Don't include MCP definitions, MCP bundles and launch configs")
+	noDesktop := fs.Bool("no-desktop", false, "Don't include desktop saved layouts and quick-launch favorites")
+	includeSecrets := fs.Bool("include-secrets", false, "Keep literal API keys and tokens in MCP env/headers (default: replace them with env: references)")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck profile export [name] -o <file> [options]")
+		fmt.Println()
+		fmt.Println("Export a profile (default: the current one) to a bundle with its sessions,")
+		fmt.Println("groups and notes, the MCP definitions, MCP bundles and launch configs from")
+		fmt.Println("config.toml, and the desktop app's saved layouts and quick-launch favorites.")
+		fmt.Println("Restore it on another machine with 'agent-deck profile import'.")
+		fmt.Println()
+		fmt.Println("Literal secrets in MCP env and headers are replaced with env: references")
+		fmt.Println("(e.g. EXA_API_KEY = \"env:EXA_API_KEY\") unless --include-secrets is given;")
+		fmt.Println("set those variables on the importing machine.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck profile export work -o deck.tar.gz")
+		fmt.Println("  agent-deck -p work profile export -o - --no-desktop > deck.tar.gz")
+	}
+
+	if err := fs.Parse(reorderProfileBundleArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if *output == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+	name := profile
+	if fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+
+	bundle, err := session.LoadProfileBundle(name, session.ExportOptions{
+		NoConfig:       *noConfig,
+		NoDesktop:      *noDesktop,
+		IncludeSecrets: *includeSecrets,
+	})
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(2)
+	}
+	if *includeSecrets && bundle.Config != nil {
+		fmt.Fprintln(os.Stderr, "Warning: --include-secrets writes MCP API keys and tokens to the bundle in plain text.")
+		fmt.Fprintln(os.Stderr, "         Anyone with the file can use them; don't share or commit it.")
+	}
+
+	if *output == "-" {
+		if err := bundle.Write(os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	tmpPath := *output + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to create %s: %v", *output, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	err = bundle.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, *output)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	masked := bundle.Manifest.MaskedSecrets
+	if len(masked) > 0 && !*jsonOutput {
+		out.Print(fmt.Sprintf("Masked %d MCP secret(s); set these variables where the bundle is imported:\n", len(masked)), nil)
+		for _, m := range masked {
+			out.Print(fmt.Sprintf("  %s %s %s.%s -> %s\n", bulletSymbol, m.MCP, m.Field, m.Key, m.Ref), nil)
+		}
+	}
+	if masked == nil {
+		masked = []session.SecretFinding{}
+	}
+
+	out.Success(fmt.Sprintf("Exported profile '%s' to %s (%d sessions, %d groups, %d notes, %d layouts, %d favorites)",
+		bundle.Manifest.Profile, *output, len(bundle.Data.Instances), len(bundle.Data.Groups),
+		len(bundle.Notes), len(bundle.Layouts), len(bundle.Favorites)), map[string]interface{}{
+		"success":        true,
+		"profile":        bundle.Manifest.Profile,
+		"file":           *output,
+		"sessions":       len(bundle.Data.Instances),
+		"groups":         len(bundle.Data.Groups),
+		"notes":          len(bundle.Notes),
+		"config":         bundle.Config != nil,
+		"layouts":        len(bundle.Layouts),
+		"favorites":      len(bundle.Favorites),
+		"masked_secrets": masked,
+	})
+}
+
+// handleProfileImport creates (or merges into) a profile from a bundle
+func handleProfileImport(args []string) {
+	fs := flag.NewFlagSet("profile import", flag.ExitOnError)
+	as := fs.String("as", "", "Import into this profile (default: the exported profile's name)")
+	merge := fs.Bool("merge", false, "Import into an existing profile")
+	overwrite := fs.Bool("overwrite", false, "Replace sessions, groups and config entries that already exist (default: keep ours)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be imported without changing anything")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	var maps []session.PathMapping
+	fs.Func("map", "Rewrite paths under FROM to TO, e.g. /Users/a=/home/a (repeatable)", func(s string) error {
+		m, err := session.ParsePathMapping(s)
+		if err != nil {
+			return err
+		}
+		maps = append(maps, m)
+		return nil
+	})
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck profile import <file> [options]")
+		fmt.Println()
+		fmt.Println("Import a bundle written by 'agent-deck profile export' (- reads stdin).")
+		fmt.Println("A new profile is created unless --merge is given. Entries that already")
+		fmt.Println("exist (sessions by ID, groups by path, config entries by name, layouts by")
+		fmt.Println("ID, favorites by path) are skipped unless --overwrite is given.")
+		fmt.Println()
+		fmt.Println("Paths under the exporting user's home directory are moved to yours;")
+		fmt.Println("use --map for anything else. Remote sessions keep their paths.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck profile import deck.tar.gz")
+		fmt.Println("  agent-deck profile import deck.tar.gz --as work-laptop --map /Users/a/code=/home/a/src")
+		fmt.Println("  agent-deck profile import deck.tar.gz --merge --dry-run")
+	}
+
+	if err := fs.Parse(reorderProfileBundleArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	file := fs.Arg(0)
+	if file == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	in := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to open bundle: %v", err), ErrCodeNotFound)
+			os.Exit(2)
+		}
+		defer f.Close()
+		in = f
+	}
+	bundle, err := session.ReadProfileBundle(in)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	result, err := session.ImportProfileBundle(bundle, session.ImportOptions{
+		Profile:   *as,
+		Merge:     *merge,
+		Overwrite: *overwrite,
+		DryRun:    *dryRun,
+		Maps:      maps,
+	})
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	action := "into"
+	if result.Created {
+		action = "as new profile"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s '%s' %s '%s'", verb, bundle.Manifest.Profile, action, result.Profile)
+	for _, c := range []struct {
+		name   string
+		counts session.ImportCounts
+	}{
+		{"sessions", result.Sessions},
+		{"groups", result.Groups},
+		{"MCPs", result.MCPs},
+		{"MCP bundles", result.MCPBundles},
+		{"launch configs", result.LaunchConfigs},
+		{"layouts", result.Layouts},
+		{"favorites", result.Favorites},
+	} {
+		if c.counts == (session.ImportCounts{}) {
+			continue
+		}
+		fmt.Fprintf(&b, "\n  %-15s %d added", c.name+":", c.counts.Added)
+		if c.counts.Replaced > 0 {
+			fmt.Fprintf(&b, ", %d replaced", c.counts.Replaced)
+		}
+		if c.counts.Skipped > 0 {
+			fmt.Fprintf(&b, ", %d skipped (already exist)", c.counts.Skipped)
+		}
+	}
+	if result.Notes > 0 {
+		fmt.Fprintf(&b, "\n  %-15s %d", "notes:", result.Notes)
+	}
+	if masked := bundle.Manifest.MaskedSecrets; len(masked) > 0 && result.MCPs != (session.ImportCounts{}) {
+		b.WriteString("\n  MCP secrets were masked on export; set these variables:")
+		for _, m := range masked {
+			fmt.Fprintf(&b, "\n    %s (%s %s.%s)", strings.TrimPrefix(m.Ref, session.SecretRefEnv), m.MCP, m.Field, m.Key)
+		}
+	}
+	if result.Created && !*dryRun {
+		fmt.Fprintf(&b, "\n  Use with: agent-deck -p %s", result.Profile)
+	}
+
+	out.Success(b.String(), map[string]interface{}{
+		"success": true,
+		"dry_run": *dryRun,
+		"result":  result,
+	})
+}
+
+// reorderProfileBundleArgs moves flags before positional arguments so that
+// "profile import deck.tar.gz --merge" parses
+func reorderProfileBundleArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"-o":   true,
+		"--as": true, "-as": true,
+		"--map": true, "-map": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
@@ -200,6 +200,7 @@ func handleRegisterSession(profile string, args []string) {
 			"SAVE_ERROR")
 		os.Exit(exitGeneralError)
 	}
+	session.RecordAudit(storage.Profile(), session.AuditCreate, newInstance, "registered tmux session "+sessionTmux)
 
 	// Output success
 	outputSuccess(*jsonOutput, isQuiet,
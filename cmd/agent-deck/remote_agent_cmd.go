@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// handleRemoteAgent serves the remote agent protocol on stdin/stdout.
+// agent-deck starts this over ssh on remote hosts so that every tmux
+// operation shares one long-lived SSH channel. Not meant to be run by hand.
+func handleRemoteAgent(args []string) {
+	fs := flag.NewFlagSet("remote-agent", flag.ExitOnError)
+	tmuxPath := fs.String("tmux-path", "", "Path to the tmux binary (default: tmux from PATH)")
+
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: agent-deck remote-agent [--tmux-path <path>]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Serve tmux operations for a remote agent-deck over stdin/stdout.")
+		fmt.Fprintln(os.Stderr, "Started automatically via ssh; not meant to be run by hand.")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	// stdout carries protocol frames; keep logs out of it
+	log.SetOutput(io.Discard)
+
+	if *tmuxPath != "" {
+		// The local executor runs "tmux" from PATH
+		_ = os.Setenv("PATH", filepath.Dir(*tmuxPath)+string(os.PathListSeparator)+os.Getenv("PATH"))
+	}
+
+	if err := tmux.ServeRemoteAgent(os.Stdin, os.Stdout, tmux.NewLocalExecutor(), Version); err != nil {
+		fmt.Fprintf(os.Stderr, "remote-agent: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// handleRemote dispatches remote subcommands
+func handleRemote(profile string, args []string) {
+	if len(args) == 0 {
+		printRemoteHelp()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "push":
+		handleRemoteSync(profile, "push", args[1:])
+	case "pull":
+		handleRemoteSync(profile, "pull", args[1:])
+	case "help", "-h", "--help":
+		printRemoteHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown remote command '%s'\n", args[0])
+		printRemoteHelp()
+		os.Exit(1)
+	}
+}
+
+// printRemoteHelp prints help for remote commands
+func printRemoteHelp() {
+	fmt.Println("Usage: agent-deck remote <command> [options]")
+	fmt.Println()
+	fmt.Println("Copy files between this machine and a remote session's host.")
+	fmt.Println("Only files whose sha256 differs are transferred; relative remote")
+	fmt.Println("paths are resolved against the session's project path.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  push <session> <local-path> [remote-path]   Upload a file or directory")
+	fmt.Println("  pull <session> <remote-path> [local-path]   Download a file or directory")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck remote push api ./fixtures          # -> <project>/fixtures")
+	fmt.Println("  agent-deck remote push api . --delete          # Mirror the current directory")
+	fmt.Println("  agent-deck remote pull api out/report.html     # -> ./report.html")
+	fmt.Println("  agent-deck remote pull api . --dry-run --json  # Show what would change")
+}
+
+// handleRemoteSync implements remote push and remote pull
+func handleRemoteSync(profile, direction string, args []string) {
+	fs := flag.NewFlagSet("remote "+direction, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("q", false, "Quiet mode")
+	dryRun := fs.Bool("dry-run", false, "Show what would be transferred without copying")
+	deleteExtra := fs.Bool("delete", false, "Delete destination files that don't exist in the source")
+	exclude := fs.String("exclude", "", "Comma-separated names or globs to skip, in addition to .git")
+	all := fs.Bool("all", false, "Don't skip .git")
+
+	fs.Usage = func() {
+		if direction == "push" {
+			fmt.Println("Usage: agent-deck remote push <session> <local-path> [remote-path] [options]")
+		} else {
+			fmt.Println("Usage: agent-deck remote pull <session> <remote-path> [local-path] [options]")
+		}
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderRemoteArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet)
+	if fs.NArg() < 2 || fs.NArg() > 3 {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	_, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(fs.Arg(0), instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	if !inst.IsRemote() {
+		out.Error(fmt.Sprintf("session '%s' is not a remote session", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	opts := sshpkg.SyncOptions{
+		Exclude: remoteSyncExclude(*exclude, *all),
+		Delete:  *deleteExtra,
+		DryRun:  *dryRun,
+	}
+
+	var result *sshpkg.SyncResult
+	var from, to string
+	if direction == "push" {
+		from, to = fs.Arg(1), fs.Arg(2)
+		if to == "" {
+			abs, _ := filepath.Abs(from)
+			to = filepath.Base(abs)
+		}
+		to = session.RemoteSessionPath(inst, to)
+		result, err = session.PushToRemoteSession(inst, from, to, opts)
+		to = inst.RemoteHost + ":" + to
+	} else {
+		from, to = session.RemoteSessionPath(inst, fs.Arg(1)), fs.Arg(2)
+		if to == "" {
+			to = path.Base(from)
+		}
+		result, err = session.PullFromRemoteSession(inst, from, to, opts)
+		from = inst.RemoteHost + ":" + from
+	}
+	if err != nil {
+		out.Error(fmt.Sprintf("%s failed: %v", direction, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		out.Print("", map[string]interface{}{
+			"success":       true,
+			"direction":     direction,
+			"session_id":    inst.ID,
+			"session_title": inst.Title,
+			"from":          from,
+			"to":            to,
+			"dry_run":       *dryRun,
+			"transferred":   result.Transferred,
+			"deleted":       result.Deleted,
+			"unchanged":     result.Unchanged,
+			"bytes":         result.Bytes,
+		})
+		return
+	}
+
+	verb := "Copied"
+	if *dryRun {
+		verb = "Would copy"
+	}
+	for _, name := range result.Transferred {
+		out.Print(fmt.Sprintf("  %s %s\n", bulletSymbol, name), nil)
+	}
+	for _, name := range result.Deleted {
+		out.Print(fmt.Sprintf("  %s %s (delete)\n", bulletSymbol, name), nil)
+	}
+	out.Success(fmt.Sprintf("%s %d file(s), %s, %s -> %s (%d unchanged, %d deleted)",
+		verb, len(result.Transferred), formatSize(result.Bytes), from, to, result.Unchanged, len(result.Deleted)), nil)
+}
+
+// remoteSyncExclude builds SyncOptions.Exclude from --exclude and --all
+func remoteSyncExclude(list string, all bool) []string {
+	exclude := []string{}
+	if !all {
+		exclude = append(exclude, sshpkg.DefaultSyncExclude...)
+	}
+	for _, pattern := range strings.Split(list, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			exclude = append(exclude, pattern)
+		}
+	}
+	return exclude
+}
+
+// reorderRemoteArgs moves flags before positional arguments so that
+// "remote push api ./dir --dry-run" parses
+func reorderRemoteArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--exclude": true, "-exclude": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") && arg != "-" {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReorderRemoteArgs(t *testing.T) {
+	got := reorderRemoteArgs([]string{"api", "./dir", "--exclude", "node_modules", "--dry-run", "out"})
+	want := []string{"--exclude", "node_modules", "--dry-run", "api", "./dir", "out"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reorderRemoteArgs = %v, want %v", got, want)
+	}
+}
+
+func TestRemoteSyncExclude(t *testing.T) {
+	if got := remoteSyncExclude("node_modules, *.log,", false); !reflect.DeepEqual(got, []string{".git", "node_modules", "*.log"}) {
+		t.Errorf("remoteSyncExclude = %v", got)
+	}
+	if got := remoteSyncExclude("", true); len(got) != 0 || got == nil {
+		t.Errorf("--all should give an empty, non-nil list, got %#v", got)
+	}
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/profile"
@@ -30,6 +32,14 @@ func handleSession(profile string, args []string) {
 		handleSessionRestart(profile, args[1:])
 	case "fork":
 		handleSessionFork(profile, args[1:])
+	case "migrate":
+		handleSessionMigrate(profile, args[1:])
+	case "move-profile":
+		handleSessionMoveProfile(profile, args[1:], false)
+	case "copy-profile":
+		handleSessionMoveProfile(profile, args[1:], true)
+	case "forward":
+		handleSessionForward(profile, args[1:])
 	case "attach":
 		handleSessionAttach(profile, args[1:])
 	case "show":
@@ -42,6 +52,10 @@ func handleSession(profile string, args []string) {
 		handleSessionUnsetParent(profile, args[1:])
 	case "set":
 		handleSessionSet(profile, args[1:])
+	case "tag":
+		handleSessionTag(profile, args[1:])
+	case "notes":
+		handleSessionNotes(profile, args[1:])
 	case "send":
 		handleSessionSend(profile, args[1:])
 	case "output":
@@ -66,10 +80,16 @@ func printSessionHelp() {
 	fmt.Println("  stop <id>               Stop/kill session process")
 	fmt.Println("  restart <id>            Restart session (Claude: reload MCPs)")
 	fmt.Println("  fork <id>               Fork Claude session with context")
+	fmt.Println("  migrate <id> --to <host|local>  Move session to an SSH host or back")
+	fmt.Println("  move-profile <id> <profile>  Move session (and sub-sessions) to another profile")
+	fmt.Println("  copy-profile <id> <profile>  Copy session to another profile")
+	fmt.Println("  forward <id> [port...]  List or add localhost port forwards (remote sessions)")
 	fmt.Println("  attach <id>             Attach to session interactively")
 	fmt.Println("  show [id]               Show session details (auto-detect current if no id)")
 	fmt.Println("  current                 Show current session and profile (auto-detect)")
 	fmt.Println("  set <id> <field> <value>  Update session property")
+	fmt.Println("  tag <id> [tag...]       Add/remove tags and key=value metadata")
+	fmt.Println("  notes <id>              Show or edit the session's private notes")
 	fmt.Println("  send <id> <message>     Send a message to a running session")
 	fmt.Println("  output <id>             Get the last response from a session")
 	fmt.Println("  set-parent <id> <parent>  Link session as sub-session of parent")
@@ -85,6 +105,9 @@ func printSessionHelp() {
 	fmt.Println("  agent-deck session stop abc123")
 	fmt.Println("  agent-deck session restart my-project")
 	fmt.Println("  agent-deck session fork my-project -t \"my-project-fork\"")
+	fmt.Println("  agent-deck session migrate my-project --to dev-box")
+	fmt.Println("  agent-deck session move-profile my-project work --group clients")
+	fmt.Println("  agent-deck session forward my-project 3000              # localhost:3000 -> host:3000")
 	fmt.Println("  agent-deck session attach my-project")
 	fmt.Println("  agent-deck session show                  # Auto-detect current session")
 	fmt.Println("  agent-deck session show my-project --json")
@@ -92,6 +115,7 @@ func printSessionHelp() {
 	fmt.Println("  agent-deck session unset-parent sub-task             # Remove sub-session link")
 	fmt.Println("  agent-deck session output my-project                 # Get last response from session")
 	fmt.Println("  agent-deck session output my-project --json          # Get response as JSON")
+	fmt.Println("  agent-deck session tag my-project urgent --meta ticket=ENG-42")
 	fmt.Println()
 	fmt.Println("Set command fields:")
 	fmt.Println("  title              Session title")
@@ -185,6 +209,7 @@ func handleSessionStart(profile string, args []string) {
 			os.Exit(1)
 		}
 	}
+	session.RecordAudit(profile, session.AuditStart, inst, "")
 
 	// Output success
 	jsonData := map[string]interface{}{
@@ -256,6 +281,7 @@ func handleSessionStop(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to stop session: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	session.RecordAudit(profile, session.AuditStop, inst, "")
 
 	// Save updated state
 	if err := saveSessionData(storage, instances); err != nil {
@@ -317,6 +343,7 @@ func handleSessionRestart(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to restart session: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	session.RecordAudit(profile, session.AuditRestart, inst, "")
 
 	// Save updated state
 	if err := saveSessionData(storage, instances); err != nil {
@@ -438,6 +465,7 @@ func handleSessionFork(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to start forked session: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	session.RecordAudit(profile, session.AuditFork, forkedInst, "from "+inst.ID)
 
 	// Output success
 	out.Success(fmt.Sprintf("Forked session: %s -> %s (%s)", inst.Title, forkedInst.Title, TruncateID(forkedInst.ID)), map[string]interface{}{
@@ -448,6 +476,529 @@ func handleSessionFork(profile string, args []string) {
 	})
 }
 
+// handleSessionMigrate moves a session between this machine and an SSH host
+func handleSessionMigrate(profile string, args []string) {
+	fs := flag.NewFlagSet("session migrate", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	to := fs.String("to", "", "Target SSH host ID, or 'local'")
+	path := fs.String("path", "", "Project path on the target (default: same as source)")
+	title := fs.String("title", "", "Title for migrated session (default: <title>@<target>)")
+	titleShort := fs.String("t", "", "Title for migrated session (short)")
+	useGit := fs.Bool("git", false, "Push the current git branch and check it out on the target instead of copying files")
+	exclude := fs.String("exclude", "", "Comma-separated patterns to skip when copying files")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session migrate <id|title> --to <host|local> [options]")
+		fmt.Println()
+		fmt.Println("Move a session to an SSH host or back to this machine. The project is")
+		fmt.Println("copied (or its git branch pushed with --git), the Claude conversation is")
+		fmt.Println("copied into the target's Claude config dir, and a new session resumes it")
+		fmt.Println("there. The original session is stopped before a final copy of what changed")
+		fmt.Println("since the first one, and restarted if the new session fails to start.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session migrate my-project --to dev-box")
+		fmt.Println("  agent-deck session migrate my-project --to dev-box --path ~/src/my-project --git")
+		fmt.Println("  agent-deck session migrate my-project@dev-box --to local")
+	}
+
+	if err := fs.Parse(reorderSessionMigrateArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier := fs.Arg(0)
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	if identifier == "" || *to == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, groupsData, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	opts := session.MigrateOptions{
+		To:          *to,
+		ProjectPath: *path,
+		Title:       mergeFlags(*title, *titleShort),
+		Git:         *useGit,
+	}
+	for _, pattern := range strings.Split(*exclude, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			opts.Exclude = append(opts.Exclude, pattern)
+		}
+	}
+
+	if !quietMode && !*jsonOutput {
+		fmt.Printf("Migrating %s to %s...\n", inst.Title, *to)
+	}
+	result, err := session.MigrateSession(inst, opts)
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to migrate: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	newInst := result.Instance
+
+	// Stop the original before the final copy so nothing it writes from here
+	// on is lost, and the two agents never work on the project at once
+	wasRunning := inst.Exists()
+	if wasRunning {
+		if err := inst.Kill(); err != nil {
+			out.Error(fmt.Sprintf("failed to stop original session (nothing migrated): %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		session.RecordAudit(profile, session.AuditStop, inst, "migrating to "+newInst.ID)
+	}
+	// restoreOriginal restarts the original after a failure so it's left
+	// running as before
+	restoreOriginal := func() string {
+		if !wasRunning {
+			return "original left stopped"
+		}
+		if err := inst.Restart(); err != nil {
+			return fmt.Sprintf("original stopped, restart it with: agent-deck session start %s (%v)", inst.ID, err)
+		}
+		session.RecordAudit(profile, session.AuditRestart, inst, "migration failed")
+		return "original left running"
+	}
+
+	if err := result.Resync(); err != nil {
+		note := restoreOriginal()
+		out.Error(fmt.Sprintf("failed to copy final changes (%s): %v", note, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	instances = append(instances, newInst)
+	groupTree := session.NewGroupTreeWithGroups(instances, groupsData)
+	if newInst.GroupPath != "" {
+		groupTree.CreateGroup(newInst.GroupPath)
+	}
+
+	// Save before creating the tmux session so a failed start leaves a
+	// stopped session rather than an orphan
+	if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+		out.Error(fmt.Sprintf("failed to save: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	if err := newInst.Start(); err != nil {
+		note := restoreOriginal()
+		_ = saveSessionData(storage, instances)
+		out.Error(fmt.Sprintf("failed to start migrated session (%s): %v", note, err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	session.RecordAudit(profile, session.AuditCreate, newInst, "migrated from "+inst.ID)
+
+	if err := saveSessionData(storage, instances); err != nil {
+		out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	transferred := 0
+	if result.Sync != nil {
+		transferred = len(result.Sync.Transferred)
+	}
+	if !*jsonOutput {
+		if result.Branch != "" {
+			out.Print(fmt.Sprintf("  %s checked out branch %s\n", bulletSymbol, result.Branch), nil)
+		} else if result.Sync != nil {
+			out.Print(fmt.Sprintf("  %s copied %d file(s), %s\n", bulletSymbol, transferred, formatSize(result.Sync.Bytes)), nil)
+		}
+		if result.Conversation != "" {
+			out.Print(fmt.Sprintf("  %s resuming conversation %s\n", bulletSymbol, newInst.ClaudeSessionID), nil)
+		}
+	}
+
+	out.Success(fmt.Sprintf("Migrated session: %s (%s) -> %s (%s)", inst.Title, result.From, newInst.Title, result.To), map[string]interface{}{
+		"success":      true,
+		"id":           inst.ID,
+		"new_id":       newInst.ID,
+		"new_title":    newInst.Title,
+		"from":         result.From,
+		"to":           result.To,
+		"project_path": result.ProjectPath,
+		"transferred":  transferred,
+		"branch":       result.Branch,
+		"conversation": result.Conversation,
+	})
+}
+
+// reorderSessionMigrateArgs moves flags before the session identifier so
+// "session migrate my-project --to dev-box" parses
+func reorderSessionMigrateArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--to": true, "-to": true,
+		"--path": true, "-path": true,
+		"--title": true, "-title": true,
+		"-t": true, "--t": true,
+		"--exclude": true, "-exclude": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
+
+// handleSessionMoveProfile moves (or copies) a session and its sub-sessions
+// to another profile
+func handleSessionMoveProfile(profile string, args []string, copyMode bool) {
+	name := "move-profile"
+	if copyMode {
+		name = "copy-profile"
+	}
+	fs := flag.NewFlagSet("session "+name, flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	copyFlag := fs.Bool("copy", copyMode, "Keep the session in this profile and add a stopped copy")
+	group := fs.String("group", "", "Group in the target profile (default: the session's own group)")
+	groupShort := fs.String("g", "", "Group in the target profile (short)")
+
+	fs.Usage = func() {
+		fmt.Printf("Usage: agent-deck session %s <id|title> <profile> [options]\n", name)
+		fmt.Println()
+		fmt.Println("Move a session, with its sub-sessions, groups, worktree fields, tags and")
+		fmt.Println("notes, to another profile. A running session keeps running. With --copy")
+		fmt.Println("(or copy-profile) the original stays and the target gets a stopped copy")
+		fmt.Println("with a new ID and a fresh conversation.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session move-profile my-project work")
+		fmt.Println("  agent-deck session move-profile my-project work --group clients/acme")
+		fmt.Println("  agent-deck -p work session copy-profile my-project personal")
+	}
+
+	if err := fs.Parse(reorderSessionMoveProfileArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier, target := fs.Arg(0), fs.Arg(1)
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	if identifier == "" || target == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	// Release this process's handle before TransferSession opens both profiles
+	from := storage.Profile()
+	_ = storage.Close()
+
+	result, err := session.TransferSession(from, target, inst.ID, session.TransferOptions{
+		Copy:  *copyFlag,
+		Group: mergeFlags(*group, *groupShort),
+	})
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	verb := "Moved"
+	if result.Copy {
+		verb = "Copied"
+	}
+	if !*jsonOutput {
+		if len(result.Sessions) > 1 {
+			out.Print(fmt.Sprintf("  %s with %d sub-session(s)\n", bulletSymbol, len(result.Sessions)-1), nil)
+		}
+		for _, g := range result.GroupsCreated {
+			out.Print(fmt.Sprintf("  %s created group %s\n", bulletSymbol, g), nil)
+		}
+		if result.UnlinkedParent != "" {
+			out.Print(fmt.Sprintf("  %s parent session %s stays in '%s'; sub-session link removed\n", bulletSymbol, TruncateID(result.UnlinkedParent), result.From), nil)
+		}
+	}
+
+	out.Success(fmt.Sprintf("%s session: %s (%s -> %s)", verb, inst.Title, result.From, result.To), map[string]interface{}{
+		"success":         true,
+		"id":              inst.ID,
+		"title":           inst.Title,
+		"from":            result.From,
+		"to":              result.To,
+		"copy":            result.Copy,
+		"sessions":        result.Sessions,
+		"groups_created":  result.GroupsCreated,
+		"unlinked_parent": result.UnlinkedParent,
+	})
+}
+
+// reorderSessionMoveProfileArgs moves flags before the positional arguments
+// so "session move-profile my-project work --group clients" parses
+func reorderSessionMoveProfileArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--group": true, "-group": true,
+		"-g": true, "--g": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
+
+// handleSessionForward lists, adds or removes a remote session's port forwards
+func handleSessionForward(profile string, args []string) {
+	fs := flag.NewFlagSet("session forward", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	remove := fs.Bool("remove", false, "Remove the given forwards (by local port)")
+	wait := fs.Bool("wait", false, "Hold the session's forwards open in this process until interrupted")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session forward <id|title> [PORT|LOCAL:REMOTE ...] [options]")
+		fmt.Println()
+		fmt.Println("Forward localhost ports to a remote session's SSH host. Forwards are saved")
+		fmt.Println("with the session and held open by the TUI while the session runs; URLs")
+		fmt.Println("like http://localhost:3000 in the session's output are forwarded")
+		fmt.Println("automatically. Without ports, lists the session's forwards.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session forward my-project 3000")
+		fmt.Println("  agent-deck session forward my-project 8080:3000")
+		fmt.Println("  agent-deck session forward my-project 3000 --remove")
+		fmt.Println("  agent-deck session forward my-project --wait    # without the TUI running")
+	}
+
+	if err := fs.Parse(reorderSessionForwardArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier := fs.Arg(0)
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	if identifier == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	if !inst.IsRemote() {
+		out.Error(fmt.Sprintf("session '%s' is local: its ports are already on localhost", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	var forwards []session.PortForward
+	for _, spec := range fs.Args()[1:] {
+		f, err := session.ParsePortForward(spec)
+		if err != nil {
+			out.Error(err.Error(), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		forwards = append(forwards, f)
+	}
+
+	if len(forwards) > 0 {
+		for _, f := range forwards {
+			if *remove {
+				if !inst.RemovePortForward(f.LocalPort) {
+					out.Error(fmt.Sprintf("session '%s' has no forward on localhost:%d", inst.Title, f.LocalPort), ErrCodeNotFound)
+					os.Exit(2)
+				}
+			} else if !inst.AddPortForward(f) {
+				out.Error(fmt.Sprintf("localhost:%d is already forwarded for session '%s'", f.LocalPort, inst.Title), ErrCodeInvalidOperation)
+				os.Exit(1)
+			}
+		}
+		if err := saveSessionData(storage, instances); err != nil {
+			out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	if *wait {
+		holdPortForwards(inst, out)
+		return
+	}
+
+	type forwardJSON struct {
+		session.PortForward
+		Listening bool `json:"listening"`
+	}
+	list := make([]forwardJSON, 0, len(inst.PortForwards))
+	var sb strings.Builder
+	for _, f := range inst.PortForwards {
+		listening := session.IsLocalPortListening(f.LocalPort)
+		list = append(list, forwardJSON{PortForward: f, Listening: listening})
+		state := "not open (start the TUI or use --wait)"
+		if listening {
+			state = "listening"
+		}
+		auto := ""
+		if f.Auto {
+			auto = " (auto)"
+		}
+		sb.WriteString(fmt.Sprintf("  %s localhost:%d -> %s:%d%s  %s\n", bulletSymbol, f.LocalPort, inst.RemoteHost, f.RemotePort, auto, state))
+	}
+	jsonData := map[string]interface{}{
+		"success":  true,
+		"id":       inst.ID,
+		"title":    inst.Title,
+		"host":     inst.RemoteHost,
+		"forwards": list,
+	}
+
+	switch {
+	case len(forwards) > 0 && *remove:
+		out.Success(fmt.Sprintf("Removed %d forward(s) from %s", len(forwards), inst.Title), jsonData)
+	case len(forwards) > 0:
+		out.Success(fmt.Sprintf("Forwarding %d port(s) for %s (opened by the TUI while the session runs)", len(forwards), inst.Title), jsonData)
+	case len(list) == 0:
+		out.Print(fmt.Sprintf("No port forwards for %s\n", inst.Title), jsonData)
+	default:
+		out.Print(fmt.Sprintf("Port forwards for %s:\n%s", inst.Title, sb.String()), jsonData)
+	}
+}
+
+// holdPortForwards opens a session's forwards in this process and keeps
+// them open until interrupted
+func holdPortForwards(inst *session.Instance, out *CLIOutput) {
+	if len(inst.PortForwards) == 0 {
+		out.Error(fmt.Sprintf("session '%s' has no port forwards", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	targets := session.PortForwardTargets([]*session.Instance{inst})
+	if len(targets) == 0 {
+		out.Error(fmt.Sprintf("session '%s' is not running", inst.Title), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	manager := session.NewPortForwardManager()
+	defer manager.CloseAll()
+	manager.Sync(targets)
+
+	opened := 0
+	states := manager.States(inst.ID)
+	var sb strings.Builder
+	for _, st := range states {
+		if st.Open {
+			opened++
+			fmt.Fprintf(&sb, "  %s localhost:%d -> %s:%d\n", successSymbol, st.LocalPort, st.HostID, st.RemotePort)
+		} else {
+			fmt.Fprintf(&sb, "  %s localhost:%d: %s\n", errorSymbol, st.LocalPort, st.Error)
+		}
+	}
+	if opened == 0 {
+		msg := "no forwards could be opened"
+		for _, st := range states {
+			msg += fmt.Sprintf("; localhost:%d: %s", st.LocalPort, st.Error)
+		}
+		out.Error(msg, ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	sb.WriteString("Press Ctrl+C to close the forwards.\n")
+	out.Print(sb.String(), map[string]interface{}{
+		"session_id": inst.ID,
+		"forwards":   states,
+		"opened":     opened,
+	})
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	// Reopen forwards whose ssh process exited
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sigCh:
+			return
+		case <-ticker.C:
+			manager.Sync(targets)
+		}
+	}
+}
+
+// reorderSessionForwardArgs moves flags before positional arguments so that
+// "session forward my-project 3000 --remove" parses
+func reorderSessionForwardArgs(args []string) []string {
+	var flags []string
+	var positional []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
+
 // handleSessionAttach attaches to a session interactively
 func handleSessionAttach(profile string, args []string) {
 	fs := flag.NewFlagSet("session attach", flag.ExitOnError)
@@ -612,6 +1163,26 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
+	if inst.RemoteHost != "" {
+		jsonData["remote_host"] = inst.RemoteHost
+	}
+	if len(inst.PortForwards) > 0 {
+		jsonData["port_forwards"] = inst.PortForwards
+	}
+	if len(inst.Tags) > 0 {
+		jsonData["tags"] = inst.Tags
+	}
+	if len(inst.Meta) > 0 {
+		jsonData["meta"] = inst.Meta
+	}
+	notes, err := session.LoadNotes(profile, inst.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if notes != "" {
+		jsonData["notes"] = notes
+	}
+
 	// Build human-readable output
 	var sb strings.Builder
 
@@ -627,6 +1198,17 @@ func handleSessionShow(profile string, args []string) {
 
 	sb.WriteString(fmt.Sprintf("Tool:    %s\n", inst.Tool))
 
+	if len(inst.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("Tags:    %s\n", session.FormatTags(inst.Tags)))
+	}
+	for i, pair := range session.FormatMeta(inst.Meta) {
+		label := "         "
+		if i == 0 {
+			label = "Meta:    "
+		}
+		sb.WriteString(label + pair + "\n")
+	}
+
 	if inst.Command != "" {
 		sb.WriteString(fmt.Sprintf("Command: %s\n", inst.Command))
 	}
@@ -674,6 +1256,32 @@ func handleSessionShow(profile string, args []string) {
 		}
 	}
 
+	if inst.RemoteHost != "" {
+		sb.WriteString(fmt.Sprintf("Host:    %s\n", inst.RemoteHost))
+	}
+	for i, f := range inst.PortForwards {
+		label := "         "
+		if i == 0 {
+			label = "Ports:   "
+		}
+		state := "not open"
+		if session.IsLocalPortListening(f.LocalPort) {
+			state = "listening"
+		}
+		auto := ""
+		if f.Auto {
+			auto = ", auto"
+		}
+		sb.WriteString(fmt.Sprintf("%slocalhost:%d -> %d (%s%s)\n", label, f.LocalPort, f.RemotePort, state, auto))
+	}
+
+	if notes != "" {
+		sb.WriteString("\nNotes:\n")
+		for _, line := range strings.Split(strings.TrimRight(notes, "\n"), "\n") {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
 	out.Print(sb.String(), jsonData)
 }
 
@@ -806,6 +1414,328 @@ func handleSessionSet(profile string, args []string) {
 	})
 }
 
+// handleSessionTag adds or removes a session's tags and metadata, or shows
+// them when nothing is changed
+func handleSessionTag(profile string, args []string) {
+	fs := flag.NewFlagSet("session tag", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+	clear := fs.Bool("clear", false, "Remove all tags and metadata first")
+	var removeTags, metaSets []string
+	fs.Func("remove", "Tag to remove (can specify multiple times)", func(s string) error {
+		removeTags = append(removeTags, s)
+		return nil
+	})
+	fs.Func("meta", "Set metadata key=value; key= removes the key (can specify multiple times)", func(s string) error {
+		if _, _, err := session.ParseMetaAssignment(s); err != nil {
+			return err
+		}
+		metaSets = append(metaSets, s)
+		return nil
+	})
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session tag <id|title> [tag...] [options]")
+		fmt.Println()
+		fmt.Println("Add tags and key/value metadata to a session. Tags are lowercased;")
+		fmt.Println("without changes, shows the session's tags and metadata.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session tag my-project urgent backend")
+		fmt.Println("  agent-deck session tag my-project --remove urgent")
+		fmt.Println("  agent-deck session tag my-project --meta client=acme --meta ticket=ENG-42")
+		fmt.Println("  agent-deck session tag my-project --meta ticket=      # Remove a key")
+		fmt.Println("  agent-deck list --tag backend --meta client=acme")
+	}
+
+	if err := fs.Parse(reorderSessionTagArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier := fs.Arg(0)
+	quietMode := *quiet || *quietShort
+	out := NewCLIOutput(*jsonOutput, quietMode)
+
+	if identifier == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	storage, instances, groupsData, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+
+	addTags := fs.Args()[1:]
+	for _, tag := range addTags {
+		if session.NormalizeTag(tag) == "" {
+			out.Error(fmt.Sprintf("invalid tag %q", tag), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	changed := false
+	if *clear && (len(inst.Tags) > 0 || len(inst.Meta) > 0) {
+		inst.Tags = nil
+		inst.Meta = nil
+		changed = true
+	}
+	if inst.RemoveTags(removeTags...) {
+		changed = true
+	}
+	if inst.AddTags(addTags...) {
+		changed = true
+	}
+	for _, m := range metaSets {
+		key, value, _ := session.ParseMetaAssignment(m)
+		if inst.SetMeta(key, value) {
+			changed = true
+		}
+	}
+
+	if changed {
+		groupTree := session.NewGroupTreeWithGroups(instances, groupsData)
+		if err := storage.SaveWithGroups(instances, groupTree); err != nil {
+			out.Error(fmt.Sprintf("failed to save session state: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+	}
+
+	tags := inst.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	meta := inst.Meta
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	jsonData := map[string]interface{}{
+		"success": true,
+		"id":      inst.ID,
+		"title":   inst.Title,
+		"tags":    tags,
+		"meta":    meta,
+	}
+
+	var sb strings.Builder
+	if len(inst.Tags) > 0 {
+		sb.WriteString(fmt.Sprintf("Tags: %s\n", session.FormatTags(inst.Tags)))
+	}
+	if len(inst.Meta) > 0 {
+		sb.WriteString(fmt.Sprintf("Meta: %s\n", strings.Join(session.FormatMeta(inst.Meta), ", ")))
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("No tags or metadata\n")
+	}
+
+	if changed {
+		out.Success(fmt.Sprintf("Updated tags for %s\n%s", inst.Title, strings.TrimSuffix(sb.String(), "\n")), jsonData)
+		return
+	}
+	out.Print(fmt.Sprintf("%s:\n%s", inst.Title, sb.String()), jsonData)
+}
+
+// handleSessionNotes shows or edits a session's notes. Notes are kept in the
+// profile directory and never sent to the agent.
+func handleSessionNotes(profile string, args []string) {
+	fs := flag.NewFlagSet("session notes", flag.ExitOnError)
+	edit := fs.Bool("edit", false, "Open the notes in $VISUAL / $EDITOR")
+	editShort := fs.Bool("e", false, "Open the notes in $EDITOR (short)")
+	set := fs.String("set", "", "Replace the notes with text")
+	appendText := fs.String("append", "", "Append a line to the notes")
+	clear := fs.Bool("clear", false, "Delete the notes")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck session notes <id|title> [options]")
+		fmt.Println()
+		fmt.Println("Show or edit free-form notes for a session (what it's doing, what's")
+		fmt.Println("left, gotchas). Notes are stored in the profile directory and are not")
+		fmt.Println("sent to the agent.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  agent-deck session notes my-project")
+		fmt.Println("  agent-deck session notes my-project --edit")
+		fmt.Println("  agent-deck session notes my-project --append \"TODO: rerun migrations\"")
+	}
+
+	if err := fs.Parse(reorderSessionNotesArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	identifier := fs.Arg(0)
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	if identifier == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	setGiven := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "set" {
+			setGiven = true
+		}
+	})
+	actions := 0
+	for _, on := range []bool{*edit || *editShort, setGiven, *appendText != "", *clear} {
+		if on {
+			actions++
+		}
+	}
+	if actions > 1 {
+		out.Error("use only one of --edit, --set, --append and --clear", ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	storage, instances, _, err := loadSessionData(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeNotFound)
+		os.Exit(1)
+	}
+	inst, errMsg, errCode := ResolveSession(identifier, instances)
+	if inst == nil {
+		out.Error(errMsg, errCode)
+		if errCode == ErrCodeNotFound {
+			os.Exit(2)
+		}
+		os.Exit(1)
+	}
+	profile = storage.Profile()
+
+	notes, err := session.LoadNotes(profile, inst.ID)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	changed := true
+	switch {
+	case *edit || *editShort:
+		path, err := session.NotesPath(profile, inst.ID)
+		if err == nil {
+			var cmd *exec.Cmd
+			if cmd, err = session.NotesEditorCommand(path); err == nil {
+				cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+				err = cmd.Run()
+			}
+		}
+		if err != nil {
+			out.Error(fmt.Sprintf("failed to edit notes: %v", err), ErrCodeInvalidOperation)
+			os.Exit(1)
+		}
+		notes, err = session.LoadNotes(profile, inst.ID)
+		if err == nil {
+			// Normalizes the file (removes it if left blank)
+			err = session.SaveNotes(profile, inst.ID, notes)
+		}
+	case setGiven:
+		notes = *set
+		err = session.SaveNotes(profile, inst.ID, notes)
+	case *appendText != "":
+		if notes != "" && !strings.HasSuffix(notes, "\n") {
+			notes += "\n"
+		}
+		notes += *appendText + "\n"
+		err = session.SaveNotes(profile, inst.ID, notes)
+	case *clear:
+		notes = ""
+		err = session.SaveNotes(profile, inst.ID, "")
+	default:
+		changed = false
+	}
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	if strings.TrimSpace(notes) == "" {
+		notes = ""
+	}
+
+	jsonData := map[string]interface{}{
+		"success": true,
+		"id":      inst.ID,
+		"title":   inst.Title,
+		"notes":   notes,
+	}
+	if changed {
+		out.Success(fmt.Sprintf("Updated notes for %s", inst.Title), jsonData)
+		return
+	}
+	if notes == "" {
+		out.Print(fmt.Sprintf("No notes for %s (add with: agent-deck session notes %q --edit)\n", inst.Title, inst.Title), jsonData)
+		return
+	}
+	out.Print(notes, jsonData)
+}
+
+// reorderSessionNotesArgs moves flags before positional arguments
+func reorderSessionNotesArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--set": true, "-set": true,
+		"--append": true, "-append": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
+
+// reorderSessionTagArgs moves flags before positional arguments so that
+// "session tag my-project urgent --remove old" parses
+func reorderSessionTagArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--remove": true, "-remove": true,
+		"--meta": true, "-meta": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
+
 // loadSessionData loads storage and session data for a profile
 // The Storage.LoadWithGroups() method already handles tmux reconnection internally
 func loadSessionData(profile string) (*session.Storage, []*session.Instance, []*session.GroupData, error) {
@@ -1216,6 +2146,7 @@ func handleSessionSend(profile string, args []string) {
 		out.Error(fmt.Sprintf("failed to send Enter: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	session.RecordAudit(profile, session.AuditSend, inst, session.AuditMessageDetail(message))
 
 	out.Success(fmt.Sprintf("Sent message to '%s'", inst.Title), map[string]interface{}{
 		"success":       true,
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReorderSessionMigrateArgs(t *testing.T) {
+	got := reorderSessionMigrateArgs([]string{"my-project", "--to", "dev-box", "--git", "-t", "api@dev"})
+	want := []string{"--to", "dev-box", "--git", "-t", "api@dev", "my-project"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reorderSessionMigrateArgs = %v, want %v", got, want)
+	}
+}
+
+func TestReorderSessionForwardArgs(t *testing.T) {
+	got := reorderSessionForwardArgs([]string{"my-project", "3000", "8080:80", "--remove", "--json"})
+	want := []string{"--remove", "--json", "my-project", "3000", "8080:80"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reorderSessionForwardArgs = %v, want %v", got, want)
+	}
+}
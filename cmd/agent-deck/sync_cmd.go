@@ -0,0 +1,277 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// handleSync dispatches sync subcommands (default: sync now)
+func handleSync(profile string, args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") && args[0] != "-h" && args[0] != "--help" {
+		handleSyncNow(profile, args)
+		return
+	}
+
+	switch args[0] {
+	case "now":
+		handleSyncNow(profile, args[1:])
+	case "init":
+		handleSyncInit(profile, args[1:])
+	case "status":
+		handleSyncStatus(profile, args[1:])
+	case "help", "-h", "--help":
+		printSyncHelp()
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown sync command '%s'\n", args[0])
+		printSyncHelp()
+		os.Exit(1)
+	}
+}
+
+// pushSavedProfiles syncs the profiles a CLI command saved when [sync] is
+// enabled, so changes made outside the TUI reach the other machines. The
+// command already succeeded, so sync failures and conflicts are warnings.
+func pushSavedProfiles(command string) {
+	if command == "sync" {
+		return
+	}
+	settings := session.GetSyncSettings()
+	if !settings.Enabled || settings.Remote == "" {
+		return
+	}
+	for _, profile := range session.SavedProfiles() {
+		result, err := session.SyncProfile(profile)
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "Warning: failed to sync profile '%s': %v\n", profile, err)
+		case len(result.Conflicts) > 0:
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", result.ConflictSummary())
+		}
+	}
+}
+
+// printSyncHelp prints help for sync commands
+func printSyncHelp() {
+	fmt.Println("Usage: agent-deck sync [command] [options]")
+	fmt.Println()
+	fmt.Println("Sync a profile's sessions, groups and MCP/launch config between machines")
+	fmt.Println("through a git repository. Changes are merged by session ID and group path;")
+	fmt.Println("entries changed on both machines keep the local value and are reported.")
+	fmt.Println("With [sync] enabled the TUI pulls on start and pushes after saves, and")
+	fmt.Println("CLI commands that change sessions or groups push before they exit.")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  now (default)       Pull, merge and push the profile")
+	fmt.Println("  init <remote>       Configure the sync repository and sync")
+	fmt.Println("  status              Show sync settings and the last sync")
+	fmt.Println()
+	fmt.Println("Examples:")
+	fmt.Println("  agent-deck sync init git@github.com:me/agent-deck-sync.git")
+	fmt.Println("  agent-deck -p work sync")
+}
+
+// handleSyncNow syncs the profile with the [sync] remote
+func handleSyncNow(profile string, args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck sync [now] [options]")
+		fmt.Println()
+		fmt.Println("Pull the profile from the sync repository, merge it with local changes")
+		fmt.Println("and push the result.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	result, err := session.SyncProfile(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	printSyncResult(out, result)
+}
+
+// handleSyncInit writes [sync] to config.toml and runs the first sync
+func handleSyncInit(profile string, args []string) {
+	fs := flag.NewFlagSet("sync init", flag.ExitOnError)
+	branch := fs.String("branch", "main", "Branch to sync on")
+	noConfig := fs.Bool("no-config", false, "Don't sync [mcps], [mcp_bundles] and [launch_configs]")
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+	quiet := fs.Bool("quiet", false, "Minimal output")
+	quietShort := fs.Bool("q", false, "Minimal output (short)")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck sync init <remote> [options]")
+		fmt.Println()
+		fmt.Println("Enable syncing through a git repository (URL or path; an empty repository")
+		fmt.Println("is fine) and sync the profile. Run it on each machine.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(reorderSyncArgs(args)); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, *quiet || *quietShort)
+	remote := fs.Arg(0)
+	if remote == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	config, err := session.LoadUserConfig()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to load config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	configCopy := *config
+	configCopy.Sync.Enabled = true
+	configCopy.Sync.Remote = remote
+	configCopy.Sync.Branch = *branch
+	configCopy.Sync.SkipConfig = *noConfig
+	if err := session.SaveUserConfig(&configCopy); err != nil {
+		out.Error(fmt.Sprintf("failed to save config: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
+	result, err := session.SyncProfile(profile)
+	if err != nil {
+		out.Error(fmt.Sprintf("sync configured, but the first sync failed: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	printSyncResult(out, result)
+}
+
+// handleSyncStatus shows the sync settings and the profile's last sync
+func handleSyncStatus(profile string, args []string) {
+	fs := flag.NewFlagSet("sync status", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output as JSON")
+
+	fs.Usage = func() {
+		fmt.Println("Usage: agent-deck sync status [options]")
+		fmt.Println()
+		fmt.Println("Show the sync settings and the commit the profile was last synced to.")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		os.Exit(1)
+	}
+
+	out := NewCLIOutput(*jsonOutput, false)
+	settings := session.GetSyncSettings()
+	commit, at, err := session.LastSync(profile)
+	if err != nil {
+		out.Error(err.Error(), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	repoDir, _ := session.SyncRepoDir()
+	profile = session.GetEffectiveProfile(profile)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Profile:   %s\n", profile)
+	if settings.Remote == "" {
+		b.WriteString("Sync:      not configured (agent-deck sync init <remote>)\n")
+	} else {
+		enabled := "enabled (TUI pulls on start and pushes after saves)"
+		if !settings.Enabled {
+			enabled = "disabled in the TUI (agent-deck sync still works)"
+		}
+		fmt.Fprintf(&b, "Sync:      %s\n", enabled)
+		fmt.Fprintf(&b, "Remote:    %s (branch %s)\n", settings.Remote, settings.Branch)
+		fmt.Fprintf(&b, "Repo:      %s\n", FormatPath(repoDir))
+		if commit == "" {
+			b.WriteString("Last sync: never\n")
+		} else {
+			fmt.Fprintf(&b, "Last sync: %s (%s)\n", TruncateID(commit), at.Local().Format("2006-01-02 15:04"))
+		}
+	}
+
+	data := map[string]interface{}{
+		"profile": profile,
+		"enabled": settings.Enabled,
+		"remote":  settings.Remote,
+		"branch":  settings.Branch,
+		"repo":    repoDir,
+	}
+	if commit != "" {
+		data["commit"] = commit
+		data["synced_at"] = at
+	}
+	out.Print(b.String(), data)
+}
+
+// printSyncResult prints what a sync pulled and pushed, and its conflicts
+func printSyncResult(out *CLIOutput, result *session.SyncResult) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Synced profile '%s'", result.Profile)
+	if result.Pushed {
+		b.WriteString(" (pushed)")
+	}
+	for _, c := range []struct {
+		name    string
+		changes session.SyncChanges
+	}{
+		{"sessions", result.Sessions},
+		{"groups", result.Groups},
+	} {
+		if c.changes.Any() {
+			fmt.Fprintf(&b, "\n  %-9s %d added, %d updated, %d removed", c.name+":", c.changes.Added, c.changes.Updated, c.changes.Removed)
+		}
+	}
+	if result.ConfigChanged {
+		b.WriteString("\n  config:   MCP/launch config updated")
+	}
+	if len(result.Conflicts) > 0 {
+		fmt.Fprintf(&b, "\n  %d conflict(s), kept the local changes:", len(result.Conflicts))
+		for _, c := range result.Conflicts {
+			fmt.Fprintf(&b, "\n    %s", c.String())
+		}
+	}
+
+	out.Success(b.String(), map[string]interface{}{
+		"success": true,
+		"result":  result,
+	})
+}
+
+// reorderSyncArgs moves flags before positional arguments so that
+// "sync init <remote> --branch sync" parses
+func reorderSyncArgs(args []string) []string {
+	valueFlags := map[string]bool{
+		"--branch": true, "-branch": true,
+	}
+
+	var flags []string
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if strings.HasPrefix(arg, "-") {
+			flags = append(flags, arg)
+			if !strings.Contains(arg, "=") && valueFlags[arg] && i+1 < len(args) {
+				i++
+				flags = append(flags, args[i])
+			}
+		} else {
+			positional = append(positional, arg)
+		}
+	}
+	return append(flags, positional...)
+}
@@ -123,6 +123,7 @@ func handleTry(profile string, args []string) {
 					out.Error(fmt.Sprintf("starting session: %v", err), ErrCodeInvalidOperation)
 					os.Exit(1)
 				}
+				session.RecordAudit(profile, session.AuditStart, inst, "")
 			}
 			out.Print(
 				fmt.Sprintf("Session: %s (%s)\nPath: %s\n", inst.Title, inst.ID[:8], exp.Path),
@@ -150,12 +151,14 @@ func handleTry(profile string, args []string) {
 		out.Error(err.Error(), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	session.RecordAudit(profile, session.AuditCreate, newInst, "")
 
 	// Start the session
 	if err := newInst.Start(); err != nil {
 		out.Error(fmt.Sprintf("starting session: %v", err), ErrCodeInvalidOperation)
 		os.Exit(1)
 	}
+	session.RecordAudit(profile, session.AuditStart, newInst, "")
 
 	action := "Created"
 	if !created {
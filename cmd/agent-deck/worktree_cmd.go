@@ -281,6 +281,9 @@ func handleWorktreeCleanup(profile string, args []string) {
 		fmt.Println("Orphans are detected as:")
 		fmt.Println("  - Sessions with WorktreePath set but the directory doesn't exist")
 		fmt.Println("  - Worktrees that exist but no session points to them")
+		fmt.Println("    (sessions in the archive still count as pointing to theirs)")
+		fmt.Println()
+		fmt.Println("Removed sessions are archived; restore them with 'agent-deck archive restore'.")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -302,6 +305,18 @@ func handleWorktreeCleanup(profile string, args []string) {
 		os.Exit(1)
 	}
 
+	// Archived sessions still own their worktrees: restoring one brings it back
+	archive, err := session.OpenArchive(storage.Profile())
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to open archive: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+	archived, err := archive.List()
+	if err != nil {
+		out.Error(fmt.Sprintf("failed to read archive: %v", err), ErrCodeInvalidOperation)
+		os.Exit(1)
+	}
+
 	// Find orphaned sessions (WorktreePath set but directory doesn't exist)
 	var orphanedSessions []*session.Instance
 	for _, inst := range instances {
@@ -336,6 +351,11 @@ func handleWorktreeCleanup(profile string, args []string) {
 						sessionPaths[inst.WorktreePath] = true
 					}
 				}
+				for _, entry := range archived {
+					if entry.Session.WorktreePath != "" {
+						sessionPaths[entry.Session.WorktreePath] = true
+					}
+				}
 
 				// Check each worktree (skip the first one which is usually the main repo)
 				for i, wt := range worktrees {
@@ -425,24 +445,30 @@ func handleWorktreeCleanup(profile string, args []string) {
 		return
 	}
 
-	// Remove orphaned sessions
-	removedSessions := 0
+	// Remove orphaned sessions, archiving them like 'remove' and the TUI do
+	var removed []*session.Instance
 	for _, inst := range orphanedSessions {
+		// Archive before killing so a failed archive leaves the session intact
+		if err := archive.Add(inst, "worktree cleanup"); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to archive session %s, keeping it: %v\n", inst.Title, err)
+			continue
+		}
 		// Kill tmux session if it exists
 		if inst.Exists() {
 			if err := inst.Kill(); err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to kill tmux session %s: %v\n", inst.Title, err)
 			}
 		}
-		removedSessions++
-		fmt.Printf("Removed session: %s\n", inst.Title)
+		removed = append(removed, inst)
+		fmt.Printf("Removed session: %s (restore with: agent-deck archive restore %q)\n", inst.Title, inst.Title)
 	}
+	removedSessions := len(removed)
 
 	// Filter out removed sessions from instances
 	if removedSessions > 0 {
 		var remaining []*session.Instance
 		removedIDs := make(map[string]bool)
-		for _, inst := range orphanedSessions {
+		for _, inst := range removed {
 			removedIDs[inst.ID] = true
 		}
 		for _, inst := range instances {
@@ -456,6 +482,9 @@ func handleWorktreeCleanup(profile string, args []string) {
 			out.Error(fmt.Sprintf("failed to save session data: %v", err), ErrCodeInvalidOperation)
 			os.Exit(1)
 		}
+		for _, inst := range removed {
+			session.RecordAudit(profile, session.AuditDelete, inst, "worktree cleanup")
+		}
 	}
 
 	// Remove orphaned worktrees
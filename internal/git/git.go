@@ -42,6 +42,31 @@ func gitCommand(args ...string) *exec.Cmd {
 	return cmd
 }
 
+// Run runs git in dir and returns its trimmed standard output. The error
+// includes git's standard error.
+func Run(dir string, args ...string) (string, error) {
+	cmd := gitCommand(append([]string{"-C", dir}, args...)...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		// Name the subcommand, skipping "-c key=value" options
+		name := ""
+		for i := 0; i < len(args) && name == ""; i++ {
+			if args[i] == "-c" {
+				i++
+			} else if !strings.HasPrefix(args[i], "-") {
+				name = args[i]
+			}
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s: %w", name, msg, err)
+		}
+		return "", fmt.Errorf("git %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 // Worktree represents a git worktree
 type Worktree struct {
 	Path   string // Filesystem path to the worktree
@@ -0,0 +1,255 @@
+package mcppool
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+// HealthState is the protocol-level health of a pooled MCP
+type HealthState int
+
+const (
+	HealthUnknown      HealthState = iota // Not probed yet
+	HealthHealthy                         // Answered a ping probe
+	HealthUnresponsive                    // Socket up but probe timed out or failed
+)
+
+func (h HealthState) String() string {
+	switch h {
+	case HealthHealthy:
+		return "healthy"
+	case HealthUnresponsive:
+		return "unresponsive"
+	default:
+		return "unknown"
+	}
+}
+
+// Defaults for health checking and crash-loop quarantine
+const (
+	DefaultHealthCheckTimeout  = 5 * time.Second
+	DefaultQuarantineThreshold = 3
+	DefaultQuarantineWindow    = 5 * time.Minute
+
+	// unresponsiveFailLimit is how many consecutive failed probes mark an
+	// owned proxy as failed (hung process), so it gets restarted
+	unresponsiveFailLimit = 2
+)
+
+var probeCounter atomic.Uint64
+
+// PingSocket checks that the MCP behind a pool socket answers JSON-RPC.
+// It connects as a dedicated internal client and sends "ping". Any response
+// with the probe's ID counts, including an error such as "method not found"
+// from servers that don't implement ping: the server is alive and parsing
+// requests. Only a timeout, a closed connection or an unparseable reply fail.
+// Returns the round-trip time.
+func PingSocket(socketPath string, timeout time.Duration) (time.Duration, error) {
+	start := time.Now()
+	conn, err := net.DialTimeout("unix", socketPath, timeout)
+	if err != nil {
+		return 0, fmt.Errorf("connect failed: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(start.Add(timeout))
+
+	if _, err := probe(conn, bufio.NewReader(conn), "ping"); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// probe sends one request and waits for the response with the same ID,
+// skipping notifications and responses meant for other clients
+func probe(conn net.Conn, reader *bufio.Reader, method string) (*JSONRPCResponse, error) {
+	id := fmt.Sprintf("agentdeck-health-%d", probeCounter.Add(1))
+	req := JSONRPCRequest{JSONRPC: "2.0", Method: method, ID: id}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return nil, fmt.Errorf("%s: write failed: %w", method, err)
+	}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("%s: no response: %w", method, err)
+		}
+		var resp JSONRPCResponse
+		if err := json.Unmarshal(line, &resp); err != nil {
+			return nil, fmt.Errorf("%s: unparseable response: %w", method, err)
+		}
+		if respID, ok := resp.ID.(string); ok && respID == id {
+			return &resp, nil
+		}
+	}
+}
+
+// HealthInfo is the last probe result for a proxy
+type HealthInfo struct {
+	State     HealthState
+	Latency   time.Duration
+	LastCheck time.Time
+	LastError string
+	Failures  int // Consecutive failed probes
+}
+
+// setHealth records a probe result and returns the consecutive failure count
+func (p *SocketProxy) setHealth(latency time.Duration, err error) int {
+	p.statusMu.Lock()
+	defer p.statusMu.Unlock()
+	p.health.LastCheck = time.Now()
+	if err != nil {
+		p.health.State = HealthUnresponsive
+		p.health.LastError = err.Error()
+		p.health.Latency = 0
+		p.health.Failures++
+	} else {
+		p.health.State = HealthHealthy
+		p.health.LastError = ""
+		p.health.Latency = latency
+		p.health.Failures = 0
+	}
+	return p.health.Failures
+}
+
+// GetHealth safely reads the last probe result
+func (p *SocketProxy) GetHealth() HealthInfo {
+	p.statusMu.RLock()
+	defer p.statusMu.RUnlock()
+	return p.health
+}
+
+// QuarantineInfo describes an MCP taken out of the pool after crash-looping
+type QuarantineInfo struct {
+	Name    string
+	Since   time.Time
+	Crashes int
+	Reason  string
+}
+
+// SetQuarantineHandler registers a callback invoked (in its own goroutine)
+// when an MCP is quarantined, so callers can move sessions off its socket
+func (p *Pool) SetQuarantineHandler(fn func(QuarantineInfo)) {
+	p.mu.Lock()
+	p.onQuarantine = fn
+	p.mu.Unlock()
+}
+
+// IsQuarantined returns true if the MCP was taken out of the pool
+func (p *Pool) IsQuarantined(name string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.quarantined[name]
+	return ok
+}
+
+// Quarantined returns all quarantined MCPs
+func (p *Pool) Quarantined() []QuarantineInfo {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	list := make([]QuarantineInfo, 0, len(p.quarantined))
+	for _, q := range p.quarantined {
+		list = append(list, q)
+	}
+	return list
+}
+
+// Unquarantine clears quarantine and crash history and restarts the proxy
+func (p *Pool) Unquarantine(name string) error {
+	p.mu.Lock()
+	if _, ok := p.quarantined[name]; !ok {
+		p.mu.Unlock()
+		return fmt.Errorf("%s is not quarantined", name)
+	}
+	delete(p.quarantined, name)
+	delete(p.crashes, name)
+	p.mu.Unlock()
+
+	log.Printf("[Pool] %s: quarantine lifted, restarting", name)
+	return p.RestartProxy(name)
+}
+
+// recordCrash adds a crash to the MCP's history and returns the number of
+// crashes inside the quarantine window, or -1 if this proxy's failure was
+// already counted (restart still rate limited). Caller must hold p.mu.
+func (p *Pool) recordCrash(name string) int {
+	if proxy, ok := p.proxies[name]; ok {
+		if proxy.crashCounted {
+			return -1
+		}
+		proxy.crashCounted = true
+	}
+	window := p.config.QuarantineWindow
+	if window <= 0 {
+		window = DefaultQuarantineWindow
+	}
+	now := time.Now()
+	recent := []time.Time{now}
+	for _, t := range p.crashes[name] {
+		if now.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	p.crashes[name] = recent
+	return len(recent)
+}
+
+// quarantine stops the proxy and keeps it out of the pool until Unquarantine.
+// Sessions fall back to stdio since IsRunning reports false. Caller must hold p.mu.
+func (p *Pool) quarantine(name string, crashes int, reason string) {
+	proxy, exists := p.proxies[name]
+	if !exists {
+		return
+	}
+	_ = proxy.Stop()
+	proxy.SetStatus(StatusQuarantined)
+
+	info := QuarantineInfo{Name: name, Since: time.Now(), Crashes: crashes, Reason: reason}
+	p.quarantined[name] = info
+	log.Printf("[Pool] ⛔ %s: quarantined after %d crashes (%s)", name, crashes, reason)
+
+	if p.onQuarantine != nil {
+		go p.onQuarantine(info)
+	}
+}
+
+func (p *Pool) healthCheckTimeout() time.Duration {
+	if p.config.HealthCheckTimeout > 0 {
+		return p.config.HealthCheckTimeout
+	}
+	return DefaultHealthCheckTimeout
+}
+
+// checkProxyHealth probes every running proxy. Owned proxies that stop
+// answering are marked failed so restartFailedProxies picks them up.
+func (p *Pool) checkProxyHealth() {
+	p.mu.RLock()
+	var running []*SocketProxy
+	for _, proxy := range p.proxies {
+		if proxy.GetStatus() == StatusRunning {
+			running = append(running, proxy)
+		}
+	}
+	p.mu.RUnlock()
+
+	timeout := p.healthCheckTimeout()
+	for _, proxy := range running {
+		latency, err := PingSocket(proxy.socketPath, timeout)
+		failures := proxy.setHealth(latency, err)
+		if err == nil {
+			continue
+		}
+		log.Printf("[Pool] %s: health probe failed (%d in a row): %v", proxy.name, failures, err)
+		if proxy.mcpProcess != nil && failures >= unresponsiveFailLimit {
+			proxy.SetStatus(StatusFailed)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package mcppool
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// serveFakeMCP answers JSON-RPC requests on a Unix socket using handler.
+// A nil result from handler means "don't answer".
+func serveFakeMCP(t *testing.T, handler func(method string) map[string]interface{}) string {
+	t.Helper()
+	socketPath := filepath.Join(t.TempDir(), "mcp.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer conn.Close()
+				// Unrelated traffic the probe must skip
+				_, _ = conn.Write([]byte(`{"jsonrpc":"2.0","method":"notifications/progress"}` + "\n"))
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					var req JSONRPCRequest
+					if json.Unmarshal(scanner.Bytes(), &req) != nil {
+						continue
+					}
+					resp := handler(req.Method)
+					if resp == nil {
+						continue
+					}
+					resp["jsonrpc"] = "2.0"
+					resp["id"] = req.ID
+					data, _ := json.Marshal(resp)
+					_, _ = conn.Write(append(data, '\n'))
+				}
+			}(conn)
+		}
+	}()
+	return socketPath
+}
+
+func TestPingSocket(t *testing.T) {
+	socketPath := serveFakeMCP(t, func(method string) map[string]interface{} {
+		return map[string]interface{}{"result": map[string]interface{}{}}
+	})
+	if _, err := PingSocket(socketPath, time.Second); err != nil {
+		t.Fatalf("PingSocket failed: %v", err)
+	}
+}
+
+func TestPingSocket_ErrorReplyIsHealthy(t *testing.T) {
+	// Servers without ping answer "method not found": still alive
+	var methods []string
+	socketPath := serveFakeMCP(t, func(method string) map[string]interface{} {
+		methods = append(methods, method)
+		return map[string]interface{}{"error": map[string]interface{}{"code": -32601}}
+	})
+	if _, err := PingSocket(socketPath, time.Second); err != nil {
+		t.Fatalf("error reply should count as healthy: %v", err)
+	}
+	if len(methods) != 1 || methods[0] != "ping" {
+		t.Errorf("expected a single ping, got %v", methods)
+	}
+}
+
+func TestPingSocket_UnparseableReply(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "mcp.sock")
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = bufio.NewReader(conn).ReadBytes('\n')
+		_, _ = conn.Write([]byte("Traceback (most recent call last):\n"))
+	}()
+
+	if _, err := PingSocket(socketPath, time.Second); err == nil {
+		t.Fatal("expected an error for a non-JSON reply")
+	}
+}
+
+func TestPingSocket_Timeout(t *testing.T) {
+	socketPath := serveFakeMCP(t, func(method string) map[string]interface{} {
+		return nil // hung server: accepts connections but never answers
+	})
+	start := time.Now()
+	if _, err := PingSocket(socketPath, 200*time.Millisecond); err == nil {
+		t.Fatal("expected timeout error from hung server")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("probe took %v, timeout not enforced", elapsed)
+	}
+}
+
+func TestRecordCrash_Window(t *testing.T) {
+	pool, _ := NewPool(context.Background(), &PoolConfig{Enabled: true, QuarantineWindow: time.Minute})
+	pool.crashes["exa"] = []time.Time{time.Now().Add(-2 * time.Minute), time.Now().Add(-10 * time.Second)}
+
+	if got := pool.recordCrash("exa"); got != 2 {
+		t.Errorf("recordCrash() = %d, want 2 (old crash outside window)", got)
+	}
+}
+
+func TestRestartFailedProxies_QuarantinesCrashLoop(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	pool, _ := NewPool(context.Background(), &PoolConfig{Enabled: true, PoolAll: true, QuarantineThreshold: 1})
+	defer pool.Shutdown()
+
+	quarantined := make(chan QuarantineInfo, 1)
+	pool.SetQuarantineHandler(func(info QuarantineInfo) { quarantined <- info })
+
+	name := fmt.Sprintf("crashtest-%d", os.Getpid())
+	if err := pool.Start(name, "sh", []string{"-c", "exit 1"}, nil); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+
+	// Wait for the process exit to mark the proxy failed
+	deadline := time.Now().Add(5 * time.Second)
+	for pool.proxies[name].GetStatus() != StatusFailed {
+		if time.Now().After(deadline) {
+			t.Fatal("proxy never marked failed")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	pool.restartFailedProxies()
+
+	if !pool.IsQuarantined(name) {
+		t.Fatal("crash-looping MCP should be quarantined")
+	}
+	if pool.IsRunning(name) {
+		t.Error("quarantined MCP must not report running (sessions fall back to stdio)")
+	}
+	select {
+	case info := <-quarantined:
+		if info.Name != name || info.Crashes != 1 {
+			t.Errorf("unexpected quarantine info: %+v", info)
+		}
+	case <-time.After(time.Second):
+		t.Error("quarantine handler not called")
+	}
+
+	// Quarantined proxies are not restarted by later monitor runs
+	pool.restartFailedProxies()
+	if got := pool.proxies[name].GetStatus(); got != StatusQuarantined {
+		t.Errorf("status = %v, want quarantined", got)
+	}
+}
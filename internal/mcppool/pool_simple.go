@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,23 +19,35 @@ type Pool struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	config  *PoolConfig
+
+	crashes      map[string][]time.Time    // Recent crash times per MCP
+	quarantined  map[string]QuarantineInfo // MCPs kept out of the pool
+	onQuarantine func(QuarantineInfo)
 }
 
 type PoolConfig struct {
-	Enabled        bool
-	PoolAll        bool
-	ExcludeMCPs    []string
-	PoolMCPs       []string
-	FallbackStdio  bool
+	Enabled       bool
+	PoolAll       bool
+	ExcludeMCPs   []string
+	PoolMCPs      []string
+	FallbackStdio bool
+
+	// HealthCheckTimeout bounds each ping probe (0 = default)
+	HealthCheckTimeout time.Duration
+	// QuarantineThreshold crashes within QuarantineWindow quarantine an MCP (0 = default)
+	QuarantineThreshold int
+	QuarantineWindow    time.Duration
 }
 
 func NewPool(ctx context.Context, config *PoolConfig) (*Pool, error) {
 	ctx, cancel := context.WithCancel(ctx)
 	return &Pool{
-		proxies: make(map[string]*SocketProxy),
-		ctx:     ctx,
-		cancel:  cancel,
-		config:  config,
+		proxies:     make(map[string]*SocketProxy),
+		ctx:         ctx,
+		cancel:      cancel,
+		config:      config,
+		crashes:     make(map[string][]time.Time),
+		quarantined: make(map[string]QuarantineInfo),
 	}, nil
 }
 
@@ -173,8 +186,9 @@ func (p *Pool) Shutdown() error {
 	return nil
 }
 
-// StartHealthMonitor launches a background goroutine that checks for
-// failed proxies every 10 seconds and restarts them automatically.
+// StartHealthMonitor launches a background goroutine that every 10 seconds
+// restarts failed proxies (quarantining crash loops) and probes running
+// proxies with a protocol-level ping.
 func (p *Pool) StartHealthMonitor() {
 	go func() {
 		ticker := time.NewTicker(10 * time.Second)
@@ -186,6 +200,7 @@ func (p *Pool) StartHealthMonitor() {
 				return
 			case <-ticker.C:
 				p.restartFailedProxies()
+				p.checkProxyHealth()
 			}
 		}
 	}()
@@ -206,7 +221,23 @@ func (p *Pool) restartFailedProxies() {
 	}
 	p.mu.RUnlock()
 
+	threshold := p.config.QuarantineThreshold
+	if threshold <= 0 {
+		threshold = DefaultQuarantineThreshold
+	}
+
 	for _, name := range failedProxies {
+		// Crash-loop detection: too many crashes in the window quarantines
+		// the MCP instead of restarting it again
+		p.mu.Lock()
+		crashes := p.recordCrash(name)
+		if crashes >= threshold {
+			p.quarantine(name, crashes, "crash loop")
+			p.mu.Unlock()
+			continue
+		}
+		p.mu.Unlock()
+
 		if err := p.RestartProxyWithRateLimit(name); err != nil {
 			log.Printf("[Pool] Failed to restart %s: %v", name, err)
 		}
@@ -270,13 +301,20 @@ func (p *Pool) ListServers() []ProxyInfo {
 
 	list := []ProxyInfo{}
 	for _, proxy := range p.proxies {
+		health := proxy.GetHealth()
 		list = append(list, ProxyInfo{
-			Name:        proxy.name,
-			SocketPath:  proxy.socketPath,
-			Status:      proxy.GetStatus().String(),
-			Clients:     proxy.GetClientCount(),
+			Name:       proxy.name,
+			SocketPath: proxy.socketPath,
+			Status:     proxy.GetStatus().String(),
+			Clients:    proxy.GetClientCount(),
+			Health:     health.State.String(),
+			Latency:    health.Latency,
+			LastError:  health.LastError,
 		})
 	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
 	return list
 }
 
@@ -299,6 +337,9 @@ type ProxyInfo struct {
 	SocketPath string
 	Status     string
 	Clients    int
+	Health     string        // Last probe result: healthy, unresponsive, unknown
+	Latency    time.Duration // Round-trip of the last successful probe
+	LastError  string        // Error of the last failed probe
 }
 
 // DiscoverExistingSockets scans for existing pool sockets owned by another agent-deck instance
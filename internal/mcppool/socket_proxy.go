@@ -46,6 +46,8 @@ type SocketProxy struct {
 	statusMu     sync.RWMutex // Protects Status field
 	lastRestart  time.Time    // For rate limiting restarts
 	restartCount int          // Track restart attempts
+	health       HealthInfo   // Last protocol probe result (protected by statusMu)
+	crashCounted bool         // Failure already recorded in Pool.crashes (protected by Pool.mu)
 }
 
 // SetStatus safely updates the proxy status
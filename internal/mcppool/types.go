@@ -5,9 +5,10 @@ type ServerStatus int
 
 const (
 	StatusStopped ServerStatus = iota
-	StatusStarting  
+	StatusStarting
 	StatusRunning
 	StatusFailed
+	StatusQuarantined // Crash-looping, kept out of the pool until lifted
 )
 
 func (s ServerStatus) String() string {
@@ -20,6 +21,8 @@ func (s ServerStatus) String() string {
 		return "running"
 	case StatusFailed:
 		return "failed"
+	case StatusQuarantined:
+		return "quarantined"
 	default:
 		return "unknown"
 	}
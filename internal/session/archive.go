@@ -0,0 +1,279 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveFile is the archive's file name within a profile directory
+const archiveFile = "archive.json"
+
+// ArchivedSession is a deleted session kept in the profile's archive so it
+// can be restored with its metadata, Claude session link and worktree
+type ArchivedSession struct {
+	Session    *InstanceData `json:"session"`
+	ArchivedAt time.Time     `json:"archived_at"`
+	Reason     string        `json:"reason,omitempty"`
+}
+
+// archiveData is the JSON structure of archive.json
+type archiveData struct {
+	SchemaVersion int                `json:"schema_version"`
+	Sessions      []*ArchivedSession `json:"sessions"`
+}
+
+// Archive holds a profile's deleted sessions until they are restored or
+// purged. Sessions older than [archive] retention_days are purged whenever a
+// session is archived or the archive is listed.
+type Archive struct {
+	path     string
+	fileLock *fileLock
+}
+
+// OpenArchive returns the archive of a profile (empty = effective profile)
+func OpenArchive(profile string) (*Archive, error) {
+	dir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create profile directory: %w", err)
+	}
+	path := filepath.Join(dir, archiveFile)
+	return &Archive{path: path, fileLock: newFileLock(path)}, nil
+}
+
+// Path returns the archive file
+func (a *Archive) Path() string {
+	return a.path
+}
+
+// List returns the archived sessions, most recently archived first
+func (a *Archive) List() ([]*ArchivedSession, error) {
+	var sessions []*ArchivedSession
+	err := a.update(func(archived []*ArchivedSession) ([]*ArchivedSession, bool) {
+		sessions = archived
+		return archived, false
+	})
+	return sessions, err
+}
+
+// Add archives inst with a reason (e.g. "deleted in TUI")
+func (a *Archive) Add(inst *Instance, reason string) error {
+	entry := &ArchivedSession{
+		Session:    buildStorageData([]*Instance{inst}, nil).Instances[0],
+		ArchivedAt: time.Now(),
+		Reason:     reason,
+	}
+	return a.update(func(archived []*ArchivedSession) ([]*ArchivedSession, bool) {
+		kept := archived[:0]
+		for _, s := range archived {
+			if s.Session.ID != inst.ID {
+				kept = append(kept, s)
+			}
+		}
+		return append(kept, entry), true
+	})
+}
+
+// Find resolves an archived session by ID, ID prefix or title. Titles match
+// the most recently archived session.
+func (a *Archive) Find(identifier string) (*ArchivedSession, error) {
+	archived, err := a.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range archived {
+		if s.Session.ID == identifier {
+			return s, nil
+		}
+	}
+	for _, s := range archived {
+		if s.Session.Title == identifier || strings.HasPrefix(s.Session.ID, identifier) {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("archived session not found: %s", identifier)
+}
+
+// Take removes a session from the archive and returns it (for restore)
+func (a *Archive) Take(id string) (*ArchivedSession, error) {
+	var taken *ArchivedSession
+	err := a.update(func(archived []*ArchivedSession) ([]*ArchivedSession, bool) {
+		for i, s := range archived {
+			if s.Session.ID == id {
+				taken = s
+				return append(archived[:i], archived[i+1:]...), true
+			}
+		}
+		return archived, false
+	})
+	if err == nil && taken == nil {
+		err = fmt.Errorf("archived session not found: %s", id)
+	}
+	return taken, err
+}
+
+// Purge permanently deletes the given archived sessions and their notes, or
+// all of them if ids is empty. Returns how many were deleted.
+func (a *Archive) Purge(ids []string) (int, error) {
+	purge := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		purge[id] = true
+	}
+	var purged []string
+	err := a.update(func(archived []*ArchivedSession) ([]*ArchivedSession, bool) {
+		kept := archived[:0]
+		for _, s := range archived {
+			if len(ids) == 0 || purge[s.Session.ID] {
+				purged = append(purged, s.Session.ID)
+				continue
+			}
+			kept = append(kept, s)
+		}
+		return kept, len(purged) > 0
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := deleteNotesIn(filepath.Dir(a.path), purged); err != nil {
+		log.Printf("[ARCHIVE] %v", err)
+	}
+	return len(purged), nil
+}
+
+// update runs fn on the archived sessions (newest first, expired ones
+// already dropped) under the archive's file lock and writes the result if fn
+// reports a change or sessions expired
+func (a *Archive) update(fn func([]*ArchivedSession) ([]*ArchivedSession, bool)) error {
+	handle, err := a.fileLock.Lock()
+	if err != nil {
+		return fmt.Errorf("failed to acquire archive lock: %w", err)
+	}
+	defer func() { _ = handle.Unlock() }()
+
+	data := &archiveData{}
+	raw, err := os.ReadFile(a.path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, data); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", a.path, err)
+		}
+		if data.SchemaVersion > StorageSchemaVersion {
+			return schemaTooNewError(a.path, data.SchemaVersion)
+		}
+	}
+
+	sessions, expired := dropExpiredArchived(data.Sessions, GetArchiveSettings().Retention(), time.Now())
+	if expired > 0 {
+		kept := make(map[string]bool, len(sessions))
+		for _, s := range sessions {
+			kept[s.Session.ID] = true
+		}
+		var expiredIDs []string
+		for _, s := range data.Sessions {
+			if s != nil && s.Session != nil && !kept[s.Session.ID] {
+				expiredIDs = append(expiredIDs, s.Session.ID)
+			}
+		}
+		defer func() {
+			if err := deleteNotesIn(filepath.Dir(a.path), expiredIDs); err != nil {
+				log.Printf("[ARCHIVE] %v", err)
+			}
+		}()
+	}
+	sessions, changed := fn(sessions)
+	if !changed && expired == 0 {
+		return nil
+	}
+	if expired > 0 {
+		log.Printf("[ARCHIVE] Purged %d session(s) past retention from %s", expired, a.path)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].ArchivedAt.After(sessions[j].ArchivedAt) })
+	out, err := json.MarshalIndent(archiveData{SchemaVersion: StorageSchemaVersion, Sessions: sessions}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal archive: %w", err)
+	}
+	tmpPath := a.path + ".tmp"
+	if err := os.WriteFile(tmpPath, out, 0600); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := os.Rename(tmpPath, a.path); err != nil {
+		return fmt.Errorf("failed to write archive: %w", err)
+	}
+	return nil
+}
+
+// dropExpiredArchived removes sessions archived longer than retention ago
+// (retention <= 0 keeps everything) and sorts the rest newest first
+func dropExpiredArchived(sessions []*ArchivedSession, retention time.Duration, now time.Time) ([]*ArchivedSession, int) {
+	kept := make([]*ArchivedSession, 0, len(sessions))
+	for _, s := range sessions {
+		if s == nil || s.Session == nil {
+			continue
+		}
+		if retention > 0 && now.Sub(s.ArchivedAt) > retention {
+			continue
+		}
+		kept = append(kept, s)
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].ArchivedAt.After(kept[j].ArchivedAt) })
+	return kept, len(sessions) - len(kept)
+}
+
+// RestoreArchivedSession rebuilds the Instance of an archived session. It
+// does not start it; call Revive once the session is saved. Fails if a
+// session with the same ID exists.
+func RestoreArchivedSession(entry *ArchivedSession, existing []*Instance) (*Instance, error) {
+	for _, inst := range existing {
+		if inst.ID == entry.Session.ID {
+			return nil, fmt.Errorf("session %s already exists", inst.ID)
+		}
+	}
+	data := &StorageData{Instances: []*InstanceData{entry.Session}}
+	instances, _, err := (&Storage{}).convertToInstances(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) != 1 || instances[0] == nil {
+		return nil, fmt.Errorf("failed to rebuild session %s", entry.Session.ID)
+	}
+	return instances[0], nil
+}
+
+// Revive recreates the tmux session of a stopped session, resuming the
+// tool's conversation when its session ID is known. It does nothing if the
+// tmux session still exists.
+func (i *Instance) Revive() error {
+	if i.tmuxSession != nil && i.tmuxSession.Exists() {
+		return nil
+	}
+	if !i.IsRemote() {
+		if _, err := os.Stat(i.ProjectPath); err != nil {
+			return fmt.Errorf("project path %s: %w", i.ProjectPath, err)
+		}
+		return i.Restart()
+	}
+
+	// Restart's fallback creates a local tmux session, so start the remote
+	// one directly with a resume command
+	if i.tmuxSession == nil {
+		return fmt.Errorf("tmux session not initialized")
+	}
+	command := i.Command
+	if i.Tool == "claude" && i.ClaudeSessionID != "" {
+		i.Command = i.claudeResumeCommand(true)
+	}
+	err := i.Start()
+	i.Command = command
+	return err
+}
@@ -0,0 +1,123 @@
+package session
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestArchive(t *testing.T) *Archive {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+	path := filepath.Join(t.TempDir(), archiveFile)
+	return &Archive{path: path, fileLock: newFileLock(path)}
+}
+
+func TestArchive_AddFindTakePurge(t *testing.T) {
+	a := newTestArchive(t)
+
+	api := NewInstance("api", "/srv/api")
+	api.GroupPath = "work"
+	api.ClaudeSessionID = "0b6a5f6e-1111-2222-3333-444455556666"
+	web := NewInstance("web", "/srv/web")
+	if err := a.Add(api, "deleted in TUI"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Add(web, "removed with agent-deck rm"); err != nil {
+		t.Fatal(err)
+	}
+	// Archiving the same session again replaces the entry
+	if err := a.Add(web, "again"); err != nil {
+		t.Fatal(err)
+	}
+
+	archived, err := a.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archived) != 2 || archived[0].Session.ID != web.ID || archived[0].Reason != "again" {
+		t.Fatalf("List() = %+v, want web (newest) then api", archived)
+	}
+
+	found, err := a.Find("api")
+	if err != nil || found.Session.ID != api.ID || found.Session.ClaudeSessionID != api.ClaudeSessionID {
+		t.Fatalf("Find(title) = %+v, %v", found, err)
+	}
+	if found, err := a.Find(web.ID[:6]); err != nil || found.Session.ID != web.ID {
+		t.Errorf("Find(prefix) = %+v, %v", found, err)
+	}
+	if _, err := a.Find("missing"); err == nil {
+		t.Error("Find(missing) should fail")
+	}
+
+	if taken, err := a.Take(api.ID); err != nil || taken.Session.GroupPath != "work" {
+		t.Fatalf("Take = %+v, %v", taken, err)
+	}
+	if _, err := a.Take(api.ID); err == nil {
+		t.Error("Take of a restored session should fail")
+	}
+
+	if n, err := a.Purge(nil); err != nil || n != 1 {
+		t.Errorf("Purge(all) = %d, %v, want 1", n, err)
+	}
+	if archived, _ := a.List(); len(archived) != 0 {
+		t.Errorf("archive not empty after purge: %+v", archived)
+	}
+}
+
+func TestDropExpiredArchived(t *testing.T) {
+	now := time.Now()
+	sessions := []*ArchivedSession{
+		{Session: &InstanceData{ID: "old"}, ArchivedAt: now.Add(-40 * 24 * time.Hour)},
+		{Session: &InstanceData{ID: "new"}, ArchivedAt: now.Add(-time.Hour)},
+		{Session: &InstanceData{ID: "mid"}, ArchivedAt: now.Add(-10 * 24 * time.Hour)},
+	}
+
+	kept, expired := dropExpiredArchived(sessions, 30*24*time.Hour, now)
+	if expired != 1 || len(kept) != 2 || kept[0].Session.ID != "new" || kept[1].Session.ID != "mid" {
+		t.Errorf("30 day retention kept %d (expired %d), want new, mid", len(kept), expired)
+	}
+
+	// Zero retention keeps everything
+	if kept, expired := dropExpiredArchived(sessions, 0, now); expired != 0 || len(kept) != 3 {
+		t.Errorf("zero retention expired %d", expired)
+	}
+}
+
+func TestRestoreArchivedSession(t *testing.T) {
+	a := newTestArchive(t)
+	inst := NewInstanceWithTool("api", "/srv/api", "claude")
+	inst.GroupPath = "work"
+	inst.ClaudeSessionID = "0b6a5f6e-1111-2222-3333-444455556666"
+	if err := a.Add(inst, "deleted in TUI"); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := a.Find(inst.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := RestoreArchivedSession(entry, nil)
+	if err != nil {
+		t.Fatalf("RestoreArchivedSession: %v", err)
+	}
+	if restored.ID != inst.ID || restored.GroupPath != "work" || restored.ClaudeSessionID != inst.ClaudeSessionID ||
+		restored.GetTmuxSession() == nil {
+		t.Errorf("restored = %+v", restored)
+	}
+
+	if _, err := RestoreArchivedSession(entry, []*Instance{restored}); err == nil {
+		t.Error("restoring over an existing session ID should fail")
+	}
+}
+
+func TestArchiveSettings_Retention(t *testing.T) {
+	if got := (ArchiveSettings{RetentionDays: 7}).Retention(); got != 7*24*time.Hour {
+		t.Errorf("Retention() = %v", got)
+	}
+	if got := (ArchiveSettings{RetentionDays: -1}).Retention(); got != 0 {
+		t.Errorf("negative retention = %v, want 0 (keep forever)", got)
+	}
+}
@@ -0,0 +1,338 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// auditLogFile is the audit log's file name within a profile directory
+const auditLogFile = "audit.log"
+
+// auditMaxSize is the size at which audit.log is rotated to the next free
+// audit.log.N. Rotated logs are kept; nothing is ever deleted. (A variable so
+// tests can lower it.)
+var auditMaxSize int64 = 10 * 1024 * 1024
+
+// Session actions recorded in the audit log
+const (
+	AuditCreate    = "create"
+	AuditStart     = "start"
+	AuditStop      = "stop"
+	AuditRestart   = "restart"
+	AuditFork      = "fork"
+	AuditSend      = "send"
+	AuditDelete    = "delete"
+	AuditMove      = "move"
+	AuditMCPAttach = "mcp_attach"
+	AuditMCPDetach = "mcp_detach"
+)
+
+// AuditActions lists the recorded actions
+var AuditActions = []string{
+	AuditCreate, AuditStart, AuditStop, AuditRestart, AuditFork,
+	AuditSend, AuditDelete, AuditMove, AuditMCPAttach, AuditMCPDetach,
+}
+
+// AuditEntry is one line of a profile's audit log: an action on a session
+// and the process that performed it
+type AuditEntry struct {
+	Time      time.Time `json:"time"`
+	Action    string    `json:"action"`
+	SessionID string    `json:"session_id"`
+	Title     string    `json:"title,omitempty"`
+	Detail    string    `json:"detail,omitempty"` // e.g. the target group of a move or the MCP name
+	Source    string    `json:"source"`           // tui, cli, desktop (or AGENTDECK_AUDIT_SOURCE)
+	Command   string    `json:"command"`          // argv[0], subcommand and flag names (see auditCommand)
+	PID       int       `json:"pid"`
+	PPID      int       `json:"ppid"`
+	Parent    string    `json:"parent,omitempty"` // Parent process name, e.g. bash for a script
+	User      string    `json:"user,omitempty"`
+}
+
+// AuditFilter selects entries in ReadAudit. Zero values match everything.
+type AuditFilter struct {
+	SessionID string    // ID or ID prefix
+	Title     string    // Exact title, matched when SessionID doesn't match
+	Since     time.Time // Entries at or after this time
+	Actions   []string
+	Source    string
+	Limit     int // Most recent N entries
+}
+
+var (
+	auditMu     sync.Mutex
+	auditSource = "cli"
+
+	auditProcessOnce sync.Once
+	auditProcess     AuditEntry
+)
+
+// SetAuditSource names the front end (tui, cli, desktop) recorded with this
+// process's actions. AGENTDECK_AUDIT_SOURCE overrides it so wrappers and
+// scripts can identify themselves.
+func SetAuditSource(source string) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditSource = source
+}
+
+// auditProcessInfo returns the fields identifying this process, computed once
+func auditProcessInfo() AuditEntry {
+	auditProcessOnce.Do(func() {
+		auditProcess = AuditEntry{
+			Command: auditCommand(os.Args),
+			PID:     os.Getpid(),
+			PPID:    os.Getppid(),
+		}
+		if u, err := user.Current(); err == nil {
+			auditProcess.User = u.Username
+		}
+		if out, err := exec.Command("ps", "-o", "comm=", "-p", strconv.Itoa(auditProcess.PPID)).Output(); err == nil {
+			auditProcess.Parent = filepath.Base(strings.TrimSpace(string(out)))
+		}
+	})
+	return auditProcess
+}
+
+// auditCommandGroups are the commands whose first argument is a subcommand
+var auditCommandGroups = map[string]bool{
+	"archive": true, "debug": true, "group": true, "host": true, "mcp": true,
+	"profile": true, "remote": true, "session": true, "sync": true,
+	"worktree": true, "wt": true,
+}
+
+// auditCommand returns argv[0] with the subcommand and flag names from args.
+// Flag values and positional arguments are left out: messages, titles and
+// MCP env values passed on the command line can carry secrets.
+func auditCommand(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	parts := []string{args[0]}
+	var commands []string
+	flagValue := false // The previous arg was a flag that may take this as its value
+	for _, arg := range args[1:] {
+		if arg == "--" {
+			break
+		}
+		if len(arg) > 1 && strings.HasPrefix(arg, "-") {
+			name, _, hasValue := strings.Cut(arg, "=")
+			parts = append(parts, name)
+			flagValue = !hasValue
+			continue
+		}
+		wantCommand := len(commands) == 0 || len(commands) == 1 && auditCommandGroups[commands[0]]
+		if !flagValue && wantCommand {
+			commands = append(commands, arg)
+			parts = append(parts, arg)
+		}
+		flagValue = false
+	}
+	return strings.Join(parts, " ")
+}
+
+// auditLogPath returns a profile's audit log (empty = effective profile)
+func auditLogPath(profile string) (string, error) {
+	dir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, auditLogFile), nil
+}
+
+// RecordAudit appends an action on inst to the profile's audit log. Failing
+// to record never fails the action itself, so errors are only logged.
+func RecordAudit(profile, action string, inst *Instance, detail string) {
+	if inst == nil {
+		return
+	}
+	RecordAuditEntry(profile, AuditEntry{Action: action, SessionID: inst.ID, Title: inst.Title, Detail: detail})
+}
+
+// RecordAuditEntry appends an entry, filling in the time and process fields
+func RecordAuditEntry(profile string, entry AuditEntry) {
+	if err := appendAudit(profile, entry); err != nil {
+		log.Printf("[AUDIT] %v", err)
+	}
+}
+
+func appendAudit(profile string, entry AuditEntry) error {
+	proc := auditProcessInfo()
+	entry.Command, entry.PID, entry.PPID, entry.Parent, entry.User = proc.Command, proc.PID, proc.PPID, proc.Parent, proc.User
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	entry.Source = auditSource
+	if env := os.Getenv("AGENTDECK_AUDIT_SOURCE"); env != "" {
+		entry.Source = env
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	path, err := auditLogPath(profile)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create profile directory: %w", err)
+	}
+
+	// Other processes append to the same file; the lock keeps rotation from
+	// racing with their writes
+	handle, err := newFileLock(path).Lock()
+	if err != nil {
+		return fmt.Errorf("failed to acquire audit log lock: %w", err)
+	}
+	defer func() { _ = handle.Unlock() }()
+
+	if info, err := os.Stat(path); err == nil && info.Size() >= auditMaxSize {
+		rotated, err := rotatedAuditLogs(path)
+		if err != nil {
+			return err
+		}
+		next := 1
+		if n := len(rotated); n > 0 {
+			next = auditLogNumber(path, rotated[n-1]) + 1
+		}
+		if err := os.Rename(path, fmt.Sprintf("%s.%d", path, next)); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// ReadAudit returns the profile's audit entries matching filter, oldest first
+func ReadAudit(profile string, filter AuditFilter) ([]AuditEntry, error) {
+	path, err := auditLogPath(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := rotatedAuditLogs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []AuditEntry
+	for _, p := range append(rotated, path) {
+		f, err := os.Open(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to open audit log: %w", err)
+		}
+		scanner := bufio.NewScanner(f)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var entry AuditEntry
+			if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+				continue // Torn or foreign line
+			}
+			if filter.matches(&entry) {
+				entries = append(entries, entry)
+			}
+		}
+		err = scanner.Err()
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
+
+// rotatedAuditLogs returns the rotated logs next to path, oldest first
+func rotatedAuditLogs(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rotated audit logs: %w", err)
+	}
+	var rotated []string
+	for _, m := range matches {
+		if auditLogNumber(path, m) > 0 {
+			rotated = append(rotated, m)
+		}
+	}
+	sort.Slice(rotated, func(i, j int) bool {
+		return auditLogNumber(path, rotated[i]) < auditLogNumber(path, rotated[j])
+	})
+	return rotated, nil
+}
+
+// auditLogNumber returns N for path.N, or 0 for anything else (e.g. the lock)
+func auditLogNumber(path, rotated string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(rotated, path+"."))
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+func (f AuditFilter) matches(e *AuditEntry) bool {
+	if f.SessionID != "" || f.Title != "" {
+		byID := f.SessionID != "" && strings.HasPrefix(e.SessionID, f.SessionID)
+		byTitle := f.Title != "" && e.Title == f.Title
+		if !byID && !byTitle {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if f.Source != "" && e.Source != f.Source {
+		return false
+	}
+	if len(f.Actions) > 0 {
+		found := false
+		for _, a := range f.Actions {
+			if a == e.Action {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// AuditMessageDetail describes a sent message for the log by its length
+// only, since messages can carry secrets
+func AuditMessageDetail(message string) string {
+	return fmt.Sprintf("%d chars", utf8.RuneCountInString(message))
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAuditLog_RecordAndFilter(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AGENTDECK_AUDIT_SOURCE", "")
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	api := &Instance{ID: "aaaa1111-1", Title: "api"}
+	web := &Instance{ID: "bbbb2222-2", Title: "web"}
+	SetAuditSource("tui")
+	t.Cleanup(func() { SetAuditSource("cli") })
+
+	RecordAuditEntry("default", AuditEntry{Time: time.Now().Add(-3 * time.Hour), Action: AuditCreate, SessionID: api.ID, Title: api.Title})
+	RecordAudit("default", AuditStop, api, "")
+	RecordAudit("default", AuditRestart, web, "")
+	t.Setenv("AGENTDECK_AUDIT_SOURCE", "nightly.sh")
+	RecordAudit("default", AuditSend, api, AuditMessageDetail("run\n  the   tests"))
+	RecordAudit("other", AuditDelete, api, "")
+
+	all, err := ReadAudit("default", AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("want 4 entries in the default profile, got %d", len(all))
+	}
+	if e := all[1]; e.Source != "tui" || e.PID != os.Getpid() || e.Command == "" || e.Title != "api" {
+		t.Errorf("process fields not recorded: %+v", e)
+	}
+	if e := all[3]; e.Source != "nightly.sh" || e.Detail != "17 chars" {
+		t.Errorf("source override or detail: %+v", e)
+	}
+
+	recent, _ := ReadAudit("default", AuditFilter{SessionID: "aaaa", Since: time.Now().Add(-2 * time.Hour)})
+	if len(recent) != 2 || recent[0].Action != AuditStop || recent[1].Action != AuditSend {
+		t.Errorf("session+since filter: %+v", recent)
+	}
+	byTitle, _ := ReadAudit("default", AuditFilter{SessionID: "web", Title: "web"})
+	if len(byTitle) != 1 || byTitle[0].SessionID != web.ID {
+		t.Errorf("title filter: %+v", byTitle)
+	}
+	actions, _ := ReadAudit("default", AuditFilter{Actions: []string{AuditStop, AuditRestart}, Limit: 1})
+	if len(actions) != 1 || actions[0].Action != AuditRestart {
+		t.Errorf("action filter with limit should keep the most recent: %+v", actions)
+	}
+	if other, _ := ReadAudit("other", AuditFilter{}); len(other) != 1 {
+		t.Errorf("profiles should have separate logs, got %d entries", len(other))
+	}
+}
+
+func TestAuditLog_ReadsRotatedLog(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	path, err := auditLogPath("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	old := `{"time":"2024-01-01T00:00:00Z","action":"create","session_id":"s1"}` + "\n" + "not json\n"
+	if err := os.WriteFile(path+".1", []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+	RecordAudit("default", AuditStart, &Instance{ID: "s1"}, "")
+
+	entries, err := ReadAudit("default", AuditFilter{SessionID: "s1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Action != AuditCreate || entries[1].Action != AuditStart {
+		t.Errorf("want the rotated entry first, got %+v", entries)
+	}
+}
+
+func TestAuditCommand_LeavesOutValues(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"agent-deck", "session", "send", "api", "deploy with token sk-live-123", "--no-wait"}, "agent-deck session send --no-wait"},
+		{[]string{"/usr/local/bin/agent-deck", "-p", "work", "add", ".", "-c", "claude", "--title=secret"}, "/usr/local/bin/agent-deck -p add -c --title"},
+		{[]string{"agent-deck", "rm", "my-project"}, "agent-deck rm"},
+		{[]string{"agent-deck", "mcp", "attach", "api", "github"}, "agent-deck mcp attach"},
+		{[]string{"agent-deck", "session", "set", "api", "--", "-title"}, "agent-deck session set"},
+		{[]string{"agent-deck"}, "agent-deck"},
+	}
+	for _, tt := range tests {
+		if got := auditCommand(tt.args); got != tt.want {
+			t.Errorf("auditCommand(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestAuditLog_RotationKeepsOldLogs(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+	defer func(size int64) { auditMaxSize = size }(auditMaxSize)
+	auditMaxSize = 1 // Rotate before every write after the first
+
+	for i := 0; i < 12; i++ {
+		RecordAudit("default", AuditSend, &Instance{ID: "s1"}, strconv.Itoa(i))
+	}
+
+	path, err := auditLogPath("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".11"); err != nil {
+		t.Errorf("want 11 rotated logs: %v", err)
+	}
+	entries, err := ReadAudit("default", AuditFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 12 {
+		t.Fatalf("rotation lost entries: got %d, want 12", len(entries))
+	}
+	for i, e := range entries {
+		if e.Detail != strconv.Itoa(i) {
+			t.Errorf("entry %d: detail %q, want oldest first across .10 and .11", i, e.Detail)
+		}
+	}
+}
+
+func TestAuditMessageDetail(t *testing.T) {
+	if got := AuditMessageDetail("deploy with token=s3crét"); got != "24 chars" {
+		t.Errorf("AuditMessageDetail = %q, want length only", got)
+	}
+}
@@ -157,9 +157,8 @@ func ListProfiles() ([]string, error) {
 	var profiles []string
 	for _, entry := range entries {
 		if entry.IsDir() {
-			// Verify it has a sessions.json file (valid profile)
-			sessionsPath := filepath.Join(profilesDir, entry.Name(), "sessions.json")
-			if _, err := os.Stat(sessionsPath); err == nil {
+			// Verify it has a sessions file (valid profile)
+			if ok, _ := hasSessionsFile(filepath.Join(profilesDir, entry.Name())); ok {
 				profiles = append(profiles, entry.Name())
 			}
 		}
@@ -176,15 +175,21 @@ func ProfileExists(profile string) (bool, error) {
 		return false, err
 	}
 
-	sessionsPath := filepath.Join(profileDir, "sessions.json")
-	_, err = os.Stat(sessionsPath)
-	if os.IsNotExist(err) {
-		return false, nil
-	}
-	if err != nil {
-		return false, err
+	return hasSessionsFile(profileDir)
+}
+
+// hasSessionsFile reports whether profileDir holds sessions.json or sessions.db
+func hasSessionsFile(profileDir string) (bool, error) {
+	for _, name := range []string{jsonStorageFile, sqliteStorageFile} {
+		_, err := os.Stat(filepath.Join(profileDir, name))
+		if err == nil {
+			return true, nil
+		}
+		if !os.IsNotExist(err) {
+			return false, err
+		}
 	}
-	return true, nil
+	return false, nil
 }
 
 // CreateProfile creates a new empty profile
@@ -215,8 +220,9 @@ func CreateProfile(profile string) error {
 
 	// Create empty sessions.json
 	emptyData := StorageData{
-		Instances: []*InstanceData{},
-		Groups:    []*GroupData{},
+		SchemaVersion: StorageSchemaVersion,
+		Instances:     []*InstanceData{},
+		Groups:        []*GroupData{},
 	}
 
 	data, err := json.MarshalIndent(emptyData, "", "  ")
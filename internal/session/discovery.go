@@ -77,12 +77,21 @@ func GroupByProject(instances []*Instance) map[string][]*Instance {
 }
 
 // FilterByQuery filters sessions by title, project path, tool, or status
-// Supports status filters: "waiting", "running", "idle", "error"
+// Supports status filters: "waiting", "running", "idle", "error", and
+// "tag:<tag>" / "meta:<key>[=<value>]" terms combined with the rest
 func FilterByQuery(instances []*Instance, query string) []*Instance {
 	if query == "" {
 		return instances
 	}
 
+	// "tag:urgent" and "meta:client=acme" terms narrow the sessions first
+	if filtered, rest, ok := filterByTagsAndMeta(instances, query); ok {
+		if strings.TrimSpace(rest) == "" {
+			return filtered
+		}
+		instances, query = filtered, rest
+	}
+
 	query = strings.ToLower(strings.TrimSpace(query))
 
 	// Check for status filters
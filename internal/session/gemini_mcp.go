@@ -75,20 +75,10 @@ func WriteGeminiMCPSettings(enabledNames []string) error {
 					Args:    []string{"-U", socketPath},
 				}
 			} else {
-				// Use stdio mode
-				args := def.Args
-				if args == nil {
-					args = []string{}
-				}
-				env := def.Env
-				if env == nil {
-					env = map[string]string{}
-				}
-				mcpServers[name] = MCPServerConfig{
-					Command: def.Command,
-					Args:    args,
-					Env:     env,
-				}
+				// Use stdio mode (Gemini's format has no "type" field)
+				serverConfig := stdioServerConfig(name, def)
+				serverConfig.Type = ""
+				mcpServers[name] = serverConfig
 			}
 		}
 	}
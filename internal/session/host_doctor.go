@@ -0,0 +1,313 @@
+package session
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// HostCheck.Status values
+const (
+	HostCheckOK   = "ok"
+	HostCheckWarn = "warn"
+	HostCheckFail = "fail"
+)
+
+// remoteAgentDeckDir is where 'host doctor --install' puts the binary.
+// It's on the PATH that RunCommand and StartStream set up, which
+// remote-agent, register-session and remote storage commands go through.
+const remoteAgentDeckDir = "~/.local/bin"
+
+// hostDoctorTools are the agent CLIs checked on every host
+var hostDoctorTools = []string{"claude", "gemini", "codex", "opencode"}
+
+// HostCheck is the result of one prerequisite check on an SSH host
+type HostCheck struct {
+	Name string `json:"name"`
+	// Status is "ok", "warn" or "fail"
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Detail  string `json:"detail,omitempty"`
+}
+
+// HostDoctorReport lists the prerequisite checks of an SSH host
+type HostDoctorReport struct {
+	HostID string `json:"host_id"`
+	// OS and Arch use GOOS/GOARCH names ("linux", "arm64"); empty if unknown
+	OS     string      `json:"os,omitempty"`
+	Arch   string      `json:"arch,omitempty"`
+	Checks []HostCheck `json:"checks"`
+}
+
+// Failed reports whether any check failed
+func (r *HostDoctorReport) Failed() bool {
+	for _, c := range r.Checks {
+		if c.Status == HostCheckFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Check returns the named check, or nil
+func (r *HostDoctorReport) Check(name string) *HostCheck {
+	for i := range r.Checks {
+		if r.Checks[i].Name == name {
+			return &r.Checks[i]
+		}
+	}
+	return nil
+}
+
+// hostRunner is the part of ssh.Connection used by the doctor
+type hostRunner interface {
+	RunCommand(command string) (string, error)
+	RunCommandWithStdin(command string, stdin io.Reader) (string, error)
+}
+
+// RunHostDoctor checks that an SSH host has what remote sessions need:
+// tmux (tmux_path), agent-deck (for register-session and remote-agent,
+// compared against localVersion), the agent CLIs, git, and nc -U when
+// forward_mcps is set. Connection failures are returned as a failed check.
+func RunHostDoctor(hostID, localVersion string) *HostDoctorReport {
+	report := &HostDoctorReport{HostID: hostID}
+	def := GetSSHHostDef(hostID)
+	if def == nil {
+		report.Checks = append(report.Checks, HostCheck{Name: "ssh", Status: HostCheckFail, Detail: "host not found in config.toml"})
+		return report
+	}
+	conn, err := sshpkg.DefaultPool().Get(hostID)
+	if err != nil {
+		report.Checks = append(report.Checks, HostCheck{Name: "ssh", Status: HostCheckFail, Detail: err.Error()})
+		return report
+	}
+	report.Checks = append(report.Checks, HostCheck{Name: "ssh", Status: HostCheckOK})
+	runHostDoctor(conn, *def, localVersion, report)
+	return report
+}
+
+// runHostDoctor appends the remote prerequisite checks to report
+func runHostDoctor(conn hostRunner, def SSHHostDef, localVersion string, report *HostDoctorReport) {
+	// Platform
+	if out, err := conn.RunCommand("uname -sm"); err != nil {
+		report.Checks = append(report.Checks, HostCheck{Name: "platform", Status: HostCheckWarn, Detail: err.Error()})
+	} else {
+		report.OS, report.Arch = parseUname(out)
+		report.Checks = append(report.Checks, HostCheck{Name: "platform", Status: HostCheckOK, Version: strings.TrimSpace(out)})
+	}
+
+	// tmux
+	tmuxPath := def.TmuxPath
+	if tmuxPath == "" {
+		tmuxPath = "tmux"
+	}
+	check := HostCheck{Name: "tmux", Path: def.TmuxPath}
+	if out, err := conn.RunCommand(sshpkg.RemoteQuote(tmuxPath) + " -V"); err != nil {
+		check.Status = HostCheckFail
+		check.Detail = fmt.Sprintf("%s not found", tmuxPath)
+		if def.TmuxPath == "" {
+			check.Detail += " (install tmux or set tmux_path)"
+		}
+	} else {
+		check.Status = HostCheckOK
+		check.Version = firstLine(out)
+	}
+	report.Checks = append(report.Checks, check)
+
+	// agent-deck
+	report.Checks = append(report.Checks, checkRemoteAgentDeck(conn, localVersion))
+
+	// Agent CLIs: the default tool is required, others are informational
+	defaultTool := GetDefaultTool()
+	tools := append([]string(nil), hostDoctorTools...)
+	if cmd := defaultToolCommand(defaultTool); !containsToolName(tools, cmd) {
+		tools = append(tools, cmd)
+	}
+	for _, tool := range tools {
+		check := HostCheck{Name: tool}
+		out, err := conn.RunCommand(remoteShell(fmt.Sprintf("command -v %[1]s && %[1]s --version 2>&1 | head -1", sshpkg.ShellQuote(tool))))
+		switch {
+		case err != nil && tool == defaultToolCommand(defaultTool):
+			check.Status = HostCheckFail
+			check.Detail = "default tool not installed"
+		case err != nil:
+			check.Status = HostCheckWarn
+			check.Detail = "not installed"
+		default:
+			lines := strings.SplitN(strings.TrimSpace(out), "\n", 2)
+			check.Status = HostCheckOK
+			check.Path = lines[0]
+			if len(lines) > 1 {
+				check.Version = strings.TrimSpace(lines[1])
+			}
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	// git (worktrees, project detection)
+	if out, err := conn.RunCommand("git --version"); err != nil {
+		report.Checks = append(report.Checks, HostCheck{Name: "git", Status: HostCheckWarn, Detail: "not installed"})
+	} else {
+		report.Checks = append(report.Checks, HostCheck{Name: "git", Status: HostCheckOK, Version: firstLine(out)})
+	}
+
+	// nc -U for forwarded MCP sockets
+	if len(def.ForwardMCPs) > 0 {
+		check := HostCheck{Name: "nc", Status: HostCheckOK}
+		if _, err := conn.RunCommand(remoteShell("nc -h 2>&1 | grep -q -- -U")); err != nil {
+			check.Status = HostCheckFail
+			check.Detail = "nc with -U support is required for forward_mcps (install netcat-openbsd)"
+		}
+		report.Checks = append(report.Checks, check)
+	}
+}
+
+// checkRemoteAgentDeck looks for agent-deck on the PATH set up by RunCommand,
+// so it finds the same binary other remote callers will run
+func checkRemoteAgentDeck(conn hostRunner, localVersion string) HostCheck {
+	check := HostCheck{Name: "agent-deck"}
+	out, err := conn.RunCommand(remoteShell(`command -v agent-deck && agent-deck version`))
+	if err != nil {
+		check.Status = HostCheckFail
+		check.Detail = "not installed (needed for register-session and remote-agent; use --install)"
+		return check
+	}
+	lines := strings.SplitN(strings.TrimSpace(out), "\n", 2)
+	check.Path = lines[0]
+	if len(lines) > 1 {
+		check.Version = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(lines[1]), "Agent Deck "), "v")
+	}
+	check.Status = HostCheckOK
+	if localVersion != "" && check.Version != localVersion {
+		check.Status = HostCheckWarn
+		check.Detail = fmt.Sprintf("local version is %s (use --install to upgrade)", localVersion)
+	}
+	return check
+}
+
+// defaultToolCommand returns the binary of the default tool ("claude" if unset)
+func defaultToolCommand(tool string) string {
+	if tool == "" {
+		return "claude"
+	}
+	if def := GetToolDef(tool); def != nil && def.Command != "" {
+		if fields := strings.Fields(def.Command); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	return tool
+}
+
+func containsToolName(tools []string, name string) bool {
+	for _, t := range tools {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseUname maps `uname -sm` output to GOOS/GOARCH names
+func parseUname(out string) (goos, goarch string) {
+	fields := strings.Fields(out)
+	if len(fields) < 2 {
+		return "", ""
+	}
+	goos = strings.ToLower(fields[0])
+	switch fields[1] {
+	case "x86_64", "amd64":
+		goarch = "amd64"
+	case "aarch64", "arm64":
+		goarch = "arm64"
+	case "armv7l", "armv6l":
+		goarch = "arm"
+	case "i386", "i686":
+		goarch = "386"
+	default:
+		goarch = fields[1]
+	}
+	return goos, goarch
+}
+
+// remoteShell runs a compound command under sh, so the PATH set up by
+// RunCommand applies to all of it and non-POSIX login shells don't matter
+func remoteShell(script string) string {
+	return "sh -c " + sshpkg.ShellQuote(script)
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// InstallAgentDeckOnHost uploads an agent-deck binary to ~/.local/bin on the
+// host and verifies its checksum. An empty binaryPath uploads the running
+// executable, which is only allowed when the host's OS/arch (from a doctor
+// report) matches this machine. Returns the remote path.
+func InstallAgentDeckOnHost(hostID, binaryPath string, report *HostDoctorReport) (string, error) {
+	conn, err := sshpkg.DefaultPool().Get(hostID)
+	if err != nil {
+		return "", err
+	}
+	return installAgentDeck(conn, binaryPath, report)
+}
+
+func installAgentDeck(conn hostRunner, binaryPath string, report *HostDoctorReport) (string, error) {
+	if binaryPath == "" {
+		if report == nil || report.OS == "" {
+			return "", fmt.Errorf("remote platform unknown; pass --binary with an agent-deck built for the host")
+		}
+		if report.OS != runtime.GOOS || report.Arch != runtime.GOARCH {
+			return "", fmt.Errorf("host is %s/%s but this agent-deck is %s/%s; build one with GOOS=%s GOARCH=%s go build ./cmd/agent-deck and pass --binary",
+				report.OS, report.Arch, runtime.GOOS, runtime.GOARCH, report.OS, report.Arch)
+		}
+		exe, err := os.Executable()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate agent-deck binary: %w", err)
+		}
+		if binaryPath, err = filepath.EvalSymlinks(exe); err != nil {
+			return "", fmt.Errorf("failed to locate agent-deck binary: %w", err)
+		}
+	}
+
+	f, err := os.Open(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open binary: %w", err)
+	}
+	defer f.Close()
+
+	// Hash while uploading so the file is read once
+	hash := sha256.New()
+	remoteDir := strings.Replace(remoteAgentDeckDir, "~", "$HOME", 1)
+	upload := fmt.Sprintf(`mkdir -p "%[1]s" && cat > "%[1]s/agent-deck.new" && chmod 755 "%[1]s/agent-deck.new"`, remoteDir)
+	if _, err := conn.RunCommandWithStdin(remoteShell(upload), io.TeeReader(f, hash)); err != nil {
+		return "", fmt.Errorf("upload failed: %w", err)
+	}
+	want := hex.EncodeToString(hash.Sum(nil))
+
+	verify := fmt.Sprintf(`f="%s/agent-deck.new"; (sha256sum "$f" 2>/dev/null || shasum -a 256 "$f") | cut -d' ' -f1`, remoteDir)
+	out, err := conn.RunCommand(remoteShell(verify))
+	if err != nil {
+		return "", fmt.Errorf("checksum failed: %w", err)
+	}
+	if got := strings.TrimSpace(out); got != want {
+		_, _ = conn.RunCommand(remoteShell(fmt.Sprintf(`rm -f "%s/agent-deck.new"`, remoteDir)))
+		return "", fmt.Errorf("checksum mismatch after upload (got %s, want %s)", got, want)
+	}
+
+	if _, err := conn.RunCommand(remoteShell(fmt.Sprintf(`mv -f "%[1]s/agent-deck.new" "%[1]s/agent-deck"`, remoteDir))); err != nil {
+		return "", fmt.Errorf("install failed: %w", err)
+	}
+	return remoteAgentDeckDir + "/agent-deck", nil
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// localRunner runs "remote" commands with the local sh, like ssh would.
+// RunCommand adds ~/.local/bin to PATH, as ssh.Connection.RunCommand does.
+type localRunner struct{}
+
+func (localRunner) RunCommand(command string) (string, error) {
+	out, err := exec.Command("sh", "-c", "PATH=$HOME/.local/bin:$PATH "+command).Output()
+	return string(out), err
+}
+
+func (localRunner) RunCommandWithStdin(command string, stdin io.Reader) (string, error) {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.Stdin = stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+func writeScript(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRunHostDoctor_Checks(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ClearUserConfigCache()
+
+	bin := filepath.Join(home, "bin")
+	writeScript(t, filepath.Join(bin, "mytmux"), `echo "tmux 3.4"`)
+	writeScript(t, filepath.Join(bin, "claude"), `echo "1.0.0 (Claude Code)"`)
+	writeScript(t, filepath.Join(home, ".local", "bin", "agent-deck"), `echo "Agent Deck v0.1.0"`)
+	t.Setenv("PATH", bin+":/usr/bin:/bin")
+
+	def := SSHHostDef{Host: "dev", TmuxPath: "~/bin/mytmux"} // Expanded by the remote shell
+	report := &HostDoctorReport{HostID: "dev"}
+	runHostDoctor(localRunner{}, def, "0.2.0", report)
+
+	if report.OS != runtime.GOOS {
+		t.Errorf("OS = %q, want %q", report.OS, runtime.GOOS)
+	}
+	if c := report.Check("tmux"); c == nil || c.Status != HostCheckOK || c.Version != "tmux 3.4" {
+		t.Errorf("tmux check = %+v", c)
+	}
+	if c := report.Check("agent-deck"); c == nil || c.Status != HostCheckWarn || c.Version != "0.1.0" {
+		t.Errorf("agent-deck check = %+v, want version mismatch warning", c)
+	}
+	if c := report.Check("claude"); c == nil || c.Status != HostCheckOK || c.Version != "1.0.0 (Claude Code)" {
+		t.Errorf("claude check = %+v", c)
+	}
+	if c := report.Check("gemini"); c == nil || c.Status != HostCheckWarn {
+		t.Errorf("gemini check = %+v, want warn (not installed)", c)
+	}
+	if report.Check("nc") != nil {
+		t.Error("nc is only checked when forward_mcps is set")
+	}
+	if report.Failed() {
+		t.Errorf("report should not fail: %+v", report.Checks)
+	}
+
+	// Missing tmux_path and default tool fail the report
+	if err := os.Remove(filepath.Join(bin, "claude")); err != nil {
+		t.Fatal(err)
+	}
+	report = &HostDoctorReport{HostID: "dev"}
+	runHostDoctor(localRunner{}, SSHHostDef{Host: "dev", TmuxPath: "/nonexistent/tmux"}, "0.1.0", report)
+	if c := report.Check("tmux"); c == nil || c.Status != HostCheckFail {
+		t.Errorf("tmux check = %+v, want fail", c)
+	}
+	if c := report.Check("claude"); c == nil || c.Status != HostCheckFail {
+		t.Errorf("claude check = %+v, want fail (default tool)", c)
+	}
+	if c := report.Check("agent-deck"); c == nil || c.Status != HostCheckOK {
+		t.Errorf("agent-deck check = %+v, want ok (same version)", c)
+	}
+}
+
+func TestInstallAgentDeck(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	binary := filepath.Join(t.TempDir(), "agent-deck")
+	content := bytes.Repeat([]byte("agent-deck-binary\n"), 1000)
+	if err := os.WriteFile(binary, content, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := installAgentDeck(localRunner{}, binary, nil)
+	if err != nil {
+		t.Fatalf("installAgentDeck: %v", err)
+	}
+	if path != "~/.local/bin/agent-deck" {
+		t.Errorf("path = %q", path)
+	}
+	installed := filepath.Join(home, ".local", "bin", "agent-deck")
+	got, err := os.ReadFile(installed)
+	if err != nil || !bytes.Equal(got, content) {
+		t.Fatalf("installed binary differs (err=%v)", err)
+	}
+	if info, _ := os.Stat(installed); info.Mode().Perm()&0100 == 0 {
+		t.Error("installed binary is not executable")
+	}
+
+	// The running executable is only uploaded to a matching platform
+	report := &HostDoctorReport{OS: "plan9", Arch: "mips"}
+	if _, err := installAgentDeck(localRunner{}, "", report); err == nil {
+		t.Error("expected platform mismatch error")
+	}
+}
+
+func TestParseUname(t *testing.T) {
+	tests := map[string][2]string{
+		"Linux x86_64\n": {"linux", "amd64"},
+		"Darwin arm64":   {"darwin", "arm64"},
+		"Linux aarch64":  {"linux", "arm64"},
+		"":               {"", ""},
+	}
+	for in, want := range tests {
+		goos, goarch := parseUname(in)
+		if goos != want[0] || goarch != want[1] {
+			t.Errorf("parseUname(%q) = %s/%s, want %s/%s", in, goos, goarch, want[0], want[1])
+		}
+	}
+}
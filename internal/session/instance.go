@@ -49,6 +49,10 @@ type Instance struct {
 	ParentSessionID   string `json:"parent_session_id,omitempty"`   // Links to parent session (makes this a sub-session)
 	ParentProjectPath string `json:"parent_project_path,omitempty"` // Parent's project path (for --add-dir access)
 
+	// Organization across groups (session tag/meta, list --tag, tag: search)
+	Tags []string          `json:"tags,omitempty"` // Normalized with NormalizeTags
+	Meta map[string]string `json:"meta,omitempty"` // e.g. ticket=ENG-42, client=acme
+
 	// Git worktree support
 	WorktreePath     string `json:"worktree_path,omitempty"`      // Path to worktree (if session is in worktree)
 	WorktreeRepoRoot string `json:"worktree_repo_root,omitempty"` // Original repo root
@@ -102,6 +106,10 @@ type Instance struct {
 	RemoteHost     string `json:"remote_host,omitempty"`      // SSH host identifier from config
 	RemoteTmuxName string `json:"remote_tmux_name,omitempty"` // tmux session name on remote host
 
+	// PortForwards are localhost ports forwarded to the remote host while the
+	// session runs (see PortForwardManager)
+	PortForwards []PortForward `json:"port_forwards,omitempty"`
+
 	tmuxSession *tmux.Session // Internal tmux session
 
 	// lastErrorCheck tracks when we last confirmed the session doesn't exist
@@ -908,6 +916,9 @@ func (i *Instance) Start() error {
 	// Load custom patterns for status detection
 	i.loadCustomPatternsFromConfig()
 
+	// Remote sessions: share forwarded pool MCPs (forward_mcps in [ssh_hosts])
+	i.setupRemoteMCPForwards()
+
 	// Start the tmux session
 	if err := i.tmuxSession.Start(command); err != nil {
 		return fmt.Errorf("failed to start tmux session: %w", err)
@@ -969,6 +980,9 @@ func (i *Instance) StartWithMessage(message string) error {
 	// Load custom patterns for status detection
 	i.loadCustomPatternsFromConfig()
 
+	// Remote sessions: share forwarded pool MCPs (forward_mcps in [ssh_hosts])
+	i.setupRemoteMCPForwards()
+
 	// Start the tmux session
 	if err := i.tmuxSession.Start(command); err != nil {
 		return fmt.Errorf("failed to start tmux session: %w", err)
@@ -1919,7 +1933,10 @@ func (i *Instance) Restart() error {
 
 	// Regenerate .mcp.json before restart to use socket pool if available
 	// Skip if MCP dialog just wrote the config (avoids race condition)
-	if i.Tool == "claude" && !skipRegen {
+	if i.Tool == "claude" && i.IsRemote() {
+		// Re-establish socket forwards (they die with the ControlMaster)
+		i.setupRemoteMCPForwards()
+	} else if i.Tool == "claude" && !skipRegen {
 		if err := i.regenerateMCPConfig(); err != nil {
 			log.Printf("[MCP-DEBUG] Warning: MCP config regeneration failed: %v", err)
 			// Continue with restart - Claude will use existing .mcp.json or defaults
@@ -2118,6 +2135,17 @@ func (i *Instance) Restart() error {
 // Respects: CLAUDE_CONFIG_DIR, dangerous_mode from user config
 // IMPORTANT: Also sets CLAUDE_SESSION_ID in tmux environment so detection works after restart
 func (i *Instance) buildClaudeResumeCommand() string {
+	// Check if session has actual conversation data
+	// If not, use --session-id instead of --resume to avoid "No conversation found" error
+	useResume := sessionHasConversationData(i.ClaudeSessionID, i.ProjectPath)
+	log.Printf("[SESSION-DATA] buildClaudeResumeCommand: sessionID=%s, path=%s, useResume=%v",
+		i.ClaudeSessionID, i.ProjectPath, useResume)
+	return i.claudeResumeCommand(useResume)
+}
+
+// claudeResumeCommand builds the command for buildClaudeResumeCommand.
+// useResume selects --resume (the conversation exists) over --session-id.
+func (i *Instance) claudeResumeCommand(useResume bool) string {
 	// Get the configured Claude command (e.g., "claude", "cdw", "cdp")
 	// If a custom command is set, we skip CLAUDE_CONFIG_DIR prefix since the alias handles it
 	claudeCmd := GetClaudeCommand()
@@ -2138,12 +2166,6 @@ func (i *Instance) buildClaudeResumeCommand() string {
 		dangerousMode = userConfig.Claude.DangerousMode
 	}
 
-	// Check if session has actual conversation data
-	// If not, use --session-id instead of --resume to avoid "No conversation found" error
-	useResume := sessionHasConversationData(i.ClaudeSessionID, i.ProjectPath)
-	log.Printf("[SESSION-DATA] buildClaudeResumeCommand: sessionID=%s, path=%s, useResume=%v",
-		i.ClaudeSessionID, i.ProjectPath, useResume)
-
 	// Build dangerous mode flag
 	dangerousFlag := ""
 	if dangerousMode {
@@ -0,0 +1,174 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetMCPBundles returns MCP bundles from config.toml as a map of bundle name to MCP names
+func GetMCPBundles() map[string][]string {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || config.MCPBundles == nil {
+		return make(map[string][]string)
+	}
+	return config.MCPBundles
+}
+
+// GetMCPBundleNames returns sorted list of bundle names from config.toml
+func GetMCPBundleNames() []string {
+	bundles := GetMCPBundles()
+	names := make([]string, 0, len(bundles))
+	for name := range bundles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetMCPBundle returns the MCP names in a bundle
+// Returns nil if the bundle is not defined
+func GetMCPBundle(name string) []string {
+	bundles := GetMCPBundles()
+	if members, ok := bundles[name]; ok {
+		return members
+	}
+	return nil
+}
+
+// ResolveMCPBundles expands bundle names into a deduplicated list of MCP names.
+// Every bundle must exist and every member must be defined in [mcps], so that
+// attaching a bundle is all-or-nothing.
+func ResolveMCPBundles(bundleNames []string) ([]string, error) {
+	bundles := GetMCPBundles()
+	availableMCPs := GetAvailableMCPs()
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, bundleName := range bundleNames {
+		members, ok := bundles[bundleName]
+		if !ok {
+			return nil, fmt.Errorf("MCP bundle '%s' not found in config.toml", bundleName)
+		}
+
+		var missing []string
+		for _, name := range members {
+			if _, ok := availableMCPs[name]; !ok {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("MCP bundle '%s' references undefined MCPs: %s",
+				bundleName, strings.Join(missing, ", "))
+		}
+
+		for _, name := range members {
+			if !seen[name] {
+				seen[name] = true
+				result = append(result, name)
+			}
+		}
+	}
+	return result, nil
+}
+
+// MergeMCPNames appends names from add that are not already in current.
+// Order of current is preserved.
+func MergeMCPNames(current, add []string) []string {
+	result := make([]string, 0, len(current)+len(add))
+	seen := make(map[string]bool)
+	for _, name := range current {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	for _, name := range add {
+		if !seen[name] {
+			seen[name] = true
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// SubtractMCPNames returns current without any names in remove
+func SubtractMCPNames(current, remove []string) []string {
+	drop := make(map[string]bool, len(remove))
+	for _, name := range remove {
+		drop[name] = true
+	}
+	result := make([]string, 0, len(current))
+	for _, name := range current {
+		if !drop[name] {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// AttachMCPBundles attaches every MCP in the given bundles with a single write.
+// Claude (and other tools) get the project's .mcp.json regenerated via
+// WriteMCPJsonFromConfig; Gemini gets settings.json via WriteGeminiMCPSettings.
+// Returns the MCP names that were newly attached.
+func AttachMCPBundles(tool, projectPath string, bundleNames []string) ([]string, error) {
+	members, err := ResolveMCPBundles(bundleNames)
+	if err != nil {
+		return nil, err
+	}
+
+	current := attachedMCPNamesForTool(tool, projectPath)
+	added := SubtractMCPNames(members, current)
+	if len(added) == 0 {
+		return nil, nil
+	}
+
+	if err := writeMCPNamesForTool(tool, projectPath, MergeMCPNames(current, members)); err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// DetachMCPBundles detaches every MCP in the given bundles with a single write.
+// Returns the MCP names that were actually removed.
+func DetachMCPBundles(tool, projectPath string, bundleNames []string) ([]string, error) {
+	members, err := ResolveMCPBundles(bundleNames)
+	if err != nil {
+		return nil, err
+	}
+
+	current := attachedMCPNamesForTool(tool, projectPath)
+	remaining := SubtractMCPNames(current, members)
+	if len(remaining) == len(current) {
+		return nil, nil
+	}
+
+	if err := writeMCPNamesForTool(tool, projectPath, remaining); err != nil {
+		return nil, err
+	}
+	return SubtractMCPNames(current, remaining), nil
+}
+
+// attachedMCPNamesForTool returns the MCPs currently attached at the scope
+// bundles operate on: global settings.json for Gemini, local .mcp.json otherwise
+func attachedMCPNamesForTool(tool, projectPath string) []string {
+	if tool == "gemini" {
+		return GetGeminiMCPNames()
+	}
+	return GetMCPInfo(projectPath).Local()
+}
+
+// writeMCPNamesForTool regenerates the MCP config for a tool and clears the cache
+func writeMCPNamesForTool(tool, projectPath string, names []string) error {
+	var err error
+	if tool == "gemini" {
+		err = WriteGeminiMCPSettings(names)
+	} else {
+		err = WriteMCPJsonFromConfig(projectPath, names)
+	}
+	if err != nil {
+		return err
+	}
+	ClearMCPCache(projectPath)
+	return nil
+}
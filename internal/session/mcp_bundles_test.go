@@ -0,0 +1,160 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// setupMCPBundleConfig writes a config.toml with MCPs and bundles to an isolated HOME
+func setupMCPBundleConfig(t *testing.T) {
+	t.Helper()
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	agentDeckDir := filepath.Join(tempDir, ".agent-deck")
+	if err := os.MkdirAll(agentDeckDir, 0700); err != nil {
+		t.Fatalf("Failed to create agent-deck dir: %v", err)
+	}
+
+	configContent := `
+[mcps.exa]
+command = "npx"
+args = ["-y", "exa-mcp-server"]
+
+[mcps.firecrawl]
+command = "npx"
+args = ["-y", "firecrawl-mcp"]
+
+[mcps.fetch]
+command = "uvx"
+args = ["mcp-server-fetch"]
+
+[mcp_bundles]
+web-research = ["exa", "firecrawl", "fetch"]
+scraping = ["firecrawl", "fetch"]
+broken = ["exa", "does-not-exist"]
+`
+	if err := os.WriteFile(filepath.Join(agentDeckDir, "config.toml"), []byte(configContent), 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+}
+
+func TestGetMCPBundles(t *testing.T) {
+	setupMCPBundleConfig(t)
+
+	names := GetMCPBundleNames()
+	want := []string{"broken", "scraping", "web-research"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("GetMCPBundleNames() = %v, want %v", names, want)
+	}
+
+	if got := GetMCPBundle("web-research"); !reflect.DeepEqual(got, []string{"exa", "firecrawl", "fetch"}) {
+		t.Errorf("GetMCPBundle(web-research) = %v", got)
+	}
+	if got := GetMCPBundle("missing"); got != nil {
+		t.Errorf("GetMCPBundle(missing) = %v, want nil", got)
+	}
+}
+
+func TestResolveMCPBundles(t *testing.T) {
+	setupMCPBundleConfig(t)
+
+	got, err := ResolveMCPBundles([]string{"scraping", "web-research"})
+	if err != nil {
+		t.Fatalf("ResolveMCPBundles failed: %v", err)
+	}
+	want := []string{"firecrawl", "fetch", "exa"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveMCPBundles() = %v, want %v (deduplicated, in order)", got, want)
+	}
+
+	if _, err := ResolveMCPBundles([]string{"missing"}); err == nil {
+		t.Error("expected error for unknown bundle")
+	}
+
+	_, err = ResolveMCPBundles([]string{"broken"})
+	if err == nil || !strings.Contains(err.Error(), "does-not-exist") {
+		t.Errorf("expected error naming undefined MCP, got %v", err)
+	}
+}
+
+func TestMergeAndSubtractMCPNames(t *testing.T) {
+	merged := MergeMCPNames([]string{"a", "b"}, []string{"b", "c", "c"})
+	if !reflect.DeepEqual(merged, []string{"a", "b", "c"}) {
+		t.Errorf("MergeMCPNames() = %v", merged)
+	}
+
+	remaining := SubtractMCPNames([]string{"a", "b", "c"}, []string{"b", "x"})
+	if !reflect.DeepEqual(remaining, []string{"a", "c"}) {
+		t.Errorf("SubtractMCPNames() = %v", remaining)
+	}
+}
+
+func TestAttachDetachMCPBundles_Local(t *testing.T) {
+	setupMCPBundleConfig(t)
+	projectPath := t.TempDir()
+
+	added, err := AttachMCPBundles("claude", projectPath, []string{"web-research"})
+	if err != nil {
+		t.Fatalf("AttachMCPBundles failed: %v", err)
+	}
+	if len(added) != 3 {
+		t.Errorf("expected 3 MCPs attached, got %v", added)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, ".mcp.json"))
+	if err != nil {
+		t.Fatalf("expected .mcp.json to be written: %v", err)
+	}
+	var cfg struct {
+		MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatalf("invalid .mcp.json: %v", err)
+	}
+	for _, name := range []string{"exa", "firecrawl", "fetch"} {
+		if _, ok := cfg.MCPServers[name]; !ok {
+			t.Errorf(".mcp.json missing %s", name)
+		}
+	}
+
+	// Attaching again is a no-op
+	added, err = AttachMCPBundles("claude", projectPath, []string{"scraping"})
+	if err != nil {
+		t.Fatalf("second AttachMCPBundles failed: %v", err)
+	}
+	if len(added) != 0 {
+		t.Errorf("expected no new MCPs, got %v", added)
+	}
+
+	removed, err := DetachMCPBundles("claude", projectPath, []string{"scraping"})
+	if err != nil {
+		t.Fatalf("DetachMCPBundles failed: %v", err)
+	}
+	sort.Strings(removed)
+	if !reflect.DeepEqual(removed, []string{"fetch", "firecrawl"}) {
+		t.Errorf("DetachMCPBundles removed %v", removed)
+	}
+	if local := GetMCPInfo(projectPath).Local(); !reflect.DeepEqual(local, []string{"exa"}) {
+		t.Errorf("remaining local MCPs = %v, want [exa]", local)
+	}
+}
+
+func TestAttachMCPBundles_InvalidBundleWritesNothing(t *testing.T) {
+	setupMCPBundleConfig(t)
+	projectPath := t.TempDir()
+
+	if _, err := AttachMCPBundles("claude", projectPath, []string{"broken"}); err == nil {
+		t.Fatal("expected error for bundle with undefined MCP")
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".mcp.json")); !os.IsNotExist(err) {
+		t.Error("invalid bundle must not write .mcp.json")
+	}
+}
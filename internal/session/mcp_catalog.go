@@ -17,7 +17,25 @@ type MCPServerConfig struct {
 	Command string            `json:"command,omitempty"`
 	Args    []string          `json:"args,omitempty"`
 	Env     map[string]string `json:"env,omitempty"`
-	URL     string            `json:"url,omitempty"` // For HTTP transport
+	URL     string            `json:"url,omitempty"`     // For HTTP transport
+	Headers map[string]string `json:"headers,omitempty"` // For HTTP transport
+	HTTPURL string            `json:"httpUrl,omitempty"` // Gemini's streamable HTTP endpoint (read-only)
+}
+
+// ReadMCPServersFile reads the top-level mcpServers map from a Claude or Gemini
+// style config file (.mcp.json, .claude.json, settings.json)
+func ReadMCPServersFile(path string) (map[string]MCPServerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return config.MCPServers, nil
 }
 
 // getExternalSocketPath returns the socket path if an external pool socket exists and is alive
@@ -63,8 +81,9 @@ func WriteMCPJsonFromConfig(projectPath string, enabledNames []string) error {
 					transport = "http" // default to http if URL is set
 				}
 				mcpConfig.MCPServers[name] = MCPServerConfig{
-					Type: transport,
-					URL:  def.URL,
+					Type:    transport,
+					URL:     def.URL,
+					Headers: httpServerHeaders(name, def),
 				}
 				log.Printf("[MCP] ✓ %s: using %s transport at %s", name, transport, def.URL)
 				continue
@@ -81,6 +100,7 @@ func WriteMCPJsonFromConfig(projectPath string, enabledNames []string) error {
 						Args:    []string{"-U", socketPath},
 					}
 					log.Printf("[MCP-POOL] ✓ %s: using socket %s", name, socketPath)
+					trackPooledProjectPath(projectPath)
 					continue
 				}
 
@@ -124,20 +144,7 @@ func WriteMCPJsonFromConfig(projectPath string, enabledNames []string) error {
 			}
 
 			// Fallback to stdio mode (pool disabled, excluded, or socket failed with fallback enabled)
-			args := def.Args
-			if args == nil {
-				args = []string{}
-			}
-			env := def.Env
-			if env == nil {
-				env = map[string]string{}
-			}
-			mcpConfig.MCPServers[name] = MCPServerConfig{
-				Type:    "stdio",
-				Command: def.Command,
-				Args:    args,
-				Env:     env,
-			}
+			mcpConfig.MCPServers[name] = stdioServerConfig(name, def)
 			log.Printf("[MCP-POOL] ⚠️ %s: using stdio (NOT pooled)", name)
 		}
 	}
@@ -191,8 +198,9 @@ func WriteGlobalMCP(enabledNames []string) error {
 					transport = "http" // default to http if URL is set
 				}
 				mcpServers[name] = MCPServerConfig{
-					Type: transport,
-					URL:  def.URL,
+					Type:    transport,
+					URL:     def.URL,
+					Headers: httpServerHeaders(name, def),
 				}
 				log.Printf("[MCP] ✓ Global %s: using %s transport at %s", name, transport, def.URL)
 				continue
@@ -252,20 +260,7 @@ func WriteGlobalMCP(enabledNames []string) error {
 			}
 
 			// Fallback to stdio mode (pool disabled, excluded, or socket failed with fallback enabled)
-			args := def.Args
-			if args == nil {
-				args = []string{}
-			}
-			env := def.Env
-			if env == nil {
-				env = map[string]string{}
-			}
-			mcpServers[name] = MCPServerConfig{
-				Type:    "stdio",
-				Command: def.Command,
-				Args:    args,
-				Env:     env,
-			}
+			mcpServers[name] = stdioServerConfig(name, def)
 			log.Printf("[MCP-POOL] ⚠️ Global %s: using stdio (NOT pooled)", name)
 		}
 	}
@@ -442,8 +437,9 @@ func WriteUserMCP(enabledNames []string) error {
 					transport = "http" // default to http if URL is set
 				}
 				mcpServers[name] = MCPServerConfig{
-					Type: transport,
-					URL:  def.URL,
+					Type:    transport,
+					URL:     def.URL,
+					Headers: httpServerHeaders(name, def),
 				}
 				log.Printf("[MCP] ✓ User %s: using %s transport at %s", name, transport, def.URL)
 				continue
@@ -503,20 +499,7 @@ func WriteUserMCP(enabledNames []string) error {
 			}
 
 			// Fallback to stdio mode (pool disabled, excluded, or socket failed with fallback enabled)
-			args := def.Args
-			if args == nil {
-				args = []string{}
-			}
-			env := def.Env
-			if env == nil {
-				env = map[string]string{}
-			}
-			mcpServers[name] = MCPServerConfig{
-				Type:    "stdio",
-				Command: def.Command,
-				Args:    args,
-				Env:     env,
-			}
+			mcpServers[name] = stdioServerConfig(name, def)
 			log.Printf("[MCP-POOL] ⚠️ User %s: using stdio (NOT pooled)", name)
 		}
 	}
@@ -0,0 +1,470 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/mcppool"
+)
+
+// mcpQuarantineFile records quarantined pool MCPs so the CLI can show them
+const mcpQuarantineFile = "mcp_quarantine.json"
+
+// MCPQuarantineRecord is a pool MCP taken out of the pool after crash-looping
+type MCPQuarantineRecord struct {
+	Name    string    `json:"name"`
+	Since   time.Time `json:"since"`
+	Crashes int       `json:"crashes"`
+	Reason  string    `json:"reason"`
+	// Rewritten lists the config files switched from the pool socket to stdio
+	Rewritten []string `json:"rewritten,omitempty"`
+	// PID is the process whose pool quarantined the MCP
+	PID int `json:"pid,omitempty"`
+}
+
+// MCPHealth is the pool health of a single MCP as shown in 'mcp list' and the TUI
+type MCPHealth struct {
+	Name string `json:"name"`
+	// Status is "running", "failed", "quarantined", "stopped", or "not pooled"
+	Status string `json:"status"`
+	// Health is the last protocol probe result: "healthy", "unresponsive", "unknown"
+	Health    string `json:"health"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// MCPHealth.Status values in addition to mcppool.ServerStatus names
+const (
+	MCPPoolStatusNotPooled   = "not pooled"
+	MCPPoolStatusStopped     = "stopped"
+	MCPPoolStatusQuarantined = "quarantined"
+)
+
+// pooledProjectPaths are project directories whose .mcp.json may reference a
+// pool socket. Used to move sessions to stdio when an MCP is quarantined.
+var (
+	pooledProjectPaths   = make(map[string]bool)
+	pooledProjectPathsMu sync.Mutex
+)
+
+func trackPooledProjectPath(projectPath string) {
+	if projectPath == "" {
+		return
+	}
+	pooledProjectPathsMu.Lock()
+	pooledProjectPaths[projectPath] = true
+	pooledProjectPathsMu.Unlock()
+}
+
+func getPooledProjectPaths() []string {
+	pooledProjectPathsMu.Lock()
+	defer pooledProjectPathsMu.Unlock()
+	paths := make([]string, 0, len(pooledProjectPaths))
+	for p := range pooledProjectPaths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func getMCPQuarantinePath() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, mcpQuarantineFile), nil
+}
+
+// LoadMCPQuarantine returns the MCPs quarantined by the running pool
+func LoadMCPQuarantine() map[string]MCPQuarantineRecord {
+	records := make(map[string]MCPQuarantineRecord)
+	path, err := getMCPQuarantinePath()
+	if err != nil {
+		return records
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return records
+	}
+	if err := json.Unmarshal(data, &records); err != nil {
+		log.Printf("[Pool] Ignoring unreadable %s: %v", path, err)
+		return make(map[string]MCPQuarantineRecord)
+	}
+	return records
+}
+
+func saveMCPQuarantine(records map[string]MCPQuarantineRecord) error {
+	path, err := getMCPQuarantinePath()
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
+// updateMCPQuarantine applies fn to the quarantine records under a file lock,
+// since every TUI's pool and the CLI share the file
+func updateMCPQuarantine(fn func(records map[string]MCPQuarantineRecord)) error {
+	path, err := getMCPQuarantinePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	handle, err := newFileLock(path).Lock()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = handle.Unlock() }()
+
+	records := LoadMCPQuarantine()
+	fn(records)
+	return saveMCPQuarantine(records)
+}
+
+// recordedQuarantines are the MCPs this process's pool recorded in the
+// quarantine file; removing one from the file (mcp unquarantine) lifts it
+var (
+	recordedQuarantines   = make(map[string]bool)
+	recordedQuarantinesMu sync.Mutex
+)
+
+// handleMCPQuarantine is the pool's quarantine callback: it rewrites config
+// files that point at the quarantined socket to stdio and records the quarantine
+func handleMCPQuarantine(info mcppool.QuarantineInfo) {
+	rewritten, err := RewritePooledMCPToStdio(info.Name, getPooledProjectPaths())
+	if err != nil {
+		log.Printf("[Pool] %s: failed to rewrite sessions to stdio: %v", info.Name, err)
+	}
+	for _, path := range rewritten {
+		log.Printf("[Pool] %s: switched %s to stdio (restart sessions to apply)", info.Name, path)
+	}
+
+	recordedQuarantinesMu.Lock()
+	defer recordedQuarantinesMu.Unlock()
+	err = updateMCPQuarantine(func(records map[string]MCPQuarantineRecord) {
+		records[info.Name] = MCPQuarantineRecord{
+			Name:      info.Name,
+			Since:     info.Since,
+			Crashes:   info.Crashes,
+			Reason:    info.Reason,
+			Rewritten: rewritten,
+			PID:       os.Getpid(),
+		}
+	})
+	if err != nil {
+		log.Printf("[Pool] Failed to save quarantine state: %v", err)
+		return
+	}
+	recordedQuarantines[info.Name] = true
+}
+
+// pruneMCPQuarantine drops the records of pools that are gone: a fresh pool
+// retries every MCP, but other running TUIs keep their quarantines
+func pruneMCPQuarantine() error {
+	return updateMCPQuarantine(func(records map[string]MCPQuarantineRecord) {
+		for name, r := range records {
+			if r.PID == 0 || r.PID == os.Getpid() || !isProcessAlive(r.PID) {
+				delete(records, name)
+			}
+		}
+	})
+}
+
+// releaseMCPQuarantine drops the records of this process's pool (on shutdown)
+func releaseMCPQuarantine() error {
+	recordedQuarantinesMu.Lock()
+	defer recordedQuarantinesMu.Unlock()
+	recordedQuarantines = make(map[string]bool)
+	return updateMCPQuarantine(func(records map[string]MCPQuarantineRecord) {
+		for name, r := range records {
+			if r.PID == os.Getpid() {
+				delete(records, name)
+			}
+		}
+	})
+}
+
+// UnquarantineMCP removes name's quarantine record. The pool that
+// quarantined it (this process's or another TUI's) notices within
+// mcpQuarantineWatchInterval, lifts the quarantine and restarts the MCP.
+func UnquarantineMCP(name string) (MCPQuarantineRecord, error) {
+	var (
+		record MCPQuarantineRecord
+		found  bool
+	)
+	err := updateMCPQuarantine(func(records map[string]MCPQuarantineRecord) {
+		record, found = records[name]
+		delete(records, name)
+	})
+	if err != nil {
+		return record, fmt.Errorf("failed to update quarantine state: %w", err)
+	}
+	if !found {
+		return record, fmt.Errorf("%s is not quarantined", name)
+	}
+	if pool := GetGlobalPool(); pool != nil {
+		liftRemovedQuarantines(pool)
+	}
+	return record, nil
+}
+
+// mcpQuarantineWatchInterval is how often a pool checks for quarantines
+// lifted with 'agent-deck mcp unquarantine'
+const mcpQuarantineWatchInterval = 5 * time.Second
+
+// watchMCPQuarantine lifts the pool's quarantines whose records were removed
+// until ctx is done
+func watchMCPQuarantine(ctx context.Context, pool *mcppool.Pool) {
+	ticker := time.NewTicker(mcpQuarantineWatchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			liftRemovedQuarantines(pool)
+		}
+	}
+}
+
+// liftRemovedQuarantines unquarantines the MCPs this process recorded that
+// are no longer in the quarantine file
+func liftRemovedQuarantines(pool *mcppool.Pool) {
+	recordedQuarantinesMu.Lock()
+	records := LoadMCPQuarantine()
+	var lift []string
+	for name := range recordedQuarantines {
+		if _, ok := records[name]; !ok {
+			delete(recordedQuarantines, name)
+			lift = append(lift, name)
+		}
+	}
+	recordedQuarantinesMu.Unlock()
+
+	for _, name := range lift {
+		if !pool.IsQuarantined(name) {
+			continue
+		}
+		if err := pool.Unquarantine(name); err != nil {
+			log.Printf("[Pool] %s: failed to lift quarantine: %v", name, err)
+		}
+	}
+}
+
+// isPoolSocketEntry returns true if a server entry connects to the pool socket of name
+func isPoolSocketEntry(entry interface{}, name string) bool {
+	server, ok := entry.(map[string]interface{})
+	if !ok || server["command"] != "nc" {
+		return false
+	}
+	args, ok := server["args"].([]interface{})
+	if !ok || len(args) != 2 || args[0] != "-U" {
+		return false
+	}
+	socketPath, _ := args[1].(string)
+	return filepath.Base(socketPath) == fmt.Sprintf("agentdeck-mcp-%s.sock", name)
+}
+
+// replacePoolSocketEntries swaps pool socket entries for name in an mcpServers
+// object. Returns true if anything changed.
+func replacePoolSocketEntries(servers interface{}, name string, stdio MCPServerConfig) bool {
+	m, ok := servers.(map[string]interface{})
+	if !ok || !isPoolSocketEntry(m[name], name) {
+		return false
+	}
+	m[name] = stdio
+	return true
+}
+
+// RewritePooledMCPToStdio switches every config entry that connects to the
+// pool socket of an MCP back to a stdio launch: .mcp.json in the given
+// project paths, Claude's .claude.json files (global and per-project), and
+// Gemini's settings.json. Returns the files that were changed.
+func RewritePooledMCPToStdio(name string, projectPaths []string) ([]string, error) {
+	def, ok := GetAvailableMCPs()[name]
+	if !ok {
+		return nil, fmt.Errorf("MCP %s not defined in config.toml", name)
+	}
+	stdio := stdioServerConfig(name, def)
+	geminiStdio := stdio
+	geminiStdio.Type = "" // Gemini's format has no "type" field
+
+	type target struct {
+		path     string
+		stdio    MCPServerConfig
+		projects bool // also rewrite projects[*].mcpServers (.claude.json)
+	}
+	var targets []target
+	for _, projectPath := range projectPaths {
+		targets = append(targets, target{path: filepath.Join(projectPath, ".mcp.json"), stdio: stdio})
+	}
+	targets = append(targets,
+		target{path: GetUserMCPRootPath(), stdio: stdio, projects: true},
+		target{path: filepath.Join(GetClaudeConfigDir(), ".claude.json"), stdio: stdio, projects: true},
+		target{path: filepath.Join(GetGeminiConfigDir(), "settings.json"), stdio: geminiStdio},
+	)
+
+	var rewritten []string
+	var firstErr error
+	seen := make(map[string]bool)
+	for _, t := range targets {
+		if seen[t.path] {
+			continue
+		}
+		seen[t.path] = true
+
+		changed, err := rewritePoolSocketFile(t.path, name, t.stdio, t.projects)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if changed {
+			rewritten = append(rewritten, t.path)
+		}
+	}
+	return rewritten, firstErr
+}
+
+// rewritePoolSocketFile rewrites one config file, preserving all other fields
+func rewritePoolSocketFile(path, name string, stdio MCPServerConfig, projects bool) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+	var rawConfig map[string]interface{}
+	if err := json.Unmarshal(data, &rawConfig); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	changed := replacePoolSocketEntries(rawConfig["mcpServers"], name, stdio)
+	if projects {
+		if projectMap, ok := rawConfig["projects"].(map[string]interface{}); ok {
+			for _, proj := range projectMap {
+				if p, ok := proj.(map[string]interface{}); ok {
+					if replacePoolSocketEntries(p["mcpServers"], name, stdio) {
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+
+	newData, err := json.MarshalIndent(rawConfig, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal %s: %w", path, err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, newData, info.Mode().Perm()); err != nil {
+		return false, fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return false, fmt.Errorf("failed to save %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// GetMCPPoolHealth returns pool health for every MCP in config.toml.
+// With a pool in this process (TUI) the monitor's last results are used;
+// otherwise (CLI) live pool sockets are probed directly.
+func GetMCPPoolHealth() []MCPHealth {
+	names := GetAvailableMCPNames()
+	result := make([]MCPHealth, 0, len(names))
+
+	if pool := GetGlobalPool(); pool != nil {
+		servers := make(map[string]mcppool.ProxyInfo)
+		for _, s := range pool.ListServers() {
+			servers[s.Name] = s
+		}
+		for _, name := range names {
+			h := MCPHealth{Name: name, Status: MCPPoolStatusNotPooled, Health: mcppool.HealthUnknown.String()}
+			if s, ok := servers[name]; ok {
+				h.Status = s.Status
+				h.Health = s.Health
+				h.LatencyMs = s.Latency.Milliseconds()
+				h.Error = s.LastError
+			} else if pool.ShouldPool(name) {
+				h.Status = MCPPoolStatusStopped
+			}
+			result = append(result, h)
+		}
+		return result
+	}
+
+	config, _ := LoadUserConfig()
+	poolEnabled := config != nil && config.MCPPool.Enabled
+	quarantined := LoadMCPQuarantine()
+	timeout := mcppool.DefaultHealthCheckTimeout
+	if config != nil && config.MCPPool.HealthCheckTimeout > 0 {
+		timeout = time.Duration(config.MCPPool.HealthCheckTimeout) * time.Second
+	}
+
+	result = make([]MCPHealth, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		result[i] = MCPHealth{Name: name, Status: MCPPoolStatusNotPooled, Health: mcppool.HealthUnknown.String()}
+		if q, ok := quarantined[name]; ok {
+			result[i].Status = MCPPoolStatusQuarantined
+			result[i].Error = fmt.Sprintf("%d crashes (%s) since %s", q.Crashes, q.Reason, q.Since.Format(time.RFC3339))
+		} else if socketPath := getExternalSocketPath(name); socketPath != "" {
+			result[i].Status = mcppool.StatusRunning.String()
+			wg.Add(1)
+			go func(h *MCPHealth, socketPath string) {
+				defer wg.Done()
+				latency, err := mcppool.PingSocket(socketPath, timeout)
+				if err != nil {
+					h.Health = mcppool.HealthUnresponsive.String()
+					h.Error = err.Error()
+					return
+				}
+				h.Health = mcppool.HealthHealthy.String()
+				h.LatencyMs = latency.Milliseconds()
+			}(&result[i], socketPath)
+		} else if poolEnabled {
+			result[i].Status = MCPPoolStatusStopped
+		}
+	}
+	wg.Wait()
+	return result
+}
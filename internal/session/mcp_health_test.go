@@ -0,0 +1,166 @@
+package session
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/mcppool"
+)
+
+func TestRewritePooledMCPToStdio(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", filepath.Join(home, ".claude"))
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(filepath.Join(home, ".agent-deck", "config.toml"), `
+[mcps.exa]
+command = "npx"
+args = ["-y", "exa-mcp-server"]
+`)
+
+	project := t.TempDir()
+	writeFile(filepath.Join(project, ".mcp.json"), `{
+  "mcpServers": {
+    "exa": {"command": "nc", "args": ["-U", "/tmp/agentdeck-mcp-exa.sock"]},
+    "fetch": {"command": "nc", "args": ["-U", "/tmp/agentdeck-mcp-fetch.sock"]}
+  }
+}`)
+	writeFile(filepath.Join(home, ".claude.json"), `{
+  "numStartups": 7,
+  "projects": {
+    "/work/app": {"mcpServers": {"exa": {"command": "nc", "args": ["-U", "/tmp/agentdeck-mcp-exa.sock"]}}}
+  }
+}`)
+
+	rewritten, err := RewritePooledMCPToStdio("exa", []string{project})
+	if err != nil {
+		t.Fatalf("RewritePooledMCPToStdio failed: %v", err)
+	}
+	if len(rewritten) != 2 {
+		t.Fatalf("expected .mcp.json and .claude.json rewritten, got %v", rewritten)
+	}
+
+	servers, err := ReadMCPServersFile(filepath.Join(project, ".mcp.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if servers["exa"].Command != "npx" {
+		t.Errorf("exa should use stdio, got %+v", servers["exa"])
+	}
+	if servers["fetch"].Command != "nc" {
+		t.Errorf("other pooled MCPs must be untouched, got %+v", servers["fetch"])
+	}
+
+	data, _ := os.ReadFile(filepath.Join(home, ".claude.json"))
+	var claude struct {
+		NumStartups int `json:"numStartups"`
+		Projects    map[string]struct {
+			MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &claude); err != nil {
+		t.Fatal(err)
+	}
+	if claude.NumStartups != 7 {
+		t.Error("unrelated .claude.json fields must be preserved")
+	}
+	if claude.Projects["/work/app"].MCPServers["exa"].Command != "npx" {
+		t.Errorf("project entry not rewritten: %+v", claude.Projects["/work/app"])
+	}
+
+	// Nothing left to rewrite
+	if rewritten, _ := RewritePooledMCPToStdio("exa", []string{project}); len(rewritten) != 0 {
+		t.Errorf("second rewrite changed %v", rewritten)
+	}
+}
+
+func TestHandleMCPQuarantine_RecordedForCLI(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	if err := os.MkdirAll(filepath.Join(home, ".agent-deck"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	config := `
+[mcps.flaky]
+command = "flaky-mcp"
+
+[mcp_pool]
+enabled = true
+pool_all = true
+`
+	if err := os.WriteFile(filepath.Join(home, ".agent-deck", "config.toml"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	handleMCPQuarantine(mcppool.QuarantineInfo{Name: "flaky", Since: time.Now(), Crashes: 3, Reason: "crash loop"})
+
+	records := LoadMCPQuarantine()
+	if rec, ok := records["flaky"]; !ok || rec.Crashes != 3 {
+		t.Fatalf("quarantine not recorded: %+v", records)
+	}
+
+	health := GetMCPPoolHealth()
+	if len(health) != 1 || health[0].Status != MCPPoolStatusQuarantined {
+		t.Errorf("GetMCPPoolHealth() = %+v, want flaky quarantined", health)
+	}
+
+	if err := saveMCPQuarantine(nil); err != nil {
+		t.Fatal(err)
+	}
+	if health := GetMCPPoolHealth(); health[0].Status != MCPPoolStatusStopped {
+		t.Errorf("without socket or quarantine, status = %q, want stopped", health[0].Status)
+	}
+}
+
+func TestMCPQuarantine_OwnedByPool(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+	t.Cleanup(func() { _ = releaseMCPQuarantine() })
+
+	handleMCPQuarantine(mcppool.QuarantineInfo{Name: "mine", Since: time.Now(), Crashes: 3, Reason: "crash loop"})
+	err := updateMCPQuarantine(func(records map[string]MCPQuarantineRecord) {
+		records["other-tui"] = MCPQuarantineRecord{Name: "other-tui", PID: os.Getppid()}
+		records["gone"] = MCPQuarantineRecord{Name: "gone", PID: 1 << 30}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new pool in this process clears its own and dead pools' records only
+	if err := pruneMCPQuarantine(); err != nil {
+		t.Fatal(err)
+	}
+	records := LoadMCPQuarantine()
+	if _, ok := records["other-tui"]; !ok || len(records) != 1 {
+		t.Errorf("after prune: %+v, want only other-tui", records)
+	}
+
+	if _, err := UnquarantineMCP("other-tui"); err != nil {
+		t.Fatalf("UnquarantineMCP: %v", err)
+	}
+	if _, err := UnquarantineMCP("other-tui"); err == nil {
+		t.Error("second UnquarantineMCP should fail")
+	}
+	if records := LoadMCPQuarantine(); len(records) != 0 {
+		t.Errorf("after unquarantine: %+v", records)
+	}
+}
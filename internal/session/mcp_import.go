@@ -0,0 +1,333 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// MCPImportSource is a config file that may contain MCP server definitions
+type MCPImportSource struct {
+	// Kind is "claude", "project", "gemini", or "cursor"
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+	// ProjectPath selects projects[path].mcpServers in a .claude.json file (optional)
+	ProjectPath string `json:"project_path,omitempty"`
+}
+
+// Import candidate statuses
+const (
+	MCPImportNew       = "new"       // Not in config.toml, will be added
+	MCPImportExists    = "exists"    // Same name and definition already in config.toml
+	MCPImportDuplicate = "duplicate" // Same command+args already defined under another name
+	MCPImportConflict  = "conflict"  // Same name with a different definition in config.toml
+)
+
+// MCPImportCandidate is an MCP found in an import source
+type MCPImportCandidate struct {
+	Name    string   `json:"name"`
+	Def     MCPDef   `json:"def"`
+	Sources []string `json:"sources"`
+	Status  string   `json:"status"`
+	// ExistingName is the config.toml entry this duplicates (for MCPImportDuplicate)
+	ExistingName string `json:"existing_name,omitempty"`
+	// Existing is the current config.toml definition (for MCPImportConflict)
+	Existing *MCPDef `json:"existing,omitempty"`
+}
+
+// DefaultMCPImportSources returns the standard locations scanned by
+// 'agent-deck mcp import'. Missing files are skipped by ScanMCPImportSources.
+func DefaultMCPImportSources(projectPath string) []MCPImportSource {
+	var sources []MCPImportSource
+	seen := make(map[string]bool)
+	add := func(kind, path, project string) {
+		key := path + "\x00" + project
+		if path == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		sources = append(sources, MCPImportSource{Kind: kind, Path: path, ProjectPath: project})
+	}
+
+	add("claude", GetUserMCPRootPath(), "")
+	add("claude", filepath.Join(GetClaudeConfigDir(), ".claude.json"), "")
+	if projectPath != "" {
+		add("claude", GetUserMCPRootPath(), projectPath)
+		add("claude", filepath.Join(GetClaudeConfigDir(), ".claude.json"), projectPath)
+		add("project", filepath.Join(projectPath, ".mcp.json"), "")
+		add("cursor", filepath.Join(projectPath, ".cursor", "mcp.json"), "")
+	}
+	add("gemini", filepath.Join(GetGeminiConfigDir(), "settings.json"), "")
+	if home, err := os.UserHomeDir(); err == nil {
+		add("cursor", filepath.Join(home, ".cursor", "mcp.json"), "")
+	}
+	return sources
+}
+
+// readMCPImportSource reads MCP server entries from a single source
+func readMCPImportSource(src MCPImportSource) (map[string]MCPServerConfig, error) {
+	if src.ProjectPath == "" {
+		return ReadMCPServersFile(src.Path)
+	}
+
+	data, err := os.ReadFile(src.Path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		Projects map[string]struct {
+			MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", src.Path, err)
+	}
+	return config.Projects[src.ProjectPath].MCPServers, nil
+}
+
+// isAgentDeckManagedServer returns true for entries agent-deck itself wrote:
+// pool socket connections and 'agent-deck mcp exec' wrappers
+func isAgentDeckManagedServer(server MCPServerConfig) bool {
+	if server.Command == "nc" && len(server.Args) == 2 && server.Args[0] == "-U" &&
+		strings.Contains(filepath.Base(server.Args[1]), "agentdeck-mcp-") {
+		return true
+	}
+	base := filepath.Base(server.Command)
+	return (base == "agent-deck" || base == "agent-deck.exe") &&
+		len(server.Args) >= 2 && server.Args[0] == "mcp" && server.Args[1] == "exec"
+}
+
+// mcpDefFromServerConfig converts a Claude/Gemini/Cursor entry to an MCPDef.
+// Env and header values are plain there, so ones that look like secret
+// references (DATABASE_URL = "file:./dev.db") are escaped with literal:.
+func mcpDefFromServerConfig(server MCPServerConfig) MCPDef {
+	def := MCPDef{
+		Command: server.Command,
+		Args:    server.Args,
+		Env:     escapeSecretRefs(server.Env),
+		Headers: escapeSecretRefs(server.Headers),
+	}
+	switch {
+	case server.HTTPURL != "":
+		// Gemini uses httpUrl for streamable HTTP servers
+		def.URL = server.HTTPURL
+		def.Transport = "http"
+	case server.URL != "":
+		def.URL = server.URL
+		def.Transport = server.Type
+		if def.Transport == "" || def.Transport == "stdio" {
+			// Gemini/Cursor use url without type for SSE servers
+			def.Transport = "sse"
+		}
+		if def.Transport == "streamable-http" {
+			def.Transport = "http"
+		}
+	}
+	if len(def.Env) == 0 {
+		def.Env = nil
+	}
+	if len(def.Headers) == 0 {
+		def.Headers = nil
+	}
+	if len(def.Args) == 0 {
+		def.Args = nil
+	}
+	return def
+}
+
+// mcpDedupeKey identifies an MCP by what it runs: URL for HTTP/SSE MCPs,
+// command plus args for stdio MCPs
+func mcpDedupeKey(def MCPDef) string {
+	if def.URL != "" {
+		return "url\x00" + def.URL
+	}
+	return "cmd\x00" + def.Command + "\x00" + strings.Join(def.Args, "\x00")
+}
+
+// sameMCPDef compares the parts of two definitions that affect how the MCP runs
+func sameMCPDef(a, b MCPDef) bool {
+	if mcpDedupeKey(a) != mcpDedupeKey(b) {
+		return false
+	}
+	if len(a.Env) != 0 || len(b.Env) != 0 {
+		if !reflect.DeepEqual(a.Env, b.Env) {
+			return false
+		}
+	}
+	if len(a.Headers) != 0 || len(b.Headers) != 0 {
+		if !reflect.DeepEqual(a.Headers, b.Headers) {
+			return false
+		}
+	}
+	return true
+}
+
+// ScanMCPImportSources reads all sources and classifies each discovered MCP
+// against the MCPs already defined in config.toml. Entries are deduplicated by
+// command+args (or URL); the first name seen wins. Unreadable sources are
+// returned as errors without stopping the scan; missing files are skipped.
+func ScanMCPImportSources(sources []MCPImportSource) ([]MCPImportCandidate, []error) {
+	existing := GetAvailableMCPs()
+	existingByKey := make(map[string]string, len(existing))
+	for _, name := range GetAvailableMCPNames() {
+		key := mcpDedupeKey(existing[name])
+		if _, ok := existingByKey[key]; !ok {
+			existingByKey[key] = name
+		}
+	}
+
+	var errs []error
+	var candidates []*MCPImportCandidate
+	byKey := make(map[string]*MCPImportCandidate)
+	byName := make(map[string]*MCPImportCandidate)
+
+	for _, src := range sources {
+		servers, err := readMCPImportSource(src)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+
+		label := src.Path
+		if src.ProjectPath != "" {
+			label = fmt.Sprintf("%s (project %s)", src.Path, src.ProjectPath)
+		}
+
+		names := make([]string, 0, len(servers))
+		for name := range servers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			server := servers[name]
+			if isAgentDeckManagedServer(server) {
+				continue
+			}
+			def := mcpDefFromServerConfig(server)
+			if def.Command == "" && def.URL == "" {
+				continue
+			}
+
+			key := mcpDedupeKey(def)
+			if c, ok := byKey[key]; ok {
+				c.Sources = appendIfMissing(c.Sources, label)
+				continue
+			}
+
+			// Same name, different command from another source: keep both
+			importName := name
+			if _, taken := byName[importName]; taken {
+				importName = fmt.Sprintf("%s-%s", name, src.Kind)
+			}
+			for n := 2; ; n++ {
+				if _, taken := byName[importName]; !taken {
+					break
+				}
+				importName = fmt.Sprintf("%s-%s-%d", name, src.Kind, n)
+			}
+
+			c := &MCPImportCandidate{Name: importName, Def: def, Sources: []string{label}}
+			if current, ok := existing[importName]; ok {
+				if sameMCPDef(current, def) {
+					c.Status = MCPImportExists
+				} else {
+					c.Status = MCPImportConflict
+					currentCopy := current
+					c.Existing = &currentCopy
+				}
+			} else if existingName, ok := existingByKey[key]; ok {
+				c.Status = MCPImportDuplicate
+				c.ExistingName = existingName
+			} else {
+				c.Status = MCPImportNew
+			}
+
+			byKey[key] = c
+			byName[importName] = c
+			candidates = append(candidates, c)
+		}
+	}
+
+	result := make([]MCPImportCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, errs
+}
+
+// ApplyMCPImports writes new candidates (and conflicts, if overwrite is set)
+// into config.toml's [mcps] via SaveUserConfig. Returns the imported names.
+func ApplyMCPImports(candidates []MCPImportCandidate, overwrite bool) ([]string, error) {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Create a copy to avoid modifying cached config
+	configCopy := *config
+	configCopy.MCPs = make(map[string]MCPDef, len(config.MCPs)+len(candidates))
+	for k, v := range config.MCPs {
+		configCopy.MCPs[k] = v
+	}
+
+	var imported []string
+	for _, c := range candidates {
+		if c.Status == MCPImportNew || (overwrite && c.Status == MCPImportConflict) {
+			configCopy.MCPs[c.Name] = c.Def
+			imported = append(imported, c.Name)
+		}
+	}
+	if len(imported) == 0 {
+		return nil, nil
+	}
+
+	if err := SaveUserConfig(&configCopy); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+	return imported, nil
+}
+
+// escapeSecretRefs returns a copy of values with EscapeSecretRef applied
+func escapeSecretRefs(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	escaped := make(map[string]string, len(values))
+	for k, v := range values {
+		escaped[k] = EscapeSecretRef(v)
+	}
+	return escaped
+}
+
+// ConvertLiteralSecretsToRefs replaces env values that look like plaintext
+// secrets with env:KEY references, so the imported definition reads the value
+// from the environment instead of storing it in config.toml.
+// Returns the keys that were converted.
+func (d *MCPDef) ConvertLiteralSecretsToRefs() []string {
+	var converted []string
+	if len(d.Env) == 0 {
+		return nil
+	}
+	env := make(map[string]string, len(d.Env))
+	for key, value := range d.Env {
+		if LooksLikeLiteralSecret(key, value) {
+			env[key] = SecretRefEnv + key
+			converted = append(converted, key)
+		} else {
+			env[key] = value
+		}
+	}
+	d.Env = env
+	sort.Strings(converted)
+	return converted
+}
@@ -0,0 +1,194 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setupMCPImportEnv creates an isolated HOME with config.toml and tool configs
+func setupMCPImportEnv(t *testing.T) (home, project string) {
+	t.Helper()
+	home = t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("CLAUDE_CONFIG_DIR", filepath.Join(home, ".claude"))
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(filepath.Join(home, ".agent-deck", "config.toml"), `
+[mcps.fetch]
+command = "uvx"
+args = ["mcp-server-fetch"]
+
+[mcps.github]
+command = "npx"
+args = ["-y", "@modelcontextprotocol/server-github"]
+`)
+
+	writeFile(filepath.Join(home, ".claude.json"), `{
+  "mcpServers": {
+    "exa": {"type": "stdio", "command": "npx", "args": ["-y", "exa-mcp-server"], "env": {"EXA_API_KEY": "abcdef1234567890"}},
+    "web-fetch": {"command": "uvx", "args": ["mcp-server-fetch"]},
+    "pooled": {"command": "nc", "args": ["-U", "/tmp/agentdeck-mcp-pooled.sock"]}
+  }
+}`)
+
+	writeFile(filepath.Join(home, ".gemini", "settings.json"), `{
+  "theme": "dark",
+  "mcpServers": {
+    "exa": {"command": "npx", "args": ["-y", "exa-mcp-server"]},
+    "docs": {"httpUrl": "https://docs.example.com/mcp"}
+  }
+}`)
+
+	project = t.TempDir()
+	writeFile(filepath.Join(project, ".cursor", "mcp.json"), `{
+  "mcpServers": {
+    "github": {"command": "docker", "args": ["run", "ghcr.io/github/github-mcp-server"]},
+    "events": {"url": "https://events.example.com/sse"}
+  }
+}`)
+	return home, project
+}
+
+func TestScanMCPImportSources(t *testing.T) {
+	_, project := setupMCPImportEnv(t)
+
+	candidates, errs := ScanMCPImportSources(DefaultMCPImportSources(project))
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scan errors: %v", errs)
+	}
+
+	byName := make(map[string]MCPImportCandidate)
+	for _, c := range candidates {
+		byName[c.Name] = c
+	}
+
+	if _, ok := byName["pooled"]; ok {
+		t.Error("agent-deck pool socket entries must be skipped")
+	}
+
+	exa, ok := byName["exa"]
+	if !ok || exa.Status != MCPImportNew {
+		t.Fatalf("exa should be new, got %+v", exa)
+	}
+	if len(exa.Sources) != 2 {
+		t.Errorf("exa should be deduplicated across claude and gemini, sources=%v", exa.Sources)
+	}
+
+	if c := byName["web-fetch"]; c.Status != MCPImportDuplicate || c.ExistingName != "fetch" {
+		t.Errorf("web-fetch should duplicate [mcps.fetch], got %+v", c)
+	}
+
+	if c := byName["github"]; c.Status != MCPImportConflict || c.Existing == nil {
+		t.Errorf("github should conflict with existing definition, got %+v", c)
+	}
+
+	if c := byName["docs"]; c.Def.URL != "https://docs.example.com/mcp" || c.Def.Transport != "http" {
+		t.Errorf("gemini httpUrl should import as http transport, got %+v", c.Def)
+	}
+	if c := byName["events"]; c.Def.Transport != "sse" {
+		t.Errorf("url without type should import as sse, got %+v", c.Def)
+	}
+}
+
+func TestScanMCPImportSources_RenamesUntilFree(t *testing.T) {
+	setupMCPImportEnv(t)
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	first := write("first.json", `{"mcpServers": {
+  "lint": {"command": "lint-a"},
+  "lint-cursor": {"command": "lint-b"}
+}}`)
+	second := write("second.json", `{"mcpServers": {"lint": {"command": "lint-c"}}}`)
+	third := write("third.json", `{"mcpServers": {"lint": {"command": "lint-d"}}}`)
+
+	candidates, errs := ScanMCPImportSources([]MCPImportSource{
+		{Kind: "cursor", Path: first},
+		{Kind: "cursor", Path: second},
+		{Kind: "cursor", Path: third},
+	})
+	if len(errs) != 0 {
+		t.Fatalf("unexpected scan errors: %v", errs)
+	}
+
+	commands := make(map[string]string)
+	for _, c := range candidates {
+		if _, dup := commands[c.Name]; dup {
+			t.Fatalf("name %q imported twice", c.Name)
+		}
+		commands[c.Name] = c.Def.Command
+	}
+	want := map[string]string{"lint": "lint-a", "lint-cursor": "lint-b", "lint-cursor-2": "lint-c", "lint-cursor-3": "lint-d"}
+	for name, command := range want {
+		if commands[name] != command {
+			t.Errorf("%s = %q, want %q (all: %v)", name, commands[name], command, commands)
+		}
+	}
+}
+
+func TestApplyMCPImports(t *testing.T) {
+	_, project := setupMCPImportEnv(t)
+
+	candidates, _ := ScanMCPImportSources(DefaultMCPImportSources(project))
+	for i := range candidates {
+		if candidates[i].Name == "exa" {
+			if keys := candidates[i].Def.ConvertLiteralSecretsToRefs(); len(keys) != 1 || keys[0] != "EXA_API_KEY" {
+				t.Fatalf("ConvertLiteralSecretsToRefs() = %v", keys)
+			}
+		}
+	}
+
+	imported, err := ApplyMCPImports(candidates, false)
+	if err != nil {
+		t.Fatalf("ApplyMCPImports failed: %v", err)
+	}
+	if len(imported) != 3 {
+		t.Errorf("expected exa, docs, events imported, got %v", imported)
+	}
+
+	config, err := ReloadUserConfig()
+	if err != nil {
+		t.Fatalf("ReloadUserConfig failed: %v", err)
+	}
+	if got := config.MCPs["exa"].Env["EXA_API_KEY"]; got != "env:EXA_API_KEY" {
+		t.Errorf("exa env = %q, want env:EXA_API_KEY", got)
+	}
+	if config.MCPs["github"].Command != "npx" {
+		t.Error("conflicting entry must not be overwritten without overwrite=true")
+	}
+
+	// Re-scanning now reports everything as already present
+	candidates, _ = ScanMCPImportSources(DefaultMCPImportSources(project))
+	for _, c := range candidates {
+		if c.Status == MCPImportNew {
+			t.Errorf("%s still reported as new after import", c.Name)
+		}
+	}
+
+	imported, err = ApplyMCPImports(candidates, true)
+	if err != nil {
+		t.Fatalf("ApplyMCPImports(overwrite) failed: %v", err)
+	}
+	config, _ = ReloadUserConfig()
+	if config.MCPs["github"].Command != "docker" {
+		t.Errorf("github should be overwritten, imported=%v", imported)
+	}
+}
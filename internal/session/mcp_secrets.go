@@ -0,0 +1,425 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Secret reference prefixes accepted in MCPDef.Env and MCPDef.Headers values.
+// References are resolved at launch time and never written to generated files.
+//
+//	env:EXA_API_KEY           value of an environment variable
+//	file:~/.secrets/exa       contents of a file (trailing newline trimmed)
+//	cmd:pass show exa         stdout of a shell command (trailing newline trimmed)
+//
+// A plain value that happens to start with one of these prefixes is written
+// with a literal: prefix, which is stripped and the rest used as is:
+//
+//	literal:file:./dev.db     the value "file:./dev.db"
+const (
+	SecretRefEnv     = "env:"
+	SecretRefFile    = "file:"
+	SecretRefCmd     = "cmd:"
+	SecretRefLiteral = "literal:"
+)
+
+// secretCmdTimeout bounds how long a cmd: reference may take (e.g. a password manager prompt)
+const secretCmdTimeout = 30 * time.Second
+
+// IsSecretRef returns true if value is an env:, file: or cmd: secret reference
+func IsSecretRef(value string) bool {
+	return strings.HasPrefix(value, SecretRefEnv) ||
+		strings.HasPrefix(value, SecretRefFile) ||
+		strings.HasPrefix(value, SecretRefCmd)
+}
+
+// EscapeSecretRef returns value with a literal: prefix if it would otherwise
+// be read as a secret reference (or as an escaped value), so that plain
+// values taken from other tools' configs are kept as they are
+func EscapeSecretRef(value string) string {
+	if IsSecretRef(value) || strings.HasPrefix(value, SecretRefLiteral) {
+		return SecretRefLiteral + value
+	}
+	return value
+}
+
+// literalValue strips the literal: escape from a value that is not a reference
+func literalValue(value string) string {
+	return strings.TrimPrefix(value, SecretRefLiteral)
+}
+
+// ResolveSecretRef resolves a secret reference to its value.
+// literal: values are unescaped; other values are returned unchanged.
+func ResolveSecretRef(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, SecretRefLiteral):
+		return literalValue(value), nil
+
+	case strings.HasPrefix(value, SecretRefEnv):
+		name := strings.TrimPrefix(value, SecretRefEnv)
+		if name == "" {
+			return "", fmt.Errorf("empty variable name in %q", value)
+		}
+		resolved, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return resolved, nil
+
+	case strings.HasPrefix(value, SecretRefFile):
+		path := strings.TrimPrefix(value, SecretRefFile)
+		if strings.HasPrefix(path, "~/") {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to get home directory: %w", err)
+			}
+			path = filepath.Join(homeDir, path[2:])
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+
+	case strings.HasPrefix(value, SecretRefCmd):
+		command := strings.TrimPrefix(value, SecretRefCmd)
+		if strings.TrimSpace(command) == "" {
+			return "", fmt.Errorf("empty command in %q", value)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), secretCmdTimeout)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			// Don't include output in the error - it may contain the secret
+			return "", fmt.Errorf("secret command %q failed: %w", command, err)
+		}
+		return strings.TrimRight(string(out), "\r\n"), nil
+	}
+	return value, nil
+}
+
+// resolveSecretMap resolves every secret reference in a map.
+// Returns a new map; the input is not modified.
+func resolveSecretMap(values map[string]string) (map[string]string, error) {
+	if values == nil {
+		return nil, nil
+	}
+	resolved := make(map[string]string, len(values))
+	for key, value := range values {
+		v, err := ResolveSecretRef(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		resolved[key] = v
+	}
+	return resolved, nil
+}
+
+// HasSecretRefs returns true if any Env or Headers value is a secret reference
+func (d MCPDef) HasSecretRefs() bool {
+	for _, v := range d.Env {
+		if IsSecretRef(v) {
+			return true
+		}
+	}
+	for _, v := range d.Headers {
+		if IsSecretRef(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolvedEnv returns Env with all secret references resolved
+func (d MCPDef) ResolvedEnv() (map[string]string, error) {
+	return resolveSecretMap(d.Env)
+}
+
+// ResolvedHeaders returns Headers with all secret references resolved
+func (d MCPDef) ResolvedHeaders() (map[string]string, error) {
+	return resolveSecretMap(d.Headers)
+}
+
+// stdioServerConfig builds the stdio entry for a generated MCP config file.
+// MCPs whose env contains secret references are launched through
+// "agent-deck mcp exec <name>", which resolves the references at launch time,
+// so resolved secrets never land in .mcp.json or settings.json.
+func stdioServerConfig(name string, def MCPDef) MCPServerConfig {
+	for _, v := range def.Env {
+		if IsSecretRef(v) {
+			log.Printf("[MCP] %s: env has secret references, launching via agent-deck mcp exec", name)
+			return MCPServerConfig{
+				Type:    "stdio",
+				Command: agentDeckExecutable(),
+				Args:    []string{"mcp", "exec", name},
+				Env:     map[string]string{},
+			}
+		}
+	}
+
+	args := def.Args
+	if args == nil {
+		args = []string{}
+	}
+	env := make(map[string]string, len(def.Env))
+	for k, v := range def.Env {
+		env[k] = literalValue(v)
+	}
+	return MCPServerConfig{
+		Type:    "stdio",
+		Command: def.Command,
+		Args:    args,
+		Env:     env,
+	}
+}
+
+// httpServerHeaders converts configured headers for a generated MCP config file.
+// env: references become ${VAR} expansions resolved by the agent at startup;
+// file: and cmd: references cannot be expressed without writing the secret,
+// so those headers are omitted.
+func httpServerHeaders(name string, def MCPDef) map[string]string {
+	if len(def.Headers) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(def.Headers))
+	for key, value := range def.Headers {
+		switch {
+		case strings.HasPrefix(value, SecretRefEnv):
+			headers[key] = "${" + strings.TrimPrefix(value, SecretRefEnv) + "}"
+		case IsSecretRef(value):
+			log.Printf("[MCP] %s: header %s uses a file:/cmd: reference, not written to config", name, key)
+		default:
+			headers[key] = literalValue(value)
+		}
+	}
+	return headers
+}
+
+// agentDeckExecutable returns the path of the running agent-deck binary,
+// falling back to "agent-deck" on PATH
+func agentDeckExecutable() string {
+	if path, err := os.Executable(); err == nil {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		// Only the CLI binary implements "mcp exec" (not the desktop app or tests)
+		if base := filepath.Base(path); base == "agent-deck" || base == "agent-deck.exe" {
+			return path
+		}
+	}
+	return "agent-deck"
+}
+
+// secretKeyPattern matches env/header names that usually hold credentials
+var secretKeyPattern = regexp.MustCompile(`(?i)(api[_-]?key|token|secret|password|passwd|auth|credential|private[_-]?key)`)
+
+// secretValuePattern matches well-known credential formats regardless of key name
+var secretValuePattern = regexp.MustCompile(`^(sk-[A-Za-z0-9_-]{16,}|ghp_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}|xox[abpr]-[A-Za-z0-9-]{10,}|AKIA[0-9A-Z]{16}|AIza[0-9A-Za-z_-]{30,}|Bearer\s+\S{12,})$`)
+
+// LooksLikeLiteralSecret returns true if a key/value pair looks like a plaintext
+// credential: a secret-sounding key with a literal value, or a value in a
+// well-known token format. References and ${VAR} expansions are not secrets.
+func LooksLikeLiteralSecret(key, value string) bool {
+	if value == "" || IsSecretRef(value) || strings.Contains(value, "${") {
+		return false
+	}
+	if secretValuePattern.MatchString(value) {
+		return true
+	}
+	return secretKeyPattern.MatchString(key) && len(value) >= 8
+}
+
+// SecretFinding describes a literal secret found in config or a generated file
+type SecretFinding struct {
+	File    string `json:"file"`
+	MCP     string `json:"mcp"`
+	Field   string `json:"field"` // "env" or "headers"
+	Key     string `json:"key"`
+	Project string `json:"project,omitempty"` // Set for .claude.json projects[path] entries
+	Ref     string `json:"ref,omitempty"`     // Reference that replaced the value (MaskLiteralSecrets)
+}
+
+// FindLiteralSecretsInConfig reports literal secrets in [mcps] Env/Headers.
+// These should be replaced with env:/file:/cmd: references.
+func FindLiteralSecretsInConfig() []SecretFinding {
+	configPath, _ := GetUserConfigPath()
+	var findings []SecretFinding
+	for name, def := range GetAvailableMCPs() {
+		findings = append(findings, findLiteralSecrets(configPath, name, def.Env, def.Headers)...)
+	}
+	sortSecretFindings(findings)
+	return findings
+}
+
+// FindLiteralSecretsInMCPFile scans a generated MCP config file (.mcp.json,
+// .claude.json or Gemini settings.json) for literal secrets in mcpServers
+// entries, including per-project entries in .claude.json
+func FindLiteralSecretsInMCPFile(path string) ([]SecretFinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var config struct {
+		MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+		Projects   map[string]struct {
+			MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	var findings []SecretFinding
+	for name, server := range config.MCPServers {
+		findings = append(findings, findLiteralSecrets(path, name, server.Env, server.Headers)...)
+	}
+	for projectPath, proj := range config.Projects {
+		for name, server := range proj.MCPServers {
+			for _, f := range findLiteralSecrets(path, name, server.Env, server.Headers) {
+				f.Project = projectPath
+				findings = append(findings, f)
+			}
+		}
+	}
+	sortSecretFindings(findings)
+	return findings, nil
+}
+
+// SecretRefProblem describes a secret reference in config.toml that cannot be resolved
+type SecretRefProblem struct {
+	MCP   string `json:"mcp"`
+	Field string `json:"field"`
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// CheckSecretRefs verifies that env: and file: references in [mcps] resolve.
+// cmd: references are not executed here since they may prompt for input.
+func CheckSecretRefs() []SecretRefProblem {
+	var problems []SecretRefProblem
+	check := func(mcpName, field string, values map[string]string) {
+		for key, value := range values {
+			if !strings.HasPrefix(value, SecretRefEnv) && !strings.HasPrefix(value, SecretRefFile) {
+				continue
+			}
+			if _, err := ResolveSecretRef(value); err != nil {
+				problems = append(problems, SecretRefProblem{MCP: mcpName, Field: field, Key: key, Error: err.Error()})
+			}
+		}
+	}
+	for name, def := range GetAvailableMCPs() {
+		check(name, "env", def.Env)
+		check(name, "headers", def.Headers)
+	}
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].MCP != problems[j].MCP {
+			return problems[i].MCP < problems[j].MCP
+		}
+		return problems[i].Key < problems[j].Key
+	})
+	return problems
+}
+
+func findLiteralSecrets(file, mcpName string, env, headers map[string]string) []SecretFinding {
+	var findings []SecretFinding
+	for key, value := range env {
+		if LooksLikeLiteralSecret(key, value) {
+			findings = append(findings, SecretFinding{File: file, MCP: mcpName, Field: "env", Key: key})
+		}
+	}
+	for key, value := range headers {
+		if LooksLikeLiteralSecret(key, value) {
+			findings = append(findings, SecretFinding{File: file, MCP: mcpName, Field: "headers", Key: key})
+		}
+	}
+	return findings
+}
+
+// MaskLiteralSecrets returns a copy of mcps with literal secrets in Env and
+// Headers replaced by env: references, for config that leaves this machine
+// (profile bundles, sync repos). Existing references are kept. Env values
+// refer to a variable of the same name; header values to <MCP>_<HEADER>,
+// e.g. GITHUB_AUTHORIZATION. Returns the masked keys with their references.
+func MaskLiteralSecrets(file string, mcps map[string]MCPDef) (map[string]MCPDef, []SecretFinding) {
+	masked := make(map[string]MCPDef, len(mcps))
+	var findings []SecretFinding
+	for name, def := range mcps {
+		found := findLiteralSecrets(file, name, def.Env, def.Headers)
+		if len(found) == 0 {
+			masked[name] = def
+			continue
+		}
+		env := make(map[string]string, len(def.Env))
+		for k, v := range def.Env {
+			env[k] = v
+		}
+		headers := make(map[string]string, len(def.Headers))
+		for k, v := range def.Headers {
+			headers[k] = v
+		}
+		for i, f := range found {
+			if f.Field == "env" {
+				found[i].Ref = SecretRefEnv + f.Key
+				env[f.Key] = found[i].Ref
+			} else {
+				found[i].Ref = SecretRefEnv + secretEnvName(name+"_"+f.Key)
+				headers[f.Key] = found[i].Ref
+			}
+		}
+		if def.Env != nil {
+			def.Env = env
+		}
+		if def.Headers != nil {
+			def.Headers = headers
+		}
+		masked[name] = def
+		findings = append(findings, found...)
+	}
+	sortSecretFindings(findings)
+	return masked, findings
+}
+
+// secretEnvName turns s into an environment variable name: upper case, with
+// anything but letters and digits replaced by _
+func secretEnvName(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
+
+func sortSecretFindings(findings []SecretFinding) {
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.Project != b.Project {
+			return a.Project < b.Project
+		}
+		if a.MCP != b.MCP {
+			return a.MCP < b.MCP
+		}
+		if a.Field != b.Field {
+			return a.Field < b.Field
+		}
+		return a.Key < b.Key
+	})
+}
@@ -0,0 +1,273 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecretRef(t *testing.T) {
+	t.Setenv("AGENTDECK_TEST_SECRET", "from-env")
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("from-file\n"), 0600); err != nil {
+		t.Fatalf("Failed to write secret file: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"literal passthrough", "plain-value", "plain-value", false},
+		{"env ref", "env:AGENTDECK_TEST_SECRET", "from-env", false},
+		{"env ref unset", "env:AGENTDECK_TEST_SECRET_UNSET", "", true},
+		{"file ref trims newline", "file:" + secretFile, "from-file", false},
+		{"file ref missing", "file:" + secretFile + ".missing", "", true},
+		{"cmd ref", "cmd:printf 'from-cmd\\n'", "from-cmd", false},
+		{"cmd ref failure", "cmd:exit 3", "", true},
+		{"literal escape", "literal:file:./dev.db", "file:./dev.db", false},
+		{"literal escape of literal", "literal:literal:x", "literal:x", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveSecretRef(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveSecretRef(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveSecretRef(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveSecretRef_FileTildeExpansion(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".secrets"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".secrets", "exa"), []byte("tilde-secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ResolveSecretRef("file:~/.secrets/exa")
+	if err != nil {
+		t.Fatalf("ResolveSecretRef failed: %v", err)
+	}
+	if got != "tilde-secret" {
+		t.Errorf("got %q, want tilde-secret", got)
+	}
+}
+
+func TestLooksLikeLiteralSecret(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       bool
+	}{
+		{"EXA_API_KEY", "abcdef1234567890", true},
+		{"GITHUB_TOKEN", "ghp_aaaaaaaaaaaaaaaaaaaaaaaa", true},
+		{"SOMETHING", "sk-abcdefghijklmnopqrstu", true},
+		{"Authorization", "Bearer abcdefghijklmnop", true},
+		{"EXA_API_KEY", "env:EXA_API_KEY", false},
+		{"EXA_API_KEY", "${EXA_API_KEY}", false},
+		{"Authorization", "Bearer ${TOKEN_WITH_LONG_NAME}", false},
+		{"LOG_LEVEL", "debug-verbose", false},
+		{"API_KEY", "short", false},
+		{"API_KEY", "", false},
+	}
+	for _, tt := range tests {
+		if got := LooksLikeLiteralSecret(tt.key, tt.value); got != tt.want {
+			t.Errorf("LooksLikeLiteralSecret(%q, %q) = %v, want %v", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestMaskLiteralSecrets(t *testing.T) {
+	mcps := map[string]MCPDef{
+		"exa": {Command: "npx", Env: map[string]string{
+			"EXA_API_KEY": "abcdef1234567890",
+			"LOG_LEVEL":   "debug",
+			"OTHER_TOKEN": "env:OTHER_TOKEN",
+		}},
+		"gh-http": {URL: "https://example.com/mcp", Headers: map[string]string{"Authorization": "Bearer abcdefghijklmnop"}},
+		"plain":   {Command: "node"},
+	}
+
+	masked, findings := MaskLiteralSecrets("config.toml", mcps)
+	if got := masked["exa"].Env; got["EXA_API_KEY"] != "env:EXA_API_KEY" || got["LOG_LEVEL"] != "debug" || got["OTHER_TOKEN"] != "env:OTHER_TOKEN" {
+		t.Errorf("exa env = %v", got)
+	}
+	if got := masked["gh-http"].Headers["Authorization"]; got != "env:GH_HTTP_AUTHORIZATION" {
+		t.Errorf("header = %q, want env:GH_HTTP_AUTHORIZATION", got)
+	}
+	if mcps["exa"].Env["EXA_API_KEY"] != "abcdef1234567890" {
+		t.Error("MaskLiteralSecrets modified its input")
+	}
+	if len(findings) != 2 || findings[0].MCP != "exa" || findings[0].Ref != "env:EXA_API_KEY" ||
+		findings[1].Field != "headers" || findings[1].Key != "Authorization" {
+		t.Errorf("findings = %+v", findings)
+	}
+}
+
+func TestStdioServerConfig_SecretRefsUseExecWrapper(t *testing.T) {
+	def := MCPDef{
+		Command: "npx",
+		Args:    []string{"-y", "exa-mcp-server"},
+		Env:     map[string]string{"EXA_API_KEY": "cmd:pass show exa"},
+	}
+
+	cfg := stdioServerConfig("exa", def)
+	if cfg.Command == "npx" {
+		t.Fatal("MCP with secret refs must not be launched directly")
+	}
+	if strings.Join(cfg.Args, " ") != "mcp exec exa" {
+		t.Errorf("Args = %v, want [mcp exec exa]", cfg.Args)
+	}
+	if len(cfg.Env) != 0 {
+		t.Errorf("Env must be empty, got %v", cfg.Env)
+	}
+
+	// Literal env is written as before
+	def.Env = map[string]string{"LOG_LEVEL": "debug"}
+	cfg = stdioServerConfig("exa", def)
+	if cfg.Command != "npx" || cfg.Env["LOG_LEVEL"] != "debug" {
+		t.Errorf("literal env config changed unexpectedly: %+v", cfg)
+	}
+
+	// Escaped values are plain: launched directly, with the escape removed
+	def.Env = map[string]string{"DATABASE_URL": "literal:file:./dev.db"}
+	cfg = stdioServerConfig("exa", def)
+	if cfg.Command != "npx" || cfg.Env["DATABASE_URL"] != "file:./dev.db" {
+		t.Errorf("escaped env = %+v, want file:./dev.db passed through", cfg)
+	}
+}
+
+func TestEscapeSecretRef(t *testing.T) {
+	for value, want := range map[string]string{
+		"debug":         "debug",
+		"file:./dev.db": "literal:file:./dev.db",
+		"env:prod":      "literal:env:prod",
+		"literal:x":     "literal:literal:x",
+	} {
+		escaped := EscapeSecretRef(value)
+		if escaped != want {
+			t.Errorf("EscapeSecretRef(%q) = %q, want %q", value, escaped, want)
+		}
+		if got, err := ResolveSecretRef(escaped); err != nil || got != value {
+			t.Errorf("ResolveSecretRef(%q) = %q, %v, want %q", escaped, got, err, value)
+		}
+	}
+
+	// Values imported from other tools' configs are plain, not references
+	def := mcpDefFromServerConfig(MCPServerConfig{Command: "prisma-mcp", Env: map[string]string{"DATABASE_URL": "file:./dev.db"}})
+	if def.Env["DATABASE_URL"] != "literal:file:./dev.db" || def.HasSecretRefs() {
+		t.Errorf("imported env = %v, want the value escaped", def.Env)
+	}
+}
+
+func TestHTTPServerHeaders(t *testing.T) {
+	def := MCPDef{
+		URL: "https://example.com/mcp",
+		Headers: map[string]string{
+			"Authorization": "env:API_TOKEN",
+			"X-Secret":      "file:~/.secrets/x",
+			"X-Client":      "agent-deck",
+		},
+	}
+
+	headers := httpServerHeaders("api", def)
+	if headers["Authorization"] != "${API_TOKEN}" {
+		t.Errorf("env ref header = %q, want ${API_TOKEN}", headers["Authorization"])
+	}
+	if _, ok := headers["X-Secret"]; ok {
+		t.Error("file: header must not be written")
+	}
+	if headers["X-Client"] != "agent-deck" {
+		t.Errorf("literal header = %q", headers["X-Client"])
+	}
+}
+
+func TestWriteMCPJsonFromConfig_SecretNotWritten(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	t.Setenv("AGENTDECK_TEST_EXA_KEY", "super-secret-value-123")
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	agentDeckDir := filepath.Join(tempDir, ".agent-deck")
+	if err := os.MkdirAll(agentDeckDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	configContent := `
+[mcps.exa]
+command = "npx"
+args = ["-y", "exa-mcp-server"]
+env = { EXA_API_KEY = "env:AGENTDECK_TEST_EXA_KEY" }
+`
+	if err := os.WriteFile(filepath.Join(agentDeckDir, "config.toml"), []byte(configContent), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	projectPath := t.TempDir()
+	if err := WriteMCPJsonFromConfig(projectPath, []string{"exa"}); err != nil {
+		t.Fatalf("WriteMCPJsonFromConfig failed: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectPath, ".mcp.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "super-secret-value-123") {
+		t.Error(".mcp.json contains the resolved secret")
+	}
+	if strings.Contains(string(data), "AGENTDECK_TEST_EXA_KEY") {
+		t.Error(".mcp.json should not reference the secret source either")
+	}
+
+	findings, err := FindLiteralSecretsInMCPFile(filepath.Join(projectPath, ".mcp.json"))
+	if err != nil {
+		t.Fatalf("FindLiteralSecretsInMCPFile failed: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Errorf("expected no findings, got %+v", findings)
+	}
+}
+
+func TestFindLiteralSecretsInMCPFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".claude.json")
+	content := `{
+  "mcpServers": {
+    "github": {"command": "npx", "env": {"GITHUB_TOKEN": "ghp_aaaaaaaaaaaaaaaaaaaaaaaa"}},
+    "fetch": {"command": "uvx", "env": {"LOG_LEVEL": "info"}}
+  },
+  "projects": {
+    "/work/app": {
+      "mcpServers": {
+        "exa": {"command": "npx", "env": {"EXA_API_KEY": "abcdef1234567890"}}
+      }
+    }
+  }
+}`
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := FindLiteralSecretsInMCPFile(path)
+	if err != nil {
+		t.Fatalf("FindLiteralSecretsInMCPFile failed: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", findings)
+	}
+	if findings[0].MCP != "github" || findings[0].Key != "GITHUB_TOKEN" || findings[0].Project != "" {
+		t.Errorf("unexpected first finding: %+v", findings[0])
+	}
+	if findings[1].MCP != "exa" || findings[1].Project != "/work/app" {
+		t.Errorf("unexpected project finding: %+v", findings[1])
+	}
+}
@@ -0,0 +1,348 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// MigrateLocal is the MigrateOptions.To value for this machine
+const MigrateLocal = "local"
+
+// MigrateOptions controls MigrateSession
+type MigrateOptions struct {
+	// To is an SSH host ID from config.toml, or MigrateLocal
+	To string
+	// ProjectPath on the target; "" keeps the source's project path
+	ProjectPath string
+	// Title of the new session; "" means "<title>@<target>"
+	Title string
+	// Git pushes the current branch from the source and checks it out on the
+	// target (cloning if needed) instead of copying the working tree
+	Git bool
+	// Exclude is passed to the tree sync; nil copies everything, .git included
+	Exclude []string
+}
+
+// MigrateResult describes a migration prepared by MigrateSession
+type MigrateResult struct {
+	// Instance is the new session, not started yet
+	Instance    *Instance          `json:"-"`
+	From        string             `json:"from"`
+	To          string             `json:"to"`
+	ProjectPath string             `json:"project_path"`
+	Sync        *sshpkg.SyncResult `json:"sync,omitempty"`
+	Branch      string             `json:"branch,omitempty"`
+	// Conversation is the Claude JSONL file written on the target ("" if
+	// the session had no conversation to carry over)
+	Conversation string `json:"conversation,omitempty"`
+
+	src        *Instance
+	opts       MigrateOptions
+	from       migrateHost
+	target     migrateHost
+	srcPath    string
+	targetPath string
+}
+
+// MigrateSession moves a session's work between this machine and an SSH
+// host: it syncs the project working tree (or pushes the git branch),
+// copies the Claude conversation into the target's Claude config dir, and
+// returns a new session on the target set up to resume the same Claude
+// session ID. src may keep running during this first copy; the caller then
+// stops src, calls Resync to pick up what it wrote meanwhile, and only then
+// saves and starts the new session. Migrating between two remote hosts is
+// not supported.
+func MigrateSession(src *Instance, opts MigrateOptions) (*MigrateResult, error) {
+	to := strings.TrimSpace(opts.To)
+	if to == "" {
+		return nil, errors.New("no migration target (host ID or 'local')")
+	}
+	if to == MigrateLocal {
+		to = ""
+	}
+	if to == src.RemoteHost {
+		return nil, fmt.Errorf("session '%s' is already on %s", src.Title, migrateHostName(to))
+	}
+	if to != "" && src.IsRemote() {
+		return nil, fmt.Errorf("cannot migrate between two remote hosts (%s -> %s); migrate to local first", src.RemoteHost, to)
+	}
+	if to != "" && GetSSHHostDef(to) == nil {
+		return nil, fmt.Errorf("SSH host '%s' not found in config.toml", to)
+	}
+
+	from, err := newMigrateHost(src.RemoteHost)
+	if err != nil {
+		return nil, err
+	}
+	target, err := newMigrateHost(to)
+	if err != nil {
+		return nil, err
+	}
+	srcPath, err := from.absPath(src.ProjectPath)
+	if err != nil {
+		return nil, err
+	}
+	targetPath := opts.ProjectPath
+	if targetPath == "" {
+		targetPath = src.ProjectPath
+	}
+	if targetPath, err = target.absPath(targetPath); err != nil {
+		return nil, err
+	}
+
+	result := &MigrateResult{
+		From: from.name(), To: target.name(), ProjectPath: targetPath,
+		src: src, opts: opts, from: from, target: target, srcPath: srcPath, targetPath: targetPath,
+	}
+	if err := result.copyWork(); err != nil {
+		return nil, err
+	}
+	resume := result.Conversation != ""
+
+	// New session on the target
+	title := opts.Title
+	if title == "" {
+		title = src.Title + "@" + target.name()
+	}
+	var inst *Instance
+	if target.conn != nil {
+		if inst, err = NewRemoteInstanceWithGroup(title, targetPath, src.GroupPath, src.Tool, to); err != nil {
+			return nil, err
+		}
+	} else {
+		inst = NewInstanceWithGroupAndTool(title, targetPath, src.GroupPath, src.Tool)
+	}
+	inst.Command = src.Command
+	inst.ToolOptionsJSON = src.ToolOptionsJSON
+	if src.Tool == "claude" && src.ClaudeSessionID != "" {
+		inst.ClaudeSessionID = src.ClaudeSessionID
+		inst.Command = inst.claudeResumeCommand(resume)
+	}
+	result.Instance = inst
+	return result, nil
+}
+
+// Resync copies the working tree (or pushes the branch) and the Claude
+// conversation again. Call it after stopping the source session so the turns
+// and edits it made since MigrateSession aren't lost; only changed files are
+// sent.
+func (r *MigrateResult) Resync() error {
+	return r.copyWork()
+}
+
+// copyWork copies the project and conversation from the source to the target
+func (r *MigrateResult) copyWork() error {
+	// The tree is always copied between this machine and the remote side
+	conn := r.from.conn
+	if conn == nil {
+		conn = r.target.conn
+	}
+	copyPath := func(src, dst string, opts sshpkg.SyncOptions) (*sshpkg.SyncResult, error) {
+		if r.from.conn == nil {
+			return conn.Push(src, dst, opts)
+		}
+		return conn.Pull(src, dst, opts)
+	}
+
+	// Working tree
+	if r.opts.Git {
+		branch, err := migrateGitBranch(r.from, r.target, r.srcPath, r.targetPath)
+		if err != nil {
+			return err
+		}
+		r.Branch = branch
+	} else {
+		syncOpts := sshpkg.SyncOptions{Exclude: r.opts.Exclude}
+		if syncOpts.Exclude == nil {
+			syncOpts.Exclude = []string{}
+		}
+		synced, err := copyPath(r.srcPath, r.targetPath, syncOpts)
+		if err != nil {
+			return fmt.Errorf("sync project: %w", err)
+		}
+		if r.Sync == nil {
+			r.Sync = synced
+		} else {
+			seen := make(map[string]bool, len(r.Sync.Transferred))
+			for _, name := range r.Sync.Transferred {
+				seen[name] = true
+			}
+			for _, name := range synced.Transferred {
+				if !seen[name] {
+					r.Sync.Transferred = append(r.Sync.Transferred, name)
+				}
+			}
+			r.Sync.Deleted = append(r.Sync.Deleted, synced.Deleted...)
+			r.Sync.Bytes += synced.Bytes
+		}
+	}
+
+	// Claude conversation
+	if r.src.Tool != "claude" || r.src.ClaudeSessionID == "" {
+		return nil
+	}
+	file, err := r.from.findConversation(r.src.ClaudeSessionID)
+	if err != nil || file == "" {
+		return err
+	}
+	dest, err := r.target.conversationPath(r.targetPath, r.src.ClaudeSessionID)
+	if err != nil {
+		return err
+	}
+	if _, err := copyPath(file, dest, sshpkg.SyncOptions{}); err != nil {
+		return fmt.Errorf("copy conversation: %w", err)
+	}
+	r.Conversation = dest
+	return nil
+}
+
+func migrateHostName(hostID string) string {
+	if hostID == "" {
+		return MigrateLocal
+	}
+	return hostID
+}
+
+// migrateHost is one side of a migration: this machine (nil conn) or an
+// SSH host
+type migrateHost struct {
+	hostID string
+	conn   *sshpkg.Connection
+}
+
+func newMigrateHost(hostID string) (migrateHost, error) {
+	if hostID == "" {
+		return migrateHost{}, nil
+	}
+	conn, err := sshpkg.DefaultPool().Get(hostID)
+	if err != nil {
+		return migrateHost{}, fmt.Errorf("connect to %s: %w", hostID, err)
+	}
+	return migrateHost{hostID: hostID, conn: conn}, nil
+}
+
+func (h migrateHost) name() string {
+	return migrateHostName(h.hostID)
+}
+
+// run runs a sh script on the host and returns its stdout
+func (h migrateHost) run(script string) (string, error) {
+	if h.conn != nil {
+		return h.conn.RunCommand(remoteShell(script))
+	}
+	out, err := exec.Command("sh", "-c", script).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return "", fmt.Errorf("command failed (exit %d): %s", exitErr.ExitCode(), strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return string(out), err
+}
+
+// absPath expands ~ and makes p absolute on the host
+func (h migrateHost) absPath(p string) (string, error) {
+	if h.conn == nil {
+		return filepath.Abs(expandTilde(p))
+	}
+	if p == "~" || strings.HasPrefix(p, "~/") {
+		home, err := h.run(`printf '%s' "$HOME"`)
+		if err != nil {
+			return "", err
+		}
+		return path.Join(home, strings.TrimPrefix(p, "~")), nil
+	}
+	if !path.IsAbs(p) {
+		return "", fmt.Errorf("remote project path must be absolute or start with ~/: %s", p)
+	}
+	return path.Clean(p), nil
+}
+
+// claudeConfigDir returns the Claude config dir on the host. Remote hosts use
+// the locally configured dir when sessions are started with it explicitly
+// (see buildClaudeCommand), else their own $CLAUDE_CONFIG_DIR or ~/.claude.
+func (h migrateHost) claudeConfigDir() (string, error) {
+	if h.conn == nil || (IsClaudeConfigDirExplicit() && GetClaudeCommand() == "claude") {
+		return GetClaudeConfigDir(), nil
+	}
+	out, err := h.run(`printf '%s' "${CLAUDE_CONFIG_DIR:-$HOME/.claude}"`)
+	if err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// findConversation returns the JSONL file of a Claude session on the host,
+// or "" if there is none
+func (h migrateHost) findConversation(sessionID string) (string, error) {
+	dir, err := h.claudeConfigDir()
+	if err != nil {
+		return "", err
+	}
+	if h.conn == nil {
+		matches, _ := filepath.Glob(filepath.Join(dir, "projects", "*", sessionID+".jsonl"))
+		if len(matches) == 0 {
+			return "", nil
+		}
+		return matches[0], nil
+	}
+	out, err := h.run(fmt.Sprintf(`for f in %s/projects/*/%s.jsonl; do [ -f "$f" ] && printf '%%s' "$f" && break; done; true`,
+		sshpkg.ShellQuote(dir), sshpkg.ShellQuote(sessionID)))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// conversationPath is where Claude looks for a session's JSONL when run in
+// projectPath on the host: <config>/projects/<encoded real path>/<id>.jsonl
+func (h migrateHost) conversationPath(projectPath, sessionID string) (string, error) {
+	dir, err := h.claudeConfigDir()
+	if err != nil {
+		return "", err
+	}
+	resolved := projectPath
+	if h.conn == nil {
+		if r, err := filepath.EvalSymlinks(projectPath); err == nil {
+			resolved = r
+		}
+		return filepath.Join(dir, "projects", ConvertToClaudeDirName(resolved), sessionID+".jsonl"), nil
+	}
+	if out, err := h.run("cd " + sshpkg.ShellQuote(projectPath) + " && pwd -P"); err == nil && strings.TrimSpace(out) != "" {
+		resolved = strings.TrimSpace(out)
+	}
+	return path.Join(dir, "projects", ConvertToClaudeDirName(resolved), sessionID+".jsonl"), nil
+}
+
+// migrateGitBranch pushes the source's current branch to origin and checks
+// it out on the target, cloning origin there if the project doesn't exist.
+// Refuses to run with uncommitted changes, which would be left behind.
+func migrateGitBranch(from, to migrateHost, srcPath, targetPath string) (string, error) {
+	out, err := from.run(fmt.Sprintf(`cd %s || exit 1
+if [ -n "$(git status --porcelain)" ]; then echo "uncommitted changes in $PWD: commit them or migrate without --git" >&2; exit 1; fi
+branch=$(git rev-parse --abbrev-ref HEAD) && url=$(git remote get-url origin) || exit 1
+git push -q origin "$branch" >&2 || exit 1
+printf '%%s\n%%s\n' "$branch" "$url"`, sshpkg.ShellQuote(srcPath)))
+	if err != nil {
+		return "", fmt.Errorf("push branch on %s: %w", from.name(), err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || lines[0] == "HEAD" {
+		return "", fmt.Errorf("push branch on %s: no branch checked out", from.name())
+	}
+	branch, url := lines[0], lines[1]
+
+	_, err = to.run(fmt.Sprintf(`p=%s b=%s
+if [ -d "$p/.git" ]; then
+  cd "$p" && git fetch -q origin "$b" && { git checkout -q "$b" 2>/dev/null || git checkout -q -b "$b" FETCH_HEAD; } && git merge -q --ff-only FETCH_HEAD
+else
+  mkdir -p "$(dirname "$p")" && git clone -q --branch "$b" %s "$p"
+fi`, sshpkg.ShellQuote(targetPath), sshpkg.ShellQuote(branch), sshpkg.ShellQuote(url)))
+	if err != nil {
+		return "", fmt.Errorf("check out %s on %s: %w", branch, to.name(), err)
+	}
+	return branch, nil
+}
@@ -0,0 +1,144 @@
+package session
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateSession_InvalidTargets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	local := NewInstance("api", "/srv/api")
+	remote := remoteTestInstance("r1", "web", "box")
+
+	tests := []struct {
+		name string
+		src  *Instance
+		to   string
+		want string
+	}{
+		{"no target", local, " ", "no migration target"},
+		{"local to local", local, MigrateLocal, "already on local"},
+		{"same host", remote, "box", "already on box"},
+		{"remote to remote", remote, "other", "two remote hosts"},
+		{"unknown host", local, "nope", "not found in config.toml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MigrateSession(tt.src, MigrateOptions{To: tt.to})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("MigrateSession(to=%q) error = %v, want %q", tt.to, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrateHost_LocalConversation(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	ClearUserConfigCache()
+	defer ClearUserConfigCache()
+
+	h := migrateHost{}
+	const id = "0b6a5f6e-1111-2222-3333-444455556666"
+	if file, err := h.findConversation(id); err != nil || file != "" {
+		t.Fatalf("findConversation with no file = %q, %v", file, err)
+	}
+
+	want := filepath.Join(configDir, "projects", "-srv-api", id+".jsonl")
+	if err := os.MkdirAll(filepath.Dir(want), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(want, []byte("{}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if file, err := h.findConversation(id); err != nil || file != want {
+		t.Errorf("findConversation = %q, %v, want %q", file, err, want)
+	}
+
+	project := t.TempDir()
+	resolved, _ := filepath.EvalSymlinks(project)
+	dest, err := h.conversationPath(project, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wantDest := filepath.Join(configDir, "projects", ConvertToClaudeDirName(resolved), id+".jsonl"); dest != wantDest {
+		t.Errorf("conversationPath = %q, want %q", dest, wantDest)
+	}
+}
+
+func TestMigrateGitBranch_Local(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, k := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(k, "test")
+	}
+	for _, k := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(k, "test@example.com")
+	}
+
+	root := t.TempDir()
+	origin := filepath.Join(root, "origin.git")
+	src := filepath.Join(root, "src")
+	target := filepath.Join(root, "elsewhere", "src")
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	git(root, "init", "-q", "--bare", origin)
+	git(root, "clone", "-q", origin, src)
+	git(src, "checkout", "-q", "-b", "feature")
+	if err := os.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(src, "add", ".")
+	git(src, "commit", "-q", "-m", "work")
+
+	// Uncommitted changes would be left behind
+	if err := os.WriteFile(filepath.Join(src, "wip.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrateGitBranch(migrateHost{}, migrateHost{}, src, target); err == nil || !strings.Contains(err.Error(), "uncommitted") {
+		t.Fatalf("migrateGitBranch with dirty tree error = %v, want uncommitted changes", err)
+	}
+	_ = os.Remove(filepath.Join(src, "wip.go"))
+
+	branch, err := migrateGitBranch(migrateHost{}, migrateHost{}, src, target)
+	if err != nil {
+		t.Fatalf("migrateGitBranch: %v", err)
+	}
+	if branch != "feature" {
+		t.Errorf("branch = %q, want feature", branch)
+	}
+	if got := git(target, "rev-parse", "--abbrev-ref", "HEAD"); got != "feature" {
+		t.Errorf("target is on %q, want feature", got)
+	}
+	if _, err := os.Stat(filepath.Join(target, "main.go")); err != nil {
+		t.Errorf("committed file not checked out on target: %v", err)
+	}
+
+	// Existing checkout on the target is fast-forwarded
+	if err := os.WriteFile(filepath.Join(src, "more.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git(src, "add", ".")
+	git(src, "commit", "-q", "-m", "more")
+	if _, err := migrateGitBranch(migrateHost{}, migrateHost{}, src, target); err != nil {
+		t.Fatalf("second migrateGitBranch: %v", err)
+	}
+	if git(target, "rev-parse", "HEAD") != git(src, "rev-parse", "HEAD") {
+		t.Error("target checkout was not fast-forwarded")
+	}
+}
@@ -0,0 +1,176 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// notesDir is the directory of session notes within a profile directory.
+// Each session's notes are kept in <id>.md and never sent to the agent.
+const notesDir = "notes"
+
+// notesPathIn returns the notes file of a session within a profile directory
+func notesPathIn(profileDir, id string) (string, error) {
+	if id == "" || filepath.Base(id) != id || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid session ID: %q", id)
+	}
+	return filepath.Join(profileDir, notesDir, id+".md"), nil
+}
+
+// NotesPath returns the notes file of a session (empty profile = effective
+// profile). The file may not exist yet.
+func NotesPath(profile, id string) (string, error) {
+	dir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return "", err
+	}
+	return notesPathIn(dir, id)
+}
+
+// LoadNotes returns a session's notes, or "" if it has none
+func LoadNotes(profile, id string) (string, error) {
+	path, err := NotesPath(profile, id)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read notes: %w", err)
+	}
+	return string(data), nil
+}
+
+// SaveNotes replaces a session's notes. Blank notes remove the file.
+func SaveNotes(profile, id, text string) error {
+	path, err := NotesPath(profile, id)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove notes: %w", err)
+		}
+		return nil
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(text), 0600); err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+	return nil
+}
+
+// LoadAllNotes returns the notes of every session in a profile by session ID.
+// Blank notes are skipped.
+func LoadAllNotes(profile string) (map[string]string, error) {
+	dir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, notesDir))
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	notes := make(map[string]string, len(entries))
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".md")
+		if !ok || e.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, notesDir, e.Name()))
+		if err != nil || strings.TrimSpace(string(data)) == "" {
+			continue
+		}
+		notes[id] = string(data)
+	}
+	return notes, nil
+}
+
+// DeleteNotes removes the notes of sessions that are gone for good
+// (permanently removed or purged from the archive)
+func DeleteNotes(profile string, ids ...string) error {
+	dir, err := GetProfileDir(GetEffectiveProfile(profile))
+	if err != nil {
+		return err
+	}
+	return deleteNotesIn(dir, ids)
+}
+
+func deleteNotesIn(profileDir string, ids []string) error {
+	for _, id := range ids {
+		path, err := notesPathIn(profileDir, id)
+		if err != nil {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove notes: %w", err)
+		}
+	}
+	return nil
+}
+
+// NoteMatch is a session whose notes contain a search query
+type NoteMatch struct {
+	SessionID string
+	Notes     string
+	Count     int // Occurrences of the query
+}
+
+// SearchNotes returns the sessions whose notes contain query
+// (case-insensitive), most matches first
+func SearchNotes(notes map[string]string, query string) []NoteMatch {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil
+	}
+	var matches []NoteMatch
+	for id, text := range notes {
+		if n := strings.Count(strings.ToLower(text), query); n > 0 {
+			matches = append(matches, NoteMatch{SessionID: id, Notes: text, Count: n})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Count != matches[j].Count {
+			return matches[i].Count > matches[j].Count
+		}
+		return matches[i].SessionID < matches[j].SessionID
+	})
+	return matches
+}
+
+// NotesEditorCommand returns the command that edits path in the user's
+// editor ($VISUAL, then $EDITOR, then vi). The notes directory is created so
+// the editor can write a new file.
+func NotesEditorCommand(path string) (*exec.Cmd, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	editor := os.Getenv("VISUAL")
+	if strings.TrimSpace(editor) == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	args := strings.Fields(editor)
+	if len(args) == 0 {
+		args = []string{"vi"}
+	}
+	return exec.Command(args[0], append(args[1:], path)...), nil
+}
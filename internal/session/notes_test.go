@@ -0,0 +1,101 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNotes_SaveLoadDelete(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	if notes, err := LoadNotes("work", "abc-1"); err != nil || notes != "" {
+		t.Fatalf("LoadNotes(missing) = %q, %v", notes, err)
+	}
+	if err := SaveNotes("work", "abc-1", "left: add tests"); err != nil {
+		t.Fatal(err)
+	}
+	if notes, _ := LoadNotes("work", "abc-1"); notes != "left: add tests\n" {
+		t.Errorf("LoadNotes = %q", notes)
+	}
+	if err := SaveNotes("work", "def-2", "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := LoadAllNotes("work")
+	if err != nil || len(all) != 2 || all["def-2"] != "other\n" {
+		t.Errorf("LoadAllNotes = %v, %v", all, err)
+	}
+	if all, _ := LoadAllNotes("personal"); len(all) != 0 {
+		t.Errorf("other profile has notes: %v", all)
+	}
+
+	// Blank notes remove the file
+	if err := SaveNotes("work", "abc-1", "  \n"); err != nil {
+		t.Fatal(err)
+	}
+	path, _ := NotesPath("work", "abc-1")
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("blank notes should remove %s", path)
+	}
+
+	if err := DeleteNotes("work", "def-2", "missing"); err != nil {
+		t.Fatal(err)
+	}
+	if all, _ := LoadAllNotes("work"); len(all) != 0 {
+		t.Errorf("notes left after delete: %v", all)
+	}
+
+	if _, err := NotesPath("work", "../escape"); err == nil {
+		t.Error("NotesPath should reject IDs with path separators")
+	}
+}
+
+func TestSearchNotes(t *testing.T) {
+	notes := map[string]string{
+		"a": "TODO: fix login\ntodo: docs",
+		"b": "one todo",
+		"c": "nothing",
+	}
+	matches := SearchNotes(notes, "Todo")
+	if len(matches) != 2 || matches[0].SessionID != "a" || matches[0].Count != 2 || matches[1].SessionID != "b" {
+		t.Errorf("SearchNotes = %+v", matches)
+	}
+	if SearchNotes(notes, " ") != nil {
+		t.Error("blank query should match nothing")
+	}
+}
+
+func TestArchivePurgeDeletesNotes(t *testing.T) {
+	a := newTestArchive(t)
+	inst := NewInstance("api", "/srv/api")
+	if err := a.Add(inst, "deleted in TUI"); err != nil {
+		t.Fatal(err)
+	}
+	notesPath, _ := notesPathIn(filepath.Dir(a.path), inst.ID)
+	if err := os.MkdirAll(filepath.Dir(notesPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(notesPath, []byte("keep until purged\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := a.Take(inst.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(notesPath); err != nil {
+		t.Fatalf("restoring must keep notes: %v", err)
+	}
+
+	if err := a.Add(inst, "deleted again"); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := a.Purge([]string{inst.ID}); err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v", n, err)
+	}
+	if _, err := os.Stat(notesPath); !os.IsNotExist(err) {
+		t.Error("purge should delete the session's notes")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"sync"
+	"time"
 
 	"github.com/asheshgoplani/agent-deck/internal/mcppool"
 	"github.com/asheshgoplani/agent-deck/internal/platform"
@@ -64,6 +65,10 @@ func InitializeGlobalPool(ctx context.Context, config *UserConfig, sessions []*I
 		ExcludeMCPs:   config.MCPPool.ExcludeMCPs,
 		PoolMCPs:      config.MCPPool.PoolMCPs,
 		FallbackStdio: true, // Always true - see Issue #36
+
+		HealthCheckTimeout:  time.Duration(config.MCPPool.HealthCheckTimeout) * time.Second,
+		QuarantineThreshold: config.MCPPool.QuarantineAfter,
+		QuarantineWindow:    time.Duration(config.MCPPool.QuarantineWindow) * time.Second,
 	}
 
 	// Create pool
@@ -72,6 +77,18 @@ func InitializeGlobalPool(ctx context.Context, config *UserConfig, sessions []*I
 		return nil, err
 	}
 
+	// Quarantined MCPs move their sessions to stdio. A fresh pool retries
+	// every MCP, so quarantines recorded by pools that are gone are cleared;
+	// 'agent-deck mcp unquarantine' lifts them while the pool runs.
+	pool.SetQuarantineHandler(handleMCPQuarantine)
+	if err := pruneMCPQuarantine(); err != nil {
+		log.Printf("[Pool] Failed to clear stale quarantine state: %v", err)
+	}
+	go watchMCPQuarantine(ctx, pool)
+	for _, inst := range sessions {
+		trackPooledProjectPath(inst.ProjectPath)
+	}
+
 	// FIRST: Discover existing sockets from another agent-deck instance
 	// This allows multiple TUI instances to share the same pool
 	discovered := pool.DiscoverExistingSockets()
@@ -102,9 +119,17 @@ func InitializeGlobalPool(ctx context.Context, config *UserConfig, sessions []*I
 			continue
 		}
 
+		// Resolve secret references (env:/file:/cmd:) only in the pool process,
+		// so sessions connect via socket and never see the secret values
+		env, err := def.ResolvedEnv()
+		if err != nil {
+			log.Printf("[Pool] ✗ %s: failed to resolve secret references: %v", mcpName, err)
+			continue
+		}
+
 		// Start socket proxy for this MCP
 		log.Printf("[Pool] Starting socket proxy for %s...", mcpName)
-		if err := pool.Start(mcpName, def.Command, def.Args, def.Env); err != nil {
+		if err := pool.Start(mcpName, def.Command, def.Args, env); err != nil {
 			log.Printf("[Pool] ✗ Failed to start socket proxy for %s: %v", mcpName, err)
 		} else {
 			log.Printf("[Pool] ✓ Socket proxy started: %s", mcpName)
@@ -114,7 +139,7 @@ func InitializeGlobalPool(ctx context.Context, config *UserConfig, sessions []*I
 
 	log.Printf("[Pool] Started %d socket proxies, reused %d from other instance", startedCount, skippedCount)
 
-	// Start health monitor for auto-restart of failed proxies
+	// Start health monitor: protocol pings, auto-restart and crash-loop quarantine
 	pool.StartHealthMonitor()
 
 	globalPool = pool
@@ -146,6 +171,9 @@ func ShutdownGlobalPool(shouldShutdown bool) error {
 	defer globalPoolMu.Unlock()
 
 	if globalPool != nil {
+		if err := releaseMCPQuarantine(); err != nil {
+			log.Printf("[Pool] Failed to clear quarantine state: %v", err)
+		}
 		if shouldShutdown {
 			log.Printf("[Pool] Shutting down pool (killing MCP processes)")
 			err := globalPool.Shutdown()
@@ -0,0 +1,417 @@
+package session
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// PortForward forwards localhost:LocalPort to localhost:RemotePort on a
+// remote session's SSH host
+type PortForward struct {
+	LocalPort  int `json:"local_port"`
+	RemotePort int `json:"remote_port"`
+	// Auto is set for forwards added by DetectPortForwards
+	Auto bool `json:"auto,omitempty"`
+}
+
+// String formats the forward like "localhost:8080 -> 3000"
+func (f PortForward) String() string {
+	return fmt.Sprintf("localhost:%d -> %d", f.LocalPort, f.RemotePort)
+}
+
+// ParsePortForward parses "3000" (same port on both sides) or "8080:3000"
+// (local:remote)
+func ParsePortForward(spec string) (PortForward, error) {
+	local, remote, hasLocal := strings.Cut(strings.TrimSpace(spec), ":")
+	if !hasLocal {
+		remote = local
+	}
+	lp, err1 := strconv.Atoi(local)
+	rp, err2 := strconv.Atoi(remote)
+	if err1 != nil || err2 != nil || !validPort(lp) || !validPort(rp) {
+		return PortForward{}, fmt.Errorf("invalid port forward %q (use PORT or LOCAL:REMOTE)", spec)
+	}
+	return PortForward{LocalPort: lp, RemotePort: rp}, nil
+}
+
+func validPort(p int) bool {
+	return p > 0 && p <= 65535
+}
+
+// AddPortForward adds f to the session's forwards. Returns false if its local
+// port is already forwarded.
+func (inst *Instance) AddPortForward(f PortForward) bool {
+	for _, existing := range inst.PortForwards {
+		if existing.LocalPort == f.LocalPort {
+			return false
+		}
+	}
+	inst.PortForwards = append(inst.PortForwards, f)
+	sort.Slice(inst.PortForwards, func(i, j int) bool {
+		return inst.PortForwards[i].LocalPort < inst.PortForwards[j].LocalPort
+	})
+	return true
+}
+
+// RemovePortForward removes the forward on localPort. Returns false if there
+// was none.
+func (inst *Instance) RemovePortForward(localPort int) bool {
+	for i, f := range inst.PortForwards {
+		if f.LocalPort == localPort {
+			inst.PortForwards = append(inst.PortForwards[:i], inst.PortForwards[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// localhostURLPattern matches dev server URLs like http://localhost:3000 or
+// https://127.0.0.1:8443/path
+var localhostURLPattern = regexp.MustCompile(`https?://(?:localhost|127\.0\.0\.1|0\.0\.0\.0|\[::1?\]):(\d{2,5})\b`)
+
+// DetectLocalhostPorts returns the ports of localhost URLs in terminal
+// output, in order of first appearance
+func DetectLocalhostPorts(output string) []int {
+	var ports []int
+	seen := make(map[int]bool)
+	for _, m := range localhostURLPattern.FindAllStringSubmatch(output, -1) {
+		port, err := strconv.Atoi(m[1])
+		if err != nil || !validPort(port) || seen[port] {
+			continue
+		}
+		seen[port] = true
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// DetectPortForwards adds an automatic forward for each localhost URL in a
+// remote session's output whose port isn't forwarded yet, and returns the
+// forwards it added. The local port is the remote one unless a session in
+// instances already forwards it or something else listens on it; then the
+// next free port is used. It dials local ports; the TUI runs the steps
+// (PlanPortForwards, Resolve, AddDetectedPortForwards) separately to keep
+// the dials off its Update loop.
+func (inst *Instance) DetectPortForwards(output string, instances []*Instance) []PortForward {
+	return inst.AddDetectedPortForwards(inst.PlanPortForwards(output, instances).Resolve(), instances)
+}
+
+// PortForwardPlan lists newly detected remote ports of a session and the
+// local ports already forwarded, for Resolve to pick local ports from
+type PortForwardPlan struct {
+	Title       string
+	RemotePorts []int
+	Used        map[int]bool
+}
+
+// PlanPortForwards returns the localhost URL ports in a remote session's
+// output that aren't forwarded yet. It reads instances but doesn't dial.
+func (inst *Instance) PlanPortForwards(output string, instances []*Instance) PortForwardPlan {
+	plan := PortForwardPlan{Title: inst.Title}
+	if !inst.IsRemote() {
+		return plan
+	}
+	for _, port := range DetectLocalhostPorts(output) {
+		if !inst.forwardsPort(port) {
+			plan.RemotePorts = append(plan.RemotePorts, port)
+		}
+	}
+	if len(plan.RemotePorts) > 0 {
+		plan.Used = forwardedLocalPorts(inst, instances)
+	}
+	return plan
+}
+
+// Resolve picks a free local port for each remote port of the plan. It
+// dials localhost, so the TUI calls it from a tea.Cmd.
+func (p PortForwardPlan) Resolve() []PortForward {
+	var forwards []PortForward
+	used := make(map[int]bool, len(p.Used))
+	for port := range p.Used {
+		used[port] = true
+	}
+	for _, port := range p.RemotePorts {
+		local := freeLocalPort(port, used)
+		if local == 0 {
+			log.Printf("[PORT-FORWARD] no free local port near %d for %s", port, p.Title)
+			continue
+		}
+		used[local] = true
+		forwards = append(forwards, PortForward{LocalPort: local, RemotePort: port, Auto: true})
+	}
+	return forwards
+}
+
+// AddDetectedPortForwards adds resolved forwards that are still new: their
+// remote port isn't forwarded by the session and no session in instances
+// took their local port meanwhile. Returns the forwards added.
+func (inst *Instance) AddDetectedPortForwards(forwards []PortForward, instances []*Instance) []PortForward {
+	if len(forwards) == 0 {
+		return nil
+	}
+	used := forwardedLocalPorts(inst, instances)
+	var added []PortForward
+	for _, f := range forwards {
+		if inst.forwardsPort(f.RemotePort) || used[f.LocalPort] {
+			continue
+		}
+		if inst.AddPortForward(f) {
+			used[f.LocalPort] = true
+			added = append(added, f)
+		}
+	}
+	return added
+}
+
+// forwardsPort reports whether the session forwards port, as either end
+func (inst *Instance) forwardsPort(port int) bool {
+	for _, f := range inst.PortForwards {
+		if f.RemotePort == port || f.LocalPort == port {
+			return true
+		}
+	}
+	return false
+}
+
+// freeLocalPortAttempts is how many ports after the preferred one
+// freeLocalPort tries
+const freeLocalPortAttempts = 100
+
+// forwardedLocalPorts returns the local ports forwarded by inst and instances
+func forwardedLocalPorts(inst *Instance, instances []*Instance) map[int]bool {
+	used := make(map[int]bool)
+	for _, other := range append([]*Instance{inst}, instances...) {
+		for _, f := range other.PortForwards {
+			used[f.LocalPort] = true
+		}
+	}
+	return used
+}
+
+// freeLocalPort returns port, or the next port after it, that isn't in used
+// and that nothing listens on locally. Returns 0 if there's none.
+func freeLocalPort(port int, used map[int]bool) int {
+	for p := port; p < port+freeLocalPortAttempts && validPort(p); p++ {
+		if !used[p] && !IsLocalPortListening(p) {
+			return p
+		}
+	}
+	return 0
+}
+
+// PortForwardTarget is a forward the manager should keep open
+type PortForwardTarget struct {
+	SessionID string
+	HostID    string
+	PortForward
+}
+
+// PortForwardTargets lists the forwards of remote sessions that are running.
+// Call it where instances are owned and pass the result to Sync, so the
+// manager never reads instances from another goroutine.
+func PortForwardTargets(instances []*Instance) []PortForwardTarget {
+	var targets []PortForwardTarget
+	for _, inst := range instances {
+		if !inst.IsRemote() || inst.Status == StatusError {
+			continue
+		}
+		for _, f := range inst.PortForwards {
+			targets = append(targets, PortForwardTarget{SessionID: inst.ID, HostID: inst.RemoteHost, PortForward: f})
+		}
+	}
+	return targets
+}
+
+// PortForwardState is the state of a forward held by a PortForwardManager
+type PortForwardState struct {
+	PortForward
+	HostID string `json:"host_id"`
+	Open   bool   `json:"open"`
+	Error  string `json:"error,omitempty"`
+}
+
+// portForwardRetryInterval is how long a failed forward waits before Sync
+// tries it again
+const portForwardRetryInterval = 30 * time.Second
+
+// openForward is an open forward; Done is closed when it stops
+type openForward interface {
+	io.Closer
+	Done() <-chan struct{}
+}
+
+type managedForward struct {
+	target  PortForwardTarget
+	closer  openForward
+	err     string
+	retryAt time.Time
+}
+
+// alive reports whether the forward is open and hasn't stopped on its own
+func (f *managedForward) alive() bool {
+	if f.closer == nil {
+		return false
+	}
+	select {
+	case <-f.closer.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// PortForwardManager keeps the forwards of running remote sessions open and
+// closes them when the session stops, is deleted, or the forward is removed
+type PortForwardManager struct {
+	mu       sync.Mutex
+	forwards map[string]*managedForward // "<session-id>:<local-port>"
+	open     func(t PortForwardTarget) (openForward, error)
+}
+
+// NewPortForwardManager creates a manager that opens forwards through the
+// global SSH pool
+func NewPortForwardManager() *PortForwardManager {
+	return &PortForwardManager{
+		forwards: make(map[string]*managedForward),
+		open:     openPortForward,
+	}
+}
+
+func openPortForward(t PortForwardTarget) (openForward, error) {
+	conn, err := sshpkg.DefaultPool().Get(t.HostID)
+	if err != nil {
+		return nil, err
+	}
+	forward, err := conn.ForwardPort(t.LocalPort, t.RemotePort, "localhost")
+	if err != nil {
+		return nil, err
+	}
+	return forward, nil
+}
+
+func portForwardKey(sessionID string, localPort int) string {
+	return sessionID + ":" + strconv.Itoa(localPort)
+}
+
+// Sync opens the targets that aren't open yet (retrying failed ones every
+// 30s), reopens forwards whose ssh process exited, and closes forwards that
+// are no longer targets. It dials SSH, so call
+// it off the UI goroutine; concurrent calls are not supported.
+func (m *PortForwardManager) Sync(targets []PortForwardTarget) {
+	want := make(map[string]PortForwardTarget, len(targets))
+	for _, t := range targets {
+		want[portForwardKey(t.SessionID, t.LocalPort)] = t
+	}
+
+	var toClose []io.Closer
+	var toOpen []PortForwardTarget
+	now := time.Now()
+	m.mu.Lock()
+	for key, f := range m.forwards {
+		if t, ok := want[key]; !ok || t.HostID != f.target.HostID || t.RemotePort != f.target.RemotePort {
+			if f.closer != nil {
+				toClose = append(toClose, f.closer)
+			}
+			delete(m.forwards, key)
+		}
+	}
+	for key, t := range want {
+		f, ok := m.forwards[key]
+		if ok && f.closer != nil && !f.alive() {
+			// The forward died (network drop, sshd restart): open it again
+			log.Printf("[PORT-FORWARD] %s on %s stopped, reopening", t.PortForward, t.HostID)
+			toClose = append(toClose, f.closer)
+			f.closer, f.err, f.retryAt = nil, "", time.Time{}
+		}
+		if !ok {
+			m.forwards[key] = &managedForward{target: t}
+			toOpen = append(toOpen, t)
+		} else if f.closer == nil && now.After(f.retryAt) {
+			toOpen = append(toOpen, t)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, c := range toClose {
+		_ = c.Close()
+	}
+	for _, t := range toOpen {
+		closer, err := m.open(t)
+		if err != nil {
+			log.Printf("[PORT-FORWARD] %s on %s: %v", t.PortForward, t.HostID, err)
+		}
+		m.mu.Lock()
+		f, ok := m.forwards[portForwardKey(t.SessionID, t.LocalPort)]
+		switch {
+		case !ok:
+			// Removed while dialing
+			if closer != nil {
+				_ = closer.Close()
+			}
+		case err != nil:
+			f.err = err.Error()
+			f.retryAt = time.Now().Add(portForwardRetryInterval)
+		default:
+			f.closer, f.err = closer, ""
+		}
+		m.mu.Unlock()
+	}
+}
+
+// States returns the forwards held for a session, ordered by local port
+func (m *PortForwardManager) States(sessionID string) []PortForwardState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var states []PortForwardState
+	for _, f := range m.forwards {
+		if f.target.SessionID != sessionID {
+			continue
+		}
+		state := PortForwardState{
+			PortForward: f.target.PortForward,
+			HostID:      f.target.HostID,
+			Open:        f.alive(),
+			Error:       f.err,
+		}
+		if f.closer != nil && !state.Open {
+			state.Error = "ssh exited; reopening"
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].LocalPort < states[j].LocalPort })
+	return states
+}
+
+// CloseAll closes every forward
+func (m *PortForwardManager) CloseAll() {
+	m.mu.Lock()
+	forwards := m.forwards
+	m.forwards = make(map[string]*managedForward)
+	m.mu.Unlock()
+	for _, f := range forwards {
+		if f.closer != nil {
+			_ = f.closer.Close()
+		}
+	}
+}
+
+// IsLocalPortListening reports whether something accepts connections on
+// localhost:port, e.g. a forward held open by another agent-deck process
+func IsLocalPortListening(port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)), 200*time.Millisecond)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
@@ -0,0 +1,151 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestParsePortForward(t *testing.T) {
+	if f, err := ParsePortForward("3000"); err != nil || f != (PortForward{LocalPort: 3000, RemotePort: 3000}) {
+		t.Errorf("ParsePortForward(3000) = %+v, %v", f, err)
+	}
+	if f, err := ParsePortForward("8080:3000"); err != nil || f != (PortForward{LocalPort: 8080, RemotePort: 3000}) {
+		t.Errorf("ParsePortForward(8080:3000) = %+v, %v", f, err)
+	}
+	for _, bad := range []string{"", "abc", "0", "70000", "1:2:3", ":3000"} {
+		if _, err := ParsePortForward(bad); err == nil {
+			t.Errorf("ParsePortForward(%q) should fail", bad)
+		}
+	}
+}
+
+func TestDetectPortForwards(t *testing.T) {
+	output := "  VITE v5.0.0  ready in 300 ms\n" +
+		"  ➜  Local:   http://localhost:5173/\n" +
+		"Server listening on http://127.0.0.1:8000 (press CTRL+C)\n" +
+		"again http://localhost:5173/ and postgres://localhost:5432/db\n"
+	if got := DetectLocalhostPorts(output); !reflect.DeepEqual(got, []int{5173, 8000}) {
+		t.Fatalf("DetectLocalhostPorts = %v, want [5173 8000]", got)
+	}
+
+	local := NewInstance("api", "/srv/api")
+	if added := local.DetectPortForwards(output, nil); added != nil {
+		t.Errorf("local session got forwards %v", added)
+	}
+
+	inst := remoteTestInstance("r1", "web", "box")
+	inst.AddPortForward(PortForward{LocalPort: 8001, RemotePort: 8000})
+	added := inst.DetectPortForwards(output, nil)
+	if len(added) != 1 || added[0] != (PortForward{LocalPort: 5173, RemotePort: 5173, Auto: true}) {
+		t.Errorf("DetectPortForwards added %+v, want 5173 only", added)
+	}
+	if again := inst.DetectPortForwards(output, nil); len(again) != 0 {
+		t.Errorf("second DetectPortForwards added %+v", again)
+	}
+	if !inst.RemovePortForward(5173) || inst.RemovePortForward(5173) {
+		t.Error("RemovePortForward should remove 5173 once")
+	}
+}
+
+func TestDetectPortForwards_AvoidsTakenLocalPorts(t *testing.T) {
+	l, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	busy := l.Addr().(*net.TCPAddr).Port
+
+	inst := remoteTestInstance("r1", "web", "box")
+	added := inst.DetectPortForwards(fmt.Sprintf("http://localhost:%d/", busy), nil)
+	if len(added) != 1 || added[0].RemotePort != busy || added[0].LocalPort == busy {
+		t.Errorf("port in local use: added %+v, want another local port for %d", added, busy)
+	}
+
+	other := remoteTestInstance("r2", "api", "box")
+	other.AddPortForward(PortForward{LocalPort: 5173, RemotePort: 5173})
+	inst = remoteTestInstance("r3", "docs", "box")
+	added = inst.DetectPortForwards("http://localhost:5173/", []*Instance{other, inst})
+	if len(added) != 1 || added[0].RemotePort != 5173 || added[0].LocalPort == 5173 {
+		t.Errorf("port forwarded by another session: added %+v", added)
+	}
+}
+
+type fakeForward struct {
+	mu     *sync.Mutex
+	closed map[int]bool
+	port   int
+	done   chan struct{}
+}
+
+func (f fakeForward) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f fakeForward) Close() error {
+	f.mu.Lock()
+	f.closed[f.port] = true
+	f.mu.Unlock()
+	return nil
+}
+
+func TestPortForwardManager_Sync(t *testing.T) {
+	var mu sync.Mutex
+	closed := make(map[int]bool)
+	m := NewPortForwardManager()
+	opened := make(map[int]fakeForward)
+	m.open = func(t PortForwardTarget) (openForward, error) {
+		if t.LocalPort == 9999 {
+			return nil, errors.New("address already in use")
+		}
+		f := fakeForward{mu: &mu, closed: closed, port: t.LocalPort, done: make(chan struct{})}
+		opened[t.LocalPort] = f
+		return f, nil
+	}
+
+	running := remoteTestInstance("r1", "web", "box")
+	running.Status = StatusRunning
+	running.AddPortForward(PortForward{LocalPort: 3000, RemotePort: 3000})
+	running.AddPortForward(PortForward{LocalPort: 9999, RemotePort: 9999})
+	stopped := remoteTestInstance("r2", "api", "box")
+	stopped.Status = StatusError
+	stopped.AddPortForward(PortForward{LocalPort: 4000, RemotePort: 4000})
+
+	m.Sync(PortForwardTargets([]*Instance{running, stopped}))
+	states := m.States("r1")
+	if len(states) != 2 || !states[0].Open || states[1].Open || states[1].Error == "" {
+		t.Fatalf("States(r1) = %+v, want 3000 open and 9999 failed", states)
+	}
+	if len(m.States("r2")) != 0 {
+		t.Error("stopped session should have no forwards open")
+	}
+
+	// ssh exits: the forward is reported closed, then reopened by Sync
+	dead := opened[3000]
+	close(dead.done)
+	if states := m.States("r1"); states[0].Open || states[0].Error == "" {
+		t.Errorf("States(r1)[0] = %+v, want closed after ssh exited", states[0])
+	}
+	m.Sync(PortForwardTargets([]*Instance{running, stopped}))
+	if states := m.States("r1"); !states[0].Open || opened[3000].done == dead.done || !closed[3000] {
+		t.Errorf("States(r1)[0] = %+v, want the dead forward closed and reopened", states[0])
+	}
+	delete(closed, 3000)
+
+	// Session stops: its forwards are closed
+	running.Status = StatusError
+	m.Sync(PortForwardTargets([]*Instance{running, stopped}))
+	if len(m.States("r1")) != 0 || !closed[3000] {
+		t.Errorf("forwards not closed after stop: states=%+v closed=%v", m.States("r1"), closed)
+	}
+
+	running.Status = StatusRunning
+	m.Sync(PortForwardTargets([]*Instance{running}))
+	m.CloseAll()
+	if len(m.States("r1")) != 0 {
+		t.Error("CloseAll left forwards behind")
+	}
+}
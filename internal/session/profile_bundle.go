@@ -0,0 +1,837 @@
+package session
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// profileBundleVersion is the format version of profile bundles written by
+// ProfileBundle.Write. Bundles with a newer version are refused.
+const profileBundleVersion = 1
+
+// maxBundleEntrySize bounds a single file read from a bundle
+const maxBundleEntrySize = 64 << 20
+
+// Bundle entry names
+const (
+	bundleManifestFile  = "manifest.json"
+	bundleSessionsFile  = "sessions.json"
+	bundleConfigFile    = "config.toml"
+	bundleLayoutsFile   = "desktop/layouts.json"
+	bundleQuickLaunch   = "quick-launch.toml"
+	bundleNotesDir      = notesDir + "/"
+	desktopLayoutsPath  = "desktop/layouts.json" // Relative to ~/.agent-deck
+	quickLaunchFileName = "quick-launch.toml"    // Relative to ~/.agent-deck
+)
+
+// ProfileBundleManifest describes a profile bundle
+type ProfileBundleManifest struct {
+	Version    int       `json:"version"`
+	Profile    string    `json:"profile"`
+	ExportedAt time.Time `json:"exported_at"`
+	// Home is the exporting user's home directory. Paths under it are moved
+	// to the importing user's home directory.
+	Home     string `json:"home,omitempty"`
+	Sessions int    `json:"sessions"`
+	Groups   int    `json:"groups"`
+	// MaskedSecrets are the MCP env/header values replaced by env:
+	// references on export; set those variables where the bundle is imported
+	MaskedSecrets []SecretFinding `json:"masked_secrets,omitempty"`
+}
+
+// BundleConfig is the part of config.toml carried by a profile bundle
+type BundleConfig struct {
+	MCPs          map[string]MCPDef       `toml:"mcps,omitempty"`
+	MCPBundles    map[string][]string     `toml:"mcp_bundles,omitempty"`
+	LaunchConfigs map[string]LaunchConfig `toml:"launch_configs,omitempty"`
+}
+
+// ProfileBundle is a profile's sessions, groups and notes with the config
+// and desktop state needed to use them on another machine (agent-deck
+// profile export / import)
+type ProfileBundle struct {
+	Manifest ProfileBundleManifest
+	Data     *StorageData
+	Notes    map[string]string // Session ID -> notes
+	Config   *BundleConfig     // nil if exported without config
+
+	// Desktop app saved layouts and quick-launch favorites, kept as decoded
+	// JSON/TOML so fields this package doesn't know survive the round trip
+	Layouts   []map[string]interface{}
+	Favorites []map[string]interface{}
+}
+
+// ExportOptions selects what LoadProfileBundle includes besides sessions,
+// groups and notes
+type ExportOptions struct {
+	NoConfig  bool // Skip MCP definitions, MCP bundles and launch configs
+	NoDesktop bool // Skip desktop saved layouts and quick-launch favorites
+	// IncludeSecrets keeps literal MCP env/header secrets instead of
+	// replacing them with env: references
+	IncludeSecrets bool
+}
+
+// LoadProfileBundle collects a profile's bundle (empty profile = effective
+// profile)
+func LoadProfileBundle(profile string, opts ExportOptions) (*ProfileBundle, error) {
+	profile = GetEffectiveProfile(profile)
+	exists, err := ProfileExists(profile)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("profile '%s' does not exist", profile)
+	}
+
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = storage.Close() }()
+	data, err := storage.LoadStorageData()
+	if err != nil {
+		return nil, err
+	}
+	notes, err := LoadAllNotes(profile)
+	if err != nil {
+		return nil, err
+	}
+	home, _ := os.UserHomeDir()
+
+	b := &ProfileBundle{
+		Manifest: ProfileBundleManifest{
+			Version:    profileBundleVersion,
+			Profile:    profile,
+			ExportedAt: time.Now(),
+			Home:       home,
+			Sessions:   len(data.Instances),
+			Groups:     len(data.Groups),
+		},
+		Data:  data,
+		Notes: make(map[string]string),
+	}
+	for _, inst := range data.Instances {
+		if text, ok := notes[inst.ID]; ok {
+			b.Notes[inst.ID] = text
+		}
+	}
+
+	if !opts.NoConfig {
+		config, err := LoadUserConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		mcps := config.MCPs
+		if !opts.IncludeSecrets {
+			mcps, b.Manifest.MaskedSecrets = MaskLiteralSecrets(bundleConfigFile, config.MCPs)
+		}
+		b.Config = &BundleConfig{
+			MCPs:          mcps,
+			MCPBundles:    config.MCPBundles,
+			LaunchConfigs: config.LaunchConfigs,
+		}
+	}
+	if !opts.NoDesktop {
+		if b.Layouts, err = readDesktopLayouts(); err != nil {
+			return nil, err
+		}
+		if b.Favorites, err = readQuickLaunchFavorites(); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// Write writes the bundle as a gzipped tar archive
+func (b *ProfileBundle) Write(w io.Writer) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0600,
+			Size:    int64(len(data)),
+			ModTime: b.Manifest.ExportedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	addJSON := func(name string, v interface{}) error {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		return add(name, data)
+	}
+
+	if err := addJSON(bundleManifestFile, b.Manifest); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := addJSON(bundleSessionsFile, b.Data); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	ids := make([]string, 0, len(b.Notes))
+	for id := range b.Notes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if err := add(bundleNotesDir+id+".md", []byte(b.Notes[id])); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if b.Config != nil {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(b.Config); err != nil {
+			return fmt.Errorf("failed to encode config: %w", err)
+		}
+		if err := add(bundleConfigFile, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if len(b.Layouts) > 0 {
+		if err := addJSON(bundleLayoutsFile, map[string]interface{}{"layouts": b.Layouts}); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+	if len(b.Favorites) > 0 {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{"favorites": b.Favorites}); err != nil {
+			return fmt.Errorf("failed to encode favorites: %w", err)
+		}
+		if err := add(bundleQuickLaunch, buf.Bytes()); err != nil {
+			return fmt.Errorf("failed to write bundle: %w", err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+// ReadProfileBundle reads a bundle written by Write
+func ReadProfileBundle(r io.Reader) (*ProfileBundle, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a profile bundle: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	b := &ProfileBundle{Notes: make(map[string]string)}
+	hasManifest := false
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxBundleEntrySize {
+			return nil, fmt.Errorf("bundle entry %s is too large", hdr.Name)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleEntrySize))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read bundle: %w", err)
+		}
+
+		switch name := hdr.Name; {
+		case name == bundleManifestFile:
+			if err := json.Unmarshal(data, &b.Manifest); err != nil {
+				return nil, fmt.Errorf("invalid bundle manifest: %w", err)
+			}
+			hasManifest = true
+		case name == bundleSessionsFile:
+			b.Data = &StorageData{}
+			if err := json.Unmarshal(data, b.Data); err != nil {
+				return nil, fmt.Errorf("invalid bundle sessions: %w", err)
+			}
+		case name == bundleConfigFile:
+			b.Config = &BundleConfig{}
+			if err := toml.Unmarshal(data, b.Config); err != nil {
+				return nil, fmt.Errorf("invalid bundle config: %w", err)
+			}
+		case name == bundleLayoutsFile:
+			var file struct {
+				Layouts []map[string]interface{} `json:"layouts"`
+			}
+			if err := json.Unmarshal(data, &file); err != nil {
+				return nil, fmt.Errorf("invalid bundle layouts: %w", err)
+			}
+			b.Layouts = file.Layouts
+		case name == bundleQuickLaunch:
+			var file struct {
+				Favorites []map[string]interface{} `toml:"favorites"`
+			}
+			if err := toml.Unmarshal(data, &file); err != nil {
+				return nil, fmt.Errorf("invalid bundle favorites: %w", err)
+			}
+			b.Favorites = file.Favorites
+		case strings.HasPrefix(name, bundleNotesDir) && strings.HasSuffix(name, ".md"):
+			id := strings.TrimSuffix(strings.TrimPrefix(name, bundleNotesDir), ".md")
+			if filepath.Base(id) == id {
+				b.Notes[id] = string(data)
+			}
+		}
+	}
+
+	if !hasManifest || b.Data == nil {
+		return nil, fmt.Errorf("not a profile bundle: missing %s or %s", bundleManifestFile, bundleSessionsFile)
+	}
+	if b.Manifest.Version > profileBundleVersion {
+		return nil, fmt.Errorf("bundle format version %d is newer than this agent-deck supports (%d); upgrade agent-deck",
+			b.Manifest.Version, profileBundleVersion)
+	}
+	if b.Data.SchemaVersion > StorageSchemaVersion {
+		return nil, fmt.Errorf("bundle sessions use schema version %d, newer than this agent-deck supports (%d); upgrade agent-deck",
+			b.Data.SchemaVersion, StorageSchemaVersion)
+	}
+	if err := validateStorageData(b.Data); err != nil {
+		return nil, fmt.Errorf("invalid bundle sessions: %w", err)
+	}
+	return b, nil
+}
+
+// PathMapping rewrites paths under From to To (profile import --map)
+type PathMapping struct {
+	From string
+	To   string
+}
+
+// ParsePathMapping parses "FROM=TO"
+func ParsePathMapping(s string) (PathMapping, error) {
+	from, to, ok := strings.Cut(s, "=")
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if !ok || from == "" || to == "" {
+		return PathMapping{}, fmt.Errorf("invalid path mapping %q: use FROM=TO", s)
+	}
+	return PathMapping{From: filepath.Clean(from), To: filepath.Clean(to)}, nil
+}
+
+// rewritePath applies the longest mapping whose From is path or a parent of
+// it. Paths starting with ~ are left alone.
+func rewritePath(path string, maps []PathMapping) string {
+	if path == "" || strings.HasPrefix(path, "~") {
+		return path
+	}
+	best := -1
+	for i, m := range maps {
+		if path == m.From || strings.HasPrefix(path, strings.TrimSuffix(m.From, "/")+"/") {
+			if best < 0 || len(m.From) > len(maps[best].From) {
+				best = i
+			}
+		}
+	}
+	if best < 0 {
+		return path
+	}
+	return maps[best].To + strings.TrimPrefix(path, maps[best].From)
+}
+
+// RewritePaths applies path mappings to local session paths, group default
+// paths, launch config MCP files, favorites and saved layouts. Paths of
+// remote sessions are on their host and are not rewritten.
+func (b *ProfileBundle) RewritePaths(maps []PathMapping) {
+	if len(maps) == 0 {
+		return
+	}
+	for _, inst := range b.Data.Instances {
+		if inst.RemoteHost != "" {
+			continue
+		}
+		inst.ProjectPath = rewritePath(inst.ProjectPath, maps)
+		inst.ParentProjectPath = rewritePath(inst.ParentProjectPath, maps)
+		inst.WorktreePath = rewritePath(inst.WorktreePath, maps)
+		inst.WorktreeRepoRoot = rewritePath(inst.WorktreeRepoRoot, maps)
+	}
+	for _, g := range b.Data.Groups {
+		g.DefaultPath = rewritePath(g.DefaultPath, maps)
+	}
+	if b.Config != nil {
+		for key, lc := range b.Config.LaunchConfigs {
+			lc.MCPConfigPath = rewritePath(lc.MCPConfigPath, maps)
+			b.Config.LaunchConfigs[key] = lc
+		}
+	}
+	for _, fav := range b.Favorites {
+		if path, ok := fav["path"].(string); ok {
+			fav["path"] = rewritePath(path, maps)
+		}
+	}
+	for _, layout := range b.Layouts {
+		rewriteLayoutPaths(layout, maps)
+	}
+}
+
+// rewriteLayoutPaths rewrites the projectPath of every local pane binding in
+// a saved layout tree
+func rewriteLayoutPaths(node map[string]interface{}, maps []PathMapping) {
+	if binding, ok := node["binding"].(map[string]interface{}); ok {
+		if remote, _ := binding["remoteHost"].(string); remote == "" {
+			if path, ok := binding["projectPath"].(string); ok {
+				binding["projectPath"] = rewritePath(path, maps)
+			}
+		}
+	}
+	if layout, ok := node["layout"].(map[string]interface{}); ok {
+		rewriteLayoutPaths(layout, maps)
+	}
+	if children, ok := node["children"].([]interface{}); ok {
+		for _, child := range children {
+			if c, ok := child.(map[string]interface{}); ok {
+				rewriteLayoutPaths(c, maps)
+			}
+		}
+	}
+}
+
+// ImportOptions controls ImportProfileBundle
+type ImportOptions struct {
+	Profile   string        // Target profile (default: the bundle's profile)
+	Merge     bool          // Import into an existing profile
+	Overwrite bool          // Replace conflicting sessions, groups and config entries instead of skipping them
+	DryRun    bool          // Report what would change without writing
+	Maps      []PathMapping // Path rewrites (the exporting home is mapped to ours after these)
+}
+
+// ImportCounts counts the entries of one kind an import added, replaced or
+// skipped because they already existed
+type ImportCounts struct {
+	Added    int `json:"added"`
+	Replaced int `json:"replaced"`
+	Skipped  int `json:"skipped"`
+}
+
+func (c *ImportCounts) count(exists, overwrite bool) bool {
+	switch {
+	case !exists:
+		c.Added++
+	case overwrite:
+		c.Replaced++
+	default:
+		c.Skipped++
+		return false
+	}
+	return true
+}
+
+// ImportResult summarizes a profile import
+type ImportResult struct {
+	Profile       string       `json:"profile"`
+	Created       bool         `json:"created"`
+	Sessions      ImportCounts `json:"sessions"`
+	Groups        ImportCounts `json:"groups"`
+	Notes         int          `json:"notes"`
+	MCPs          ImportCounts `json:"mcps"`
+	MCPBundles    ImportCounts `json:"mcp_bundles"`
+	LaunchConfigs ImportCounts `json:"launch_configs"`
+	Layouts       ImportCounts `json:"layouts"`
+	Favorites     ImportCounts `json:"favorites"`
+}
+
+// ImportProfileBundle writes a bundle's sessions into a new profile (or an
+// existing one with Merge) and merges its config and desktop state. Paths
+// are rewritten first.
+func ImportProfileBundle(b *ProfileBundle, opts ImportOptions) (*ImportResult, error) {
+	profile := opts.Profile
+	if profile == "" {
+		profile = b.Manifest.Profile
+	}
+	if profile == "" {
+		return nil, fmt.Errorf("no target profile: use --as")
+	}
+	if filepath.Base(profile) != profile || profile == "." || profile == ".." {
+		return nil, fmt.Errorf("invalid profile name: %s", profile)
+	}
+
+	maps := append([]PathMapping{}, opts.Maps...)
+	if home, err := os.UserHomeDir(); err == nil && b.Manifest.Home != "" && filepath.Clean(b.Manifest.Home) != home {
+		maps = append(maps, PathMapping{From: filepath.Clean(b.Manifest.Home), To: home})
+	}
+	b.RewritePaths(maps)
+
+	exists, err := ProfileExists(profile)
+	if err != nil {
+		return nil, err
+	}
+	if exists && !opts.Merge {
+		return nil, fmt.Errorf("profile '%s' already exists: use --merge to import into it, or --as to pick another name", profile)
+	}
+
+	result := &ImportResult{Profile: profile, Created: !exists}
+	if !exists && !opts.DryRun {
+		if err := CreateProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sessions and groups
+	var imported []string
+	if exists || !opts.DryRun {
+		storage, err := NewStorageWithProfile(profile)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = storage.Close() }()
+		if imported, err = importBundleSessions(storage, b.Data, opts, result); err != nil {
+			return nil, err
+		}
+	} else {
+		imported = mergeStorageData(&StorageData{SchemaVersion: StorageSchemaVersion}, b.Data, opts.Overwrite, result)
+	}
+	for _, id := range imported {
+		text, ok := b.Notes[id]
+		if !ok {
+			continue
+		}
+		result.Notes++
+		if !opts.DryRun {
+			if err := SaveNotes(profile, id, text); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if b.Config != nil {
+		if err := importBundleConfig(b.Config, opts, result); err != nil {
+			return nil, err
+		}
+	}
+	if len(b.Layouts) > 0 {
+		if err := importDesktopLayouts(b.Layouts, opts, result); err != nil {
+			return nil, err
+		}
+	}
+	if len(b.Favorites) > 0 {
+		if err := importQuickLaunchFavorites(b.Favorites, opts, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// importBundleSessions merges a bundle's sessions and groups into storage.
+// Load, merge and save happen under the profile lock, so a save from a
+// running TUI or CLI can't land in between and be overwritten.
+func importBundleSessions(storage *Storage, data *StorageData, opts ImportOptions, result *ImportResult) ([]string, error) {
+	if opts.DryRun {
+		target, err := storage.LoadStorageData()
+		if err != nil {
+			return nil, err
+		}
+		return mergeStorageData(target, data, opts.Overwrite, result), nil
+	}
+
+	unlock, err := storage.lockForUpdate()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	target, err := storage.loadStorageDataLocked()
+	if err != nil {
+		return nil, err
+	}
+	imported := mergeStorageData(target, data, opts.Overwrite, result)
+	if err := storage.saveStorageDataLocked(target); err != nil {
+		return nil, fmt.Errorf("failed to save sessions: %w", err)
+	}
+	return imported, nil
+}
+
+// mergeStorageData adds src's sessions and groups to dst and returns the IDs
+// of the sessions taken from src
+func mergeStorageData(dst, src *StorageData, overwrite bool, result *ImportResult) []string {
+	sessionIndex := make(map[string]int, len(dst.Instances))
+	for i, inst := range dst.Instances {
+		sessionIndex[inst.ID] = i
+	}
+	var imported []string
+	for _, inst := range src.Instances {
+		i, exists := sessionIndex[inst.ID]
+		if !result.Sessions.count(exists, overwrite) {
+			continue
+		}
+		if exists {
+			dst.Instances[i] = inst
+		} else {
+			dst.Instances = append(dst.Instances, inst)
+		}
+		imported = append(imported, inst.ID)
+	}
+
+	groupIndex := make(map[string]int, len(dst.Groups))
+	maxOrder := -1
+	for i, g := range dst.Groups {
+		groupIndex[g.Path] = i
+		if g.Order > maxOrder {
+			maxOrder = g.Order
+		}
+	}
+	for _, g := range src.Groups {
+		i, exists := groupIndex[g.Path]
+		if !result.Groups.count(exists, overwrite) {
+			continue
+		}
+		if exists {
+			g.Order = dst.Groups[i].Order
+			dst.Groups[i] = g
+			continue
+		}
+		if len(dst.Groups) > 0 {
+			maxOrder++
+			g.Order = maxOrder
+		}
+		dst.Groups = append(dst.Groups, g)
+	}
+	return imported
+}
+
+// importBundleConfig merges MCP definitions, MCP bundles and launch configs
+// into config.toml
+func importBundleConfig(bc *BundleConfig, opts ImportOptions, result *ImportResult) error {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	configCopy := *config
+	configCopy.MCPs = make(map[string]MCPDef, len(config.MCPs))
+	for k, v := range config.MCPs {
+		configCopy.MCPs[k] = v
+	}
+	configCopy.MCPBundles = make(map[string][]string, len(config.MCPBundles))
+	for k, v := range config.MCPBundles {
+		configCopy.MCPBundles[k] = v
+	}
+	configCopy.LaunchConfigs = make(map[string]LaunchConfig, len(config.LaunchConfigs))
+	for k, v := range config.LaunchConfigs {
+		configCopy.LaunchConfigs[k] = v
+	}
+
+	changed := false
+	for _, name := range sortedKeys(bc.MCPs) {
+		_, exists := configCopy.MCPs[name]
+		if result.MCPs.count(exists, opts.Overwrite) {
+			configCopy.MCPs[name] = bc.MCPs[name]
+			changed = true
+		}
+	}
+	for _, name := range sortedKeys(bc.MCPBundles) {
+		_, exists := configCopy.MCPBundles[name]
+		if result.MCPBundles.count(exists, opts.Overwrite) {
+			configCopy.MCPBundles[name] = bc.MCPBundles[name]
+			changed = true
+		}
+	}
+	for _, key := range sortedKeys(bc.LaunchConfigs) {
+		_, exists := configCopy.LaunchConfigs[key]
+		if result.LaunchConfigs.count(exists, opts.Overwrite) {
+			configCopy.LaunchConfigs[key] = bc.LaunchConfigs[key]
+			changed = true
+		}
+	}
+
+	if !changed || opts.DryRun {
+		return nil
+	}
+	if err := SaveUserConfig(&configCopy); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// readDesktopLayouts returns the desktop app's saved layouts
+func readDesktopLayouts() ([]map[string]interface{}, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, desktopLayoutsPath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved layouts: %w", err)
+	}
+	var file struct {
+		Layouts []map[string]interface{} `json:"layouts"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse saved layouts: %w", err)
+	}
+	return file.Layouts, nil
+}
+
+// importDesktopLayouts merges saved layouts by ID into desktop/layouts.json
+func importDesktopLayouts(layouts []map[string]interface{}, opts ImportOptions, result *ImportResult) error {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, desktopLayoutsPath)
+
+	file := map[string]interface{}{}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read saved layouts: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("failed to parse saved layouts: %w", err)
+		}
+	}
+	existing, _ := file["layouts"].([]interface{})
+	index := make(map[string]int, len(existing))
+	for i, l := range existing {
+		if m, ok := l.(map[string]interface{}); ok {
+			if id, ok := m["id"].(string); ok {
+				index[id] = i
+			}
+		}
+	}
+
+	changed := false
+	for _, layout := range layouts {
+		id, _ := layout["id"].(string)
+		i, exists := index[id]
+		exists = exists && id != ""
+		if !result.Layouts.count(exists, opts.Overwrite) {
+			continue
+		}
+		if exists {
+			existing[i] = layout
+		} else {
+			existing = append(existing, layout)
+		}
+		changed = true
+	}
+	if !changed || opts.DryRun {
+		return nil
+	}
+
+	file["layouts"] = existing
+	out, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode saved layouts: %w", err)
+	}
+	return writeFileAtomic(path, out)
+}
+
+// readQuickLaunchFavorites returns the desktop app's quick-launch favorites
+func readQuickLaunchFavorites() ([]map[string]interface{}, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return nil, err
+	}
+	var file struct {
+		Favorites []map[string]interface{} `toml:"favorites"`
+	}
+	if _, err := toml.DecodeFile(filepath.Join(dir, quickLaunchFileName), &file); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read quick-launch favorites: %w", err)
+	}
+	return file.Favorites, nil
+}
+
+// importQuickLaunchFavorites merges favorites by path into quick-launch.toml
+func importQuickLaunchFavorites(favorites []map[string]interface{}, opts ImportOptions, result *ImportResult) error {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, quickLaunchFileName)
+
+	file := map[string]interface{}{}
+	if _, err := toml.DecodeFile(path, &file); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read quick-launch favorites: %w", err)
+	}
+	var existing []map[string]interface{}
+	switch favs := file["favorites"].(type) {
+	case []map[string]interface{}:
+		existing = favs
+	case []interface{}:
+		for _, f := range favs {
+			if m, ok := f.(map[string]interface{}); ok {
+				existing = append(existing, m)
+			}
+		}
+	}
+	index := make(map[string]int, len(existing))
+	for i, f := range existing {
+		if p, ok := f["path"].(string); ok {
+			index[p] = i
+		}
+	}
+
+	changed := false
+	for _, fav := range favorites {
+		p, _ := fav["path"].(string)
+		i, exists := index[p]
+		if !result.Favorites.count(exists, opts.Overwrite) {
+			continue
+		}
+		if exists {
+			existing[i] = fav
+		} else {
+			index[p] = len(existing)
+			existing = append(existing, fav)
+		}
+		changed = true
+	}
+	if !changed || opts.DryRun {
+		return nil
+	}
+
+	file["favorites"] = existing
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(file); err != nil {
+		return fmt.Errorf("failed to encode quick-launch favorites: %w", err)
+	}
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// writeFileAtomic writes data to path through a temp file and rename
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
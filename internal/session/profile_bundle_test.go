@@ -0,0 +1,223 @@
+package session
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestProfile creates a profile with one local and one remote session,
+// a group, notes, an MCP definition and a quick-launch favorite
+func writeTestProfile(t *testing.T, home string) {
+	t.Helper()
+	if err := CreateProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+	storage, err := NewStorageWithProfile("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = storage.Close() }()
+	data := &StorageData{
+		SchemaVersion: StorageSchemaVersion,
+		Instances: []*InstanceData{
+			{ID: "local-1", Title: "api", ProjectPath: filepath.Join(home, "code/api"), GroupPath: "backend", Tool: "claude", Status: StatusIdle},
+			{ID: "remote-1", Title: "box", ProjectPath: filepath.Join(home, "code/box"), GroupPath: "backend", Tool: "claude", Status: StatusIdle, RemoteHost: "dev"},
+		},
+		Groups: []*GroupData{{Name: "backend", Path: "backend", DefaultPath: "/srv/backend"}},
+	}
+	if err := storage.SaveStorageData(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveNotes("work", "local-1", "left off in auth"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveUserConfig(&UserConfig{MCPs: map[string]MCPDef{"exa": {Command: "npx"}}}); err != nil {
+		t.Fatal(err)
+	}
+	dir, _ := GetAgentDeckDir()
+	fav := "[[favorites]]\nname = \"api\"\npath = \"" + filepath.Join(home, "code/api") + "\"\ntool = \"claude\"\n"
+	if err := os.WriteFile(filepath.Join(dir, quickLaunchFileName), []byte(fav), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exportTestBundle(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	bundle, err := LoadProfileBundle("work", ExportOptions{})
+	if err != nil {
+		t.Fatalf("LoadProfileBundle: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := bundle.Write(&buf); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	return &buf
+}
+
+func TestProfileBundle_RoundTrip(t *testing.T) {
+	oldHome := t.TempDir()
+	t.Setenv("HOME", oldHome)
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+	writeTestProfile(t, oldHome)
+	buf := exportTestBundle(t)
+
+	newHome := t.TempDir()
+	t.Setenv("HOME", newHome)
+	ClearUserConfigCache()
+
+	bundle, err := ReadProfileBundle(buf)
+	if err != nil {
+		t.Fatalf("ReadProfileBundle: %v", err)
+	}
+	result, err := ImportProfileBundle(bundle, ImportOptions{
+		Profile: "laptop",
+		Maps:    []PathMapping{{From: "/srv", To: "/opt"}},
+	})
+	if err != nil {
+		t.Fatalf("ImportProfileBundle: %v", err)
+	}
+	if !result.Created || result.Sessions.Added != 2 || result.Groups.Added != 1 || result.Notes != 1 ||
+		result.MCPs.Added != 1 || result.Favorites.Added != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	storage, err := NewStorageWithProfile("laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = storage.Close() }()
+	data, err := storage.LoadStorageData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := map[string]string{}
+	for _, inst := range data.Instances {
+		paths[inst.ID] = inst.ProjectPath
+	}
+	if want := filepath.Join(newHome, "code/api"); paths["local-1"] != want {
+		t.Errorf("local path = %q, want %q (home rewritten)", paths["local-1"], want)
+	}
+	if want := filepath.Join(oldHome, "code/box"); paths["remote-1"] != want {
+		t.Errorf("remote path = %q, want %q (unchanged)", paths["remote-1"], want)
+	}
+	if len(data.Groups) != 1 || data.Groups[0].DefaultPath != "/opt/backend" {
+		t.Errorf("group default path not mapped: %+v", data.Groups)
+	}
+	if notes, _ := LoadNotes("laptop", "local-1"); notes != "left off in auth\n" {
+		t.Errorf("notes = %q", notes)
+	}
+	if config, _ := LoadUserConfig(); config.MCPs["exa"].Command != "npx" {
+		t.Errorf("MCP definition not imported: %+v", config.MCPs)
+	}
+	favorites, err := readQuickLaunchFavorites()
+	if err != nil || len(favorites) != 1 || favorites[0]["path"] != filepath.Join(newHome, "code/api") {
+		t.Errorf("favorites = %v, %v", favorites, err)
+	}
+}
+
+func TestProfileBundle_MergeConflicts(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+	writeTestProfile(t, home)
+
+	bundle, err := ReadProfileBundle(exportTestBundle(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ImportProfileBundle(bundle, ImportOptions{}); err == nil {
+		t.Fatal("importing over an existing profile without Merge should fail")
+	}
+
+	bundle.Data.Instances[0].Title = "renamed"
+	result, err := ImportProfileBundle(bundle, ImportOptions{Merge: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sessions.Skipped != 2 || result.Groups.Skipped != 1 || result.MCPs.Skipped != 1 {
+		t.Errorf("merge without overwrite should skip everything: %+v", result)
+	}
+
+	result, err = ImportProfileBundle(bundle, ImportOptions{Merge: true, Overwrite: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Sessions.Replaced != 2 {
+		t.Errorf("overwrite should replace sessions: %+v", result)
+	}
+	storage, err := NewStorageWithProfile("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = storage.Close() }()
+	data, _ := storage.LoadStorageData()
+	if len(data.Instances) != 2 || data.Instances[0].Title != "renamed" {
+		t.Errorf("sessions after overwrite: %d, first %q", len(data.Instances), data.Instances[0].Title)
+	}
+}
+
+func TestProfileBundle_MasksSecrets(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+	writeTestProfile(t, home)
+	config := &UserConfig{MCPs: map[string]MCPDef{"exa": {Command: "npx", Env: map[string]string{"EXA_API_KEY": "abcdef1234567890"}}}}
+	if err := SaveUserConfig(config); err != nil {
+		t.Fatal(err)
+	}
+
+	bundle, err := LoadProfileBundle("work", ExportOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bundle.Config.MCPs["exa"].Env["EXA_API_KEY"]; got != "env:EXA_API_KEY" {
+		t.Errorf("exported secret = %q, want env:EXA_API_KEY", got)
+	}
+	if len(bundle.Manifest.MaskedSecrets) != 1 || bundle.Manifest.MaskedSecrets[0].Key != "EXA_API_KEY" {
+		t.Errorf("masked = %+v", bundle.Manifest.MaskedSecrets)
+	}
+	var buf bytes.Buffer
+	if err := bundle.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadProfileBundle(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read.Manifest.MaskedSecrets) != 1 {
+		t.Errorf("masked keys not kept in the manifest: %+v", read.Manifest)
+	}
+
+	bundle, err = LoadProfileBundle("work", ExportOptions{IncludeSecrets: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := bundle.Config.MCPs["exa"].Env["EXA_API_KEY"]; got != "abcdef1234567890" || len(bundle.Manifest.MaskedSecrets) != 0 {
+		t.Errorf("IncludeSecrets: secret = %q, masked = %+v", got, bundle.Manifest.MaskedSecrets)
+	}
+}
+
+func TestRewritePath(t *testing.T) {
+	maps := []PathMapping{{From: "/Users/a", To: "/home/a"}, {From: "/Users/a/code", To: "/src"}}
+	tests := map[string]string{
+		"/Users/a/notes":    "/home/a/notes",
+		"/Users/a/code/api": "/src/api",
+		"/Users/a":          "/home/a",
+		"/Users/ab":         "/Users/ab",
+		"~/code":            "~/code",
+		"":                  "",
+	}
+	for in, want := range tests {
+		if got := rewritePath(in, maps); got != want {
+			t.Errorf("rewritePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if _, err := ParsePathMapping("/a"); err == nil {
+		t.Error("ParsePathMapping should reject a mapping without =")
+	}
+}
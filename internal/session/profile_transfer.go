@@ -0,0 +1,291 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/tmux"
+)
+
+// TransferOptions controls TransferSession
+type TransferOptions struct {
+	Copy  bool   // Keep the session in the source profile and add a stopped copy
+	Group string // Group in the target profile (default: the session's own group)
+}
+
+// TransferredSession is one session moved or copied by TransferSession
+type TransferredSession struct {
+	ID    string `json:"id"`
+	NewID string `json:"new_id,omitempty"` // ID of the copy
+	Title string `json:"title"`
+}
+
+// TransferResult reports what TransferSession moved or copied
+type TransferResult struct {
+	From          string               `json:"from"`
+	To            string               `json:"to"`
+	Copy          bool                 `json:"copy"`
+	Sessions      []TransferredSession `json:"sessions"` // The session first, then its sub-sessions
+	GroupsCreated []string             `json:"groups_created,omitempty"`
+	// Parent session left behind in the source profile; the link was dropped
+	UnlinkedParent string `json:"unlinked_parent,omitempty"`
+}
+
+// TransferSession moves (or with opts.Copy, copies) a session and its
+// sub-sessions from one profile to another, with their groups, worktree
+// fields, tags and notes. Both profiles' storages stay locked from reading to
+// writing so no concurrent save is lost. A running session keeps running;
+// copies are stopped and get new IDs, new tmux sessions and a fresh
+// conversation.
+func TransferSession(from, to, id string, opts TransferOptions) (*TransferResult, error) {
+	from = GetEffectiveProfile(from)
+	if to == "" {
+		return nil, fmt.Errorf("target profile is required")
+	}
+	if from == to {
+		return nil, fmt.Errorf("session is already in profile '%s'", to)
+	}
+	if exists, err := ProfileExists(to); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, fmt.Errorf("profile '%s' does not exist (create it with: agent-deck profile create %s)", to, to)
+	}
+
+	src, err := NewStorageWithProfile(from)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = src.Close() }()
+	dst, err := NewStorageWithProfile(to)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = dst.Close() }()
+
+	// Lock in profile name order so transfers in opposite directions can't
+	// deadlock
+	first, second := src, dst
+	if to < from {
+		first, second = dst, src
+	}
+	unlockFirst, err := first.lockForUpdate()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockFirst()
+	unlockSecond, err := second.lockForUpdate()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockSecond()
+
+	srcData, err := src.loadStorageDataLocked()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile '%s': %w", from, err)
+	}
+	dstData, err := dst.loadStorageDataLocked()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load profile '%s': %w", to, err)
+	}
+
+	result, err := transferSessionData(srcData, dstData, id, opts)
+	if err != nil {
+		return nil, err
+	}
+	result.From, result.To = from, to
+
+	// Write the target first: failing in between leaves the session in both
+	// profiles rather than in neither
+	if err := dst.saveStorageDataLocked(dstData); err != nil {
+		return nil, fmt.Errorf("failed to save profile '%s': %w", to, err)
+	}
+	if !opts.Copy {
+		if err := src.saveStorageDataLocked(srcData); err != nil {
+			return nil, fmt.Errorf("session copied to '%s', but failed to remove it from '%s': %w", to, from, err)
+		}
+	}
+
+	for _, s := range result.Sessions {
+		if err := transferNotes(from, to, s, opts.Copy); err != nil {
+			log.Printf("[TRANSFER] %v", err)
+		}
+		if opts.Copy {
+			RecordAuditEntry(to, AuditEntry{Action: AuditCreate, SessionID: s.NewID, Title: s.Title, Detail: "copied from profile " + from + " (" + s.ID + ")"})
+		} else {
+			detail := "profile " + from + " -> " + to
+			RecordAuditEntry(from, AuditEntry{Action: AuditMove, SessionID: s.ID, Title: s.Title, Detail: detail})
+			RecordAuditEntry(to, AuditEntry{Action: AuditMove, SessionID: s.ID, Title: s.Title, Detail: detail})
+		}
+	}
+	return result, nil
+}
+
+// transferSessionData moves or copies session id and its sub-sessions from
+// src to dst, creating their groups in dst
+func transferSessionData(src, dst *StorageData, id string, opts TransferOptions) (*TransferResult, error) {
+	var root *InstanceData
+	for _, inst := range src.Instances {
+		if inst.ID == id {
+			root = inst
+			break
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+
+	// The session first, then its sub-sessions (breadth first)
+	subtree := []*InstanceData{root}
+	inSubtree := map[string]bool{root.ID: true}
+	for i := 0; i < len(subtree); i++ {
+		for _, inst := range src.Instances {
+			if inst.ParentSessionID == subtree[i].ID && !inSubtree[inst.ID] {
+				subtree = append(subtree, inst)
+				inSubtree[inst.ID] = true
+			}
+		}
+	}
+
+	dstIDs := make(map[string]bool, len(dst.Instances))
+	for _, inst := range dst.Instances {
+		dstIDs[inst.ID] = true
+	}
+	if !opts.Copy {
+		for _, inst := range subtree {
+			if dstIDs[inst.ID] {
+				return nil, fmt.Errorf("session %s already exists in the target profile", inst.ID)
+			}
+		}
+	}
+
+	result := &TransferResult{Copy: opts.Copy}
+	newIDs := make(map[string]string, len(subtree))
+	var moved []*InstanceData
+	for _, inst := range subtree {
+		out := inst
+		if opts.Copy {
+			out = copyInstanceData(inst)
+			newIDs[inst.ID] = out.ID
+		}
+		if opts.Group != "" && inst.GroupPath == root.GroupPath {
+			out.GroupPath = opts.Group
+		}
+		result.Sessions = append(result.Sessions, TransferredSession{ID: inst.ID, NewID: newIDs[inst.ID], Title: inst.Title})
+		moved = append(moved, out)
+	}
+
+	// Sub-sessions point at their (copied) parent; the top session keeps its
+	// parent only if the target profile has it
+	for _, inst := range moved[1:] {
+		if newID, ok := newIDs[inst.ParentSessionID]; ok {
+			inst.ParentSessionID = newID
+		}
+	}
+	if top := moved[0]; top.ParentSessionID != "" && !dstIDs[top.ParentSessionID] {
+		result.UnlinkedParent = top.ParentSessionID
+		top.ParentSessionID = ""
+		top.ParentProjectPath = ""
+	}
+
+	for _, inst := range moved {
+		result.GroupsCreated = append(result.GroupsCreated, ensureGroupData(src, dst, inst.GroupPath)...)
+	}
+	dst.Instances = append(dst.Instances, moved...)
+
+	if !opts.Copy {
+		kept := make([]*InstanceData, 0, len(src.Instances))
+		for _, inst := range src.Instances {
+			if !inSubtree[inst.ID] {
+				kept = append(kept, inst)
+			}
+		}
+		src.Instances = kept
+	}
+	return result, nil
+}
+
+// copyInstanceData returns a stopped copy of inst with a new ID and tmux
+// session. Tool conversation IDs are dropped so the copy doesn't write to
+// the original's conversation (fork a session to keep its context).
+func copyInstanceData(inst *InstanceData) *InstanceData {
+	c := *inst
+	c.ID = generateID()
+	c.TmuxSession = tmux.NewSession(inst.Title, inst.ProjectPath).Name
+	if c.RemoteHost != "" {
+		c.RemoteTmuxName = c.TmuxSession
+	}
+	c.Status = StatusIdle
+	c.CreatedAt = time.Now()
+	c.LastAccessedAt = time.Time{}
+	c.WaitingSince = time.Time{}
+	c.ClaudeSessionID, c.ClaudeDetectedAt = "", time.Time{}
+	c.GeminiSessionID, c.GeminiDetectedAt = "", time.Time{}
+	c.OpenCodeSessionID, c.OpenCodeDetectedAt = "", time.Time{}
+	c.LatestPrompt = ""
+	c.LoadedMCPNames = nil
+	c.PortForwards = append([]PortForward(nil), inst.PortForwards...)
+	c.Tags = append([]string(nil), inst.Tags...)
+	if inst.Meta != nil {
+		c.Meta = make(map[string]string, len(inst.Meta))
+		for k, v := range inst.Meta {
+			c.Meta[k] = v
+		}
+	}
+	return &c
+}
+
+// ensureGroupData adds groupPath and its parents to dst when missing, taking
+// their names and settings from src. Returns the paths added.
+func ensureGroupData(src, dst *StorageData, groupPath string) []string {
+	if groupPath == "" {
+		return nil
+	}
+	existing := make(map[string]bool, len(dst.Groups))
+	maxOrder := 0
+	for _, g := range dst.Groups {
+		existing[g.Path] = true
+		if g.Order > maxOrder {
+			maxOrder = g.Order
+		}
+	}
+
+	var added []string
+	parts := strings.Split(groupPath, "/")
+	for i := range parts {
+		path := strings.Join(parts[:i+1], "/")
+		if existing[path] {
+			continue
+		}
+		group := &GroupData{Name: parts[i], Path: path, Expanded: true}
+		for _, g := range src.Groups {
+			if g.Path == path {
+				copied := *g
+				group = &copied
+				break
+			}
+		}
+		maxOrder++
+		group.Order = maxOrder
+		dst.Groups = append(dst.Groups, group)
+		existing[path] = true
+		added = append(added, path)
+	}
+	return added
+}
+
+// transferNotes moves (or copies) a transferred session's notes
+func transferNotes(from, to string, s TransferredSession, keep bool) error {
+	notes, err := LoadNotes(from, s.ID)
+	if err != nil || notes == "" {
+		return err
+	}
+	if keep {
+		return SaveNotes(to, s.NewID, notes)
+	}
+	if err := SaveNotes(to, s.ID, notes); err != nil {
+		return err
+	}
+	return DeleteNotes(from, s.ID)
+}
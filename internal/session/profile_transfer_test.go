@@ -0,0 +1,137 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func setupTransferProfiles(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AGENTDECK_PROFILE", "")
+	ClearUserConfigCache()
+	t.Cleanup(ClearUserConfigCache)
+
+	personal, err := NewStorageWithProfile("personal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := personal.SaveStorageData(&StorageData{
+		Instances: []*InstanceData{
+			{ID: "lead", Title: "lead", ProjectPath: "/src/api", GroupPath: "clients/acme", Tool: "claude",
+				TmuxSession: "agentdeck_lead_1", ClaudeSessionID: "conv-1", WorktreeBranch: "feature", Tags: []string{"urgent"}},
+			{ID: "helper", Title: "helper", ProjectPath: "/src/api", GroupPath: "clients/acme", Tool: "claude",
+				TmuxSession: "agentdeck_helper_1", ParentSessionID: "lead", ParentProjectPath: "/src/api"},
+			{ID: "other", Title: "other", ProjectPath: "/src/blog", GroupPath: "blog", Tool: "shell", TmuxSession: "agentdeck_other_1"},
+		},
+		Groups: []*GroupData{
+			{Name: "Clients", Path: "clients", Expanded: false, Order: 0},
+			{Name: "ACME", Path: "clients/acme", Expanded: true, Order: 1, DefaultPath: "/src"},
+			{Name: "blog", Path: "blog", Order: 2},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveNotes("personal", "lead", "remember the API key rotation"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateProfile("work"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadProfileData(t *testing.T, profile string) *StorageData {
+	t.Helper()
+	s, err := NewStorageWithProfile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := s.LoadStorageData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestTransferSession_Move(t *testing.T) {
+	setupTransferProfiles(t)
+
+	result, err := TransferSession("personal", "work", "lead", TransferOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Sessions) != 2 || result.Sessions[1].ID != "helper" {
+		t.Fatalf("sub-session should move with its parent: %+v", result.Sessions)
+	}
+	if strings.Join(result.GroupsCreated, ",") != "clients,clients/acme" {
+		t.Errorf("groups created = %v", result.GroupsCreated)
+	}
+
+	personal := loadProfileData(t, "personal")
+	if len(personal.Instances) != 1 || personal.Instances[0].ID != "other" {
+		t.Errorf("personal should only keep 'other', got %d sessions", len(personal.Instances))
+	}
+	work := loadProfileData(t, "work")
+	lead, helper := sessionByID(work, "lead"), sessionByID(work, "helper")
+	if lead == nil || helper == nil {
+		t.Fatalf("sessions not in work: %+v", work.Instances)
+	}
+	if lead.TmuxSession != "agentdeck_lead_1" || lead.ClaudeSessionID != "conv-1" || lead.WorktreeBranch != "feature" {
+		t.Errorf("moved session lost fields: %+v", lead)
+	}
+	if helper.ParentSessionID != "lead" {
+		t.Errorf("parent link lost: %q", helper.ParentSessionID)
+	}
+	var acme *GroupData
+	for _, g := range work.Groups {
+		if g.Path == "clients/acme" {
+			acme = g
+		}
+	}
+	if acme == nil || acme.Name != "ACME" || acme.DefaultPath != "/src" {
+		t.Errorf("group settings not carried over: %+v", acme)
+	}
+	if notes, _ := LoadNotes("work", "lead"); notes == "" {
+		t.Error("notes should move with the session")
+	}
+	if notes, _ := LoadNotes("personal", "lead"); notes != "" {
+		t.Error("notes should be removed from the source profile")
+	}
+	if entries, _ := ReadAudit("personal", AuditFilter{SessionID: "lead", Actions: []string{AuditMove}}); len(entries) != 1 {
+		t.Errorf("move should be audited in the source profile, got %d", len(entries))
+	}
+
+	if _, err := TransferSession("personal", "work", "lead", TransferOptions{}); err == nil {
+		t.Error("moving a session that is no longer there should fail")
+	}
+	if _, err := TransferSession("work", "missing", "lead", TransferOptions{}); err == nil {
+		t.Error("moving to a profile that doesn't exist should fail")
+	}
+}
+
+func TestTransferSession_CopySubSession(t *testing.T) {
+	setupTransferProfiles(t)
+
+	result, err := TransferSession("personal", "work", "helper", TransferOptions{Copy: true, Group: "inbox"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.UnlinkedParent != "lead" {
+		t.Errorf("parent left behind should be reported: %+v", result)
+	}
+	if len(loadProfileData(t, "personal").Instances) != 3 {
+		t.Error("copy should leave the source profile alone")
+	}
+
+	work := loadProfileData(t, "work")
+	if len(work.Instances) != 1 {
+		t.Fatalf("want 1 copy, got %d", len(work.Instances))
+	}
+	c := work.Instances[0]
+	if c.ID == "helper" || c.ID != result.Sessions[0].NewID || c.TmuxSession == "agentdeck_helper_1" {
+		t.Errorf("copy should get a new ID and tmux session: %+v", c)
+	}
+	if c.ParentSessionID != "" || c.ParentProjectPath != "" || c.GroupPath != "inbox" || c.Status != StatusIdle {
+		t.Errorf("copy = %+v", c)
+	}
+}
@@ -0,0 +1,340 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// remoteCacheDirName holds one last-known snapshot per SSH host in each
+// profile (~/.agent-deck/profiles/<profile>/remote-cache/<host-id>.json), since
+// every profile tracks its own sessions on a host
+const remoteCacheDirName = "remote-cache"
+
+// remoteCachePreviewLines is how much terminal output is kept per session
+const remoteCachePreviewLines = 60
+
+// RemoteSessionSnapshot is the last known state of a remote session, shown
+// read-only while its host is unreachable
+type RemoteSessionSnapshot struct {
+	ID           string    `json:"id"`
+	Title        string    `json:"title"`
+	TmuxName     string    `json:"tmux_name,omitempty"`
+	ProjectPath  string    `json:"project_path,omitempty"`
+	GroupPath    string    `json:"group_path,omitempty"`
+	Tool         string    `json:"tool,omitempty"`
+	Status       string    `json:"status"`
+	Preview      string    `json:"preview,omitempty"`
+	PreviewAt    time.Time `json:"preview_at,omitempty"`
+	CreatedAt    time.Time `json:"created_at,omitempty"`
+	LastActivity time.Time `json:"last_activity,omitempty"`
+	// LastSeen is the last successful discovery that found the session
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// RemoteHostCache is the last-known snapshot of an SSH host's sessions
+type RemoteHostCache struct {
+	HostID string `json:"host_id"`
+	// UpdatedAt is the last successful contact with the host
+	UpdatedAt time.Time               `json:"updated_at"`
+	Sessions  []RemoteSessionSnapshot `json:"sessions"`
+}
+
+// Session returns the snapshot of a session, or nil
+func (c *RemoteHostCache) Session(id string) *RemoteSessionSnapshot {
+	for i := range c.Sessions {
+		if c.Sessions[i].ID == id {
+			return &c.Sessions[i]
+		}
+	}
+	return nil
+}
+
+// RemoteReconcileReport compares a discovery run with the host's last-known
+// snapshot, separating sessions that are gone from ones that merely couldn't
+// be reached
+type RemoteReconcileReport struct {
+	HostID    string `json:"host_id"`
+	Reachable bool   `json:"reachable"`
+	// LastContact is when the snapshot was taken (zero if there was none)
+	LastContact time.Time `json:"last_contact,omitempty"`
+	// Disappeared were on the host at last contact and no longer exist
+	Disappeared []RemoteSessionSnapshot `json:"disappeared,omitempty"`
+	// Unreachable are kept from the snapshot because the host is down
+	Unreachable []RemoteSessionSnapshot `json:"unreachable,omitempty"`
+	// Appeared are titles of sessions not in the snapshot
+	Appeared []string `json:"appeared,omitempty"`
+}
+
+// Summary describes the report in one line, or "" when nothing is notable
+func (r *RemoteReconcileReport) Summary() string {
+	if !r.Reachable {
+		if len(r.Unreachable) == 0 {
+			return ""
+		}
+		return fmt.Sprintf("%s unreachable: showing %d session(s) from %s", r.HostID, len(r.Unreachable), formatCacheAge(r.LastContact))
+	}
+	if len(r.Disappeared) == 0 {
+		return ""
+	}
+	titles := make([]string, len(r.Disappeared))
+	for i, s := range r.Disappeared {
+		titles[i] = s.Title
+	}
+	return fmt.Sprintf("%s: %d session(s) gone since %s: %s", r.HostID, len(r.Disappeared), formatCacheAge(r.LastContact), strings.Join(titles, ", "))
+}
+
+func formatCacheAge(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+var (
+	remoteCacheMu sync.Mutex
+	// remoteCaches holds loaded snapshots by remoteCacheKey; previews are
+	// updated in memory and written with the next reconcile
+	remoteCaches = make(map[string]*remoteCacheEntry)
+)
+
+// remoteCacheEntry is a loaded snapshot and the mtime of the file it was read
+// from or written to, so a rewrite by another process is picked up
+type remoteCacheEntry struct {
+	cache   *RemoteHostCache
+	modTime time.Time
+}
+
+func remoteCacheKey(profile, hostID string) string {
+	return profile + "/" + hostID
+}
+
+// remoteCachePath returns ~/.agent-deck/profiles/<profile>/remote-cache/<host-id>.json
+func remoteCachePath(profile, hostID string) (string, error) {
+	dir, err := GetProfileDir(profile)
+	if err != nil {
+		return "", err
+	}
+	name := filepath.Base(hostID)
+	if name == "." || name == ".." || name == string(filepath.Separator) {
+		return "", fmt.Errorf("invalid host id: %q", hostID)
+	}
+	return filepath.Join(dir, remoteCacheDirName, name+".json"), nil
+}
+
+// loadRemoteHostCacheLocked returns the in-memory snapshot of a host in a
+// profile, reading it from disk the first time and again whenever the file's
+// mtime changes. Returns nil if there is none.
+func loadRemoteHostCacheLocked(profile, hostID string) *RemoteHostCache {
+	key := remoteCacheKey(profile, hostID)
+	entry := remoteCaches[key]
+	path, err := remoteCachePath(profile, hostID)
+	if err != nil {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("[REMOTE-CACHE] Failed to stat %s: %v", path, err)
+		}
+		if entry != nil {
+			return entry.cache
+		}
+		return nil
+	}
+	if entry != nil && info.ModTime().Equal(entry.modTime) {
+		return entry.cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Printf("[REMOTE-CACHE] Failed to read %s: %v", path, err)
+		if entry != nil {
+			return entry.cache
+		}
+		return nil
+	}
+	var c RemoteHostCache
+	if err := json.Unmarshal(data, &c); err != nil {
+		log.Printf("[REMOTE-CACHE] Ignoring corrupt %s: %v", path, err)
+		if entry != nil {
+			return entry.cache
+		}
+		return nil
+	}
+	// Previews captured here since the last save are newer than the file's
+	if entry != nil {
+		for i := range c.Sessions {
+			if old := entry.cache.Session(c.Sessions[i].ID); old != nil && old.PreviewAt.After(c.Sessions[i].PreviewAt) {
+				c.Sessions[i].Preview, c.Sessions[i].PreviewAt = old.Preview, old.PreviewAt
+			}
+		}
+	}
+	remoteCaches[key] = &remoteCacheEntry{cache: &c, modTime: info.ModTime()}
+	return &c
+}
+
+func saveRemoteHostCacheLocked(profile string, c *RemoteHostCache) error {
+	key := remoteCacheKey(profile, c.HostID)
+	remoteCaches[key] = &remoteCacheEntry{cache: c}
+	path, err := remoteCachePath(profile, c.HostID)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+	if info, err := os.Stat(path); err == nil {
+		remoteCaches[key].modTime = info.ModTime()
+	}
+	return nil
+}
+
+// GetRemoteHostCache returns a copy of the last-known snapshot of a host in
+// a profile, or nil if it was never reached
+func GetRemoteHostCache(profile, hostID string) *RemoteHostCache {
+	profile = GetEffectiveProfile(profile)
+	remoteCacheMu.Lock()
+	defer remoteCacheMu.Unlock()
+	c := loadRemoteHostCacheLocked(profile, hostID)
+	if c == nil {
+		return nil
+	}
+	cp := *c
+	cp.Sessions = append([]RemoteSessionSnapshot(nil), c.Sessions...)
+	return &cp
+}
+
+// GetRemoteSessionSnapshot returns the last-known state of a remote session, or nil
+func GetRemoteSessionSnapshot(profile, hostID, sessionID string) *RemoteSessionSnapshot {
+	c := GetRemoteHostCache(profile, hostID)
+	if c == nil {
+		return nil
+	}
+	return c.Session(sessionID)
+}
+
+// SetRemoteSessionPreview records the latest terminal output of a remote
+// session for its snapshot. It is kept in memory and saved by the next
+// ReconcileRemoteHost.
+func SetRemoteSessionPreview(profile, hostID, sessionID, content string) {
+	profile = GetEffectiveProfile(profile)
+	remoteCacheMu.Lock()
+	defer remoteCacheMu.Unlock()
+	c := loadRemoteHostCacheLocked(profile, hostID)
+	if c == nil {
+		return
+	}
+	if s := c.Session(sessionID); s != nil {
+		s.Preview = lastLines(content, remoteCachePreviewLines)
+		s.PreviewAt = time.Now()
+	}
+}
+
+// ReconcileRemoteHost compares a discovery run for hostID with the host's
+// last-known snapshot in profile. instances are the profile's sessions after
+// discovery and staleIDs the ones discovery found gone; discoverErr is the
+// host's discovery error, if any. A reachable host's snapshot is then
+// replaced (keeping previews); an unreachable host's snapshot is left as is
+// and its sessions are reported as unreachable rather than gone.
+func ReconcileRemoteHost(profile, hostID string, instances []*Instance, staleIDs []string, discoverErr error) *RemoteReconcileReport {
+	profile = GetEffectiveProfile(profile)
+	remoteCacheMu.Lock()
+	defer remoteCacheMu.Unlock()
+
+	report := &RemoteReconcileReport{HostID: hostID, Reachable: discoverErr == nil}
+	cache := loadRemoteHostCacheLocked(profile, hostID)
+	if cache != nil {
+		report.LastContact = cache.UpdatedAt
+	}
+
+	if discoverErr != nil {
+		if cache != nil {
+			report.Unreachable = append(report.Unreachable, cache.Sessions...)
+		}
+		return report
+	}
+
+	stale := make(map[string]bool, len(staleIDs))
+	for _, id := range staleIDs {
+		stale[id] = true
+	}
+	now := time.Now()
+	next := &RemoteHostCache{HostID: hostID, UpdatedAt: now}
+	alive := make(map[string]bool)
+	for _, inst := range instances {
+		if inst.RemoteHost != hostID || stale[inst.ID] {
+			continue
+		}
+		alive[inst.ID] = true
+		snap := RemoteSessionSnapshot{
+			ID:           inst.ID,
+			Title:        inst.Title,
+			TmuxName:     effectiveRemoteTmuxName(inst),
+			ProjectPath:  inst.ProjectPath,
+			GroupPath:    inst.GroupPath,
+			Tool:         inst.Tool,
+			Status:       string(inst.Status),
+			CreatedAt:    inst.CreatedAt,
+			LastActivity: inst.GetLastActivityTime(),
+			LastSeen:     now,
+		}
+		if cache != nil {
+			if old := cache.Session(inst.ID); old != nil {
+				snap.Preview, snap.PreviewAt = old.Preview, old.PreviewAt
+			} else {
+				report.Appeared = append(report.Appeared, inst.Title)
+			}
+		}
+		next.Sessions = append(next.Sessions, snap)
+	}
+	sort.Slice(next.Sessions, func(i, j int) bool { return next.Sessions[i].ID < next.Sessions[j].ID })
+
+	if cache != nil {
+		for _, s := range cache.Sessions {
+			if !alive[s.ID] {
+				report.Disappeared = append(report.Disappeared, s)
+			}
+		}
+	}
+
+	if err := saveRemoteHostCacheLocked(profile, next); err != nil {
+		log.Printf("[REMOTE-CACHE] Failed to save snapshot of %s: %v", hostID, err)
+	}
+	return report
+}
+
+// lastLines returns the last n lines of s, without trailing blank lines
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, " \t\r\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
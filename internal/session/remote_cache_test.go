@@ -0,0 +1,141 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetRemoteCache points the cache at a temp home and drops loaded snapshots
+func resetRemoteCache(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	remoteCacheMu.Lock()
+	remoteCaches = make(map[string]*remoteCacheEntry)
+	remoteCacheMu.Unlock()
+}
+
+func remoteTestInstance(id, title, host string) *Instance {
+	inst := NewInstance(title, "/srv/"+title)
+	inst.ID = id
+	inst.RemoteHost = host
+	inst.RemoteTmuxName = "agentdeck_" + title + "_0000abcd"
+	return inst
+}
+
+func TestReconcileRemoteHost(t *testing.T) {
+	resetRemoteCache(t)
+
+	api := remoteTestInstance("r1", "api", "box")
+	web := remoteTestInstance("r2", "web", "box")
+	other := remoteTestInstance("r3", "db", "other")
+
+	// First contact: nothing to compare against
+	report := ReconcileRemoteHost("default", "box", []*Instance{api, web, other}, nil, nil)
+	if !report.Reachable || len(report.Disappeared) != 0 || len(report.Appeared) != 0 {
+		t.Fatalf("first reconcile = %+v, want reachable with no changes", report)
+	}
+	cache := GetRemoteHostCache("default", "box")
+	if cache == nil || len(cache.Sessions) != 2 {
+		t.Fatalf("snapshot = %+v, want api and web only", cache)
+	}
+
+	SetRemoteSessionPreview("default", "box", "r1", "line1\nline2\n\n")
+
+	// Host down: sessions are unreachable, not gone, and the snapshot stays
+	report = ReconcileRemoteHost("default", "box", []*Instance{api, web}, nil, errors.New("dial tcp: timeout"))
+	if report.Reachable || len(report.Unreachable) != 2 || len(report.Disappeared) != 0 {
+		t.Fatalf("offline reconcile = %+v, want 2 unreachable", report)
+	}
+	if !strings.Contains(report.Summary(), "unreachable") {
+		t.Errorf("Summary() = %q, want unreachable notice", report.Summary())
+	}
+
+	// Back online: web was removed on the host, a new session appeared
+	worker := remoteTestInstance("r4", "worker", "box")
+	report = ReconcileRemoteHost("default", "box", []*Instance{api, web, worker}, []string{"r2"}, nil)
+	if len(report.Disappeared) != 1 || report.Disappeared[0].Title != "web" {
+		t.Errorf("Disappeared = %+v, want [web]", report.Disappeared)
+	}
+	if len(report.Appeared) != 1 || report.Appeared[0] != "worker" {
+		t.Errorf("Appeared = %v, want [worker]", report.Appeared)
+	}
+	if !strings.Contains(report.Summary(), "web") {
+		t.Errorf("Summary() = %q, want it to name web", report.Summary())
+	}
+
+	// The preview survives the reconcile and is reloaded from disk
+	remoteCacheMu.Lock()
+	remoteCaches = make(map[string]*remoteCacheEntry)
+	remoteCacheMu.Unlock()
+	snap := GetRemoteSessionSnapshot("default", "box", "r1")
+	if snap == nil || snap.Preview != "line1\nline2" {
+		t.Fatalf("snapshot of api = %+v, want preview kept", snap)
+	}
+	if GetRemoteSessionSnapshot("default", "box", "r2") != nil {
+		t.Error("web should be gone from the snapshot")
+	}
+}
+
+func TestReconcileRemoteHost_PerProfile(t *testing.T) {
+	resetRemoteCache(t)
+
+	api := remoteTestInstance("r1", "api", "box")
+	web := remoteTestInstance("r2", "web", "box")
+
+	// Each profile has its own sessions on the same host
+	ReconcileRemoteHost("work", "box", []*Instance{api}, nil, nil)
+	ReconcileRemoteHost("personal", "box", []*Instance{web}, nil, nil)
+	report := ReconcileRemoteHost("work", "box", []*Instance{api}, nil, nil)
+	if len(report.Disappeared) != 0 {
+		t.Errorf("work Disappeared = %+v, want none", report.Disappeared)
+	}
+	report = ReconcileRemoteHost("personal", "box", []*Instance{web}, nil, nil)
+	if len(report.Disappeared) != 0 {
+		t.Errorf("personal Disappeared = %+v, want none", report.Disappeared)
+	}
+}
+
+func TestGetRemoteHostCache_ReloadsRewrittenFile(t *testing.T) {
+	resetRemoteCache(t)
+
+	api := remoteTestInstance("r1", "api", "box")
+	web := remoteTestInstance("r2", "web", "box")
+	ReconcileRemoteHost("default", "box", []*Instance{api}, nil, nil)
+	if c := GetRemoteHostCache("default", "box"); c == nil || len(c.Sessions) != 1 {
+		t.Fatalf("snapshot = %+v, want api only", c)
+	}
+
+	// Another process rewrites the snapshot
+	path, err := remoteCachePath("default", "box")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(&RemoteHostCache{HostID: "box", UpdatedAt: time.Now(), Sessions: []RemoteSessionSnapshot{{ID: "r1"}, {ID: web.ID}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(2 * time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if c := GetRemoteHostCache("default", "box"); c == nil || len(c.Sessions) != 2 {
+		t.Errorf("snapshot = %+v, want the rewritten file with 2 sessions", c)
+	}
+}
+
+func TestLastLines(t *testing.T) {
+	if got := lastLines("a\nb\nc\n\n", 2); got != "b\nc" {
+		t.Errorf("lastLines = %q, want %q", got, "b\nc")
+	}
+	if got := lastLines("a", 5); got != "a" {
+		t.Errorf("lastLines = %q, want %q", got, "a")
+	}
+}
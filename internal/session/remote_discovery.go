@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -39,7 +40,7 @@ type UpdatedInstance struct {
 	NewGroupPath string
 }
 
-// RemoteStorageSnapshot contains data fetched from remote sessions.json
+// RemoteStorageSnapshot contains data fetched from the remote's storage
 type RemoteStorageSnapshot struct {
 	Groups              []*GroupData      // Remote's group definitions
 	SessionGroupPaths   map[string]string // tmux_session name -> group_path mapping
@@ -56,26 +57,36 @@ var agentDeckSessionPattern = regexp.MustCompile(`^agentdeck_(.+)_([0-9a-f]{8})$
 // Note: Uses 'default' profile. Multi-profile support would require profile detection.
 const remoteSessionsJSONPath = "~/.agent-deck/profiles/default/sessions.json"
 
-// FetchRemoteStorageSnapshot reads the remote's sessions.json to get group structure
+// remoteStorageCommand prints the remote's default profile through its
+// storage backend (sessions.json or sessions.db), then, after
+// remoteStorageSeparator, sessions.json for agent-deck versions without
+// 'debug storage'
+const (
+	remoteStorageSeparator = "--- agent-deck sessions.json ---"
+	remoteStorageCommand   = "agent-deck -p default debug storage 2>/dev/null; echo; echo '" + remoteStorageSeparator + "'; cat " + remoteSessionsJSONPath + " 2>/dev/null"
+)
+
+// FetchRemoteStorageSnapshot reads the remote's sessions and groups to get group structure
 // Returns nil on errors (gracefully degrades to flat structure)
 func FetchRemoteStorageSnapshot(sshExec *tmux.SSHExecutor) *RemoteStorageSnapshot {
-	// Read remote sessions.json
-	output, err := sshExec.RunCommand("cat " + remoteSessionsJSONPath + " 2>/dev/null || echo '{}'")
+	snapshot, err := fetchRemoteStorageSnapshot(sshExec)
 	if err != nil {
-		log.Printf("[REMOTE-DISCOVERY] Failed to read remote sessions.json: %v", err)
-		return nil
+		log.Printf("[REMOTE-DISCOVERY] Failed to read remote sessions: %v", err)
 	}
+	return snapshot
+}
 
-	output = strings.TrimSpace(output)
-	if output == "" || output == "{}" {
-		return nil
+// fetchRemoteStorageSnapshot is FetchRemoteStorageSnapshot, returning an error
+// when the remote storage couldn't be read over SSH. Missing or unparsable
+// storage is not an error (nil snapshot).
+func fetchRemoteStorageSnapshot(sshExec *tmux.SSHExecutor) (*RemoteStorageSnapshot, error) {
+	output, err := sshExec.RunCommand(remoteStorageCommand)
+	if err != nil {
+		return nil, err
 	}
-
-	// Parse JSON
-	var data StorageData
-	if err := json.Unmarshal([]byte(output), &data); err != nil {
-		log.Printf("[REMOTE-DISCOVERY] Failed to parse remote sessions.json: %v", err)
-		return nil
+	data := parseRemoteStorage(output)
+	if data == nil {
+		return nil, nil
 	}
 
 	// Build session-to-group, session-to-tool, session-to-title, and session-to-custom-label mappings
@@ -119,7 +130,25 @@ func FetchRemoteStorageSnapshot(sshExec *tmux.SSHExecutor) *RemoteStorageSnapsho
 		SessionTitles:       sessionTitles,
 		SessionCustomLabels: sessionCustomLabels,
 		AllSessions:         allSessions,
+	}, nil
+}
+
+// parseRemoteStorage parses the output of remoteStorageCommand, preferring
+// what the remote's backend returned. Returns nil if neither part parses.
+func parseRemoteStorage(output string) *StorageData {
+	viaBackend, file, _ := strings.Cut(output, remoteStorageSeparator)
+	for _, part := range []string{viaBackend, file} {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "{}" {
+			continue
+		}
+		var data StorageData
+		if err := json.Unmarshal([]byte(part), &data); err != nil {
+			continue // e.g. an agent-deck without 'debug storage' printing usage
+		}
+		return &data
 	}
+	return nil
 }
 
 // resolveRemoteHostGroupPath resolves the local group path for a remote host.
@@ -187,6 +216,23 @@ func TransformRemoteGroups(remoteGroups []*GroupData, groupPrefix, groupName str
 	return transformed
 }
 
+// AutoDiscoverHostIDs returns the configured SSH hosts with auto_discover
+// enabled, sorted, or nil when remote discovery is off
+func AutoDiscoverHostIDs() []string {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || !GetRemoteDiscoverySettings().Enabled {
+		return nil
+	}
+	var hostIDs []string
+	for hostID, hostDef := range config.SSHHosts {
+		if hostDef.AutoDiscover {
+			hostIDs = append(hostIDs, hostID)
+		}
+	}
+	sort.Strings(hostIDs)
+	return hostIDs
+}
+
 // DiscoverRemoteTmuxSessions discovers agentdeck_* sessions from all configured SSH hosts
 // that have auto_discover enabled. It returns newly discovered instances, updated instances
 // (existing sessions whose group paths changed), stale instance IDs, remote groups
@@ -295,7 +341,10 @@ func DiscoverRemoteSessionsForHost(hostID string, existing []*Instance) ([]*Inst
 	}
 
 	// Fetch remote storage snapshot to get group structure
-	remoteSnapshot := FetchRemoteStorageSnapshot(sshExec)
+	remoteSnapshot, snapshotErr := fetchRemoteStorageSnapshot(sshExec)
+	if snapshotErr != nil {
+		log.Printf("[REMOTE-DISCOVERY] Failed to read remote sessions: %v", snapshotErr)
+	}
 
 	// Transform remote groups to local paths
 	var transformedGroups []*GroupData
@@ -305,7 +354,12 @@ func DiscoverRemoteSessionsForHost(hostID string, existing []*Instance) ([]*Inst
 
 	// Find stale sessions (ones we have locally but no longer exist on remote)
 	// A session is only stale if it's not in running tmux AND not in sessions.json
-	staleIDs := FindStaleRemoteSessionsWithSnapshot(existing, hostID, remoteSessions, remoteSnapshot)
+	// If the connection dropped after listing tmux, sessions.json is unknown:
+	// keep everything rather than prune sessions that are merely unreachable
+	var staleIDs []string
+	if snapshotErr == nil {
+		staleIDs = FindStaleRemoteSessionsWithSnapshot(existing, hostID, remoteSessions, remoteSnapshot)
+	}
 
 	var discovered []*Instance
 	var updated []*UpdatedInstance
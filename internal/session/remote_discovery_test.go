@@ -237,6 +237,38 @@ func TestParseTitleFromTmuxName(t *testing.T) {
 	}
 }
 
+func TestParseRemoteStorage(t *testing.T) {
+	backend := `{"instances":[{"id":"db","tmux_session":"agentdeck_db_1"}]}`
+	file := `{"instances":[{"id":"file","tmux_session":"agentdeck_file_1"}]}`
+	usage := "Unknown debug subcommand: storage\nUsage: agent-deck debug <subcommand>"
+
+	tests := []struct {
+		name   string
+		output string
+		wantID string
+	}{
+		{name: "backend wins", output: backend + "\n" + remoteStorageSeparator + "\n" + file, wantID: "db"},
+		{name: "old binary falls back to file", output: usage + "\n" + remoteStorageSeparator + "\n" + file, wantID: "file"},
+		{name: "no agent-deck on PATH", output: "\n" + remoteStorageSeparator + "\n" + file, wantID: "file"},
+		{name: "nothing", output: "\n" + remoteStorageSeparator + "\n", wantID: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := parseRemoteStorage(tt.output)
+			if tt.wantID == "" {
+				if data != nil {
+					t.Fatalf("parseRemoteStorage() = %+v, want nil", data)
+				}
+				return
+			}
+			if data == nil || len(data.Instances) != 1 || data.Instances[0].ID != tt.wantID {
+				t.Fatalf("parseRemoteStorage() = %+v, want instance %q", data, tt.wantID)
+			}
+		})
+	}
+}
+
 func TestEffectiveRemoteTmuxName(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -0,0 +1,177 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// forwardedSocketPrefix marks pool sockets forwarded to a remote host.
+// It differs from the local pool prefix so a remote agent-deck pool on the
+// same host never collides with forwarded sockets.
+const forwardedSocketPrefix = "agentdeck-fwd-"
+
+var unsafeSocketChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// remoteMCPSocketPath returns where a forwarded pool socket is exposed on the
+// remote host: /tmp/agentdeck-fwd-<local hostname>-<mcp>.sock. The local
+// hostname keeps forwards from different machines to the same host apart.
+func remoteMCPSocketPath(name string) string {
+	local, err := os.Hostname()
+	if err != nil || local == "" {
+		local = "local"
+	}
+	local = unsafeSocketChars.ReplaceAllString(strings.Split(local, ".")[0], "_")
+	return fmt.Sprintf("/tmp/%s%s-%s.sock", forwardedSocketPrefix, local, name)
+}
+
+// localPoolSocketPath returns the local socket of a running pooled MCP, or ""
+func localPoolSocketPath(name string) string {
+	if pool := GetGlobalPool(); pool != nil {
+		if pool.IsRunning(name) {
+			return pool.GetSocketPath(name)
+		}
+		return ""
+	}
+	// CLI mode: use the TUI's pool socket if it is alive
+	return getExternalSocketPath(name)
+}
+
+// ForwardMCPSocketsToHost forwards the pool sockets of the host's
+// forward_mcps to the remote host over its SSH ControlMaster.
+// Returns MCP name -> remote socket path for every MCP that was forwarded;
+// MCPs that aren't running in the pool are skipped.
+func ForwardMCPSocketsToHost(hostID string) (map[string]string, error) {
+	def := GetSSHHostDef(hostID)
+	if def == nil || len(def.ForwardMCPs) == 0 {
+		return nil, nil
+	}
+
+	conn, err := sshpkg.DefaultPool().Get(hostID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", hostID, err)
+	}
+
+	forwarded := make(map[string]string)
+	for _, name := range def.ForwardMCPs {
+		localPath := localPoolSocketPath(name)
+		if localPath == "" {
+			log.Printf("[MCP-FWD] %s: %s not running in pool, not forwarded", hostID, name)
+			continue
+		}
+		remotePath := remoteMCPSocketPath(name)
+		if err := conn.ForwardRemoteSocket(remotePath, localPath); err != nil {
+			log.Printf("[MCP-FWD] %s: forwarding %s failed: %v", hostID, name, err)
+			continue
+		}
+		log.Printf("[MCP-FWD] ✓ %s: %s → %s", hostID, localPath, remotePath)
+		forwarded[name] = remotePath
+	}
+	return forwarded, nil
+}
+
+// mergeForwardedMCPServers updates a remote .mcp.json: forwarded MCPs get
+// nc entries for their remote sockets, stale forwarded entries are removed,
+// and everything else is preserved.
+func mergeForwardedMCPServers(existing []byte, forwarded map[string]string) ([]byte, error) {
+	rawConfig := make(map[string]interface{})
+	if len(strings.TrimSpace(string(existing))) > 0 {
+		if err := json.Unmarshal(existing, &rawConfig); err != nil {
+			return nil, fmt.Errorf("failed to parse remote .mcp.json: %w", err)
+		}
+	}
+
+	servers, _ := rawConfig["mcpServers"].(map[string]interface{})
+	if servers == nil {
+		servers = make(map[string]interface{})
+	}
+	for name, entry := range servers {
+		server, ok := entry.(map[string]interface{})
+		if !ok || server["command"] != "nc" {
+			continue
+		}
+		args, _ := server["args"].([]interface{})
+		if len(args) == 2 {
+			if socketPath, _ := args[1].(string); strings.HasPrefix(filepath.Base(socketPath), forwardedSocketPrefix) {
+				if _, still := forwarded[name]; !still {
+					delete(servers, name)
+				}
+			}
+		}
+	}
+	for name, remotePath := range forwarded {
+		servers[name] = MCPServerConfig{
+			Command: "nc",
+			Args:    []string{"-U", remotePath},
+		}
+	}
+	rawConfig["mcpServers"] = servers
+
+	return json.MarshalIndent(rawConfig, "", "  ")
+}
+
+// remoteShellPath quotes a remote path, keeping a leading ~/ expandable
+func remoteShellPath(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		return `"$HOME"/` + sshpkg.ShellQuote(path[2:])
+	}
+	return sshpkg.ShellQuote(path)
+}
+
+// WriteRemoteMCPJson writes forwarded MCP entries into .mcp.json in a
+// project directory on the remote host
+func WriteRemoteMCPJson(hostID, projectPath string, forwarded map[string]string) error {
+	conn, err := sshpkg.DefaultPool().Get(hostID)
+	if err != nil {
+		return fmt.Errorf("failed to connect to %s: %w", hostID, err)
+	}
+
+	mcpFile := strings.TrimRight(projectPath, "/") + "/.mcp.json"
+	existing, err := conn.RunCommand(fmt.Sprintf("cat %s 2>/dev/null || true", remoteShellPath(mcpFile)))
+	if err != nil {
+		return fmt.Errorf("failed to read remote .mcp.json: %w", err)
+	}
+
+	data, err := mergeForwardedMCPServers([]byte(existing), forwarded)
+	if err != nil {
+		return err
+	}
+
+	// Atomic write on the remote side
+	tmpFile := remoteShellPath(mcpFile + ".tmp")
+	command := fmt.Sprintf("cat > %s && mv %s %s", tmpFile, tmpFile, remoteShellPath(mcpFile))
+	if _, err := conn.RunCommandWithStdin(command, strings.NewReader(string(data))); err != nil {
+		return fmt.Errorf("failed to write remote .mcp.json: %w", err)
+	}
+	return nil
+}
+
+// setupRemoteMCPForwards forwards pool sockets for a remote Claude session
+// and points its remote .mcp.json at them. Failures are logged, not fatal:
+// the session still starts with whatever MCPs the host provides.
+func (i *Instance) setupRemoteMCPForwards() {
+	if !i.IsRemote() || i.Tool != "claude" {
+		return
+	}
+	def := GetSSHHostDef(i.RemoteHost)
+	if def == nil || len(def.ForwardMCPs) == 0 {
+		return
+	}
+
+	forwarded, err := ForwardMCPSocketsToHost(i.RemoteHost)
+	if err != nil {
+		log.Printf("[MCP-FWD] %s: %v", i.RemoteHost, err)
+		return
+	}
+	if err := WriteRemoteMCPJson(i.RemoteHost, i.ProjectPath, forwarded); err != nil {
+		log.Printf("[MCP-FWD] %s: %v", i.RemoteHost, err)
+		return
+	}
+	log.Printf("[MCP-FWD] %s: %d forwarded MCPs written to %s/.mcp.json", i.Title, len(forwarded), i.ProjectPath)
+}
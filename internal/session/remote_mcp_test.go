@@ -0,0 +1,77 @@
+package session
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoteMCPSocketPath(t *testing.T) {
+	path := remoteMCPSocketPath("exa")
+	base := filepath.Base(path)
+	if filepath.Dir(path) != "/tmp" || !strings.HasPrefix(base, forwardedSocketPrefix) || !strings.HasSuffix(base, "-exa.sock") {
+		t.Errorf("unexpected remote socket path %q", path)
+	}
+	if strings.HasPrefix(base, "agentdeck-mcp-") {
+		t.Error("forwarded sockets must not use the local pool prefix")
+	}
+}
+
+func TestMergeForwardedMCPServers(t *testing.T) {
+	existing := `{
+  "mcpServers": {
+    "local-only": {"command": "uvx", "args": ["mcp-server-fetch"]},
+    "old": {"command": "nc", "args": ["-U", "/tmp/agentdeck-fwd-laptop-old.sock"]},
+    "exa": {"command": "npx", "args": ["-y", "exa-mcp-server"]}
+  },
+  "other": true
+}`
+	data, err := mergeForwardedMCPServers([]byte(existing), map[string]string{
+		"exa": "/tmp/agentdeck-fwd-laptop-exa.sock",
+	})
+	if err != nil {
+		t.Fatalf("mergeForwardedMCPServers failed: %v", err)
+	}
+
+	var cfg struct {
+		MCPServers map[string]MCPServerConfig `json:"mcpServers"`
+		Other      bool                       `json:"other"`
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Other {
+		t.Error("unrelated fields must be preserved")
+	}
+	if cfg.MCPServers["local-only"].Command != "uvx" {
+		t.Error("remote-defined MCPs must be preserved")
+	}
+	if _, ok := cfg.MCPServers["old"]; ok {
+		t.Error("stale forwarded entries should be removed")
+	}
+	exa := cfg.MCPServers["exa"]
+	if exa.Command != "nc" || len(exa.Args) != 2 || exa.Args[1] != "/tmp/agentdeck-fwd-laptop-exa.sock" {
+		t.Errorf("exa should point at the forwarded socket, got %+v", exa)
+	}
+
+	// Empty remote file starts a fresh config
+	data, err = mergeForwardedMCPServers(nil, map[string]string{"exa": "/tmp/x.sock"})
+	if err != nil || !strings.Contains(string(data), "/tmp/x.sock") {
+		t.Errorf("fresh config = %s, err = %v", data, err)
+	}
+
+	// Unparseable remote file is not clobbered
+	if _, err := mergeForwardedMCPServers([]byte("{not json"), nil); err == nil {
+		t.Error("expected parse error for invalid remote .mcp.json")
+	}
+}
+
+func TestRemoteShellPath(t *testing.T) {
+	if got := remoteShellPath("~/work/my app/.mcp.json"); got != `"$HOME"/'work/my app/.mcp.json'` {
+		t.Errorf("remoteShellPath(~) = %s", got)
+	}
+	if got := remoteShellPath("/srv/app/.mcp.json"); got != "'/srv/app/.mcp.json'" {
+		t.Errorf("remoteShellPath(abs) = %s", got)
+	}
+}
@@ -0,0 +1,66 @@
+package session
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// PushToRemoteSession copies a local file or directory into a remote
+// session's host. A relative remotePath is resolved against the session's
+// project path; an empty one means <project>/<base name of localPath>.
+func PushToRemoteSession(inst *Instance, localPath, remotePath string, opts sshpkg.SyncOptions) (*sshpkg.SyncResult, error) {
+	if !inst.IsRemote() {
+		return nil, fmt.Errorf("session '%s' is not a remote session", inst.Title)
+	}
+	absLocal, err := filepath.Abs(localPath)
+	if err != nil {
+		return nil, err
+	}
+	if remotePath == "" {
+		remotePath = filepath.Base(absLocal)
+	}
+	conn, err := sshpkg.DefaultPool().Get(inst.RemoteHost)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Push(absLocal, RemoteSessionPath(inst, remotePath), opts)
+}
+
+// PullFromRemoteSession copies a file or directory from a remote session's
+// host. A relative remotePath is resolved against the session's project
+// path; an empty localPath means ./<base name of remotePath>.
+func PullFromRemoteSession(inst *Instance, remotePath, localPath string, opts sshpkg.SyncOptions) (*sshpkg.SyncResult, error) {
+	if !inst.IsRemote() {
+		return nil, fmt.Errorf("session '%s' is not a remote session", inst.Title)
+	}
+	remotePath = RemoteSessionPath(inst, remotePath)
+	if localPath == "" {
+		localPath = path.Base(remotePath)
+	}
+	absLocal, err := filepath.Abs(localPath)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := sshpkg.DefaultPool().Get(inst.RemoteHost)
+	if err != nil {
+		return nil, err
+	}
+	return conn.Pull(remotePath, absLocal, opts)
+}
+
+// RemoteSessionPath resolves p against a remote session's project path.
+// Absolute and ~/ paths are returned as is; "" and "." mean the project.
+func RemoteSessionPath(inst *Instance, p string) string {
+	if p == "~" || strings.HasPrefix(p, "/") || strings.HasPrefix(p, "~/") {
+		return path.Clean(p)
+	}
+	project := inst.ProjectPath
+	if project == "" {
+		project = "~"
+	}
+	return path.Join(project, p)
+}
@@ -0,0 +1,32 @@
+package session
+
+import (
+	"testing"
+
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+func TestRemoteSessionPath(t *testing.T) {
+	inst := &Instance{RemoteHost: "dev", ProjectPath: "/srv/api"}
+	tests := map[string]string{
+		"":           "/srv/api",
+		".":          "/srv/api",
+		"out/a.txt":  "/srv/api/out/a.txt",
+		"../shared":  "/srv/shared",
+		"/etc/hosts": "/etc/hosts",
+		"~/notes.md": "~/notes.md",
+		"~":          "~",
+	}
+	for in, want := range tests {
+		if got := RemoteSessionPath(inst, in); got != want {
+			t.Errorf("RemoteSessionPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	if got := RemoteSessionPath(&Instance{RemoteHost: "dev"}, "x"); got != "~/x" {
+		t.Errorf("without project path = %q, want ~/x", got)
+	}
+	if _, err := PushToRemoteSession(&Instance{Title: "local"}, ".", "", sshpkg.SyncOptions{}); err == nil {
+		t.Error("PushToRemoteSession should reject local sessions")
+	}
+}
@@ -1,6 +1,7 @@
 package session
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -544,3 +545,167 @@ jump_host = "bastion"
 	}
 }
 
+
+func TestSSHConfigFromDef_NativeResolvesJumpChain(t *testing.T) {
+	hosts := map[string]SSHHostDef{
+		"edge":     {Host: "edge.example.com", User: "ops", Port: 2200},
+		"bastion":  {Host: "bastion.internal", User: "admin", JumpHost: "edge"},
+		"internal": {Host: "10.0.0.50", User: "developer", JumpHost: "bastion,raw@hop.example.com:2222", Transport: "native"},
+	}
+
+	cfg := sshConfigFromDef(hosts["internal"], hosts)
+	if cfg.Transport != "native" {
+		t.Errorf("Transport = %q, want native", cfg.Transport)
+	}
+	want := []string{"ops@edge.example.com:2200", "admin@bastion.internal:0", "raw@hop.example.com:2222"}
+	if len(cfg.JumpHosts) != len(want) {
+		t.Fatalf("JumpHosts = %+v, want %v", cfg.JumpHosts, want)
+	}
+	for i, hop := range cfg.JumpHosts {
+		got := fmt.Sprintf("%s@%s:%d", hop.User, hop.Host, hop.Port)
+		if got != want[i] {
+			t.Errorf("hop %d = %s, want %s", i, got, want[i])
+		}
+	}
+
+	// The ssh binary transport passes jump_host through to -J unchanged
+	if cfg := sshConfigFromDef(hosts["bastion"], hosts); len(cfg.JumpHosts) != 0 || cfg.JumpHost != "edge" {
+		t.Errorf("ssh transport config = %+v", cfg)
+	}
+
+	// Cycles don't recurse forever
+	loop := map[string]SSHHostDef{
+		"a": {Host: "a.example.com", JumpHost: "b", Transport: "native"},
+		"b": {Host: "b.example.com", JumpHost: "a"},
+	}
+	if cfg := sshConfigFromDef(loop["a"], loop); len(cfg.JumpHosts) == 0 {
+		t.Error("expected jump chain for a")
+	}
+}
+
+// writeTestSSHConfig writes ~/.ssh/config under an isolated HOME
+func writeTestSSHConfig(t *testing.T, home, content string) {
+	t.Helper()
+	sshDir := filepath.Join(home, ".ssh")
+	if err := os.MkdirAll(sshDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sshDir, "config"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveSSHHostDef_SSHConfigAlias(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	ClearUserConfigCache()
+	writeTestSSHConfig(t, tempDir, `
+Host gpu-box
+    HostName 10.1.2.3
+    User ml
+    Port 2202
+    IdentityFile ~/.ssh/id_gpu
+    ProxyJump jump
+
+Host jump
+    HostName jump.example.com
+    User ops
+`)
+
+	eff, err := ResolveSSHHostDef(SSHHostDef{SSHConfigAlias: "gpu-box", User: "override"})
+	if err != nil {
+		t.Fatalf("ResolveSSHHostDef: %v", err)
+	}
+	if eff.Host != "10.1.2.3" || eff.User != "override" || eff.Port != 2202 || eff.JumpHost != "jump" {
+		t.Errorf("effective def = %+v", eff)
+	}
+	if eff.IdentityFile != filepath.Join(tempDir, ".ssh", "id_gpu") {
+		t.Errorf("IdentityFile = %q", eff.IdentityFile)
+	}
+
+	if _, err := ResolveSSHHostDef(SSHHostDef{SSHConfigAlias: "missing"}); err == nil {
+		t.Error("expected error for unknown alias")
+	}
+	if msg := ValidateSSHHostDef(SSHHostDef{SSHConfigAlias: "missing"}); msg == "" {
+		t.Error("ValidateSSHHostDef should reject unknown alias")
+	}
+	if msg := ValidateSSHHostDef(SSHHostDef{}); msg == "" {
+		t.Error("ValidateSSHHostDef should require host or alias")
+	}
+
+	// ssh transport: connect to the alias and let ssh apply ~/.ssh/config
+	cfg := sshConfigFromDef(SSHHostDef{SSHConfigAlias: "gpu-box"}, nil)
+	if cfg.Host != "gpu-box" || cfg.User != "" || cfg.JumpHost != "" {
+		t.Errorf("ssh transport config = %+v", cfg)
+	}
+
+	// native transport: settings and the ProxyJump chain come from ~/.ssh/config
+	cfg = sshConfigFromDef(SSHHostDef{SSHConfigAlias: "gpu-box", Transport: "native"}, nil)
+	if cfg.Host != "10.1.2.3" || cfg.User != "ml" || cfg.Port != 2202 {
+		t.Errorf("native config = %+v", cfg)
+	}
+	if len(cfg.JumpHosts) != 1 || cfg.JumpHosts[0].Host != "jump.example.com" || cfg.JumpHosts[0].User != "ops" {
+		t.Errorf("native JumpHosts = %+v", cfg.JumpHosts)
+	}
+}
+
+func TestImportSSHConfigHosts(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("HOME", tempDir)
+	ClearUserConfigCache()
+	if err := os.MkdirAll(filepath.Join(tempDir, ".agent-deck"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeTestSSHConfig(t, tempDir, `
+Host web.prod
+    HostName 203.0.113.7
+    User deploy
+
+Host dev
+    HostName dev.local
+
+Host *
+    ServerAliveInterval 30
+`)
+	if err := SetSSHHost("dev", SSHHostDef{Host: "keep.me"}); err != nil {
+		t.Fatal(err)
+	}
+
+	imports, err := ScanSSHConfigHosts("")
+	if err != nil {
+		t.Fatalf("ScanSSHConfigHosts: %v", err)
+	}
+	if len(imports) != 2 {
+		t.Fatalf("got %d candidates, want 2: %+v", len(imports), imports)
+	}
+	if imports[0].HostID != "web-prod" || imports[0].Resolved.User != "deploy" || imports[0].Exists {
+		t.Errorf("imports[0] = %+v", imports[0])
+	}
+	if imports[1].HostID != "dev" || !imports[1].Exists {
+		t.Errorf("imports[1] = %+v", imports[1])
+	}
+
+	written, err := ImportSSHConfigHosts(imports, true, false)
+	if err != nil {
+		t.Fatalf("ImportSSHConfigHosts: %v", err)
+	}
+	if len(written) != 1 || written[0] != "web-prod" {
+		t.Errorf("written = %v, want [web-prod]", written)
+	}
+
+	ClearUserConfigCache()
+	config, err := LoadUserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := config.SSHHosts["web-prod"]
+	if web.SSHConfigAlias != "web.prod" || web.Host != "" || !web.AutoDiscover {
+		t.Errorf("web-prod = %+v", web)
+	}
+	if config.SSHHosts["dev"].Host != "keep.me" {
+		t.Errorf("existing host overwritten: %+v", config.SSHHosts["dev"])
+	}
+	if !config.RemoteDiscovery.Enabled {
+		t.Error("auto_discover import should enable remote discovery")
+	}
+}
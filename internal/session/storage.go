@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -51,9 +52,12 @@ func expandTilde(path string) string {
 
 // StorageData represents the JSON structure for persistence
 type StorageData struct {
-	Instances []*InstanceData `json:"instances"`
-	Groups    []*GroupData    `json:"groups,omitempty"` // Persist empty groups
-	UpdatedAt time.Time       `json:"updated_at"`
+	// SchemaVersion is StorageSchemaVersion when written; older data is
+	// migrated on load (see storage_schema.go), 0 means pre-versioning
+	SchemaVersion int             `json:"schema_version"`
+	Instances     []*InstanceData `json:"instances"`
+	Groups        []*GroupData    `json:"groups,omitempty"` // Persist empty groups
+	UpdatedAt     time.Time       `json:"updated_at"`
 }
 
 // InstanceData represents the serializable session data
@@ -72,6 +76,13 @@ type InstanceData struct {
 	WaitingSince    time.Time `json:"waiting_since,omitempty"` // When session entered waiting status
 	TmuxSession     string    `json:"tmux_session"`
 
+	// Sub-session support: parent's project path (for --add-dir access)
+	ParentProjectPath string `json:"parent_project_path,omitempty"`
+
+	// Tags and key/value metadata
+	Tags []string          `json:"tags,omitempty"`
+	Meta map[string]string `json:"meta,omitempty"`
+
 	// Worktree support
 	WorktreePath     string `json:"worktree_path,omitempty"`
 	WorktreeRepoRoot string `json:"worktree_repo_root,omitempty"`
@@ -103,9 +114,15 @@ type InstanceData struct {
 	LaunchConfigName string `json:"launch_config_name,omitempty"`
 	DangerousMode    bool   `json:"dangerous_mode,omitempty"`
 
+	// Tool-specific launch options (see ToolOptionsWrapper)
+	ToolOptionsJSON json.RawMessage `json:"tool_options,omitempty"`
+
 	// Remote session support
 	RemoteHost     string `json:"remote_host,omitempty"`      // SSH host identifier
 	RemoteTmuxName string `json:"remote_tmux_name,omitempty"` // tmux session name on remote
+
+	// Localhost ports forwarded to the remote host while the session runs
+	PortForwards []PortForward `json:"port_forwards,omitempty"`
 }
 
 // GroupData represents serializable group data
@@ -117,7 +134,25 @@ type GroupData struct {
 	DefaultPath string `json:"default_path,omitempty"`
 }
 
-// Storage handles persistence of session data
+// SessionStore is the persistence interface shared by Storage,
+// StorageAdapter and SQLiteStore: whole-profile loads and saves of raw
+// StorageData, plus partial updates of single sessions.
+type SessionStore interface {
+	LoadStorageData() (*StorageData, error)
+	SaveStorageData(data *StorageData) error
+	ApplyFieldUpdates(updates map[string]FieldUpdate) error
+	GetUpdatedAt() (time.Time, error)
+	Path() string
+}
+
+var (
+	_ SessionStore = (*Storage)(nil)
+	_ SessionStore = (*StorageAdapter)(nil)
+	_ SessionStore = (*SQLiteStore)(nil)
+)
+
+// Storage handles persistence of session data in sessions.json, or in
+// sessions.db when [storage] backend = "sqlite" (see SQLiteStore).
 // Thread-safe with mutex protection for concurrent access within a process,
 // and file locking for cross-process safety (multiple agent-deck instances).
 //
@@ -155,6 +190,12 @@ type Storage struct {
 	profile  string     // The profile this storage is for
 	mu       sync.Mutex // Protects all file operations within this process
 	fileLock *fileLock  // Cross-process file lock (flock on Unix, LockFileEx on Windows)
+
+	// db is set when the SQLite backend is in use; path is then the
+	// database file and fileLock serializes writes to it. It is shared with
+	// the profile's other Storages in this process (see acquireSQLiteStore).
+	db        *SQLiteStore
+	closeOnce sync.Once
 }
 
 // NewStorage creates a new storage instance using the default profile.
@@ -197,16 +238,65 @@ func NewStorageWithProfile(profile string) (*Storage, error) {
 		return nil, fmt.Errorf("failed to create storage directory: %w", err)
 	}
 
-	s := &Storage{
-		path:     path,
+	jsonPath := filepath.Join(dir, jsonStorageFile)
+	jsonStorage := &Storage{
+		path:     jsonPath,
 		profile:  effectiveProfile,
-		fileLock: newFileLock(path),
+		fileLock: newFileLock(jsonPath),
 	}
 
 	// Clean up any leftover temp files from previous crashes
-	s.cleanupTempFiles()
+	jsonStorage.cleanupTempFiles()
+
+	dbPath := filepath.Join(dir, sqliteStorageFile)
+	if GetStorageSettings().Backend == StorageBackendSQLite {
+		db, err := acquireSQLiteStore(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		s := &Storage{path: dbPath, profile: effectiveProfile, db: db, fileLock: newFileLock(dbPath)}
+		migrateStorageBackend(jsonStorage, s)
+		if err := s.upgradeSQLiteSchema(); err != nil {
+			_ = s.Close()
+			return nil, err
+		}
+		return s, nil
+	}
 
-	return s, nil
+	// Switching back to JSON: pick up changes made while on SQLite
+	if _, err := os.Stat(dbPath); err == nil {
+		if db, err := acquireSQLiteStore(dbPath); err != nil {
+			log.Printf("Warning: failed to open %s to check for newer sessions: %v", dbPath, err)
+		} else {
+			migrateStorageBackend(&Storage{path: dbPath, profile: effectiveProfile, db: db}, jsonStorage)
+			_ = releaseSQLiteStore(db)
+		}
+	}
+	return jsonStorage, nil
+}
+
+// migrateStorageBackend copies all sessions and groups from one backend to
+// the other when from was written more recently, so switching
+// [storage] backend (either way) keeps the latest data. The source is left
+// as is.
+func migrateStorageBackend(from, to *Storage) {
+	fromUpdated, err := from.GetUpdatedAt()
+	if err != nil || fromUpdated.IsZero() {
+		return
+	}
+	if toUpdated, err := to.GetUpdatedAt(); err == nil && !toUpdated.Before(fromUpdated) {
+		return
+	}
+	data, err := from.LoadStorageData()
+	if err != nil {
+		log.Printf("Warning: failed to read %s for migration: %v", from.path, err)
+		return
+	}
+	if err := to.SaveStorageData(data); err != nil {
+		log.Printf("Warning: failed to migrate sessions from %s to %s: %v", from.path, to.path, err)
+		return
+	}
+	log.Printf("Migration: copied %d session(s) from %s to %s", len(data.Instances), from.path, to.path)
 }
 
 // Profile returns the profile name this storage is using
@@ -237,38 +327,18 @@ func (s *Storage) Save(instances []*Instance) error {
 	return s.SaveWithGroups(instances, nil)
 }
 
-// SaveWithGroups persists instances and groups to JSON file
-// Uses atomic write pattern with:
-// - Cross-process file locking (flock/LockFileEx) for multi-process safety
-// - Mutex for thread safety within process
-// - Rolling backups (3 generations)
-// - fsync for durability
-// - Data validation
+// SaveWithGroups persists instances and groups (see SaveStorageData)
 func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) error {
-	// ═══════════════════════════════════════════════════════════════════
-	// LOCK ORDER: Step 1 - Acquire cross-process file lock FIRST
-	// ═══════════════════════════════════════════════════════════════════
-	// This MUST be acquired before the mutex to prevent deadlocks.
-	// See Storage struct documentation for detailed lock ordering rules.
-	// Skip if fileLock is nil (e.g., in tests that create Storage directly)
-	if s.fileLock != nil {
-		handle, err := s.fileLock.Lock()
-		if err != nil {
-			return fmt.Errorf("failed to acquire cross-process lock: %w", err)
-		}
-		defer func() { _ = handle.Unlock() }()
-	}
-
-	// ═══════════════════════════════════════════════════════════════════
-	// LOCK ORDER: Step 2 - Acquire in-process mutex SECOND
-	// ═══════════════════════════════════════════════════════════════════
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	return s.SaveStorageData(buildStorageData(instances, groupTree))
+}
 
+// buildStorageData converts instances and groups to their serializable form
+func buildStorageData(instances []*Instance, groupTree *GroupTree) *StorageData {
 	// Convert instances to serializable format
-	data := StorageData{
-		Instances: make([]*InstanceData, len(instances)),
-		UpdatedAt: time.Now(),
+	data := &StorageData{
+		SchemaVersion: StorageSchemaVersion,
+		Instances:     make([]*InstanceData, len(instances)),
+		UpdatedAt:     time.Now(),
 	}
 
 	for i, inst := range instances {
@@ -309,6 +379,7 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 			ProjectPath: inst.ProjectPath,
 			GroupPath:          inst.GroupPath,
 			ParentSessionID:    inst.ParentSessionID,
+			ParentProjectPath:  inst.ParentProjectPath,
 			Command:            inst.Command,
 			Tool:               inst.Tool,
 			Status:             inst.Status,
@@ -331,8 +402,12 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 			LoadedMCPNames:     inst.LoadedMCPNames,
 			LaunchConfigName:   inst.LaunchConfigName,
 			DangerousMode:      inst.DangerousMode,
+			ToolOptionsJSON:    inst.ToolOptionsJSON,
 			RemoteHost:         inst.RemoteHost,
 			RemoteTmuxName:     inst.RemoteTmuxName,
+			PortForwards:       inst.PortForwards,
+			Tags:               inst.Tags,
+			Meta:               inst.Meta,
 		}
 	}
 
@@ -350,50 +425,7 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 		}
 	}
 
-	// Validate data before saving
-	if err := validateStorageData(&data); err != nil {
-		return fmt.Errorf("data validation failed: %w", err)
-	}
-
-	// Marshal to JSON
-	jsonData, err := json.MarshalIndent(data, "", "  ")
-	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %w", err)
-	}
-
-	// ═══════════════════════════════════════════════════════════════════
-	// ATOMIC WRITE PATTERN: Prevents data corruption on crash/power loss
-	// 1. Write to temporary file
-	// 2. fsync the temp file (ensures data reaches disk)
-	// 3. Rotate backups (rolling 3 generations)
-	// 4. Atomic rename temp to final
-	// ═══════════════════════════════════════════════════════════════════
-
-	tmpPath := s.path + ".tmp"
-
-	// Step 1: Write to temporary file (0600 = owner read/write only for security)
-	if err := os.WriteFile(tmpPath, jsonData, 0600); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Step 2: fsync the temp file to ensure data reaches disk before rename
-	// This is critical for crash safety - without fsync, data could be lost
-	if err := syncFile(tmpPath); err != nil {
-		// Log but don't fail - atomic rename still provides some safety
-		log.Printf("Warning: fsync failed for %s: %v", tmpPath, err)
-	}
-
-	// Step 3: Rotate backups before overwriting
-	if _, err := os.Stat(s.path); err == nil {
-		s.rotateBackups()
-	}
-
-	// Step 4: Atomic rename (this is atomic on POSIX systems)
-	if err := os.Rename(tmpPath, s.path); err != nil {
-		return fmt.Errorf("failed to finalize save: %w", err)
-	}
-
-	return nil
+	return data
 }
 
 // LoadStorageData reads raw StorageData from the JSON file without converting to Instance objects.
@@ -401,6 +433,10 @@ func (s *Storage) SaveWithGroups(instances []*Instance, groupTree *GroupTree) er
 // Returns empty StorageData if file doesn't exist.
 // Uses cross-process file locking to prevent races with concurrent writers.
 func (s *Storage) LoadStorageData() (*StorageData, error) {
+	if s.db != nil {
+		return s.db.LoadStorageData()
+	}
+
 	// Acquire cross-process lock first
 	// Skip if fileLock is nil (e.g., in tests that create Storage directly)
 	if s.fileLock != nil {
@@ -414,12 +450,19 @@ func (s *Storage) LoadStorageData() (*StorageData, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.readStorageDataLocked()
+}
+
+// readStorageDataLocked reads the JSON file, recovering from backups if it
+// is corrupted. Caller must hold the file lock and s.mu.
+func (s *Storage) readStorageDataLocked() (*StorageData, error) {
 	// Check if file exists
 	if _, err := os.Stat(s.path); os.IsNotExist(err) {
 		return &StorageData{
-			Instances: []*InstanceData{},
-			Groups:    []*GroupData{},
-			UpdatedAt: time.Time{},
+			SchemaVersion: StorageSchemaVersion,
+			Instances:     []*InstanceData{},
+			Groups:        []*GroupData{},
+			UpdatedAt:     time.Time{},
 		}, nil
 	}
 
@@ -435,15 +478,27 @@ func (s *Storage) LoadStorageData() (*StorageData, error) {
 		log.Printf("Successfully recovered from backup")
 	}
 
+	if err := s.upgradeSchemaLocked(data); err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
-// SaveStorageData persists raw StorageData to the JSON file.
-// Uses atomic write pattern with fsync, backup rotation, and validation.
-// This is useful for callers that work directly with InstanceData (e.g., desktop app).
-// Uses cross-process file locking to prevent races with concurrent writers.
+// SaveStorageData persists raw StorageData, replacing what is stored.
+// The JSON backend uses an atomic write pattern with:
+// - Cross-process file locking (flock/LockFileEx) for multi-process safety
+// - Mutex for thread safety within process
+// - Rolling backups (3 generations)
+// - fsync for durability
+// - Data validation
+// The SQLite backend writes only the rows that changed, under the same file
+// lock so it can't land inside another process's lockForUpdate.
 func (s *Storage) SaveStorageData(data *StorageData) error {
-	// Acquire cross-process lock first
+	// ═══════════════════════════════════════════════════════════════════
+	// LOCK ORDER: Step 1 - Acquire cross-process file lock FIRST
+	// ═══════════════════════════════════════════════════════════════════
+	// This MUST be acquired before the mutex to prevent deadlocks.
+	// See Storage struct documentation for detailed lock ordering rules.
 	// Skip if fileLock is nil (e.g., in tests that create Storage directly)
 	if s.fileLock != nil {
 		handle, err := s.fileLock.Lock()
@@ -453,11 +508,31 @@ func (s *Storage) SaveStorageData(data *StorageData) error {
 		defer func() { _ = handle.Unlock() }()
 	}
 
+	// ═══════════════════════════════════════════════════════════════════
+	// LOCK ORDER: Step 2 - Acquire in-process mutex SECOND
+	// ═══════════════════════════════════════════════════════════════════
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.db != nil {
+		return s.markSaved(s.db.SaveStorageData(data))
+	}
+
+	// Never overwrite sessions written by a newer agent-deck, even if
+	// loading them failed and the caller is saving what it has
+	if err := checkFileSchemaVersion(s.path); err != nil {
+		return err
+	}
+
+	return s.markSaved(s.writeStorageDataLocked(data))
+}
+
+// writeStorageDataLocked writes data to the JSON file. Caller must hold the
+// file lock and s.mu.
+func (s *Storage) writeStorageDataLocked(data *StorageData) error {
 	// Update timestamp
 	data.UpdatedAt = time.Now()
+	data.SchemaVersion = StorageSchemaVersion
 
 	// Validate data before saving
 	if err := validateStorageData(data); err != nil {
@@ -470,16 +545,25 @@ func (s *Storage) SaveStorageData(data *StorageData) error {
 		return fmt.Errorf("failed to marshal JSON: %w", err)
 	}
 
-	// Atomic write pattern (same as SaveWithGroups)
+	// ═══════════════════════════════════════════════════════════════════
+	// ATOMIC WRITE PATTERN: Prevents data corruption on crash/power loss
+	// 1. Write to temporary file
+	// 2. fsync the temp file (ensures data reaches disk)
+	// 3. Rotate backups (rolling 3 generations)
+	// 4. Atomic rename temp to final
+	// ═══════════════════════════════════════════════════════════════════
+
 	tmpPath := s.path + ".tmp"
 
-	// Step 1: Write to temporary file
+	// Step 1: Write to temporary file (0600 = owner read/write only for security)
 	if err := os.WriteFile(tmpPath, jsonData, 0600); err != nil {
 		return fmt.Errorf("failed to write temp file: %w", err)
 	}
 
-	// Step 2: fsync the temp file
+	// Step 2: fsync the temp file to ensure data reaches disk before rename
+	// This is critical for crash safety - without fsync, data could be lost
 	if err := syncFile(tmpPath); err != nil {
+		// Log but don't fail - atomic rename still provides some safety
 		log.Printf("Warning: fsync failed for %s: %v", tmpPath, err)
 	}
 
@@ -488,7 +572,7 @@ func (s *Storage) SaveStorageData(data *StorageData) error {
 		s.rotateBackups()
 	}
 
-	// Step 4: Atomic rename
+	// Step 4: Atomic rename (this is atomic on POSIX systems)
 	if err := os.Rename(tmpPath, s.path); err != nil {
 		return fmt.Errorf("failed to finalize save: %w", err)
 	}
@@ -496,6 +580,164 @@ func (s *Storage) SaveStorageData(data *StorageData) error {
 	return nil
 }
 
+// ApplyFieldUpdates applies partial updates to stored sessions. The SQLite
+// backend updates just those columns of each row; the JSON backend loads,
+// patches and rewrites the file under a single lock so no concurrent writer
+// slips in between.
+func (s *Storage) ApplyFieldUpdates(updates map[string]FieldUpdate) error {
+	// Acquire cross-process lock first
+	// Skip if fileLock is nil (e.g., in tests that create Storage directly)
+	if s.fileLock != nil {
+		handle, err := s.fileLock.Lock()
+		if err != nil {
+			return fmt.Errorf("failed to acquire cross-process lock: %w", err)
+		}
+		defer func() { _ = handle.Unlock() }()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.db != nil {
+		return s.markSaved(s.db.ApplyFieldUpdates(updates))
+	}
+	data, err := s.readStorageDataLocked()
+	if err != nil {
+		return err
+	}
+	modified := false
+	for _, inst := range data.Instances {
+		if update, ok := updates[inst.ID]; ok && update.applyTo(inst) {
+			modified = true
+		}
+	}
+	if !modified {
+		return nil
+	}
+	return s.markSaved(s.writeStorageDataLocked(data))
+}
+
+// lockForUpdate acquires the cross-process file lock and then s.mu for a
+// read-modify-write spanning loadStorageDataLocked and saveStorageDataLocked.
+// The returned function releases both. Both backends take the file lock:
+// SQLite's load and save are separate transactions, and its writers take the
+// lock too, so none can slip in between.
+func (s *Storage) lockForUpdate() (func(), error) {
+	var handle *lockHandle
+	if s.fileLock != nil {
+		h, err := s.fileLock.Lock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire cross-process lock: %w", err)
+		}
+		handle = h
+	}
+	s.mu.Lock()
+	return func() {
+		s.mu.Unlock()
+		if handle != nil {
+			_ = handle.Unlock()
+		}
+	}, nil
+}
+
+// loadStorageDataLocked is LoadStorageData for a caller holding lockForUpdate
+func (s *Storage) loadStorageDataLocked() (*StorageData, error) {
+	if s.db != nil {
+		return s.db.LoadStorageData()
+	}
+	return s.readStorageDataLocked()
+}
+
+// saveStorageDataLocked is SaveStorageData for a caller holding lockForUpdate
+func (s *Storage) saveStorageDataLocked(data *StorageData) error {
+	if s.db != nil {
+		return s.markSaved(s.db.SaveStorageData(data))
+	}
+	if err := checkFileSchemaVersion(s.path); err != nil {
+		return err
+	}
+	return s.markSaved(s.writeStorageDataLocked(data))
+}
+
+// savedProfiles are the profiles this process has written sessions or groups
+// to (see SavedProfiles)
+var (
+	savedProfilesMu sync.Mutex
+	savedProfiles   = make(map[string]bool)
+)
+
+// markSaved records a successful write to the profile and passes err through
+func (s *Storage) markSaved(err error) error {
+	if err == nil {
+		savedProfilesMu.Lock()
+		savedProfiles[s.profile] = true
+		savedProfilesMu.Unlock()
+	}
+	return err
+}
+
+// SavedProfiles returns the profiles this process has written sessions or
+// groups to, sorted. The CLI pushes them through [sync] before exiting.
+func SavedProfiles() []string {
+	savedProfilesMu.Lock()
+	defer savedProfilesMu.Unlock()
+	profiles := make([]string, 0, len(savedProfiles))
+	for profile := range savedProfiles {
+		profiles = append(profiles, profile)
+	}
+	sort.Strings(profiles)
+	return profiles
+}
+
+// ChangesSince returns the SQLite backend's change journal after seq, oldest
+// first. The JSON backend keeps no journal.
+func (s *Storage) ChangesSince(seq int64) ([]StorageChange, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("the change journal needs [storage] backend = %q", StorageBackendSQLite)
+	}
+	return s.db.ChangesSince(seq)
+}
+
+// upgradeSQLiteSchema writes the schema upgrade of an older database under
+// the profile lock, so it can't race another process's update. Reads only
+// upgrade in memory.
+func (s *Storage) upgradeSQLiteSchema() error {
+	if needed, err := s.db.needsSchemaUpgrade(); err != nil || !needed {
+		return err
+	}
+	unlock, err := s.lockForUpdate()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return s.db.upgradeSchema()
+}
+
+// upgradeSchemaLocked migrates data read from the JSON file to the current
+// schema. The file is copied to sessions.json.schema-v<old>.bak and the
+// migrated data written back. Caller must hold the file lock and s.mu.
+func (s *Storage) upgradeSchemaLocked(data *StorageData) error {
+	from := data.SchemaVersion
+	migrated, err := upgradeStorageData(data, s.path)
+	if err != nil || !migrated {
+		return err
+	}
+	if err := copyFile(s.path, schemaBackupPath(s.path, from)); err != nil {
+		return fmt.Errorf("failed to back up %s before schema migration: %w", s.path, err)
+	}
+	return s.writeStorageDataLocked(data)
+}
+
+// Close releases the SQLite database, if any. The database stays open
+// while other Storages of the profile use it; calling Close again is a no-op.
+func (s *Storage) Close() error {
+	var err error
+	if s.db != nil {
+		s.closeOnce.Do(func() { err = releaseSQLiteStore(s.db) })
+	}
+	return err
+}
+
 // validateStorageData checks data integrity before saving
 func validateStorageData(data *StorageData) error {
 	if data == nil {
@@ -589,6 +831,14 @@ func (s *Storage) Load() ([]*Instance, error) {
 // Automatically recovers from backup if main file is corrupted
 // Uses cross-process file locking to prevent races with concurrent writers.
 func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
+	if s.db != nil {
+		data, err := s.db.LoadStorageData()
+		if err != nil {
+			return nil, nil, err
+		}
+		return s.convertToInstances(data)
+	}
+
 	// Acquire cross-process lock first
 	// Skip if fileLock is nil (e.g., in tests that create Storage directly)
 	if s.fileLock != nil {
@@ -625,6 +875,10 @@ func (s *Storage) LoadWithGroups() ([]*Instance, []*GroupData, error) {
 		// Instead, we'll just write directly
 	}
 
+	if err := s.upgradeSchemaLocked(data); err != nil {
+		return nil, nil, err
+	}
+
 	return s.convertToInstances(data)
 }
 
@@ -672,32 +926,9 @@ func (s *Storage) recoverFromBackups() (*StorageData, error) {
 }
 
 // convertToInstances converts StorageData to Instance slice
+// Schema migrations have already run (see upgradeStorageData); the fixups
+// below depend on config or repair data any version may have written.
 func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupData, error) {
-
-	// ═══════════════════════════════════════════════════════════════════
-	// MIGRATION: Convert old "My Sessions" paths to normalized "my-sessions"
-	// Old versions used DefaultGroupName ("My Sessions") as both name AND path.
-	// This caused the group to be undeletable since path matched the protection check.
-	// Now we use DefaultGroupPath ("my-sessions") for paths, keeping name as display.
-	// ═══════════════════════════════════════════════════════════════════
-	migratedGroups := false
-	for i, g := range data.Groups {
-		if g.Path == DefaultGroupName {
-			data.Groups[i].Path = DefaultGroupPath
-			migratedGroups = true
-			log.Printf("Migration: Converted group path '%s' -> '%s'", DefaultGroupName, DefaultGroupPath)
-		}
-	}
-	for i, inst := range data.Instances {
-		if inst.GroupPath == DefaultGroupName {
-			data.Instances[i].GroupPath = DefaultGroupPath
-			migratedGroups = true
-		}
-	}
-	if migratedGroups {
-		log.Printf("Migration: Updated default group paths from '%s' to '%s'", DefaultGroupName, DefaultGroupPath)
-	}
-
 	// Convert to instances
 	instances := make([]*Instance, len(data.Instances))
 	for i, instData := range data.Instances {
@@ -837,6 +1068,7 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			ProjectPath: projectPath,
 			GroupPath:          groupPath,
 			ParentSessionID:    instData.ParentSessionID,
+			ParentProjectPath:  instData.ParentProjectPath,
 			Command:            instData.Command,
 			Tool:               instData.Tool,
 			Status:             instData.Status,
@@ -858,8 +1090,12 @@ func (s *Storage) convertToInstances(data *StorageData) ([]*Instance, []*GroupDa
 			LoadedMCPNames:     instData.LoadedMCPNames,
 			LaunchConfigName:   instData.LaunchConfigName,
 			DangerousMode:      instData.DangerousMode,
+			ToolOptionsJSON:    instData.ToolOptionsJSON,
 			RemoteHost:         instData.RemoteHost,
 			RemoteTmuxName:     instData.RemoteTmuxName,
+			PortForwards:       instData.PortForwards,
+			Tags:               instData.Tags,
+			Meta:               instData.Meta,
 			tmuxSession:        tmuxSess,
 		}
 
@@ -884,7 +1120,14 @@ func GetStoragePath() (string, error) {
 	return GetStoragePathForProfile(DefaultProfile)
 }
 
-// GetStoragePathForProfile returns the path to the sessions.json file for a specific profile.
+// Storage file names within a profile directory
+const (
+	jsonStorageFile   = "sessions.json"
+	sqliteStorageFile = "sessions.db"
+)
+
+// GetStoragePathForProfile returns the path to the storage file for a specific profile:
+// sessions.json, or sessions.db with the SQLite backend.
 func GetStoragePathForProfile(profile string) (string, error) {
 	if profile == "" {
 		profile = DefaultProfile
@@ -895,7 +1138,10 @@ func GetStoragePathForProfile(profile string) (string, error) {
 		return "", err
 	}
 
-	return filepath.Join(profileDir, "sessions.json"), nil
+	if GetStorageSettings().Backend == StorageBackendSQLite {
+		return filepath.Join(profileDir, sqliteStorageFile), nil
+	}
+	return filepath.Join(profileDir, jsonStorageFile), nil
 }
 
 // GetUpdatedAt returns the last modification timestamp of the storage file
@@ -903,6 +1149,10 @@ func GetStoragePathForProfile(profile string) (string, error) {
 // Returns an error if the file doesn't exist or can't be read.
 // Uses cross-process file locking for consistency.
 func (s *Storage) GetUpdatedAt() (time.Time, error) {
+	if s.db != nil {
+		return s.db.GetUpdatedAt()
+	}
+
 	// Acquire cross-process lock first
 	// Skip if fileLock is nil (e.g., in tests that create Storage directly)
 	if s.fileLock != nil {
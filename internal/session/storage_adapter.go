@@ -18,6 +18,8 @@ type FieldUpdate struct {
 }
 
 // StorageAdapter wraps Storage with desktop-specific features like debounced writes.
+// Flushed updates go through Storage.ApplyFieldUpdates, which is a row-level
+// update with the SQLite backend.
 // It works directly with InstanceData/GroupData (no tmux reconstruction) which is
 // appropriate for the desktop app that handles tmux separately.
 //
@@ -72,6 +74,21 @@ func (a *StorageAdapter) SaveStorageData(data *StorageData) error {
 	return a.storage.SaveStorageData(data)
 }
 
+// ApplyFieldUpdates writes field updates immediately (not debounced).
+func (a *StorageAdapter) ApplyFieldUpdates(updates map[string]FieldUpdate) error {
+	return a.storage.ApplyFieldUpdates(updates)
+}
+
+// GetUpdatedAt returns when the underlying storage was last written.
+func (a *StorageAdapter) GetUpdatedAt() (time.Time, error) {
+	return a.storage.GetUpdatedAt()
+}
+
+// Path returns the underlying storage file.
+func (a *StorageAdapter) Path() string {
+	return a.storage.Path()
+}
+
 // ScheduleUpdate queues a field update for debounced persistence.
 // Multiple updates to the same instance within the debounce window are merged.
 // The update will be written to disk after the debounce duration elapses.
@@ -141,57 +158,45 @@ func (a *StorageAdapter) FlushPendingUpdates() {
 	a.applyUpdates(updates)
 }
 
-// applyUpdates loads current data, applies field updates, and saves.
+// applyUpdates persists field updates through the storage backend.
 func (a *StorageAdapter) applyUpdates(updates map[string]FieldUpdate) {
-	data, err := a.storage.LoadStorageData()
-	if err != nil {
-		log.Printf("[storage-adapter] Failed to load data for update: %v", err)
-		return
+	if err := a.storage.ApplyFieldUpdates(updates); err != nil {
+		log.Printf("[storage-adapter] Failed to save updates: %v", err)
 	}
+}
 
+// applyTo applies the update to inst and reports whether anything was set.
+func (u FieldUpdate) applyTo(inst *InstanceData) bool {
 	modified := false
-	for i := range data.Instances {
-		inst := data.Instances[i]
-		update, ok := updates[inst.ID]
-		if !ok {
-			continue
-		}
-
-		if update.Status != nil && Status(*update.Status) != inst.Status {
-			log.Printf("[storage-adapter] %s: status %s -> %s", inst.ID, inst.Status, *update.Status)
-			inst.Status = Status(*update.Status)
-			modified = true
-		}
-		if update.WaitingSince != nil {
-			log.Printf("[storage-adapter] %s: setting waitingSince to %v", inst.ID, *update.WaitingSince)
-			inst.WaitingSince = *update.WaitingSince
-			modified = true
-		}
-		if update.ClearWaitingSince && !inst.WaitingSince.IsZero() {
-			log.Printf("[storage-adapter] %s: clearing waitingSince", inst.ID)
-			inst.WaitingSince = time.Time{}
-			modified = true
-		}
-		if update.CustomLabel != nil {
-			inst.CustomLabel = *update.CustomLabel
-			modified = true
-		}
-		if update.LastAccessedAt != nil {
-			inst.LastAccessedAt = *update.LastAccessedAt
-			modified = true
-		}
-		if update.ClaudeSessionID != nil && *update.ClaudeSessionID != inst.ClaudeSessionID {
-			log.Printf("[storage-adapter] %s: discovered ClaudeSessionID %s", inst.ID, *update.ClaudeSessionID)
-			inst.ClaudeSessionID = *update.ClaudeSessionID
-			modified = true
-		}
+	if u.Status != nil && Status(*u.Status) != inst.Status {
+		log.Printf("[storage-adapter] %s: status %s -> %s", inst.ID, inst.Status, *u.Status)
+		inst.Status = Status(*u.Status)
+		modified = true
 	}
-
-	if modified {
-		if err := a.storage.SaveStorageData(data); err != nil {
-			log.Printf("[storage-adapter] Failed to save updates: %v", err)
-		}
+	if u.WaitingSince != nil {
+		log.Printf("[storage-adapter] %s: setting waitingSince to %v", inst.ID, *u.WaitingSince)
+		inst.WaitingSince = *u.WaitingSince
+		modified = true
+	}
+	if u.ClearWaitingSince && !inst.WaitingSince.IsZero() {
+		log.Printf("[storage-adapter] %s: clearing waitingSince", inst.ID)
+		inst.WaitingSince = time.Time{}
+		modified = true
+	}
+	if u.CustomLabel != nil {
+		inst.CustomLabel = *u.CustomLabel
+		modified = true
+	}
+	if u.LastAccessedAt != nil {
+		inst.LastAccessedAt = *u.LastAccessedAt
+		modified = true
+	}
+	if u.ClaudeSessionID != nil && *u.ClaudeSessionID != inst.ClaudeSessionID {
+		log.Printf("[storage-adapter] %s: discovered ClaudeSessionID %s", inst.ID, *u.ClaudeSessionID)
+		inst.ClaudeSessionID = *u.ClaudeSessionID
+		modified = true
 	}
+	return modified
 }
 
 // HasPendingUpdates returns true if there are updates waiting to be flushed.
@@ -0,0 +1,105 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+)
+
+// StorageSchemaVersion is the StorageData schema this binary reads and
+// writes. Bump it together with a new entry in storageMigrations whenever
+// stored data needs rewriting to be understood (renamed or reshaped fields,
+// normalized values). Purely additive omitempty fields don't need a bump.
+const StorageSchemaVersion = 1
+
+// ErrStorageSchemaTooNew is returned when the stored sessions were written by
+// a newer agent-deck. They are left untouched rather than risk losing fields
+// this binary doesn't know about.
+var ErrStorageSchemaTooNew = errors.New("sessions were written by a newer version of agent-deck")
+
+// storageMigration upgrades StorageData from version-1 to version
+type storageMigration struct {
+	version     int
+	description string
+	migrate     func(data *StorageData) error
+}
+
+// storageMigrations are run in order on load for data older than their
+// version. Never edit or reorder an entry once released; append a new one.
+var storageMigrations = []storageMigration{
+	{
+		version:     1,
+		description: "use normalized default group path",
+		migrate:     migrateDefaultGroupPath,
+	},
+}
+
+// upgradeStorageData runs the migrations newer than data.SchemaVersion and
+// reports whether any ran. Data from a newer schema is rejected with
+// ErrStorageSchemaTooNew.
+func upgradeStorageData(data *StorageData, source string) (bool, error) {
+	if data.SchemaVersion > StorageSchemaVersion {
+		return false, schemaTooNewError(source, data.SchemaVersion)
+	}
+	from := data.SchemaVersion
+	for _, m := range storageMigrations {
+		if m.version <= data.SchemaVersion {
+			continue
+		}
+		if err := m.migrate(data); err != nil {
+			return false, fmt.Errorf("schema migration %d (%s) failed for %s: %w", m.version, m.description, source, err)
+		}
+		data.SchemaVersion = m.version
+	}
+	if data.SchemaVersion == from {
+		return false, nil
+	}
+	log.Printf("Migration: upgraded %s from schema version %d to %d", source, from, data.SchemaVersion)
+	return true, nil
+}
+
+// checkFileSchemaVersion returns ErrStorageSchemaTooNew if the JSON file at
+// path was written with a newer schema. Missing or unreadable files pass.
+func checkFileSchemaVersion(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if json.Unmarshal(raw, &header) != nil || header.SchemaVersion <= StorageSchemaVersion {
+		return nil
+	}
+	return schemaTooNewError(path, header.SchemaVersion)
+}
+
+func schemaTooNewError(source string, version int) error {
+	return fmt.Errorf("%w: %s has schema version %d, this agent-deck supports up to %d; upgrade agent-deck",
+		ErrStorageSchemaTooNew, source, version, StorageSchemaVersion)
+}
+
+// schemaBackupPath is where the pre-migration copy of a storage file goes
+func schemaBackupPath(path string, version int) string {
+	return fmt.Sprintf("%s.schema-v%d.bak", path, version)
+}
+
+// migrateDefaultGroupPath converts old "My Sessions" paths to the normalized
+// "my-sessions". Old versions used DefaultGroupName as both name AND path,
+// which made the group undeletable since its path matched the protection
+// check. The name is kept for display.
+func migrateDefaultGroupPath(data *StorageData) error {
+	for _, g := range data.Groups {
+		if g.Path == DefaultGroupName {
+			g.Path = DefaultGroupPath
+		}
+	}
+	for _, inst := range data.Instances {
+		if inst.GroupPath == DefaultGroupName {
+			inst.GroupPath = DefaultGroupPath
+		}
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStorageSchema_MigratesLegacyJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), jsonStorageFile)
+	legacy := `{
+  "instances": [{"id": "a", "title": "api", "project_path": "/srv/api", "group_path": "My Sessions", "tool": "shell", "status": "idle"}],
+  "groups": [{"name": "My Sessions", "path": "My Sessions", "expanded": true, "order": 0}],
+  "updated_at": "2025-01-02T03:04:05Z"
+}`
+	if err := os.WriteFile(path, []byte(legacy), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Storage{path: path, profile: "_test"}
+	instances, groups, err := s.LoadWithGroups()
+	if err != nil {
+		t.Fatalf("LoadWithGroups: %v", err)
+	}
+	if instances[0].GroupPath != DefaultGroupPath || groups[0].Path != DefaultGroupPath || groups[0].Name != DefaultGroupName {
+		t.Errorf("default group not migrated: instance %q, group %+v", instances[0].GroupPath, groups[0])
+	}
+
+	backup, err := os.ReadFile(schemaBackupPath(path, 0))
+	if err != nil || string(backup) != legacy {
+		t.Errorf("pre-migration backup = %q, %v", backup, err)
+	}
+	var onDisk StorageData
+	raw, _ := os.ReadFile(path)
+	if err := json.Unmarshal(raw, &onDisk); err != nil {
+		t.Fatal(err)
+	}
+	if onDisk.SchemaVersion != StorageSchemaVersion || onDisk.Groups[0].Path != DefaultGroupPath {
+		t.Errorf("migrated file not written back: version %d, group %q", onDisk.SchemaVersion, onDisk.Groups[0].Path)
+	}
+}
+
+func TestStorageSchema_RejectsNewerJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), jsonStorageFile)
+	newer := `{"schema_version": 99, "instances": [], "updated_at": "2030-01-01T00:00:00Z"}`
+	if err := os.WriteFile(path, []byte(newer), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Storage{path: path, profile: "_test"}
+	if _, _, err := s.LoadWithGroups(); !errors.Is(err, ErrStorageSchemaTooNew) {
+		t.Errorf("LoadWithGroups error = %v, want ErrStorageSchemaTooNew", err)
+	}
+	if _, err := s.LoadStorageData(); !errors.Is(err, ErrStorageSchemaTooNew) {
+		t.Errorf("LoadStorageData error = %v, want ErrStorageSchemaTooNew", err)
+	}
+	if err := s.SaveStorageData(&StorageData{}); !errors.Is(err, ErrStorageSchemaTooNew) {
+		t.Errorf("SaveStorageData error = %v, want ErrStorageSchemaTooNew", err)
+	}
+	if raw, _ := os.ReadFile(path); string(raw) != newer {
+		t.Errorf("newer file was modified: %s", raw)
+	}
+}
+
+func TestStorageSchema_PersistsAllInstanceFields(t *testing.T) {
+	s := &Storage{path: filepath.Join(t.TempDir(), jsonStorageFile), profile: "_test"}
+	inst := NewInstance("child", "/srv/child")
+	inst.ParentProjectPath = "/srv/parent"
+	inst.ToolOptionsJSON = json.RawMessage(`{"tool":"claude","options":{"session_mode":"new"}}`)
+	if err := s.SaveWithGroups([]*Instance{inst}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, _, err := s.LoadWithGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var opts bytes.Buffer
+	if err := json.Compact(&opts, loaded[0].ToolOptionsJSON); err != nil {
+		t.Fatal(err)
+	}
+	if loaded[0].ParentProjectPath != "/srv/parent" || opts.String() != string(inst.ToolOptionsJSON) {
+		t.Errorf("loaded ParentProjectPath %q, ToolOptionsJSON %s", loaded[0].ParentProjectPath, opts.String())
+	}
+}
+
+func TestStorageSchema_SQLite(t *testing.T) {
+	db := openTestSQLiteStore(t)
+	data := testStorageData()
+	data.Instances[0].GroupPath = DefaultGroupName
+	if err := db.SaveStorageData(data); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := db.storedSchemaVersion(); v != StorageSchemaVersion {
+		t.Fatalf("stored schema version = %d, want %d", v, StorageSchemaVersion)
+	}
+
+	// Pretend the rows predate versioning
+	if _, err := db.db.Exec(`UPDATE meta SET value = '0' WHERE key = 'schema_version'`); err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.LoadStorageData()
+	if err != nil {
+		t.Fatalf("LoadStorageData: %v", err)
+	}
+	if got.Instances[0].GroupPath != DefaultGroupPath {
+		t.Errorf("GroupPath = %q, want migrated", got.Instances[0].GroupPath)
+	}
+	// A plain read never writes
+	if _, err := os.Stat(schemaBackupPath(db.Path(), 0)); !os.IsNotExist(err) {
+		t.Errorf("read wrote a pre-migration backup: %v", err)
+	}
+	if v, _ := db.storedSchemaVersion(); v != 0 {
+		t.Errorf("read wrote schema version %d", v)
+	}
+
+	// Opening the storage writes the upgrade under the profile lock
+	s := &Storage{path: db.Path(), profile: "_test", db: db, fileLock: newFileLock(db.Path())}
+	if err := s.upgradeSQLiteSchema(); err != nil {
+		t.Fatalf("upgradeSQLiteSchema: %v", err)
+	}
+	if _, err := os.Stat(schemaBackupPath(db.Path(), 0)); err != nil {
+		t.Errorf("no pre-migration backup: %v", err)
+	}
+	if v, _ := db.storedSchemaVersion(); v != StorageSchemaVersion {
+		t.Errorf("schema version after migration = %d", v)
+	}
+	if got, err := db.readStorageData(); err != nil || got.Instances[0].GroupPath != DefaultGroupPath {
+		t.Errorf("stored rows not migrated: %v", err)
+	}
+
+	if _, err := db.db.Exec(`UPDATE meta SET value = '99' WHERE key = 'schema_version'`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.LoadStorageData(); !errors.Is(err, ErrStorageSchemaTooNew) {
+		t.Errorf("LoadStorageData error = %v, want ErrStorageSchemaTooNew", err)
+	}
+	if err := db.SaveStorageData(testStorageData()); !errors.Is(err, ErrStorageSchemaTooNew) {
+		t.Errorf("SaveStorageData over newer schema error = %v, want ErrStorageSchemaTooNew", err)
+	}
+}
@@ -0,0 +1,594 @@
+package session
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	_ "modernc.org/sqlite" // pure-Go driver, registers "sqlite"
+)
+
+// sqliteSchemaVersion is stored in PRAGMA user_version
+const sqliteSchemaVersion = 1
+
+// sqliteJournalLimit is how many change journal entries are kept
+const sqliteJournalLimit = 1000
+
+// The FieldUpdate fields have their own columns so they can be updated
+// without rewriting the row's JSON; on load they override the JSON.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS meta (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS instances (
+	id                TEXT PRIMARY KEY,
+	position          INTEGER NOT NULL,
+	status            TEXT NOT NULL DEFAULT '',
+	waiting_since     TEXT NOT NULL DEFAULT '',
+	custom_label      TEXT NOT NULL DEFAULT '',
+	last_accessed_at  TEXT NOT NULL DEFAULT '',
+	claude_session_id TEXT NOT NULL DEFAULT '',
+	data              TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS groups (
+	path     TEXT PRIMARY KEY,
+	position INTEGER NOT NULL,
+	data     TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS changes (
+	seq         INTEGER PRIMARY KEY AUTOINCREMENT,
+	at          TEXT NOT NULL,
+	instance_id TEXT NOT NULL,
+	op          TEXT NOT NULL,
+	fields      TEXT NOT NULL DEFAULT ''
+);
+`
+
+// Change journal operations
+const (
+	ChangeInsert = "insert" // instance added
+	ChangeUpdate = "update" // instance rewritten by a full save
+	ChangeDelete = "delete" // instance removed
+	ChangeFields = "fields" // FieldUpdate applied; Fields lists the columns
+	ChangeGroups = "groups" // group list replaced (InstanceID is empty)
+)
+
+// StorageChange is one entry of the SQLite change journal
+type StorageChange struct {
+	Seq        int64     `json:"seq"`
+	At         time.Time `json:"at"`
+	InstanceID string    `json:"instance_id,omitempty"`
+	Op         string    `json:"op"`
+	Fields     []string  `json:"fields,omitempty"`
+}
+
+// SQLiteStore keeps a profile's sessions in a SQLite database in WAL mode.
+// Unlike the JSON file, writers don't rewrite everything under a
+// cross-process lock: full saves only touch rows that changed, field updates
+// are single-row UPDATEs, and every change is recorded in a journal.
+type SQLiteStore struct {
+	db   *sql.DB
+	path string
+
+	// Set for stores shared through acquireSQLiteStore; refs and file are
+	// guarded by sharedSQLiteMu
+	shared bool
+	refs   int
+	file   os.FileInfo
+}
+
+// OpenSQLiteStore opens (creating if needed) the database at path
+func OpenSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(10000)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if version > sqliteSchemaVersion {
+		_ = db.Close()
+		return nil, fmt.Errorf("%s has schema version %d, newer than this agent-deck supports (%d)", path, version, sqliteSchemaVersion)
+	}
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create schema in %s: %w", path, err)
+	}
+	if _, err := db.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteSchemaVersion)); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db, path: path}, nil
+}
+
+// sharedSQLiteStores holds one open store per database file, shared by
+// every Storage of that profile in this process, so callers that don't
+// Close their Storage don't each keep a connection pool and WAL reader open
+var (
+	sharedSQLiteMu     sync.Mutex
+	sharedSQLiteStores = make(map[string]*SQLiteStore)
+)
+
+// acquireSQLiteStore returns the shared store of the database at path,
+// opening it if needed. Release it with releaseSQLiteStore. A store whose
+// file was replaced or deleted (profile removed and recreated) isn't reused.
+func acquireSQLiteStore(path string) (*SQLiteStore, error) {
+	sharedSQLiteMu.Lock()
+	defer sharedSQLiteMu.Unlock()
+
+	if store, ok := sharedSQLiteStores[path]; ok {
+		if info, err := os.Stat(path); err == nil && store.file != nil && os.SameFile(info, store.file) {
+			store.refs++
+			return store, nil
+		}
+		// Stale: existing holders keep it until they release it
+		delete(sharedSQLiteStores, path)
+	}
+
+	store, err := OpenSQLiteStore(path)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(path); err == nil {
+		store.file = info
+	}
+	store.shared, store.refs = true, 1
+	sharedSQLiteStores[path] = store
+	return store, nil
+}
+
+// releaseSQLiteStore drops a reference taken by acquireSQLiteStore and
+// closes the store when it was the last one. Stores from OpenSQLiteStore
+// are closed directly.
+func releaseSQLiteStore(store *SQLiteStore) error {
+	if !store.shared {
+		return store.Close()
+	}
+	sharedSQLiteMu.Lock()
+	defer sharedSQLiteMu.Unlock()
+
+	store.refs--
+	if store.refs > 0 {
+		return nil
+	}
+	if sharedSQLiteStores[store.path] == store {
+		delete(sharedSQLiteStores, store.path)
+	}
+	return store.Close()
+}
+
+// Path returns the database file
+func (s *SQLiteStore) Path() string {
+	return s.path
+}
+
+// Close closes the database
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func formatDBTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseDBTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// LoadStorageData reads all sessions and groups. Returns empty data if the
+// database has never been written. Rows of an older schema are upgraded in
+// memory only; upgradeSchema writes the upgrade, under the profile lock.
+func (s *SQLiteStore) LoadStorageData() (*StorageData, error) {
+	data, err := s.readStorageData()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := upgradeStorageData(data, s.path); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readStorageData reads all sessions and groups as stored
+func (s *SQLiteStore) readStorageData() (*StorageData, error) {
+	data := &StorageData{Instances: []*InstanceData{}, Groups: []*GroupData{}}
+
+	var updated string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = 'updated_at'`).Scan(&updated)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+	data.UpdatedAt = parseDBTime(updated)
+	if data.SchemaVersion, err = s.storedSchemaVersion(); err != nil {
+		return nil, err
+	}
+	if updated == "" {
+		// Never written
+		data.SchemaVersion = StorageSchemaVersion
+	}
+
+	rows, err := s.db.Query(`SELECT status, waiting_since, custom_label, last_accessed_at, claude_session_id, data
+		FROM instances ORDER BY position`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	for rows.Next() {
+		var status, waitingSince, label, accessed, claudeID, blob string
+		if err := rows.Scan(&status, &waitingSince, &label, &accessed, &claudeID, &blob); err != nil {
+			return nil, fmt.Errorf("failed to read sessions: %w", err)
+		}
+		var inst InstanceData
+		if err := json.Unmarshal([]byte(blob), &inst); err != nil {
+			return nil, fmt.Errorf("corrupt session row: %w", err)
+		}
+		inst.Status = Status(status)
+		inst.WaitingSince = parseDBTime(waitingSince)
+		inst.CustomLabel = label
+		inst.LastAccessedAt = parseDBTime(accessed)
+		inst.ClaudeSessionID = claudeID
+		data.Instances = append(data.Instances, &inst)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read sessions: %w", err)
+	}
+
+	groupRows, err := s.db.Query(`SELECT data FROM groups ORDER BY position`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read groups: %w", err)
+	}
+	defer func() { _ = groupRows.Close() }()
+	for groupRows.Next() {
+		var blob string
+		if err := groupRows.Scan(&blob); err != nil {
+			return nil, fmt.Errorf("failed to read groups: %w", err)
+		}
+		var g GroupData
+		if err := json.Unmarshal([]byte(blob), &g); err != nil {
+			return nil, fmt.Errorf("corrupt group row: %w", err)
+		}
+		data.Groups = append(data.Groups, &g)
+	}
+	if err := groupRows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read groups: %w", err)
+	}
+
+	return data, nil
+}
+
+// needsSchemaUpgrade reports whether the stored rows predate the current
+// StorageData schema
+func (s *SQLiteStore) needsSchemaUpgrade() (bool, error) {
+	updated, err := s.GetUpdatedAt()
+	if err != nil || updated.IsZero() {
+		return false, err
+	}
+	version, err := s.storedSchemaVersion()
+	if err != nil {
+		return false, err
+	}
+	return version < StorageSchemaVersion, nil
+}
+
+// upgradeSchema migrates the stored rows to the current schema, after
+// copying the database to sessions.db.schema-v<old>.bak. Caller must hold
+// the profile lock (Storage.lockForUpdate).
+func (s *SQLiteStore) upgradeSchema() error {
+	data, err := s.readStorageData()
+	if err != nil {
+		return err
+	}
+	from := data.SchemaVersion
+	migrated, err := upgradeStorageData(data, s.path)
+	if err != nil || !migrated {
+		return err
+	}
+	backup := schemaBackupPath(s.path, from)
+	_ = os.Remove(backup)
+	if _, err := s.db.Exec(`VACUUM INTO ?`, backup); err != nil {
+		return fmt.Errorf("failed to back up %s before schema migration: %w", s.path, err)
+	}
+	return s.SaveStorageData(data)
+}
+
+// storedSchemaVersion returns the StorageData schema version of the rows
+// (distinct from sqliteSchemaVersion, the table layout); 0 if unset
+func (s *SQLiteStore) storedSchemaVersion() (int, error) {
+	var version int
+	err := s.db.QueryRow(`SELECT CAST(value AS INTEGER) FROM meta WHERE key = 'schema_version'`).Scan(&version)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, fmt.Errorf("failed to read %s: %w", s.path, err)
+	}
+	return version, nil
+}
+
+// sqliteInstanceRow is an instance as stored in the instances table
+type sqliteInstanceRow struct {
+	position                                              int
+	status, waitingSince, label, accessed, claudeID, blob string
+}
+
+func newSQLiteInstanceRow(position int, inst *InstanceData) (sqliteInstanceRow, error) {
+	blob, err := json.Marshal(inst)
+	if err != nil {
+		return sqliteInstanceRow{}, err
+	}
+	return sqliteInstanceRow{
+		position:     position,
+		status:       string(inst.Status),
+		waitingSince: formatDBTime(inst.WaitingSince),
+		label:        inst.CustomLabel,
+		accessed:     formatDBTime(inst.LastAccessedAt),
+		claudeID:     inst.ClaudeSessionID,
+		blob:         string(blob),
+	}, nil
+}
+
+// SaveStorageData replaces the stored sessions and groups with data. Only
+// rows that differ are written, each change is journaled, and nothing is
+// written (not even the timestamp) when data matches what is stored.
+func (s *SQLiteStore) SaveStorageData(data *StorageData) error {
+	data.UpdatedAt = time.Now()
+	if err := validateStorageData(data); err != nil {
+		return fmt.Errorf("data validation failed: %w", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	existing := make(map[string]sqliteInstanceRow)
+	rows, err := tx.Query(`SELECT id, position, status, waiting_since, custom_label, last_accessed_at, claude_session_id, data FROM instances`)
+	if err != nil {
+		return fmt.Errorf("failed to read sessions: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		var r sqliteInstanceRow
+		if err := rows.Scan(&id, &r.position, &r.status, &r.waitingSince, &r.label, &r.accessed, &r.claudeID, &r.blob); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to read sessions: %w", err)
+		}
+		existing[id] = r
+	}
+	_ = rows.Close()
+
+	now := formatDBTime(data.UpdatedAt)
+	var storedVersion int
+	if err := tx.QueryRow(`SELECT CAST(value AS INTEGER) FROM meta WHERE key = 'schema_version'`).Scan(&storedVersion); err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if storedVersion > StorageSchemaVersion {
+		return schemaTooNewError(s.path, storedVersion)
+	}
+	// A schema migration may not change any rows but must still be recorded
+	changed := storedVersion != StorageSchemaVersion
+	journal := func(id, op, fields string) error {
+		changed = true
+		_, err := tx.Exec(`INSERT INTO changes (at, instance_id, op, fields) VALUES (?, ?, ?, ?)`, now, id, op, fields)
+		return err
+	}
+
+	for i, inst := range data.Instances {
+		row, err := newSQLiteInstanceRow(i, inst)
+		if err != nil {
+			return fmt.Errorf("failed to encode session %s: %w", inst.ID, err)
+		}
+		old, ok := existing[inst.ID]
+		delete(existing, inst.ID)
+		if ok && old == row {
+			continue
+		}
+		op := ChangeInsert
+		if ok {
+			op = ChangeUpdate
+		}
+		if _, err := tx.Exec(`INSERT INTO instances (id, position, status, waiting_since, custom_label, last_accessed_at, claude_session_id, data)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(id) DO UPDATE SET position = excluded.position, status = excluded.status,
+				waiting_since = excluded.waiting_since, custom_label = excluded.custom_label,
+				last_accessed_at = excluded.last_accessed_at, claude_session_id = excluded.claude_session_id, data = excluded.data`,
+			inst.ID, row.position, row.status, row.waitingSince, row.label, row.accessed, row.claudeID, row.blob); err != nil {
+			return fmt.Errorf("failed to write session %s: %w", inst.ID, err)
+		}
+		if err := journal(inst.ID, op, ""); err != nil {
+			return err
+		}
+	}
+	for id := range existing {
+		if _, err := tx.Exec(`DELETE FROM instances WHERE id = ?`, id); err != nil {
+			return fmt.Errorf("failed to delete session %s: %w", id, err)
+		}
+		if err := journal(id, ChangeDelete, ""); err != nil {
+			return err
+		}
+	}
+
+	if groupsChanged, err := s.replaceGroups(tx, data.Groups); err != nil {
+		return err
+	} else if groupsChanged {
+		if err := journal("", ChangeGroups, ""); err != nil {
+			return err
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := finishSQLiteWrite(tx, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// replaceGroups rewrites the groups table if groups differ from it
+func (s *SQLiteStore) replaceGroups(tx *sql.Tx, groups []*GroupData) (bool, error) {
+	var stored []string
+	rows, err := tx.Query(`SELECT data FROM groups ORDER BY position`)
+	if err != nil {
+		return false, fmt.Errorf("failed to read groups: %w", err)
+	}
+	for rows.Next() {
+		var blob string
+		if err := rows.Scan(&blob); err != nil {
+			_ = rows.Close()
+			return false, fmt.Errorf("failed to read groups: %w", err)
+		}
+		stored = append(stored, blob)
+	}
+	_ = rows.Close()
+
+	blobs := make([]string, len(groups))
+	same := len(groups) == len(stored)
+	for i, g := range groups {
+		b, err := json.Marshal(g)
+		if err != nil {
+			return false, err
+		}
+		blobs[i] = string(b)
+		same = same && stored[i] == blobs[i]
+	}
+	if same {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`DELETE FROM groups`); err != nil {
+		return false, fmt.Errorf("failed to write groups: %w", err)
+	}
+	for i, g := range groups {
+		if _, err := tx.Exec(`INSERT INTO groups (path, position, data) VALUES (?, ?, ?)`, g.Path, i, blobs[i]); err != nil {
+			return false, fmt.Errorf("failed to write group %s: %w", g.Path, err)
+		}
+	}
+	return true, nil
+}
+
+// finishSQLiteWrite stamps updated_at and the schema version, and trims the
+// journal
+func finishSQLiteWrite(tx *sql.Tx, now string) error {
+	if _, err := tx.Exec(`INSERT INTO meta (key, value) VALUES ('updated_at', ?), ('schema_version', ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value`, now, fmt.Sprint(StorageSchemaVersion)); err != nil {
+		return fmt.Errorf("failed to write timestamp: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM changes WHERE seq <= (SELECT MAX(seq) FROM changes) - ?`, sqliteJournalLimit); err != nil {
+		return fmt.Errorf("failed to trim journal: %w", err)
+	}
+	return nil
+}
+
+// ApplyFieldUpdates writes each update as a single-row UPDATE of only the
+// fields it sets. Updates for sessions that no longer exist are dropped.
+func (s *SQLiteStore) ApplyFieldUpdates(updates map[string]FieldUpdate) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	now := formatDBTime(time.Now())
+	changed := false
+	for id, u := range updates {
+		var cols []string
+		var args []interface{}
+		set := func(col string, value interface{}) {
+			cols = append(cols, col)
+			args = append(args, value)
+		}
+		if u.Status != nil {
+			set("status", *u.Status)
+		}
+		if u.WaitingSince != nil {
+			set("waiting_since", formatDBTime(*u.WaitingSince))
+		} else if u.ClearWaitingSince {
+			set("waiting_since", "")
+		}
+		if u.CustomLabel != nil {
+			set("custom_label", *u.CustomLabel)
+		}
+		if u.LastAccessedAt != nil {
+			set("last_accessed_at", formatDBTime(*u.LastAccessedAt))
+		}
+		if u.ClaudeSessionID != nil {
+			set("claude_session_id", *u.ClaudeSessionID)
+		}
+		if len(cols) == 0 {
+			continue
+		}
+
+		assignments := make([]string, len(cols))
+		for i, col := range cols {
+			assignments[i] = col + " = ?"
+		}
+		res, err := tx.Exec(`UPDATE instances SET `+strings.Join(assignments, ", ")+` WHERE id = ?`, append(args, id)...)
+		if err != nil {
+			return fmt.Errorf("failed to update session %s: %w", id, err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			continue
+		}
+		changed = true
+		if _, err := tx.Exec(`INSERT INTO changes (at, instance_id, op, fields) VALUES (?, ?, ?, ?)`,
+			now, id, ChangeFields, strings.Join(cols, ",")); err != nil {
+			return err
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := finishSQLiteWrite(tx, now); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetUpdatedAt returns when the database was last written (zero if never)
+func (s *SQLiteStore) GetUpdatedAt() (time.Time, error) {
+	var updated string
+	err := s.db.QueryRow(`SELECT value FROM meta WHERE key = 'updated_at'`).Scan(&updated)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseDBTime(updated), nil
+}
+
+// ChangesSince returns journal entries after seq, oldest first. Only the
+// last 1000 changes are kept.
+func (s *SQLiteStore) ChangesSince(seq int64) ([]StorageChange, error) {
+	rows, err := s.db.Query(`SELECT seq, at, instance_id, op, fields FROM changes WHERE seq > ? ORDER BY seq`, seq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+	var changes []StorageChange
+	for rows.Next() {
+		var c StorageChange
+		var at, fields string
+		if err := rows.Scan(&c.Seq, &at, &c.InstanceID, &c.Op, &fields); err != nil {
+			return nil, fmt.Errorf("failed to read journal: %w", err)
+		}
+		c.At = parseDBTime(at)
+		if fields != "" {
+			c.Fields = strings.Split(fields, ",")
+		}
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
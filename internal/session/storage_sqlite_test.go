@@ -0,0 +1,314 @@
+package session
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func openTestSQLiteStore(t *testing.T) *SQLiteStore {
+	t.Helper()
+	db, err := OpenSQLiteStore(filepath.Join(t.TempDir(), sqliteStorageFile))
+	if err != nil {
+		t.Fatalf("OpenSQLiteStore: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func testStorageData() *StorageData {
+	return &StorageData{
+		Instances: []*InstanceData{
+			{ID: "a", Title: "api", ProjectPath: "/srv/api", GroupPath: "work", Tool: "claude", Status: StatusRunning},
+			{ID: "b", Title: "web", ProjectPath: "/srv/web", GroupPath: "work", Tool: "shell", Status: StatusIdle,
+				RemoteHost: "box", PortForwards: []PortForward{{LocalPort: 3000, RemotePort: 3000}}},
+		},
+		Groups: []*GroupData{{Name: "Work", Path: "work", Expanded: true}},
+	}
+}
+
+func TestSQLiteStore_RoundTrip(t *testing.T) {
+	db := openTestSQLiteStore(t)
+
+	if updated, err := db.GetUpdatedAt(); err != nil || !updated.IsZero() {
+		t.Fatalf("fresh GetUpdatedAt = %v, %v, want zero", updated, err)
+	}
+	if err := db.SaveStorageData(testStorageData()); err != nil {
+		t.Fatalf("SaveStorageData: %v", err)
+	}
+
+	got, err := db.LoadStorageData()
+	if err != nil {
+		t.Fatalf("LoadStorageData: %v", err)
+	}
+	if len(got.Instances) != 2 || got.Instances[0].ID != "a" || got.Instances[1].ID != "b" {
+		t.Fatalf("instances = %+v, want a, b in order", got.Instances)
+	}
+	if !reflect.DeepEqual(got.Instances[1].PortForwards, []PortForward{{LocalPort: 3000, RemotePort: 3000}}) {
+		t.Errorf("port forwards not preserved: %+v", got.Instances[1].PortForwards)
+	}
+	if len(got.Groups) != 1 || got.Groups[0].Path != "work" || !got.Groups[0].Expanded {
+		t.Errorf("groups = %+v", got.Groups)
+	}
+	if got.UpdatedAt.IsZero() {
+		t.Error("UpdatedAt not set after save")
+	}
+}
+
+func TestSQLiteStore_Journal(t *testing.T) {
+	db := openTestSQLiteStore(t)
+
+	data := testStorageData()
+	if err := db.SaveStorageData(data); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := db.ChangesSince(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("first save journaled %+v, want 2 inserts and groups", changes)
+	}
+	last := changes[len(changes)-1].Seq
+
+	// Saving the same data writes nothing
+	before, _ := db.GetUpdatedAt()
+	if err := db.SaveStorageData(testStorageData()); err != nil {
+		t.Fatal(err)
+	}
+	if changes, _ := db.ChangesSince(last); len(changes) != 0 {
+		t.Errorf("unchanged save journaled %+v", changes)
+	}
+	if after, _ := db.GetUpdatedAt(); !after.Equal(before) {
+		t.Errorf("unchanged save bumped updated_at %v -> %v", before, after)
+	}
+
+	// One row changes, one is removed
+	data = testStorageData()
+	data.Instances[0].Title = "api v2"
+	data.Instances = data.Instances[:1]
+	if err := db.SaveStorageData(data); err != nil {
+		t.Fatal(err)
+	}
+	changes, _ = db.ChangesSince(last)
+	ops := map[string]string{}
+	for _, c := range changes {
+		ops[c.InstanceID] = c.Op
+	}
+	if !reflect.DeepEqual(ops, map[string]string{"a": ChangeUpdate, "b": ChangeDelete}) {
+		t.Errorf("journal ops = %v, want a update, b delete", ops)
+	}
+}
+
+func TestSQLiteStore_ApplyFieldUpdates(t *testing.T) {
+	db := openTestSQLiteStore(t)
+	if err := db.SaveStorageData(testStorageData()); err != nil {
+		t.Fatal(err)
+	}
+	changes, _ := db.ChangesSince(0)
+	last := changes[len(changes)-1].Seq
+
+	waiting := "waiting"
+	label := "reviewing"
+	since := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	err := db.ApplyFieldUpdates(map[string]FieldUpdate{
+		"a":       {Status: &waiting, WaitingSince: &since, CustomLabel: &label},
+		"missing": {Status: &waiting},
+	})
+	if err != nil {
+		t.Fatalf("ApplyFieldUpdates: %v", err)
+	}
+
+	got, _ := db.LoadStorageData()
+	a := got.Instances[0]
+	if a.Status != StatusWaiting || !a.WaitingSince.Equal(since) || a.CustomLabel != "reviewing" {
+		t.Errorf("a = status %s, waiting %v, label %q", a.Status, a.WaitingSince, a.CustomLabel)
+	}
+	if a.Title != "api" || got.Instances[1].Status != StatusIdle {
+		t.Error("ApplyFieldUpdates touched fields or rows it shouldn't")
+	}
+
+	changes, _ = db.ChangesSince(last)
+	if len(changes) != 1 || changes[0].InstanceID != "a" || changes[0].Op != ChangeFields ||
+		!reflect.DeepEqual(changes[0].Fields, []string{"status", "waiting_since", "custom_label"}) {
+		t.Errorf("journal = %+v, want one fields entry for a", changes)
+	}
+
+	// A later full save keeps the updated columns
+	if err := db.SaveStorageData(got); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := db.LoadStorageData(); again.Instances[0].Status != StatusWaiting {
+		t.Errorf("status after full save = %s", again.Instances[0].Status)
+	}
+}
+
+func TestStorage_ApplyFieldUpdates_JSON(t *testing.T) {
+	s := &Storage{path: filepath.Join(t.TempDir(), jsonStorageFile), profile: "_test"}
+	if err := s.SaveStorageData(testStorageData()); err != nil {
+		t.Fatal(err)
+	}
+
+	claudeID := "0b6a5f6e-1111-2222-3333-444455556666"
+	if err := s.ApplyFieldUpdates(map[string]FieldUpdate{"b": {ClaudeSessionID: &claudeID}}); err != nil {
+		t.Fatalf("ApplyFieldUpdates: %v", err)
+	}
+	got, err := s.LoadStorageData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Instances[1].ClaudeSessionID != claudeID || got.Instances[0].ClaudeSessionID != "" {
+		t.Errorf("ClaudeSessionID = %q, %q", got.Instances[0].ClaudeSessionID, got.Instances[1].ClaudeSessionID)
+	}
+}
+
+func TestMigrateStorageBackend(t *testing.T) {
+	dir := t.TempDir()
+	jsonStorage := &Storage{path: filepath.Join(dir, jsonStorageFile), profile: "_test"}
+	db, err := OpenSQLiteStore(filepath.Join(dir, sqliteStorageFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+	sqliteStorage := &Storage{path: db.Path(), profile: "_test", db: db}
+
+	if err := jsonStorage.SaveStorageData(testStorageData()); err != nil {
+		t.Fatal(err)
+	}
+
+	// JSON -> SQLite on first switch
+	migrateStorageBackend(jsonStorage, sqliteStorage)
+	instances, groups, err := sqliteStorage.LoadWithGroups()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || len(groups) != 1 {
+		t.Fatalf("after migration: %d instances, %d groups", len(instances), len(groups))
+	}
+
+	// Older source is not copied over newer data
+	time.Sleep(10 * time.Millisecond)
+	data, _ := sqliteStorage.LoadStorageData()
+	data.Instances = data.Instances[:1]
+	if err := sqliteStorage.SaveStorageData(data); err != nil {
+		t.Fatal(err)
+	}
+	migrateStorageBackend(jsonStorage, sqliteStorage)
+	if got, _ := sqliteStorage.LoadStorageData(); len(got.Instances) != 1 {
+		t.Errorf("stale JSON overwrote SQLite: %d instances", len(got.Instances))
+	}
+
+	// SQLite -> JSON when switching back
+	migrateStorageBackend(sqliteStorage, jsonStorage)
+	if got, _ := jsonStorage.LoadStorageData(); len(got.Instances) != 1 || got.Instances[0].ID != "a" {
+		t.Errorf("switching back to JSON: %+v", got.Instances)
+	}
+}
+
+func TestStorage_SQLiteLockForUpdate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), sqliteStorageFile)
+	open := func() *Storage {
+		db, err := OpenSQLiteStore(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { _ = db.Close() })
+		return &Storage{path: path, profile: "_test", db: db, fileLock: newFileLock(path)}
+	}
+	updater, other := open(), open()
+	if err := updater.SaveStorageData(testStorageData()); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := updater.lockForUpdate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := updater.loadStorageDataLocked()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A concurrent save waits for the update instead of landing inside it
+	saved := make(chan error, 1)
+	go func() {
+		d := testStorageData()
+		d.Instances[0].Title = "concurrent"
+		saved <- other.SaveStorageData(d)
+	}()
+	select {
+	case err := <-saved:
+		t.Fatalf("save went through during lockForUpdate: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	data.Instances[0].Title = "updated"
+	if err := updater.saveStorageDataLocked(data); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if err := <-saved; err != nil {
+		t.Fatal(err)
+	}
+	got, err := updater.LoadStorageData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Instances[0].Title != "concurrent" {
+		t.Errorf("title = %q, want the save that waited applied last", got.Instances[0].Title)
+	}
+}
+
+func TestAcquireSQLiteStore_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), sqliteStorageFile)
+	first, err := acquireSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := acquireSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("stores of the same file should be shared")
+	}
+
+	// The store stays open until the last holder releases it
+	a := &Storage{path: path, profile: "_test", db: first}
+	b := &Storage{path: path, profile: "_test", db: second}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SaveStorageData(testStorageData()); err != nil {
+		t.Fatalf("save after another holder closed: %v", err)
+	}
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.db.Ping(); err == nil {
+		t.Error("store still open after the last Close")
+	}
+
+	// A replaced database file gets a new store
+	third, err := acquireSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = releaseSQLiteStore(third) })
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	fourth, err := acquireSQLiteStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = releaseSQLiteStore(fourth) })
+	if fourth == third {
+		t.Error("store of a deleted file was reused")
+	}
+}
@@ -1247,3 +1247,39 @@ func TestStorageRemoteTmuxRecoveryMultiCycle(t *testing.T) {
 			data.Instances[0].TmuxSession, "agentdeck_jeeves-worker_deadbeef")
 	}
 }
+
+// TestStorage_SavedProfiles verifies that writes, but not loads, record the
+// profile for the CLI to push through [sync].
+func TestStorage_SavedProfiles(t *testing.T) {
+	saved := func(profile string) bool {
+		for _, p := range SavedProfiles() {
+			if p == profile {
+				return true
+			}
+		}
+		return false
+	}
+
+	path := filepath.Join(t.TempDir(), "sessions.json")
+	s := &Storage{path: path, profile: "_saved_test"}
+	if err := s.SaveStorageData(testStorageData()); err != nil {
+		t.Fatal(err)
+	}
+	if !saved("_saved_test") {
+		t.Fatalf("SavedProfiles() = %v, want _saved_test after a save", SavedProfiles())
+	}
+
+	loader := &Storage{path: path, profile: "_loaded_test"}
+	if _, _, err := loader.LoadWithGroups(); err != nil {
+		t.Fatal(err)
+	}
+	if saved("_loaded_test") {
+		t.Fatalf("SavedProfiles() = %v, loading must not record a profile", SavedProfiles())
+	}
+	if err := loader.ApplyFieldUpdates(map[string]FieldUpdate{}); err != nil {
+		t.Fatal(err)
+	}
+	if saved("_loaded_test") {
+		t.Fatalf("SavedProfiles() = %v, an update that changed nothing must not record a profile", SavedProfiles())
+	}
+}
@@ -0,0 +1,715 @@
+package session
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/asheshgoplani/agent-deck/internal/git"
+)
+
+// syncDirName is the git working copy used for syncing, in ~/.agent-deck.
+// It holds profiles/<profile>/sessions.json and config.toml.
+const syncDirName = "sync"
+
+// syncRepoConfigFile is the synced part of config.toml in the sync repo
+const syncRepoConfigFile = "config.toml"
+
+// syncedRefPrefix names the per-profile refs in the sync working copy that
+// point at the last commit each profile was synced to
+const syncedRefPrefix = "refs/agent-deck/synced/"
+
+// syncAttempts bounds how often a sync restarts when the remote or the local
+// sessions change while it runs
+const syncAttempts = 3
+
+// errSyncRetry restarts a sync from the fetch
+var errSyncRetry = errors.New("sync raced with another change")
+
+// Session fields that describe this machine's view of a session. They are
+// synced but never conflict: the local value is kept.
+var syncLocalSessionFields = map[string]bool{
+	"status":               true,
+	"last_accessed_at":     true,
+	"waiting_since":        true,
+	"latest_prompt":        true,
+	"loaded_mcp_names":     true,
+	"claude_detected_at":   true,
+	"gemini_detected_at":   true,
+	"opencode_detected_at": true,
+}
+
+// Group fields kept local (expanded) or merged without reporting conflicts
+// (order, reordering on both machines is common and harmless)
+var (
+	syncLocalGroupFields = map[string]bool{"expanded": true}
+	syncQuietGroupFields = map[string]bool{"order": true}
+)
+
+// SyncConflict is an entry changed on both this machine and another since
+// the last sync. The local change is kept.
+type SyncConflict struct {
+	Kind   string   `json:"kind"`             // session, group, mcp, mcp_bundle or launch_config
+	ID     string   `json:"id"`               // Session ID, group path or config entry name
+	Name   string   `json:"name,omitempty"`   // Session title or group name
+	Fields []string `json:"fields,omitempty"` // Fields changed on both machines
+	Detail string   `json:"detail,omitempty"` // Set for delete/change conflicts
+}
+
+// String describes the conflict, e.g. `session "api": title, group_path`
+func (c SyncConflict) String() string {
+	name := c.Name
+	if name == "" {
+		name = c.ID
+	}
+	s := fmt.Sprintf("%s %q", strings.ReplaceAll(c.Kind, "_", " "), name)
+	if len(c.Fields) > 0 {
+		s += ": " + strings.Join(c.Fields, ", ")
+	}
+	if c.Detail != "" {
+		s += " (" + c.Detail + ")"
+	}
+	return s
+}
+
+// SyncChanges counts entries the merge changed locally
+type SyncChanges struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+}
+
+// Any reports whether anything changed
+func (c SyncChanges) Any() bool {
+	return c.Added+c.Updated+c.Removed > 0
+}
+
+// SyncResult summarizes a sync
+type SyncResult struct {
+	Profile       string         `json:"profile"`
+	Sessions      SyncChanges    `json:"sessions"` // Changes pulled into local storage
+	Groups        SyncChanges    `json:"groups"`
+	ConfigChanged bool           `json:"config_changed"`
+	Pushed        bool           `json:"pushed"`
+	Commit        string         `json:"commit,omitempty"`
+	Conflicts     []SyncConflict `json:"conflicts,omitempty"`
+}
+
+// ConflictSummary describes the conflicts on one line for status messages
+func (r *SyncResult) ConflictSummary() string {
+	if len(r.Conflicts) == 0 {
+		return ""
+	}
+	parts := make([]string, len(r.Conflicts))
+	for i, c := range r.Conflicts {
+		parts[i] = c.String()
+	}
+	return fmt.Sprintf("sync: %d conflict(s), kept local changes: %s", len(r.Conflicts), strings.Join(parts, "; "))
+}
+
+// syncedProfile is a profile's sessions and groups as stored in the sync
+// repo. UpdatedAt is left out so unchanged profiles don't produce commits.
+type syncedProfile struct {
+	SchemaVersion int             `json:"schema_version"`
+	Instances     []*InstanceData `json:"instances"`
+	Groups        []*GroupData    `json:"groups"`
+}
+
+// SyncRepoDir returns the sync working copy (~/.agent-deck/sync)
+func SyncRepoDir() (string, error) {
+	dir, err := GetAgentDeckDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, syncDirName), nil
+}
+
+// SyncProfile pulls the profile from the [sync] remote, merges it three-way
+// with local sessions, groups and config, and pushes the result (empty
+// profile = effective profile)
+func SyncProfile(profile string) (*SyncResult, error) {
+	settings := GetSyncSettings()
+	if settings.Remote == "" {
+		return nil, fmt.Errorf("no sync remote: set [sync] remote in config.toml or run 'agent-deck sync init <remote>'")
+	}
+	profile = GetEffectiveProfile(profile)
+	dir, err := SyncRepoDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// One sync at a time across processes (TUI and CLI share the working copy)
+	handle, err := newFileLock(dir).Lock()
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock sync repo: %w", err)
+	}
+	defer func() { _ = handle.Unlock() }()
+
+	if err := ensureSyncRepo(dir, settings.Remote); err != nil {
+		return nil, err
+	}
+	for attempt := 0; attempt < syncAttempts; attempt++ {
+		result, err := syncProfileOnce(dir, profile, settings)
+		if errors.Is(err, errSyncRetry) {
+			continue
+		}
+		return result, err
+	}
+	return nil, fmt.Errorf("sync gave up: the remote or local sessions kept changing, try again")
+}
+
+// LastSync returns the commit a profile was last synced to and when it was
+// made, or "" if the profile was never synced on this machine
+func LastSync(profile string) (commit string, at time.Time, err error) {
+	dir, err := SyncRepoDir()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		return "", time.Time{}, nil
+	}
+	out, err := git.Run(dir, "log", "-1", "--format=%H %cI", syncedRefPrefix+GetEffectiveProfile(profile), "--")
+	if err != nil || out == "" {
+		return "", time.Time{}, nil
+	}
+	commit, date, _ := strings.Cut(out, " ")
+	at, _ = time.Parse(time.RFC3339, date)
+	return commit, at, nil
+}
+
+// ensureSyncRepo clones the remote into dir, or points an existing working
+// copy at it
+func ensureSyncRepo(dir, remote string) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		url, _ := git.Run(dir, "remote", "get-url", "origin")
+		if url != remote {
+			if _, err := git.Run(dir, "remote", "set-url", "origin", remote); err != nil {
+				return fmt.Errorf("failed to update sync remote: %w", err)
+			}
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+		return fmt.Errorf("failed to create sync directory: %w", err)
+	}
+	if _, err := git.Run(filepath.Dir(dir), "clone", "-q", remote, dir); err != nil {
+		return fmt.Errorf("failed to clone sync remote: %w", err)
+	}
+	return nil
+}
+
+// syncProfileOnce runs one fetch, merge, commit and push round
+func syncProfileOnce(dir, profile string, settings SyncSettings) (*SyncResult, error) {
+	if _, err := git.Run(dir, "fetch", "-q", "origin"); err != nil {
+		return nil, fmt.Errorf("failed to fetch sync remote: %w", err)
+	}
+	remoteRef := "refs/remotes/origin/" + settings.Branch
+	remoteRev, _ := git.Run(dir, "rev-parse", "-q", "--verify", remoteRef+"^{commit}")
+	headRev, _ := git.Run(dir, "rev-parse", "-q", "--verify", "HEAD^{commit}")
+
+	// The base is the last state of this profile that this machine and the
+	// remote agreed on. Neither HEAD (a fresh clone or an unpushed commit)
+	// nor the remote would do: entries missing from them would look deleted.
+	syncedRef := syncedRefPrefix + profile
+	baseRev, _ := git.Run(dir, "rev-parse", "-q", "--verify", syncedRef+"^{commit}")
+
+	profilePath := path.Join("profiles", profile, jsonStorageFile)
+	base, err := readSyncedProfile(dir, baseRev, profilePath)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := readSyncedProfile(dir, remoteRev, profilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	storage, err := NewStorageWithProfile(profile)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = storage.Close() }()
+	ours, err := storage.LoadStorageData()
+	if err != nil {
+		return nil, err
+	}
+	loadedAt := ours.UpdatedAt
+
+	result := &SyncResult{Profile: profile}
+	merged := mergeSyncedProfiles(base, &syncedProfile{
+		SchemaVersion: ours.SchemaVersion,
+		Instances:     ours.Instances,
+		Groups:        ours.Groups,
+	}, theirs, result)
+
+	var mergedConfig *BundleConfig
+	if !settings.SkipConfig {
+		if mergedConfig, err = syncConfig(dir, baseRev, remoteRev, result); err != nil {
+			return nil, err
+		}
+	}
+
+	// Apply the merge locally unless a save slipped in since loading. The
+	// check and the write share the lock so no save can land in between.
+	if result.Sessions.Any() || result.Groups.Any() {
+		if err := applySyncedProfile(storage, loadedAt, merged); err != nil {
+			return nil, err
+		}
+	}
+
+	// Commit the merge on top of the remote branch
+	switch {
+	case remoteRev != "":
+		if _, err := git.Run(dir, "checkout", "-q", "-f", "-B", settings.Branch, remoteRev); err != nil {
+			return nil, fmt.Errorf("failed to check out sync branch: %w", err)
+		}
+	case headRev == "":
+		if _, err := git.Run(dir, "symbolic-ref", "HEAD", "refs/heads/"+settings.Branch); err != nil {
+			return nil, fmt.Errorf("failed to set up sync branch: %w", err)
+		}
+	}
+	paths := []string{profilePath}
+	if err := writeSyncFile(filepath.Join(dir, filepath.FromSlash(profilePath)), merged); err != nil {
+		return nil, err
+	}
+	if mergedConfig != nil {
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(mergedConfig); err != nil {
+			return nil, fmt.Errorf("failed to encode synced config: %w", err)
+		}
+		if err := writeFileAtomic(filepath.Join(dir, syncRepoConfigFile), buf.Bytes()); err != nil {
+			return nil, err
+		}
+		paths = append(paths, syncRepoConfigFile)
+	}
+	if _, err := git.Run(dir, append([]string{"add", "--"}, paths...)...); err != nil {
+		return nil, fmt.Errorf("failed to stage sync changes: %w", err)
+	}
+	if status, err := git.Run(dir, "status", "--porcelain", "--untracked-files=no"); err != nil {
+		return nil, fmt.Errorf("failed to check sync changes: %w", err)
+	} else if status != "" {
+		host, _ := os.Hostname()
+		if _, err := git.Run(dir,
+			"-c", "user.name=agent-deck", "-c", "user.email=agent-deck@"+host,
+			"commit", "-q", "-m", fmt.Sprintf("Sync profile %s from %s", profile, host)); err != nil {
+			return nil, fmt.Errorf("failed to commit sync changes: %w", err)
+		}
+	}
+
+	headRev, _ = git.Run(dir, "rev-parse", "-q", "--verify", "HEAD^{commit}")
+	result.Commit = headRev
+	if headRev == "" {
+		return result, nil
+	}
+	if headRev == remoteRev {
+		return result, markSynced(dir, syncedRef, headRev)
+	}
+	if _, err := git.Run(dir, "push", "-q", "origin", "HEAD:refs/heads/"+settings.Branch); err != nil {
+		// Rejected because another machine pushed first: merge again
+		if _, fetchErr := git.Run(dir, "fetch", "-q", "origin"); fetchErr == nil {
+			if rev, _ := git.Run(dir, "rev-parse", "-q", "--verify", remoteRef+"^{commit}"); rev != remoteRev {
+				return nil, errSyncRetry
+			}
+		}
+		return nil, fmt.Errorf("failed to push sync changes: %w", err)
+	}
+	result.Pushed = true
+	return result, markSynced(dir, syncedRef, headRev)
+}
+
+// applySyncedProfile saves the merged sessions and groups into storage, or
+// returns errSyncRetry if storage changed since it was loaded at loadedAt
+func applySyncedProfile(storage *Storage, loadedAt time.Time, merged *syncedProfile) error {
+	unlock, err := storage.lockForUpdate()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := storage.loadStorageDataLocked()
+	if err != nil {
+		return err
+	}
+	if !current.UpdatedAt.Equal(loadedAt) {
+		return errSyncRetry
+	}
+	current.Instances, current.Groups = merged.Instances, merged.Groups
+	if err := storage.saveStorageDataLocked(current); err != nil {
+		return fmt.Errorf("failed to save synced sessions: %w", err)
+	}
+	return nil
+}
+
+// markSynced records rev as the new merge base of a profile
+func markSynced(dir, ref, rev string) error {
+	if _, err := git.Run(dir, "update-ref", ref, rev); err != nil {
+		return fmt.Errorf("failed to record sync: %w", err)
+	}
+	return nil
+}
+
+// readSyncedProfile reads a profile from a commit of the sync repo. A
+// missing commit or file is an empty profile.
+func readSyncedProfile(dir, rev, file string) (*syncedProfile, error) {
+	p := &syncedProfile{}
+	if rev == "" {
+		return p, nil
+	}
+	data, err := git.Run(dir, "show", rev+":"+file)
+	if err != nil {
+		return p, nil
+	}
+	if err := json.Unmarshal([]byte(data), p); err != nil {
+		return nil, fmt.Errorf("invalid %s in sync repo: %w", file, err)
+	}
+	if p.SchemaVersion > StorageSchemaVersion {
+		return nil, fmt.Errorf("synced sessions use schema version %d, newer than this agent-deck supports (%d); upgrade agent-deck",
+			p.SchemaVersion, StorageSchemaVersion)
+	}
+	return p, nil
+}
+
+// writeSyncFile writes a profile to the sync working copy. Machine-local
+// fields are reset so status changes and expanding groups don't produce
+// commits; sessions pulled on another machine start idle and groups
+// expanded.
+func writeSyncFile(file string, p *syncedProfile) error {
+	out := &syncedProfile{SchemaVersion: p.SchemaVersion, Instances: []*InstanceData{}, Groups: []*GroupData{}}
+	for _, inst := range p.Instances {
+		r := toSyncRecord(inst)
+		for field := range syncLocalSessionFields {
+			delete(r, field)
+		}
+		c := &InstanceData{}
+		fromSyncRecord(r, c)
+		c.Status = StatusIdle
+		out.Instances = append(out.Instances, c)
+	}
+	for _, g := range p.Groups {
+		c := *g
+		c.Expanded = true
+		out.Groups = append(out.Groups, &c)
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode synced sessions: %w", err)
+	}
+	return writeFileAtomic(file, append(data, '\n'))
+}
+
+// mergeSyncedProfiles merges sessions by ID and groups by path and records
+// the local changes and conflicts in result
+func mergeSyncedProfiles(base, ours, theirs *syncedProfile, result *SyncResult) *syncedProfile {
+	sessionEntries := func(instances []*InstanceData) []syncEntry {
+		entries := make([]syncEntry, 0, len(instances))
+		for _, inst := range instances {
+			entries = append(entries, syncEntry{key: inst.ID, name: inst.Title, record: toSyncRecord(inst)})
+		}
+		return entries
+	}
+	groupEntries := func(groups []*GroupData) []syncEntry {
+		entries := make([]syncEntry, 0, len(groups))
+		for _, g := range groups {
+			entries = append(entries, syncEntry{key: g.Path, name: g.Name, record: toSyncRecord(g)})
+		}
+		return entries
+	}
+
+	merged := &syncedProfile{SchemaVersion: StorageSchemaVersion}
+	records, conflicts, changes := threeWayMerge("session",
+		sessionEntries(base.Instances), sessionEntries(ours.Instances), sessionEntries(theirs.Instances),
+		syncLocalSessionFields, nil)
+	for _, r := range records {
+		inst := &InstanceData{}
+		fromSyncRecord(r, inst)
+		merged.Instances = append(merged.Instances, inst)
+	}
+	result.Sessions = changes
+	result.Conflicts = append(result.Conflicts, conflicts...)
+
+	records, conflicts, changes = threeWayMerge("group",
+		groupEntries(base.Groups), groupEntries(ours.Groups), groupEntries(theirs.Groups),
+		syncLocalGroupFields, syncQuietGroupFields)
+	for _, r := range records {
+		g := &GroupData{}
+		fromSyncRecord(r, g)
+		merged.Groups = append(merged.Groups, g)
+	}
+	result.Groups = changes
+	result.Conflicts = append(result.Conflicts, conflicts...)
+
+	if merged.Instances == nil {
+		merged.Instances = []*InstanceData{}
+	}
+	if merged.Groups == nil {
+		merged.Groups = []*GroupData{}
+	}
+	return merged
+}
+
+// syncConfig merges the synced part of config.toml and saves it locally if
+// the remote changed it. Returns the merged config to commit. Literal MCP
+// secrets never reach the sync repo: they're committed as env: refs (see
+// MaskLiteralSecrets), and the local literal is kept as long as the merge
+// leaves that ref alone.
+func syncConfig(dir, baseRev, remoteRev string, result *SyncResult) (*BundleConfig, error) {
+	base, err := readSyncedConfig(dir, baseRev)
+	if err != nil {
+		return nil, err
+	}
+	theirs, err := readSyncedConfig(dir, remoteRev)
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadUserConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	maskedMCPs, secrets := MaskLiteralSecrets(syncRepoConfigFile, config.MCPs)
+	ours := &BundleConfig{MCPs: maskedMCPs, MCPBundles: config.MCPBundles, LaunchConfigs: config.LaunchConfigs}
+
+	merged := &BundleConfig{}
+	changed := false
+	merged.MCPs, changed = mergeSyncedMap("mcp", base.MCPs, ours.MCPs, theirs.MCPs, result, changed)
+	merged.MCPBundles, changed = mergeSyncedMap("mcp_bundle", base.MCPBundles, ours.MCPBundles, theirs.MCPBundles, result, changed)
+	merged.LaunchConfigs, changed = mergeSyncedMap("launch_config", base.LaunchConfigs, ours.LaunchConfigs, theirs.LaunchConfigs, result, changed)
+	if !changed {
+		return merged, nil
+	}
+
+	configCopy := *config
+	configCopy.MCPs = restoreLiteralSecrets(merged.MCPs, config.MCPs, secrets)
+	configCopy.MCPBundles = merged.MCPBundles
+	configCopy.LaunchConfigs = merged.LaunchConfigs
+	if err := SaveUserConfig(&configCopy); err != nil {
+		return nil, fmt.Errorf("failed to save synced config: %w", err)
+	}
+	result.ConfigChanged = true
+	return merged, nil
+}
+
+// restoreLiteralSecrets puts the local literal secrets back into merged
+// wherever it still holds the ref they were masked as
+func restoreLiteralSecrets(merged, local map[string]MCPDef, secrets []SecretFinding) map[string]MCPDef {
+	restored := make(map[string]MCPDef, len(merged))
+	for name, def := range merged {
+		restored[name] = def
+	}
+	for _, f := range secrets {
+		def, ok := restored[f.MCP]
+		if !ok {
+			continue
+		}
+		values, literal := def.Env, local[f.MCP].Env[f.Key]
+		if f.Field == "headers" {
+			values, literal = def.Headers, local[f.MCP].Headers[f.Key]
+		}
+		if values[f.Key] != f.Ref {
+			continue
+		}
+		copied := make(map[string]string, len(values))
+		for k, v := range values {
+			copied[k] = v
+		}
+		copied[f.Key] = literal
+		if f.Field == "headers" {
+			def.Headers = copied
+		} else {
+			def.Env = copied
+		}
+		restored[f.MCP] = def
+	}
+	return restored
+}
+
+// readSyncedConfig reads config.toml from a commit of the sync repo
+func readSyncedConfig(dir, rev string) (*BundleConfig, error) {
+	c := &BundleConfig{}
+	if rev == "" {
+		return c, nil
+	}
+	data, err := git.Run(dir, "show", rev+":"+syncRepoConfigFile)
+	if err != nil {
+		return c, nil
+	}
+	if err := toml.Unmarshal([]byte(data), c); err != nil {
+		return nil, fmt.Errorf("invalid %s in sync repo: %w", syncRepoConfigFile, err)
+	}
+	return c, nil
+}
+
+// mergeSyncedMap merges config entries by name. changed is carried over
+// from earlier maps and set if the merge differs from ours.
+func mergeSyncedMap[V any](kind string, base, ours, theirs map[string]V, result *SyncResult, changed bool) (map[string]V, bool) {
+	entries := func(m map[string]V) []syncEntry {
+		entries := make([]syncEntry, 0, len(m))
+		for _, name := range sortedKeys(m) {
+			entries = append(entries, syncEntry{key: name, name: name, record: syncRecord{"value": mustMarshal(m[name])}})
+		}
+		return entries
+	}
+	records, conflicts, changes := threeWayMerge(kind, entries(base), entries(ours), entries(theirs), nil, nil)
+	result.Conflicts = append(result.Conflicts, conflicts...)
+
+	merged := make(map[string]V, len(records))
+	for _, r := range records {
+		var name string
+		var v V
+		_ = json.Unmarshal(r["key"], &name)
+		_ = json.Unmarshal(r["value"], &v)
+		merged[name] = v
+	}
+	return merged, changed || changes.Any()
+}
+
+// syncRecord is an entry as JSON fields, compared field by field
+type syncRecord map[string]json.RawMessage
+
+// syncEntry is a session, group or config entry in one version of a profile
+type syncEntry struct {
+	key    string
+	name   string
+	record syncRecord
+}
+
+func toSyncRecord(v interface{}) syncRecord {
+	var r syncRecord
+	_ = json.Unmarshal(mustMarshal(v), &r)
+	return r
+}
+
+func fromSyncRecord(r syncRecord, v interface{}) {
+	_ = json.Unmarshal(mustMarshal(r), v)
+}
+
+func mustMarshal(v interface{}) json.RawMessage {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage("null")
+	}
+	return data
+}
+
+// equal compares two records, ignoring the given fields
+func (r syncRecord) equal(other syncRecord, ignore map[string]bool) bool {
+	for k, v := range r {
+		if !ignore[k] && !bytes.Equal(v, other[k]) {
+			return false
+		}
+	}
+	for k := range other {
+		if _, ok := r[k]; !ok && !ignore[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// threeWayMerge merges keyed entries. Entries keep ours' order, with
+// entries added on the other side appended in theirs' order. Fields changed
+// on both sides keep ours and are reported as conflicts, except quiet
+// fields; local fields always keep ours. Changes are counted against ours.
+func threeWayMerge(kind string, base, ours, theirs []syncEntry, local, quiet map[string]bool) ([]syncRecord, []SyncConflict, SyncChanges) {
+	index := func(entries []syncEntry) map[string]syncEntry {
+		m := make(map[string]syncEntry, len(entries))
+		for _, e := range entries {
+			m[e.key] = e
+		}
+		return m
+	}
+	baseByKey, oursByKey, theirsByKey := index(base), index(ours), index(theirs)
+
+	var out []syncRecord
+	var conflicts []SyncConflict
+	var changes SyncChanges
+	keep := func(e syncEntry, r syncRecord) {
+		if kind != "session" && kind != "group" {
+			r["key"] = mustMarshal(e.key)
+		}
+		out = append(out, r)
+	}
+
+	for _, o := range ours {
+		b, inBase := baseByKey[o.key]
+		t, inTheirs := theirsByKey[o.key]
+		switch {
+		case inTheirs:
+			merged, fields := mergeSyncRecord(b.record, o.record, t.record, local, quiet)
+			if len(fields) > 0 {
+				conflicts = append(conflicts, SyncConflict{Kind: kind, ID: o.key, Name: o.name, Fields: fields})
+			}
+			if !merged.equal(o.record, local) {
+				changes.Updated++
+			}
+			keep(o, merged)
+		case inBase && b.record.equal(o.record, local):
+			// Deleted on another machine
+			changes.Removed++
+		case inBase:
+			conflicts = append(conflicts, SyncConflict{Kind: kind, ID: o.key, Name: o.name,
+				Detail: "deleted on another machine, changed here: kept"})
+			keep(o, o.record)
+		default:
+			// Added here
+			keep(o, o.record)
+		}
+	}
+	for _, t := range theirs {
+		if _, ok := oursByKey[t.key]; ok {
+			continue
+		}
+		b, inBase := baseByKey[t.key]
+		switch {
+		case !inBase:
+			changes.Added++
+			keep(t, t.record)
+		case b.record.equal(t.record, local):
+			// Deleted here
+		default:
+			conflicts = append(conflicts, SyncConflict{Kind: kind, ID: t.key, Name: t.name,
+				Detail: "deleted here, changed on another machine: restored"})
+			changes.Added++
+			keep(t, t.record)
+		}
+	}
+	return out, conflicts, changes
+}
+
+// mergeSyncRecord merges one entry field by field and returns the fields
+// that conflicted. base may be nil (added on both sides).
+func mergeSyncRecord(base, ours, theirs syncRecord, local, quiet map[string]bool) (syncRecord, []string) {
+	keys := make(map[string]bool, len(ours)+len(theirs))
+	for k := range ours {
+		keys[k] = true
+	}
+	for k := range theirs {
+		keys[k] = true
+	}
+
+	merged := make(syncRecord, len(keys))
+	var conflicts []string
+	for k := range keys {
+		b, o, t := base[k], ours[k], theirs[k]
+		v := o
+		switch {
+		case local[k] || bytes.Equal(o, t) || bytes.Equal(b, t):
+		case bytes.Equal(b, o):
+			v = t
+		case !quiet[k]:
+			conflicts = append(conflicts, k)
+		}
+		if v != nil {
+			merged[k] = v
+		}
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
@@ -0,0 +1,202 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NormalizeTag lowercases a tag, drops a leading '#' and replaces spaces
+// with '-'. Returns "" for tags that are empty after normalizing.
+func NormalizeTag(tag string) string {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	return strings.ToLower(strings.Join(strings.Fields(tag), "-"))
+}
+
+// NormalizeTags normalizes tags and drops empty and duplicate ones, keeping
+// the first occurrence's position
+func NormalizeTags(tags []string) []string {
+	var out []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		out = append(out, tag)
+	}
+	return out
+}
+
+// HasTag reports whether the session has tag (compared normalized)
+func (i *Instance) HasTag(tag string) bool {
+	tag = NormalizeTag(tag)
+	for _, t := range i.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds tags the session doesn't have yet. Returns true if any were
+// added.
+func (i *Instance) AddTags(tags ...string) bool {
+	before := len(i.Tags)
+	i.Tags = NormalizeTags(append(append([]string{}, i.Tags...), tags...))
+	return len(i.Tags) != before
+}
+
+// RemoveTags removes tags from the session. Returns true if any were removed.
+func (i *Instance) RemoveTags(tags ...string) bool {
+	remove := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		remove[NormalizeTag(tag)] = true
+	}
+	kept := i.Tags[:0]
+	for _, t := range i.Tags {
+		if !remove[t] {
+			kept = append(kept, t)
+		}
+	}
+	removed := len(kept) != len(i.Tags)
+	i.Tags = kept
+	if len(i.Tags) == 0 {
+		i.Tags = nil
+	}
+	return removed
+}
+
+// SetMeta sets a metadata value; an empty value removes the key. Returns
+// true if the metadata changed.
+func (i *Instance) SetMeta(key, value string) bool {
+	if value == "" {
+		if _, ok := i.Meta[key]; !ok {
+			return false
+		}
+		delete(i.Meta, key)
+		if len(i.Meta) == 0 {
+			i.Meta = nil
+		}
+		return true
+	}
+	if i.Meta == nil {
+		i.Meta = make(map[string]string)
+	}
+	if old, ok := i.Meta[key]; ok && old == value {
+		return false
+	}
+	i.Meta[key] = value
+	return true
+}
+
+// MatchesMeta reports whether the session has key, and value if it isn't
+// empty. Keys are case-sensitive, values are compared case-insensitively.
+func (i *Instance) MatchesMeta(key, value string) bool {
+	got, ok := i.Meta[key]
+	if !ok {
+		return false
+	}
+	return value == "" || strings.EqualFold(got, value)
+}
+
+// ParseMetaAssignment parses "key=value" (value may be empty, which
+// removes the key when set)
+func ParseMetaAssignment(s string) (key, value string, err error) {
+	key, value, ok := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid metadata %q: use key=value", s)
+	}
+	if strings.ContainsAny(key, " \t") {
+		return "", "", fmt.Errorf("invalid metadata key %q: no spaces allowed", key)
+	}
+	return key, strings.TrimSpace(value), nil
+}
+
+// FormatMeta formats metadata as "key=value" pairs sorted by key
+func FormatMeta(meta map[string]string) []string {
+	keys := make([]string, 0, len(meta))
+	for k := range meta {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+meta[k])
+	}
+	return pairs
+}
+
+// FormatTags formats tags as "#tag" separated by spaces
+func FormatTags(tags []string) string {
+	parts := make([]string, len(tags))
+	for i, t := range tags {
+		parts[i] = "#" + t
+	}
+	return strings.Join(parts, " ")
+}
+
+// filterByTagsAndMeta splits "tag:x" and "meta:key[=value]" terms out of a
+// search query and returns the sessions matching all of them with the rest
+// of the query. ok is false when the query has no such terms.
+func filterByTagsAndMeta(instances []*Instance, query string) (filtered []*Instance, rest string, ok bool) {
+	var tags []string
+	var metas [][2]string
+	var other []string
+	for _, term := range strings.Fields(query) {
+		lower := strings.ToLower(term)
+		switch {
+		case strings.HasPrefix(lower, "tag:") && len(term) > len("tag:"):
+			tags = append(tags, term[len("tag:"):])
+		case strings.HasPrefix(lower, "meta:") && len(term) > len("meta:"):
+			key, value, _ := strings.Cut(term[len("meta:"):], "=")
+			metas = append(metas, [2]string{key, value})
+		default:
+			other = append(other, term)
+		}
+	}
+	if len(tags) == 0 && len(metas) == 0 {
+		return instances, query, false
+	}
+
+	filtered = make([]*Instance, 0)
+	for _, inst := range instances {
+		if inst.matchesAll(tags, metas) {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered, strings.Join(other, " "), true
+}
+
+func (i *Instance) matchesAll(tags []string, metas [][2]string) bool {
+	for _, tag := range tags {
+		if !i.HasTag(tag) {
+			return false
+		}
+	}
+	for _, m := range metas {
+		if !i.MatchesMeta(m[0], m[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterByTagsAndMeta returns the sessions that have all tags and match all
+// metadata ("key=value", or "key" for any value)
+func FilterByTagsAndMeta(instances []*Instance, tags, meta []string) []*Instance {
+	metas := make([][2]string, 0, len(meta))
+	for _, m := range meta {
+		key, value, _ := strings.Cut(m, "=")
+		metas = append(metas, [2]string{key, value})
+	}
+	filtered := make([]*Instance, 0, len(instances))
+	for _, inst := range instances {
+		if inst.matchesAll(tags, metas) {
+			filtered = append(filtered, inst)
+		}
+	}
+	return filtered
+}
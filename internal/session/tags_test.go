@@ -0,0 +1,140 @@
+package session
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Urgent", " #backend ", "code review", "urgent", "#", ""})
+	want := []string{"urgent", "backend", "code-review"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeTags() = %v, want %v", got, want)
+	}
+}
+
+func TestInstance_AddRemoveTags(t *testing.T) {
+	inst := NewInstance("api", "/srv/api")
+
+	if !inst.AddTags("Urgent", "backend") {
+		t.Fatal("AddTags should report new tags")
+	}
+	if inst.AddTags("#urgent") {
+		t.Error("AddTags of an existing tag should report no change")
+	}
+	if !inst.HasTag("URGENT") || !reflect.DeepEqual(inst.Tags, []string{"urgent", "backend"}) {
+		t.Errorf("Tags = %v", inst.Tags)
+	}
+
+	if !inst.RemoveTags("urgent", "missing") || inst.HasTag("urgent") {
+		t.Errorf("RemoveTags left %v", inst.Tags)
+	}
+	if inst.RemoveTags("urgent") {
+		t.Error("RemoveTags of a missing tag should report no change")
+	}
+	inst.RemoveTags("backend")
+	if inst.Tags != nil {
+		t.Errorf("removing the last tag should leave nil, got %v", inst.Tags)
+	}
+}
+
+func TestInstance_SetMeta(t *testing.T) {
+	inst := NewInstance("api", "/srv/api")
+
+	if !inst.SetMeta("client", "acme") || inst.SetMeta("client", "acme") {
+		t.Error("SetMeta should report a change only when the value differs")
+	}
+	if !inst.MatchesMeta("client", "ACME") || !inst.MatchesMeta("client", "") || inst.MatchesMeta("Client", "") {
+		t.Errorf("MatchesMeta mismatch for %v", inst.Meta)
+	}
+	if !inst.SetMeta("client", "") || inst.Meta != nil {
+		t.Errorf("empty value should remove the key, got %v", inst.Meta)
+	}
+}
+
+func TestParseMetaAssignment(t *testing.T) {
+	tests := []struct {
+		in         string
+		key, value string
+		wantErr    bool
+	}{
+		{"client=acme", "client", "acme", false},
+		{"ticket=ENG-42=x", "ticket", "ENG-42=x", false},
+		{"ticket=", "ticket", "", false},
+		{"client", "", "", true},
+		{"=acme", "", "", true},
+		{"my key=x", "", "", true},
+	}
+	for _, tt := range tests {
+		key, value, err := ParseMetaAssignment(tt.in)
+		if (err != nil) != tt.wantErr || key != tt.key || value != tt.value {
+			t.Errorf("ParseMetaAssignment(%q) = %q, %q, %v", tt.in, key, value, err)
+		}
+	}
+}
+
+func TestFilterByQuery_TagsAndMeta(t *testing.T) {
+	api := NewInstance("api-server", "/srv/api")
+	api.AddTags("urgent", "backend")
+	api.SetMeta("client", "Acme")
+	web := NewInstance("web-app", "/srv/web")
+	web.AddTags("urgent")
+	docs := NewInstance("docs", "/srv/docs")
+	instances := []*Instance{api, web, docs}
+
+	tests := []struct {
+		query string
+		want  []*Instance
+	}{
+		{"tag:urgent", []*Instance{api, web}},
+		{"TAG:Urgent tag:backend", []*Instance{api}},
+		{"tag:urgent web", []*Instance{web}},
+		{"meta:client", []*Instance{api}},
+		{"meta:client=acme", []*Instance{api}},
+		{"meta:Client", []*Instance{}},
+		{"tag:missing", []*Instance{}},
+	}
+	for _, tt := range tests {
+		if got := FilterByQuery(instances, tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FilterByQuery(%q) returned %d sessions, want %d", tt.query, len(got), len(tt.want))
+		}
+	}
+
+	if got := FilterByTagsAndMeta(instances, []string{"urgent"}, []string{"client=acme"}); len(got) != 1 || got[0] != api {
+		t.Errorf("FilterByTagsAndMeta() = %v, want api", got)
+	}
+}
+
+func TestStorageTagsAndMetaPersistence(t *testing.T) {
+	s := &Storage{
+		path:    filepath.Join(t.TempDir(), "sessions.json"),
+		profile: "_test",
+	}
+
+	original := &Instance{
+		ID:          "test-tags",
+		Title:       "api",
+		ProjectPath: "/tmp/api",
+		Tool:        "claude",
+		Status:      StatusIdle,
+		CreatedAt:   time.Now(),
+		Tags:        []string{"urgent", "backend"},
+		Meta:        map[string]string{"client": "acme"},
+	}
+	if err := s.SaveWithGroups([]*Instance{original}, nil); err != nil {
+		t.Fatalf("SaveWithGroups failed: %v", err)
+	}
+
+	loaded, _, err := s.LoadWithGroups()
+	if err != nil {
+		t.Fatalf("LoadWithGroups failed: %v", err)
+	}
+	if len(loaded) != 1 {
+		t.Fatalf("Expected 1 instance, got %d", len(loaded))
+	}
+	if !reflect.DeepEqual(loaded[0].Tags, original.Tags) || !reflect.DeepEqual(loaded[0].Meta, original.Meta) {
+		t.Errorf("tags/meta not persisted: got %v %v", loaded[0].Tags, loaded[0].Meta)
+	}
+}
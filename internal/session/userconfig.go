@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"log"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/asheshgoplani/agent-deck/internal/platform"
@@ -35,6 +37,10 @@ type UserConfig struct {
 	// These can be attached/detached per-project via the MCP Manager (M key)
 	MCPs map[string]MCPDef `toml:"mcps"`
 
+	// MCPBundles defines named sets of MCPs that are attached/detached together
+	// Example: web-research = ["exa", "firecrawl", "fetch"]
+	MCPBundles map[string][]string `toml:"mcp_bundles"`
+
 	// SSHHosts defines remote SSH hosts for managing sessions on remote machines
 	// Use the --host flag or TUI host selector to create sessions on these hosts
 	SSHHosts map[string]SSHHostDef `toml:"ssh_hosts"`
@@ -75,6 +81,15 @@ type UserConfig struct {
 	// Instances defines multiple instance behavior settings
 	Instances InstanceSettings `toml:"instances"`
 
+	// Storage selects where sessions are persisted
+	Storage StorageSettings `toml:"storage"`
+
+	// Archive defines how long deleted sessions are kept
+	Archive ArchiveSettings `toml:"archive"`
+
+	// Sync defines git-backed syncing of profiles between machines
+	Sync SyncSettings `toml:"sync"`
+
 	// Shell defines shell environment settings for sessions
 	Shell ShellSettings `toml:"shell"`
 
@@ -121,6 +136,10 @@ type LaunchConfig struct {
 	// If set, the MCPs from this file will be loaded instead of the project's .mcp.json
 	MCPConfigPath string `toml:"mcp_config"`
 
+	// MCPBundles are names of [mcp_bundles] entries to attach when launching
+	// Members are merged into the project's .mcp.json (Claude) or settings.json (Gemini)
+	MCPBundles []string `toml:"mcp_bundles"`
+
 	// ExtraArgs are additional CLI arguments to pass to the tool
 	ExtraArgs []string `toml:"extra_args"`
 
@@ -167,6 +186,16 @@ type MCPPoolSettings struct {
 
 	// SocketWaitTimeout is seconds to wait for socket to become ready (default: 5)
 	SocketWaitTimeout int `toml:"socket_wait_timeout"`
+
+	// HealthCheckTimeout is seconds to wait for a ping health probe (default: 5)
+	HealthCheckTimeout int `toml:"health_check_timeout"`
+
+	// QuarantineAfter is how many crashes within QuarantineWindow take an MCP
+	// out of the pool; sessions fall back to stdio (default: 3)
+	QuarantineAfter int `toml:"quarantine_after"`
+
+	// QuarantineWindow is the crash counting window in seconds (default: 300)
+	QuarantineWindow int `toml:"quarantine_window"`
 }
 
 // LogSettings defines log file management configuration
@@ -269,6 +298,64 @@ type InstanceSettings struct {
 	AllowMultiple bool `toml:"allow_multiple"`
 }
 
+// Storage backends for StorageSettings.Backend
+const (
+	StorageBackendJSON   = "json"
+	StorageBackendSQLite = "sqlite"
+)
+
+// StorageSettings defines the session storage backend
+type StorageSettings struct {
+	// Backend is "json" (default, sessions.json) or "sqlite" (sessions.db).
+	// Switching either way copies the sessions over on next start.
+	Backend string `toml:"backend"`
+}
+
+// ArchiveSettings defines how long deleted sessions are kept in the archive
+type ArchiveSettings struct {
+	// RetentionDays is how long archived sessions are kept before being
+	// purged automatically (default: 30, negative: keep forever)
+	RetentionDays int `toml:"retention_days"`
+}
+
+// SyncSettings defines git-backed syncing of profiles between machines
+// (agent-deck sync)
+type SyncSettings struct {
+	// Enabled turns on syncing in the TUI (pull on start, push after saves)
+	// and pushing after CLI commands that save sessions or groups
+	Enabled bool `toml:"enabled"`
+
+	// Remote is the git repository URL or path shared by all machines
+	Remote string `toml:"remote"`
+
+	// Branch is the branch to sync on (default: "main")
+	Branch string `toml:"branch"`
+
+	// IntervalMinutes is how often the TUI pulls when nothing changed
+	// locally (default: 5, negative: only on start and after saves)
+	IntervalMinutes int `toml:"interval_minutes"`
+
+	// SkipConfig leaves [mcps], [mcp_bundles] and [launch_configs] out of
+	// syncing. Literal MCP secrets are synced as env: refs either way.
+	SkipConfig bool `toml:"skip_config"`
+}
+
+// Interval returns the periodic pull interval (0 = disabled)
+func (s SyncSettings) Interval() time.Duration {
+	if s.IntervalMinutes < 0 {
+		return 0
+	}
+	return time.Duration(s.IntervalMinutes) * time.Minute
+}
+
+// Retention returns the retention period (0 = keep forever)
+func (s ArchiveSettings) Retention() time.Duration {
+	if s.RetentionDays < 0 {
+		return 0
+	}
+	return time.Duration(s.RetentionDays) * 24 * time.Hour
+}
+
 // GetShowAnalytics returns whether to show analytics, defaulting to false
 func (p *PreviewSettings) GetShowAnalytics() bool {
 	if p.ShowAnalytics == nil {
@@ -479,6 +566,8 @@ type MCPDef struct {
 	Args []string `toml:"args"`
 
 	// Env is optional environment variables
+	// Values may be secret references resolved at launch time:
+	// "env:VAR", "file:~/.secrets/x", or "cmd:pass show x"
 	Env map[string]string `toml:"env"`
 
 	// Description is optional help text shown in the MCP Manager
@@ -494,6 +583,7 @@ type MCPDef struct {
 
 	// Headers is optional HTTP headers for HTTP/SSE MCPs (e.g., for authentication)
 	// Example: { Authorization = "Bearer token123" }
+	// Supports the same secret references as Env; env: refs are written as ${VAR}
 	Headers map[string]string `toml:"headers"`
 }
 
@@ -502,6 +592,11 @@ type SSHHostDef struct {
 	// Host is the hostname or IP address
 	Host string `toml:"host"`
 
+	// SSHConfigAlias names a Host entry in ~/.ssh/config (Include and
+	// ProxyJump are honored). HostName, User, Port, IdentityFile and ProxyJump
+	// come from that entry; fields set here override it. Host may be omitted.
+	SSHConfigAlias string `toml:"ssh_config_alias"`
+
 	// User is the SSH username (optional, uses current user if empty)
 	User string `toml:"user"`
 
@@ -537,6 +632,25 @@ type SSHHostDef struct {
 	// Use this for non-standard installations (e.g., Homebrew on macOS: /opt/homebrew/bin/tmux)
 	// Default: "tmux" (uses PATH)
 	TmuxPath string `toml:"tmux_path"`
+
+	// ForwardMCPs lists pooled MCPs whose local Unix sockets are forwarded to
+	// this host over the SSH ControlMaster (ssh -R). Remote Claude sessions get
+	// .mcp.json entries pointing at the forwarded sockets, so they share the
+	// local pool instead of spawning MCP processes on the host.
+	// Requires [mcp_pool] and nc with -U support on the remote host.
+	ForwardMCPs []string `toml:"forward_mcps"`
+
+	// Transport selects how agent-deck talks to this host:
+	// "ssh" (default) runs the ssh binary with ControlMaster multiplexing;
+	// "native" uses the built-in Go SSH client (agent auth, identity files,
+	// jump_host chains, ~/.ssh/known_hosts), which ignores ~/.ssh/config
+	// unless ssh_config_alias is set
+	Transport string `toml:"transport"`
+
+	// AcceptNewHostKeys lets the native transport record the key of a host
+	// missing from known_hosts (StrictHostKeyChecking=accept-new). By default
+	// it refuses to connect until the host was verified with ssh once.
+	AcceptNewHostKeys bool `toml:"accept_new_host_keys"`
 }
 
 // GetGroupName returns the display name for the group.
@@ -1060,6 +1174,40 @@ func GetInstanceSettings() InstanceSettings {
 	return config.Instances
 }
 
+// GetStorageSettings returns the storage settings with defaults applied
+func GetStorageSettings() StorageSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || config.Storage.Backend != StorageBackendSQLite {
+		return StorageSettings{Backend: StorageBackendJSON}
+	}
+	return config.Storage
+}
+
+// GetArchiveSettings returns the archive settings with defaults applied
+func GetArchiveSettings() ArchiveSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil || config.Archive.RetentionDays == 0 {
+		return ArchiveSettings{RetentionDays: 30}
+	}
+	return config.Archive
+}
+
+// GetSyncSettings returns the sync settings with defaults applied
+func GetSyncSettings() SyncSettings {
+	config, err := LoadUserConfig()
+	if err != nil || config == nil {
+		return SyncSettings{Branch: "main", IntervalMinutes: 5}
+	}
+	settings := config.Sync
+	if settings.Branch == "" {
+		settings.Branch = "main"
+	}
+	if settings.IntervalMinutes == 0 {
+		settings.IntervalMinutes = 5
+	}
+	return settings
+}
+
 // GetProjectDiscoverySettings returns project discovery settings with defaults applied
 func GetProjectDiscoverySettings() ProjectDiscoverySettings {
 	config, err := LoadUserConfig()
@@ -1248,6 +1396,7 @@ func getMCPPoolConfigSection() string {
 # pool_all = true           # Pool all MCPs defined above
 # fallback_to_stdio = true  # Fall back to stdio if socket fails
 # exclude_mcps = []         # MCPs to exclude from pooling
+# quarantine_after = 3      # Crashes within quarantine_window (seconds) before falling back to stdio
 `
 	}
 
@@ -1335,6 +1484,34 @@ notify_in_cli = true
 # [instances]
 # allow_multiple = true  # Allow multiple TUI instances for the same profile
 
+# Session storage backend: "json" (sessions.json, default) or "sqlite"
+# (sessions.db in WAL mode, with row-level updates and a change journal).
+# Sessions are copied over automatically when you switch either way.
+# [storage]
+# backend = "sqlite"
+
+# Deleted sessions are kept in the profile's archive (agent-deck archive,
+# Shift+A in the TUI) and purged after this many days (negative = forever)
+# [archive]
+# retention_days = 30
+
+# Sync profiles between machines through a git repository (agent-deck sync).
+# The TUI pulls on start and every interval_minutes, and pushes after saves;
+# CLI commands that change sessions or groups push before they exit.
+# Sessions and groups are merged by ID and path; conflicting edits keep the
+# local value and are reported.
+# [sync]
+# enabled = true
+# remote = "git@github.com:me/agent-deck-sync.git"
+# branch = "main"
+# interval_minutes = 5
+# skip_config = false  # Don't sync [mcps], [mcp_bundles], [launch_configs]
+#
+# Synced [mcps] never carry literal secrets: an env or header value that looks
+# like an API key or token is committed as an env: ref (e.g. env:GITHUB_TOKEN)
+# and stays literal only in this file. Set those variables on the other
+# machines, or use skip_config = true to keep MCPs out of the sync repo.
+
 # ============================================================================
 # SSH Remote Hosts
 # ============================================================================
@@ -1354,6 +1531,9 @@ notify_in_cli = true
 # port = 22
 # auto_discover = true
 # description = "Dev server - sessions auto-discovered"
+# forward_mcps = ["exa", "github"]  # Share local pooled MCPs via forwarded sockets
+# transport = "native"  # Built-in Go SSH client instead of the ssh binary
+# accept_new_host_keys = false  # native: record unknown host keys instead of refusing
 
 # Example: macOS server with Homebrew tmux
 # [ssh_hosts.mac-mini]
@@ -1383,6 +1563,11 @@ notify_in_cli = true
 # auto_discover = true
 # description = "Internal server via bastion"
 
+# Example: Reuse an entry from ~/.ssh/config (or: agent-deck host import)
+# [ssh_hosts.gpu]
+# ssh_config_alias = "gpu-box"  # HostName, User, Port, IdentityFile, ProxyJump
+# auto_discover = true
+
 # Remote discovery settings (optional - defaults shown)
 # [remote_discovery]
 # enabled = true                    # Master switch for auto-discovery
@@ -1413,6 +1598,12 @@ default_tool = "claude"
 #   env         - Environment variables (optional)
 #   description - Help text shown in the MCP Manager (optional)
 #
+# Secret references keep API keys out of config.toml and generated .mcp.json:
+#   "env:VAR"             - read from an environment variable
+#   "file:~/.secrets/x"   - read from a file
+#   "cmd:pass show x"     - output of a command
+# Run 'agent-deck mcp doctor' to find plaintext secrets.
+#
 # HTTP/SSE MCPs (remote servers):
 #   url         - The endpoint URL (http:// or https://)
 #   transport   - "http" or "sse" (defaults to "http" if url is set)
@@ -1436,7 +1627,7 @@ default_tool = "claude"
 # [mcps.github]
 # command = "npx"
 # args = ["-y", "@modelcontextprotocol/server-github"]
-# env = { GITHUB_TOKEN = "ghp_your_token_here" }
+# env = { GITHUB_TOKEN = "cmd:gh auth token" }
 # description = "GitHub repository operations"
 
 # Example: Sequential Thinking MCP
@@ -1466,6 +1657,14 @@ default_tool = "claude"
 # transport = "sse"
 # description = "Remote SSE-based MCP"
 
+# ---------- Bundles ----------
+
+# Bundles are named sets of MCPs that attach/detach in one step
+# (agent-deck mcp attach <session> <bundle> --bundle, or 📦 items in the MCP Manager)
+# Launch configs can reference bundles with: mcp_bundles = ["web-research"]
+# [mcp_bundles]
+# web-research = ["exa", "firecrawl", "fetch"]
+
 # ============================================================================
 # Custom Tool Definitions
 # ============================================================================
@@ -1609,16 +1808,233 @@ func InitSSHPool() {
 	pool := sshpkg.DefaultPool()
 
 	for hostID, def := range hosts {
-		cfg := sshpkg.Config{
+		pool.Register(hostID, sshConfigFromDef(def, hosts))
+	}
+}
+
+// sshConfigFromDef converts a host definition to an SSH pool config.
+// For the native transport, jump_host entries naming other ssh_hosts are
+// resolved to their definitions (recursively, outermost hop first).
+// Hosts with ssh_config_alias on the ssh transport connect to the alias
+// so the ssh binary applies ~/.ssh/config itself.
+func sshConfigFromDef(def SSHHostDef, hosts map[string]SSHHostDef) sshpkg.Config {
+	eff := def
+	var sshCfg *sshpkg.SSHConfig
+	if def.SSHConfigAlias != "" {
+		if def.Transport != sshpkg.TransportNative && (def.Host == "" || def.Host == def.SSHConfigAlias) {
+			eff.Host = def.SSHConfigAlias
+		} else {
+			var err error
+			if eff, sshCfg, err = resolveSSHConfigAlias(def); err != nil {
+				log.Printf("[SSH] %v", err)
+				eff = def
+				if eff.Host == "" {
+					eff.Host = def.SSHConfigAlias
+				}
+			}
+		}
+	}
+
+	cfg := sshpkg.Config{
+		Host:         eff.Host,
+		User:         eff.User,
+		Port:         eff.Port,
+		IdentityFile: eff.IdentityFile,
+		JumpHost:     eff.JumpHost,
+		TmuxPath:     def.TmuxPath,
+		Transport:    def.Transport,
+
+		AcceptNewHostKeys: def.AcceptNewHostKeys,
+	}
+	if def.Transport == sshpkg.TransportNative && eff.JumpHost != "" {
+		if def.JumpHost == "" && sshCfg != nil {
+			// ProxyJump from ~/.ssh/config: hops are ssh_config aliases
+			cfg.JumpHosts = sshCfg.JumpHosts(eff.JumpHost)
+		} else {
+			cfg.JumpHosts = resolveJumpHosts(eff.JumpHost, hosts, map[string]bool{})
+		}
+	}
+	return cfg
+}
+
+// ResolveSSHHostDef returns def with the settings it inherits from its
+// ssh_config_alias filled in, i.e. what agent-deck will actually connect with.
+// Definitions without an alias are returned unchanged.
+func ResolveSSHHostDef(def SSHHostDef) (SSHHostDef, error) {
+	eff, _, err := resolveSSHConfigAlias(def)
+	return eff, err
+}
+
+// resolveSSHConfigAlias fills empty connection fields of def from its
+// ~/.ssh/config entry. Explicit fields win over the ssh config.
+func resolveSSHConfigAlias(def SSHHostDef) (SSHHostDef, *sshpkg.SSHConfig, error) {
+	if def.SSHConfigAlias == "" {
+		return def, nil, nil
+	}
+	sshCfg, err := sshpkg.LoadSSHConfig("")
+	if err != nil {
+		return def, nil, fmt.Errorf("failed to read ssh config: %w", err)
+	}
+	if !sshCfg.HasAlias(def.SSHConfigAlias) {
+		return def, sshCfg, fmt.Errorf("ssh_config_alias %q not found in %s", def.SSHConfigAlias, sshCfg.Path)
+	}
+
+	resolved := sshCfg.Resolve(def.SSHConfigAlias)
+	eff := def
+	if eff.Host == "" || eff.Host == def.SSHConfigAlias {
+		eff.Host = resolved.HostName
+	}
+	if eff.User == "" {
+		eff.User = resolved.User
+	}
+	if eff.Port == 0 {
+		eff.Port = resolved.Port
+	}
+	if eff.IdentityFile == "" {
+		eff.IdentityFile = resolved.IdentityFile
+	}
+	if eff.JumpHost == "" {
+		eff.JumpHost = resolved.ProxyJump
+	}
+	return eff, sshCfg, nil
+}
+
+// ValidateSSHHostDef checks that a host definition can be connected to:
+// it needs a host or an ssh_config_alias that exists in ~/.ssh/config.
+// Returns an error message if invalid, empty string if valid.
+func ValidateSSHHostDef(def SSHHostDef) string {
+	if def.SSHConfigAlias != "" {
+		if _, err := ResolveSSHHostDef(def); err != nil {
+			return err.Error()
+		}
+		return ""
+	}
+	if def.Host == "" {
+		return "Host/IP address or ssh_config_alias is required"
+	}
+	return ""
+}
+
+// SSHConfigImport is a ~/.ssh/config entry offered by ImportSSHConfigHosts
+type SSHConfigImport struct {
+	HostID   string               `json:"host_id"`
+	Resolved sshpkg.SSHConfigHost `json:"resolved"`
+	// Exists is true when an [ssh_hosts.X] entry with this ID is already configured
+	Exists bool `json:"exists"`
+}
+
+// ScanSSHConfigHosts lists the literal Host aliases in an ssh config file
+// (empty path = ~/.ssh/config) with their effective settings.
+// Aliases that aren't valid host IDs get a sanitized ID.
+func ScanSSHConfigHosts(configPath string) ([]SSHConfigImport, error) {
+	sshCfg, err := sshpkg.LoadSSHConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	existing := GetAvailableSSHHosts()
+	var result []SSHConfigImport
+	for _, alias := range sshCfg.Aliases() {
+		hostID := sanitizeSSHHostID(alias)
+		if hostID == "" {
+			continue
+		}
+		_, exists := existing[hostID]
+		result = append(result, SSHConfigImport{
+			HostID:   hostID,
+			Resolved: sshCfg.Resolve(alias),
+			Exists:   exists,
+		})
+	}
+	return result, nil
+}
+
+// sanitizeSSHHostID maps an ssh alias to a valid [ssh_hosts.X] key
+func sanitizeSSHHostID(alias string) string {
+	var b strings.Builder
+	for _, r := range alias {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
+
+// ImportSSHConfigHosts adds [ssh_hosts.X] entries that reference the given
+// ssh config aliases via ssh_config_alias. Existing entries are kept unless
+// overwrite is set. Returns the host IDs written.
+func ImportSSHConfigHosts(imports []SSHConfigImport, autoDiscover, overwrite bool) ([]string, error) {
+	config, err := LoadUserConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	configCopy := *config
+	configCopy.SSHHosts = make(map[string]SSHHostDef, len(config.SSHHosts)+len(imports))
+	for k, v := range config.SSHHosts {
+		configCopy.SSHHosts[k] = v
+	}
+
+	var written []string
+	for _, imp := range imports {
+		if _, exists := configCopy.SSHHosts[imp.HostID]; exists && !overwrite {
+			continue
+		}
+		def := configCopy.SSHHosts[imp.HostID]
+		def.SSHConfigAlias = imp.Resolved.Alias
+		def.Host = ""
+		def.User = ""
+		def.Port = 0
+		def.IdentityFile = ""
+		def.JumpHost = ""
+		if autoDiscover {
+			def.AutoDiscover = true
+			configCopy.RemoteDiscovery.Enabled = true
+		}
+		configCopy.SSHHosts[imp.HostID] = def
+		written = append(written, imp.HostID)
+	}
+	if len(written) == 0 {
+		return nil, nil
+	}
+
+	if err := SaveUserConfig(&configCopy); err != nil {
+		return nil, fmt.Errorf("failed to save config: %w", err)
+	}
+
+	pool := sshpkg.DefaultPool()
+	for _, hostID := range written {
+		pool.Register(hostID, sshConfigFromDef(configCopy.SSHHosts[hostID], configCopy.SSHHosts))
+	}
+	return written, nil
+}
+
+// resolveJumpHosts expands a comma-separated jump_host list; entries that
+// aren't ssh_hosts names are parsed as [user@]host[:port]
+func resolveJumpHosts(spec string, hosts map[string]SSHHostDef, visiting map[string]bool) []sshpkg.Config {
+	var chain []sshpkg.Config
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		def, ok := hosts[part]
+		if !ok || visiting[part] {
+			chain = append(chain, sshpkg.ParseJumpHosts(part)...)
+			continue
+		}
+		visiting[part] = true
+		if def.JumpHost != "" {
+			chain = append(chain, resolveJumpHosts(def.JumpHost, hosts, visiting)...)
+		}
+		chain = append(chain, sshpkg.Config{
 			Host:         def.Host,
 			User:         def.User,
 			Port:         def.Port,
 			IdentityFile: def.IdentityFile,
-			JumpHost:     def.JumpHost,
-			TmuxPath:     def.TmuxPath,
-		}
-		pool.Register(hostID, cfg)
+		})
+		delete(visiting, part)
 	}
+	return chain
 }
 
 // SetSSHHost creates or updates an SSH host configuration in config.toml.
@@ -1647,14 +2063,7 @@ func SetSSHHost(hostID string, def SSHHostDef) error {
 
 	// Register with SSH pool
 	pool := sshpkg.DefaultPool()
-	pool.Register(hostID, sshpkg.Config{
-		Host:         def.Host,
-		User:         def.User,
-		Port:         def.Port,
-		IdentityFile: def.IdentityFile,
-		JumpHost:     def.JumpHost,
-		TmuxPath:     def.TmuxPath,
-	})
+	pool.Register(hostID, sshConfigFromDef(def, configCopy.SSHHosts))
 
 	return nil
 }
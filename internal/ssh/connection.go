@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -10,11 +11,15 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/creack/pty"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 // Connection represents an SSH connection to a remote host.
-// It wraps the native ssh command for maximum compatibility with
-// user's SSH config, agent forwarding, and jump hosts.
+// By default it wraps the ssh command for maximum compatibility with
+// user's SSH config, agent forwarding, and jump hosts. With the native
+// transport it uses a built-in Go client instead (see native.go).
 type Connection struct {
 	// Configuration
 	Host         string // hostname or IP
@@ -28,6 +33,15 @@ type Connection struct {
 	connected bool
 	lastError error
 	lastCheck time.Time
+
+	// Remote Unix socket forwards (remote path -> local path) and the
+	// ControlMaster they were added to (forwards die with the master)
+	forwardsMu     sync.Mutex
+	socketForwards map[string]string
+	forwardMaster  string
+
+	// native is the built-in Go client when Transport is "native" (nil: ssh binary)
+	native *nativeTransport
 }
 
 // Config holds SSH connection configuration
@@ -38,6 +52,17 @@ type Config struct {
 	IdentityFile string
 	JumpHost     string // Reference to another host definition
 	TmuxPath     string // Path to tmux binary on remote (default: "tmux")
+
+	// Transport selects TransportSSH (default) or TransportNative
+	Transport string
+	// JumpHosts is the resolved JumpHost chain (outermost first) for the
+	// native transport; if empty, JumpHost is parsed as "[user@]host[:port],..."
+	JumpHosts []Config
+	// KnownHostsFile is used by the native transport (default: ~/.ssh/known_hosts)
+	KnownHostsFile string
+	// AcceptNewHostKeys lets the native transport record unknown host keys
+	// (StrictHostKeyChecking=accept-new) instead of refusing to connect
+	AcceptNewHostKeys bool
 }
 
 // NewConnection creates a new SSH connection with the given configuration
@@ -47,13 +72,23 @@ func NewConnection(cfg Config) *Connection {
 		port = 22
 	}
 
-	return &Connection{
+	conn := &Connection{
 		Host:         cfg.Host,
 		User:         cfg.User,
 		Port:         port,
 		IdentityFile: expandPath(cfg.IdentityFile),
 		JumpHost:     cfg.JumpHost,
 	}
+	if cfg.Transport == TransportNative {
+		cfg.Port = port
+		conn.native = newNativeTransport(cfg)
+	}
+	return conn
+}
+
+// IsNative returns true if this connection uses the built-in Go SSH client
+func (c *Connection) IsNative() bool {
+	return c.native != nil
 }
 
 // expandPath expands ~ to home directory
@@ -71,6 +106,11 @@ func expandPath(path string) string {
 	return path
 }
 
+// ShellQuote quotes s for use as a single word in a remote POSIX shell command
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // controlSocketPath returns the path to the SSH ControlMaster socket for this connection
 func (c *Connection) controlSocketPath() string {
 	return filepath.Join(os.TempDir(), fmt.Sprintf("agentdeck-ssh-%s-%d-%s", c.Host, c.Port, c.User))
@@ -121,6 +161,17 @@ func (c *Connection) buildSSHArgs() []string {
 	return args
 }
 
+// controlArgs builds arguments for a ControlMaster control command
+// (ssh -O <op>), which acts on the existing master connection
+func (c *Connection) controlArgs(op string, extra ...string) []string {
+	args := []string{"-O", op, "-o", fmt.Sprintf("ControlPath=%s", c.controlSocketPath())}
+	if c.Port != 22 {
+		args = append(args, "-p", fmt.Sprintf("%d", c.Port))
+	}
+	args = append(args, extra...)
+	return append(args, c.Target())
+}
+
 // TestConnection tests if the SSH connection can be established
 func (c *Connection) TestConnection() error {
 	c.mu.Lock()
@@ -131,16 +182,26 @@ func (c *Connection) TestConnection() error {
 		return nil
 	}
 
-	args := c.buildSSHArgs()
-	args = append(args, "echo", "ok")
-
-	cmd := exec.Command("ssh", args...)
-	output, err := cmd.CombinedOutput()
+	var output []byte
+	var err error
+	if c.native != nil {
+		var out string
+		out, err = c.native.run("echo ok", nil)
+		output = []byte(out)
+	} else {
+		args := c.buildSSHArgs()
+		args = append(args, "echo", "ok")
+		output, err = exec.Command("ssh", args...).CombinedOutput()
+	}
 
 	c.lastCheck = time.Now()
 	if err != nil {
 		c.connected = false
-		c.lastError = fmt.Errorf("SSH connection failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
+		if c.native != nil {
+			c.lastError = fmt.Errorf("SSH connection failed: %w", err)
+		} else {
+			c.lastError = fmt.Errorf("SSH connection failed: %w (output: %s)", err, strings.TrimSpace(string(output)))
+		}
 		return c.lastError
 	}
 
@@ -180,11 +241,17 @@ func (c *Connection) GetStatusSnapshot() (connected bool, lastCheck time.Time, l
 
 // RunCommand executes a command on the remote host and returns the output
 // Commands are wrapped with PATH setup to find Homebrew tools on macOS
+// and agent-deck in ~/.local/bin
 func (c *Connection) RunCommand(command string) (string, error) {
-	args := c.buildSSHArgs()
-	// Prepend common Homebrew paths to find tmux etc. on macOS
+	// Prepend common Homebrew paths to find tmux etc. on macOS, and
+	// ~/.local/bin where install.sh and 'host doctor --install' put agent-deck.
 	// Works regardless of whether user's shell is bash or zsh
-	wrappedCmd := fmt.Sprintf("PATH=/opt/homebrew/bin:/usr/local/bin:$PATH %s", command)
+	wrappedCmd := fmt.Sprintf("PATH=/opt/homebrew/bin:/usr/local/bin:$HOME/.local/bin:$PATH %s", command)
+	if c.native != nil {
+		return c.native.run(wrappedCmd, nil)
+	}
+
+	args := c.buildSSHArgs()
 	args = append(args, wrappedCmd)
 
 	cmd := exec.Command("ssh", args...)
@@ -202,6 +269,10 @@ func (c *Connection) RunCommand(command string) (string, error) {
 
 // RunCommandWithStdin executes a command with stdin input
 func (c *Connection) RunCommandWithStdin(command string, stdin io.Reader) (string, error) {
+	if c.native != nil {
+		return c.native.run(command, stdin)
+	}
+
 	args := c.buildSSHArgs()
 	args = append(args, command)
 
@@ -219,9 +290,116 @@ func (c *Connection) RunCommandWithStdin(command string, stdin io.Reader) (strin
 	return string(output), nil
 }
 
+// Stream is a running remote command with piped stdin/stdout
+type Stream struct {
+	Stdin  io.WriteCloser
+	Stdout io.Reader
+	wait   func() error
+	kill   func() error
+}
+
+// Wait waits for the remote command to exit
+func (s *Stream) Wait() error { return s.wait() }
+
+// Kill terminates the remote command
+func (s *Stream) Kill() error { return s.kill() }
+
+// StartStream starts command on the remote host without a PTY, for
+// long-lived processes that talk over stdin/stdout. stderr is discarded.
+// PATH also includes ~/.local/bin, where install.sh puts agent-deck.
+func (c *Connection) StartStream(command string) (*Stream, error) {
+	wrappedCmd := fmt.Sprintf("PATH=/opt/homebrew/bin:/usr/local/bin:$HOME/.local/bin:$PATH %s", command)
+	if c.native != nil {
+		return c.native.startStream(wrappedCmd)
+	}
+
+	args := c.buildSSHArgs()
+	args = append(args[:len(args)-1], "-T", args[len(args)-1])
+	args = append(args, wrappedCmd)
+	cmd := exec.Command("ssh", args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start ssh: %w", err)
+	}
+	return &Stream{
+		Stdin:  stdin,
+		Stdout: stdout,
+		wait:   cmd.Wait,
+		kill:   func() error { return cmd.Process.Kill() },
+	}, nil
+}
+
+// InteractiveSession is a remote command attached to a PTY: reads return
+// terminal output, writes send keystrokes
+type InteractiveSession interface {
+	io.ReadWriteCloser
+	// Resize updates the remote terminal size
+	Resize(cols, rows int) error
+	// Wait waits for the remote command to exit (see ExitCode)
+	Wait() error
+}
+
+// execInteractive runs the ssh binary on a local PTY
+type execInteractive struct {
+	cmd  *exec.Cmd
+	ptmx *os.File
+}
+
+func (s *execInteractive) Read(p []byte) (int, error)  { return s.ptmx.Read(p) }
+func (s *execInteractive) Write(p []byte) (int, error) { return s.ptmx.Write(p) }
+func (s *execInteractive) Close() error                { return s.ptmx.Close() }
+func (s *execInteractive) Wait() error                 { return s.cmd.Wait() }
+
+func (s *execInteractive) Resize(cols, rows int) error {
+	return pty.Setsize(s.ptmx, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+}
+
+// OpenInteractive runs command on the remote host with a PTY of the given size
+func (c *Connection) OpenInteractive(command string, cols, rows int) (InteractiveSession, error) {
+	if c.native != nil {
+		wrappedCmd := fmt.Sprintf("PATH=/opt/homebrew/bin:/usr/local/bin:$PATH %s", command)
+		return c.native.openInteractive(wrappedCmd, cols, rows)
+	}
+
+	cmd, err := c.StartInteractiveSession(command)
+	if err != nil {
+		return nil, err
+	}
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to start pty: %w", err)
+	}
+	return &execInteractive{cmd: cmd, ptmx: ptmx}, nil
+}
+
+// ExitCode extracts the remote exit status from an error returned by
+// InteractiveSession.Wait or Stream.Wait
+func ExitCode(err error) (int, bool) {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	var sshExitErr *gossh.ExitError
+	if errors.As(err, &sshExitErr) {
+		return sshExitErr.ExitStatus(), true
+	}
+	return 0, false
+}
+
 // StartInteractiveSession starts an interactive SSH session with PTY
-// Returns the started command - caller must handle Wait()
+// Returns the started command - caller must handle Wait(). The native
+// transport has no ssh process to run; use OpenInteractive for it.
 func (c *Connection) StartInteractiveSession(command string) (*exec.Cmd, error) {
+	if c.native != nil {
+		return nil, fmt.Errorf("%s uses the native transport: use OpenInteractive", c.Host)
+	}
 	args := c.buildSSHArgs()
 
 	// Request PTY for interactive session
@@ -259,6 +437,11 @@ func (c *Connection) HostIdentifier() string {
 // CloseControlMaster terminates the SSH ControlMaster connection if active
 // This should be called when the connection is no longer needed to clean up resources
 func (c *Connection) CloseControlMaster() error {
+	if c.native != nil {
+		c.native.close()
+		return nil
+	}
+
 	controlPath := c.controlSocketPath()
 
 	// Check if socket exists
@@ -283,18 +466,154 @@ func (c *Connection) CloseControlMaster() error {
 	return nil
 }
 
-// ForwardPort sets up local port forwarding (for future MCP tunneling)
-func (c *Connection) ForwardPort(localPort, remotePort int, remoteHost string) (*exec.Cmd, error) {
+// masterID returns an identifier of the running ControlMaster process
+// ("Master running (pid=1234)"), or "" if no master is running
+func (c *Connection) masterID() string {
+	if c.native != nil {
+		return c.native.generation()
+	}
+	output, err := exec.Command("ssh", c.controlArgs("check")...).CombinedOutput()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// ForwardRemoteSocket exposes a local Unix socket on the remote host at
+// remotePath (ssh -R streamlocal forwarding) over the ControlMaster connection.
+// Calling it again for an active forward is a no-op; if the master was
+// restarted since, the forward is re-added.
+func (c *Connection) ForwardRemoteSocket(remotePath, localPath string) error {
+	// Ensures the ControlMaster is up (ControlMaster=auto)
+	if err := c.TestConnection(); err != nil {
+		return err
+	}
+
+	c.forwardsMu.Lock()
+	defer c.forwardsMu.Unlock()
+
+	master := c.masterID()
+	if master == "" {
+		return fmt.Errorf("no SSH ControlMaster running for %s", c.Target())
+	}
+	if master != c.forwardMaster {
+		// New master connection: previously added forwards are gone
+		c.socketForwards = make(map[string]string)
+		c.forwardMaster = master
+	}
+	if c.socketForwards[remotePath] == localPath {
+		return nil
+	}
+
+	// sshd refuses to bind over an existing file unless StreamLocalBindUnlink
+	// is set server-side, so remove any stale socket first
+	if _, err := c.RunCommand("rm -f " + ShellQuote(remotePath)); err != nil {
+		return fmt.Errorf("failed to remove stale remote socket: %w", err)
+	}
+
+	if c.native != nil {
+		if err := c.native.forwardRemoteSocket(remotePath, localPath); err != nil {
+			return err
+		}
+		c.socketForwards[remotePath] = localPath
+		return nil
+	}
+
+	spec := fmt.Sprintf("%s:%s", remotePath, localPath)
+	output, err := exec.Command("ssh", c.controlArgs("forward", "-R", spec)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("socket forward %s failed: %w (output: %s)", spec, err, strings.TrimSpace(string(output)))
+	}
+	c.socketForwards[remotePath] = localPath
+	return nil
+}
+
+// CancelRemoteSocketForward removes a forward added by ForwardRemoteSocket
+func (c *Connection) CancelRemoteSocketForward(remotePath string) error {
+	c.forwardsMu.Lock()
+	defer c.forwardsMu.Unlock()
+
+	localPath, ok := c.socketForwards[remotePath]
+	if !ok {
+		return nil
+	}
+	delete(c.socketForwards, remotePath)
+
+	if c.native != nil {
+		return c.native.cancelRemoteSocket(remotePath)
+	}
+
+	spec := fmt.Sprintf("%s:%s", remotePath, localPath)
+	output, err := exec.Command("ssh", c.controlArgs("cancel", "-R", spec)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("cancel forward %s failed: %w (output: %s)", spec, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RemoteSocketForwards returns the active socket forwards (remote path -> local path)
+func (c *Connection) RemoteSocketForwards() map[string]string {
+	c.forwardsMu.Lock()
+	defer c.forwardsMu.Unlock()
+	forwards := make(map[string]string, len(c.socketForwards))
+	for remote, local := range c.socketForwards {
+		forwards[remote] = local
+	}
+	return forwards
+}
+
+// PortForward is an active local port forward; Close stops it
+type PortForward struct {
+	LocalPort  int
+	RemotePort int
+	RemoteHost string
+	closeFn    func() error
+	done       <-chan struct{}
+}
+
+// Close stops forwarding
+func (f *PortForward) Close() error {
+	return f.closeFn()
+}
+
+// Done is closed once the forward stops, either by Close or because the
+// ssh process exited (network drop, remote sshd restart)
+func (f *PortForward) Done() <-chan struct{} {
+	return f.done
+}
+
+// ForwardPort sets up local port forwarding (ssh -L localPort:remoteHost:remotePort)
+func (c *Connection) ForwardPort(localPort, remotePort int, remoteHost string) (*PortForward, error) {
 	if remoteHost == "" {
 		remoteHost = "localhost"
 	}
+	forward := &PortForward{LocalPort: localPort, RemotePort: remotePort, RemoteHost: remoteHost}
+
+	if c.native != nil {
+		l, done, err := c.native.forwardPort(localPort, net.JoinHostPort(remoteHost, fmt.Sprintf("%d", remotePort)))
+		if err != nil {
+			return nil, err
+		}
+		forward.closeFn, forward.done = l.Close, done
+		return forward, nil
+	}
+
+	// Refuse a port something else listens on: the check below would
+	// reach that service instead of the forward
+	l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", localPort))
+	if err != nil {
+		return nil, fmt.Errorf("local port %d is already in use: %w", localPort, err)
+	}
+	_ = l.Close()
 
 	args := c.buildSSHArgs()
 	// Remove the target (last arg) temporarily
 	target := args[len(args)-1]
 	args = args[:len(args)-1]
 
-	// Add port forwarding
+	// Add port forwarding; exit instead of running without it if the local
+	// port can't be bound
+	args = append(args, "-o", "ExitOnForwardFailure=yes")
 	args = append(args, "-L", fmt.Sprintf("%d:%s:%d", localPort, remoteHost, remotePort))
 	// Add -N to not execute a remote command
 	args = append(args, "-N")
@@ -305,17 +624,38 @@ func (c *Connection) ForwardPort(localPort, remotePort int, remoteHost string) (
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start port forward: %w", err)
 	}
+	var waitErr error
+	exited := make(chan struct{})
+	go func() {
+		waitErr = cmd.Wait()
+		close(exited)
+	}()
 
 	// Wait briefly to ensure connection is established
 	time.Sleep(100 * time.Millisecond)
 
-	// Test if the local port is listening
+	// Test if the local port is listening, and that it's ssh listening
+	select {
+	case <-exited:
+		return nil, fmt.Errorf("port forward failed to establish: ssh exited: %v", waitErr)
+	default:
+	}
 	conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", localPort), 2*time.Second)
 	if err != nil {
 		_ = cmd.Process.Kill()
+		<-exited
 		return nil, fmt.Errorf("port forward failed to establish: %w", err)
 	}
 	_ = conn.Close()
 
-	return cmd, nil
+	var closeOnce sync.Once
+	forward.done = exited
+	forward.closeFn = func() error {
+		closeOnce.Do(func() {
+			_ = cmd.Process.Kill()
+			<-exited
+		})
+		return nil
+	}
+	return forward, nil
 }
@@ -164,3 +164,36 @@ func TestBuildSSHArgs_ControlPathContainsHostInfo(t *testing.T) {
 		t.Errorf("ControlPath should contain user, got %q", controlPath)
 	}
 }
+
+func TestControlArgs_Forward(t *testing.T) {
+	conn := NewConnection(Config{Host: "example.com", User: "deploy", Port: 2222})
+	args := conn.controlArgs("forward", "-R", "/tmp/remote.sock:/tmp/local.sock")
+
+	joined := strings.Join(args, " ")
+	if !strings.HasPrefix(joined, "-O forward -o ControlPath=") {
+		t.Errorf("expected control command prefix, got %q", joined)
+	}
+	if !strings.Contains(joined, "ControlPath="+conn.controlSocketPath()) {
+		t.Errorf("control command must use the connection's ControlPath, got %q", joined)
+	}
+	if !strings.Contains(joined, "-R /tmp/remote.sock:/tmp/local.sock") {
+		t.Errorf("missing -R streamlocal spec in %q", joined)
+	}
+	if args[len(args)-1] != "deploy@example.com" {
+		t.Errorf("expected target last, got %q", args[len(args)-1])
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"/tmp/a.sock": "'/tmp/a.sock'",
+		"my project":  "'my project'",
+		"it's":        `'it'\''s'`,
+		"$(rm -rf ~)": "'$(rm -rf ~)'",
+	}
+	for in, want := range tests {
+		if got := ShellQuote(in); got != want {
+			t.Errorf("ShellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,592 @@
+package ssh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// Transport names for Config.Transport
+const (
+	// TransportSSH runs the system ssh binary with ControlMaster multiplexing (default)
+	TransportSSH = "ssh"
+	// TransportNative uses the built-in Go SSH client (golang.org/x/crypto/ssh)
+	TransportNative = "native"
+)
+
+const (
+	// nativeKeepaliveInterval is how often keepalive@openssh.com is sent
+	nativeKeepaliveInterval = 30 * time.Second
+	// nativeKeepaliveTimeout closes the connection if a keepalive isn't answered
+	nativeKeepaliveTimeout = 15 * time.Second
+	// nativeDialTimeout matches ConnectTimeout=10 of the ssh binary transport
+	nativeDialTimeout = 10 * time.Second
+)
+
+// defaultIdentityFiles are tried (like ssh does) when no identity_file is set
+var defaultIdentityFiles = []string{"~/.ssh/id_ed25519", "~/.ssh/id_ecdsa", "~/.ssh/id_rsa"}
+
+// nativeTransport holds the Go SSH client for a Connection using TransportNative.
+// The client (and its jump host clients) is dialed lazily and redialed after
+// it drops; keepalives detect dead connections.
+type nativeTransport struct {
+	cfg Config
+
+	mu      sync.Mutex
+	client  *gossh.Client
+	closers []io.Closer // jump host clients, innermost last
+	gen     int         // incremented on every dial (forwards die with the client)
+
+	// Remote Unix socket listeners (remote path -> listener)
+	listeners map[string]net.Listener
+}
+
+func newNativeTransport(cfg Config) *nativeTransport {
+	return &nativeTransport{cfg: cfg, listeners: make(map[string]net.Listener)}
+}
+
+// hopAddr returns host:port for a hop config
+func hopAddr(cfg Config) string {
+	port := cfg.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+}
+
+// ParseJumpHosts parses an ssh -J style list ("[user@]host[:port],...")
+func ParseJumpHosts(spec string) []Config {
+	var hops []Config
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var hop Config
+		if at := strings.LastIndex(part, "@"); at >= 0 {
+			hop.User = part[:at]
+			part = part[at+1:]
+		}
+		if host, port, err := net.SplitHostPort(part); err == nil {
+			hop.Host = host
+			hop.Port, _ = strconv.Atoi(port)
+		} else {
+			hop.Host = part
+		}
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// get returns a connected client, dialing if needed
+func (n *nativeTransport) get() (*gossh.Client, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.client != nil {
+		return n.client, nil
+	}
+
+	hops := n.cfg.JumpHosts
+	if len(hops) == 0 && n.cfg.JumpHost != "" {
+		hops = ParseJumpHosts(n.cfg.JumpHost)
+	}
+	hops = append(append([]Config{}, hops...), n.cfg)
+
+	// The agent is only needed for the handshakes below
+	var keys agent.ExtendedAgent
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			defer conn.Close()
+			keys = agent.NewClient(conn)
+		}
+	}
+
+	var (
+		client  *gossh.Client
+		closers []io.Closer
+	)
+	for _, hop := range hops {
+		next, err := dialHop(client, hop, n.cfg, keys)
+		if err != nil {
+			for i := len(closers) - 1; i >= 0; i-- {
+				_ = closers[i].Close()
+			}
+			return nil, err
+		}
+		if client != nil {
+			closers = append(closers, client)
+		}
+		client = next
+	}
+
+	n.client = client
+	n.closers = closers
+	n.gen++
+	go n.keepalive(client)
+	return client, nil
+}
+
+// dialHop connects to hop directly (via == nil) or through an existing
+// client. Host key settings come from cfg, the target host's config.
+func dialHop(via *gossh.Client, hop Config, cfg Config, keys agent.ExtendedAgent) (*gossh.Client, error) {
+	addr := hopAddr(hop)
+	clientConfig, err := nativeClientConfig(hop, addr, cfg, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	if via == nil {
+		conn, err = net.DialTimeout("tcp", addr, nativeDialTimeout)
+	} else {
+		conn, err = via.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ssh: connect to %s: %w", addr, err)
+	}
+
+	// Bound the handshake like ConnectTimeout does
+	_ = conn.SetDeadline(time.Now().Add(nativeDialTimeout))
+	c, chans, reqs, err := gossh.NewClientConn(conn, addr, clientConfig)
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("ssh: handshake with %s as %s: %w", addr, clientConfig.User, err)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return gossh.NewClient(c, chans, reqs), nil
+}
+
+// nativeClientConfig builds auth and host key verification for one hop
+func nativeClientConfig(hop Config, addr string, cfg Config, keys agent.ExtendedAgent) (*gossh.ClientConfig, error) {
+	username := hop.User
+	if username == "" {
+		if u, err := user.Current(); err == nil {
+			username = u.Username
+		}
+	}
+
+	hostKeyCallback, algorithms, err := knownHostsCallback(cfg.KnownHostsFile, addr, cfg.AcceptNewHostKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gossh.ClientConfig{
+		User:              username,
+		Auth:              nativeAuthMethods(hop.IdentityFile, keys),
+		HostKeyCallback:   hostKeyCallback,
+		HostKeyAlgorithms: algorithms,
+		Timeout:           nativeDialTimeout,
+	}, nil
+}
+
+// nativeAuthMethods offers agent keys (if keys != nil) first, then identity
+// files (the configured one, or ssh's defaults). Passphrase-protected files
+// are skipped; load those into ssh-agent instead.
+func nativeAuthMethods(identityFile string, keys agent.ExtendedAgent) []gossh.AuthMethod {
+	var methods []gossh.AuthMethod
+
+	if keys != nil {
+		methods = append(methods, gossh.PublicKeysCallback(keys.Signers))
+	}
+
+	files := defaultIdentityFiles
+	if identityFile != "" {
+		files = []string{identityFile}
+	}
+	var signers []gossh.Signer
+	for _, file := range files {
+		data, err := os.ReadFile(expandPath(file))
+		if err != nil {
+			continue
+		}
+		signer, err := gossh.ParsePrivateKey(data)
+		if err != nil {
+			var passErr *gossh.PassphraseMissingError
+			if errors.As(err, &passErr) {
+				log.Printf("[SSH] %s is passphrase-protected; add it to ssh-agent for the native transport", file)
+			}
+			continue
+		}
+		signers = append(signers, signer)
+	}
+	if len(signers) > 0 {
+		methods = append(methods, gossh.PublicKeys(signers...))
+	}
+	return methods
+}
+
+// knownHostsCallback verifies host keys against known_hosts. Unknown hosts
+// are rejected (StrictHostKeyChecking=yes) unless acceptNew is set, in which
+// case they're added (accept-new); changed keys are always rejected. Also
+// returns the host key algorithms matching recorded keys, so the server
+// doesn't negotiate a type we can't verify.
+func knownHostsCallback(knownHostsFile, addr string, acceptNew bool) (gossh.HostKeyCallback, []string, error) {
+	if knownHostsFile == "" {
+		knownHostsFile = "~/.ssh/known_hosts"
+	}
+	knownHostsFile = expandPath(knownHostsFile)
+	if err := os.MkdirAll(filepath.Dir(knownHostsFile), 0700); err != nil {
+		return nil, nil, err
+	}
+	f, err := os.OpenFile(knownHostsFile, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ssh: open known_hosts: %w", err)
+	}
+	_ = f.Close()
+
+	verify, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ssh: read %s: %w", knownHostsFile, err)
+	}
+
+	callback := func(hostname string, remote net.Addr, key gossh.PublicKey) error {
+		err := verify(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			return fmt.Errorf("ssh: host key for %s changed (%s %s); if expected, remove the old entry from %s",
+				hostname, key.Type(), gossh.FingerprintSHA256(key), knownHostsFile)
+		}
+		if !acceptNew {
+			return fmt.Errorf("ssh: unknown host %s (%s %s); run `ssh %s` once to verify and record its key in %s, or set accept_new_host_keys = true for this host",
+				hostname, key.Type(), gossh.FingerprintSHA256(key), hostname, knownHostsFile)
+		}
+		line := knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+		kh, err := os.OpenFile(knownHostsFile, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("ssh: add %s to known_hosts: %w", hostname, err)
+		}
+		defer kh.Close()
+		if _, err := kh.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("ssh: add %s to known_hosts: %w", hostname, err)
+		}
+		log.Printf("[SSH] Added %s (%s) to %s", hostname, gossh.FingerprintSHA256(key), knownHostsFile)
+		return nil
+	}
+
+	return callback, knownHostAlgorithms(verify, addr), nil
+}
+
+// knownHostAlgorithms returns the host key algorithms for keys recorded for
+// addr, found by verifying a throwaway key and reading the expected keys
+func knownHostAlgorithms(verify gossh.HostKeyCallback, addr string) []string {
+	probe, err := gossh.NewPublicKey(ed25519.PublicKey(make([]byte, ed25519.PublicKeySize)))
+	if err != nil {
+		return nil
+	}
+	var keyErr *knownhosts.KeyError
+	if err := verify(addr, &net.TCPAddr{}, probe); !errors.As(err, &keyErr) {
+		return nil
+	}
+	var algorithms []string
+	seen := make(map[string]bool)
+	for _, known := range keyErr.Want {
+		types := []string{known.Key.Type()}
+		if known.Key.Type() == gossh.KeyAlgoRSA {
+			types = []string{gossh.KeyAlgoRSASHA512, gossh.KeyAlgoRSASHA256, gossh.KeyAlgoRSA}
+		}
+		for _, t := range types {
+			if !seen[t] {
+				seen[t] = true
+				algorithms = append(algorithms, t)
+			}
+		}
+	}
+	return algorithms
+}
+
+// keepalive pings the server and drops the client when it stops answering
+func (n *nativeTransport) keepalive(client *gossh.Client) {
+	ticker := time.NewTicker(nativeKeepaliveInterval)
+	defer ticker.Stop()
+	done := make(chan error, 1)
+	go func() { done <- client.Wait() }()
+	for {
+		select {
+		case <-done:
+			n.drop(client)
+			return
+		case <-ticker.C:
+			reply := make(chan error, 1)
+			go func() {
+				_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+				reply <- err
+			}()
+			select {
+			case err := <-reply:
+				if err != nil {
+					n.drop(client)
+					return
+				}
+			case <-time.After(nativeKeepaliveTimeout):
+				log.Printf("[SSH] %s: keepalive timed out, closing connection", n.cfg.Host)
+				n.drop(client)
+				return
+			}
+		}
+	}
+}
+
+// drop closes client (if still current) so the next call redials
+func (n *nativeTransport) drop(client *gossh.Client) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.client != client {
+		return
+	}
+	n.closeLocked()
+}
+
+func (n *nativeTransport) closeLocked() {
+	if n.client == nil {
+		return
+	}
+	for path, l := range n.listeners {
+		_ = l.Close()
+		delete(n.listeners, path)
+	}
+	_ = n.client.Close()
+	for i := len(n.closers) - 1; i >= 0; i-- {
+		_ = n.closers[i].Close()
+	}
+	n.client = nil
+	n.closers = nil
+}
+
+// close shuts down the client and its jump hosts
+func (n *nativeTransport) close() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.closeLocked()
+}
+
+// run executes command in a new session, feeding stdin if non-nil
+func (n *nativeTransport) run(command string, stdin io.Reader) (string, error) {
+	client, err := n.get()
+	if err != nil {
+		return "", err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		n.drop(client)
+		return "", fmt.Errorf("SSH command failed: %w", err)
+	}
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+	session.Stdin = stdin
+	if err := session.Run(command); err != nil {
+		var exitErr *gossh.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("remote command failed (exit %d): %s",
+				exitErr.ExitStatus(), strings.TrimSpace(stderr.String()))
+		}
+		return "", fmt.Errorf("SSH command failed: %w", err)
+	}
+	return stdout.String(), nil
+}
+
+// nativeStream adapts a gossh session to Stream
+type nativeStream struct {
+	session *gossh.Session
+}
+
+func (s *nativeStream) Wait() error { return s.session.Wait() }
+
+func (s *nativeStream) Kill() error { return s.session.Close() }
+
+func (n *nativeTransport) startStream(command string) (*Stream, error) {
+	client, err := n.get()
+	if err != nil {
+		return nil, err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		n.drop(client)
+		return nil, fmt.Errorf("failed to open SSH session: %w", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	if err := session.Start(command); err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("failed to start remote command: %w", err)
+	}
+	s := &nativeStream{session: session}
+	return &Stream{Stdin: stdin, Stdout: stdout, wait: s.Wait, kill: s.Kill}, nil
+}
+
+// nativeInteractive is a remote command on a server-side PTY
+type nativeInteractive struct {
+	session *gossh.Session
+	stdin   io.WriteCloser
+	stdout  io.Reader
+}
+
+func (s *nativeInteractive) Read(p []byte) (int, error)  { return s.stdout.Read(p) }
+func (s *nativeInteractive) Write(p []byte) (int, error) { return s.stdin.Write(p) }
+func (s *nativeInteractive) Close() error                { return s.session.Close() }
+func (s *nativeInteractive) Wait() error                 { return s.session.Wait() }
+
+func (s *nativeInteractive) Resize(cols, rows int) error {
+	return s.session.WindowChange(rows, cols)
+}
+
+func (n *nativeTransport) openInteractive(command string, cols, rows int) (InteractiveSession, error) {
+	client, err := n.get()
+	if err != nil {
+		return nil, err
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		n.drop(client)
+		return nil, fmt.Errorf("failed to open SSH session: %w", err)
+	}
+	termType := os.Getenv("TERM")
+	if termType == "" {
+		termType = "xterm-256color"
+	}
+	modes := gossh.TerminalModes{gossh.ECHO: 1, gossh.TTY_OP_ISPEED: 14400, gossh.TTY_OP_OSPEED: 14400}
+	if err := session.RequestPty(termType, rows, cols, modes); err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("failed to request PTY: %w", err)
+	}
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		_ = session.Close()
+		return nil, err
+	}
+	// A PTY merges stderr into stdout on the remote side
+	session.Stderr = io.Discard
+	if err := session.Start(command); err != nil {
+		_ = session.Close()
+		return nil, fmt.Errorf("failed to start remote command: %w", err)
+	}
+	return &nativeInteractive{session: session, stdin: stdin, stdout: stdout}, nil
+}
+
+// forwardPort listens on localPort and tunnels connections to remoteHost:remotePort.
+// The returned channel is closed when the listener stops accepting.
+func (n *nativeTransport) forwardPort(localPort int, remoteAddr string) (net.Listener, <-chan struct{}, error) {
+	if _, err := n.get(); err != nil {
+		return nil, nil, err
+	}
+	l, err := net.Listen("tcp", fmt.Sprintf("localhost:%d", localPort))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on local port %d: %w", localPort, err)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		acceptAndPipe(l, func() (net.Conn, error) {
+			// Redial through the current client if the original one dropped
+			c, err := n.get()
+			if err != nil {
+				return nil, err
+			}
+			return c.Dial("tcp", remoteAddr)
+		})
+	}()
+	return l, done, nil
+}
+
+// forwardRemoteSocket listens on remotePath on the server and connects
+// each accepted stream to the local Unix socket localPath
+func (n *nativeTransport) forwardRemoteSocket(remotePath, localPath string) error {
+	client, err := n.get()
+	if err != nil {
+		return err
+	}
+	l, err := client.ListenUnix(remotePath)
+	if err != nil {
+		return fmt.Errorf("socket forward %s:%s failed: %w", remotePath, localPath, err)
+	}
+
+	n.mu.Lock()
+	if old, ok := n.listeners[remotePath]; ok {
+		_ = old.Close()
+	}
+	n.listeners[remotePath] = l
+	n.mu.Unlock()
+
+	go acceptAndPipe(l, func() (net.Conn, error) {
+		return net.Dial("unix", localPath)
+	})
+	return nil
+}
+
+func (n *nativeTransport) cancelRemoteSocket(remotePath string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	l, ok := n.listeners[remotePath]
+	if !ok {
+		return nil
+	}
+	delete(n.listeners, remotePath)
+	return l.Close()
+}
+
+// generation identifies the current client; forwards must be re-added when it changes
+func (n *nativeTransport) generation() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.client == nil {
+		return ""
+	}
+	return fmt.Sprintf("native-%d", n.gen)
+}
+
+// acceptAndPipe copies data between accepted connections and dialed peers
+// until the listener is closed
+func acceptAndPipe(l net.Listener, dial func() (net.Conn, error)) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go func(conn net.Conn) {
+			defer conn.Close()
+			peer, err := dial()
+			if err != nil {
+				log.Printf("[SSH] forward dial failed: %v", err)
+				return
+			}
+			defer peer.Close()
+			done := make(chan struct{}, 2)
+			go func() { _, _ = io.Copy(peer, conn); done <- struct{}{} }()
+			go func() { _, _ = io.Copy(conn, peer); done <- struct{}{} }()
+			<-done
+		}(conn)
+	}
+}
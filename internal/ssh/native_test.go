@@ -0,0 +1,439 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// testSSHServer is a minimal in-process SSH server: public key auth, exec
+// sessions run through sh, and direct-tcpip channels (jump hosts, -L forwards)
+type testSSHServer struct {
+	addr        string
+	port        int
+	hostKey     gossh.Signer
+	directTCPIP atomic.Int32
+}
+
+// newTestClientKey writes a fresh ed25519 identity file and returns its public key
+func newTestClientKey(t *testing.T) (string, gossh.PublicKey) {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "id_ed25519")
+	if err := os.WriteFile(path, pem.EncodeToMemory(block), 0600); err != nil {
+		t.Fatal(err)
+	}
+	sshPub, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path, sshPub
+}
+
+func startTestSSHServer(t *testing.T, authorized gossh.PublicKey) *testSSHServer {
+	t.Helper()
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostKey, err := gossh.NewSignerFromKey(hostPriv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := &gossh.ServerConfig{
+		PublicKeyCallback: func(meta gossh.ConnMetadata, key gossh.PublicKey) (*gossh.Permissions, error) {
+			if string(key.Marshal()) == string(authorized.Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key for %s", meta.User())
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	srv := &testSSHServer{addr: l.Addr().String(), port: l.Addr().(*net.TCPAddr).Port, hostKey: hostKey}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go srv.serveConn(conn, config)
+		}
+	}()
+	return srv
+}
+
+func (s *testSSHServer) serveConn(conn net.Conn, config *gossh.ServerConfig) {
+	_, chans, reqs, err := gossh.NewServerConn(conn, config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	go func() {
+		for req := range reqs {
+			if req.WantReply {
+				_ = req.Reply(req.Type == "keepalive@openssh.com", nil)
+			}
+		}
+	}()
+	for newCh := range chans {
+		switch newCh.ChannelType() {
+		case "session":
+			go s.serveSession(newCh)
+		case "direct-tcpip":
+			go s.serveDirectTCPIP(newCh)
+		default:
+			_ = newCh.Reject(gossh.UnknownChannelType, "unsupported")
+		}
+	}
+}
+
+func (s *testSSHServer) serveSession(newCh gossh.NewChannel) {
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		return
+	}
+	defer ch.Close()
+	for req := range reqs {
+		switch req.Type {
+		case "pty-req", "window-change", "env":
+			if req.WantReply {
+				_ = req.Reply(true, nil)
+			}
+		case "exec":
+			var payload struct{ Command string }
+			_ = gossh.Unmarshal(req.Payload, &payload)
+			_ = req.Reply(true, nil)
+
+			cmd := exec.Command("sh", "-c", payload.Command)
+			cmd.Stdout = ch
+			cmd.Stderr = ch.Stderr()
+			// Copy stdin ourselves so an open stdin doesn't outlive the command
+			stdin, _ := cmd.StdinPipe()
+			go func() {
+				_, _ = io.Copy(stdin, ch)
+				_ = stdin.Close()
+			}()
+			status := 0
+			if err := cmd.Run(); err != nil {
+				status = 255
+				if exitErr, ok := err.(*exec.ExitError); ok {
+					status = exitErr.ExitCode()
+				}
+			}
+			_, _ = ch.SendRequest("exit-status", false, gossh.Marshal(struct{ Status uint32 }{uint32(status)}))
+			return
+		default:
+			if req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}
+}
+
+func (s *testSSHServer) serveDirectTCPIP(newCh gossh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := gossh.Unmarshal(newCh.ExtraData(), &payload); err != nil {
+		_ = newCh.Reject(gossh.ConnectionFailed, err.Error())
+		return
+	}
+	target, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		_ = newCh.Reject(gossh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, reqs, err := newCh.Accept()
+	if err != nil {
+		_ = target.Close()
+		return
+	}
+	s.directTCPIP.Add(1)
+	go gossh.DiscardRequests(reqs)
+	go func() {
+		_, _ = io.Copy(ch, target)
+		_ = ch.CloseWrite()
+	}()
+	_, _ = io.Copy(target, ch)
+	_ = target.Close()
+	_ = ch.Close()
+}
+
+// nativeTestConfig returns a native transport config for srv
+func nativeTestConfig(t *testing.T, srv *testSSHServer, identity string) Config {
+	t.Helper()
+	t.Setenv("SSH_AUTH_SOCK", "")
+	return Config{
+		Host:           "127.0.0.1",
+		Port:           srv.port,
+		User:           "tester",
+		IdentityFile:   identity,
+		Transport:      TransportNative,
+		KnownHostsFile: filepath.Join(t.TempDir(), "known_hosts"),
+
+		AcceptNewHostKeys: true,
+	}
+}
+
+func TestNative_RunCommand(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	conn := NewConnection(nativeTestConfig(t, srv, identity))
+	defer conn.CloseControlMaster()
+
+	if err := conn.TestConnection(); err != nil {
+		t.Fatalf("TestConnection: %v", err)
+	}
+	if !conn.IsConnected() {
+		t.Error("IsConnected() = false after successful test")
+	}
+
+	out, err := conn.RunCommand("echo hello")
+	if err != nil || strings.TrimSpace(out) != "hello" {
+		t.Errorf("RunCommand = %q, %v", out, err)
+	}
+
+	_, err = conn.RunCommand("echo boom >&2; exit 3")
+	if err == nil || !strings.Contains(err.Error(), "exit 3") || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("failing command error = %v, want exit 3 with stderr", err)
+	}
+
+	out, err = conn.RunCommandWithStdin("tr a-z A-Z", strings.NewReader("piped"))
+	if err != nil || out != "PIPED" {
+		t.Errorf("RunCommandWithStdin = %q, %v", out, err)
+	}
+}
+
+func TestNative_AuthFailureIsDescriptive(t *testing.T) {
+	_, pub := newTestClientKey(t)
+	otherIdentity, _ := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	conn := NewConnection(nativeTestConfig(t, srv, otherIdentity))
+
+	err := conn.TestConnection()
+	if err == nil {
+		t.Fatal("expected auth failure")
+	}
+	if !strings.Contains(err.Error(), "handshake with "+srv.addr) || !strings.Contains(err.Error(), "tester") {
+		t.Errorf("error %q should name the address and user", err)
+	}
+}
+
+func TestNative_KnownHosts(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	cfg := nativeTestConfig(t, srv, identity)
+
+	// Unknown host: refused by default
+	strict := cfg
+	strict.AcceptNewHostKeys = false
+	conn := NewConnection(strict)
+	if _, err := conn.RunCommand("true"); err == nil || !strings.Contains(err.Error(), "unknown host") {
+		t.Errorf("unknown host error = %v", err)
+	}
+	conn.CloseControlMaster()
+
+	// Unknown host with accept_new_host_keys: accepted and recorded
+	conn = NewConnection(cfg)
+	if _, err := conn.RunCommand("true"); err != nil {
+		t.Fatalf("first connect: %v", err)
+	}
+	conn.CloseControlMaster()
+	data, _ := os.ReadFile(cfg.KnownHostsFile)
+	if !strings.Contains(string(data), knownhosts.Normalize(srv.addr)) {
+		t.Fatalf("known_hosts not updated: %q", data)
+	}
+
+	// Recorded host: reconnect succeeds
+	conn = NewConnection(cfg)
+	if _, err := conn.RunCommand("true"); err != nil {
+		t.Fatalf("reconnect: %v", err)
+	}
+	conn.CloseControlMaster()
+
+	// Changed key: rejected
+	_, otherPriv, _ := ed25519.GenerateKey(rand.Reader)
+	otherSigner, _ := gossh.NewSignerFromKey(otherPriv)
+	line := knownhosts.Line([]string{knownhosts.Normalize(srv.addr)}, otherSigner.PublicKey())
+	if err := os.WriteFile(cfg.KnownHostsFile, []byte(line+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	conn = NewConnection(cfg)
+	_, err := conn.RunCommand("true")
+	if err == nil || !strings.Contains(err.Error(), "host key") {
+		t.Errorf("changed host key error = %v", err)
+	}
+}
+
+func TestNative_JumpHostChain(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	bastion := startTestSSHServer(t, pub)
+	target := startTestSSHServer(t, pub)
+
+	cfg := nativeTestConfig(t, target, identity)
+	cfg.JumpHosts = []Config{{Host: "127.0.0.1", Port: bastion.port, User: "tester", IdentityFile: identity}}
+	conn := NewConnection(cfg)
+	defer conn.CloseControlMaster()
+
+	out, err := conn.RunCommand("echo via-bastion")
+	if err != nil || strings.TrimSpace(out) != "via-bastion" {
+		t.Fatalf("RunCommand through jump host = %q, %v", out, err)
+	}
+	if bastion.directTCPIP.Load() != 1 {
+		t.Errorf("bastion tunneled %d connections, want 1", bastion.directTCPIP.Load())
+	}
+}
+
+func TestNative_ForwardPort(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	conn := NewConnection(nativeTestConfig(t, srv, identity))
+	defer conn.CloseControlMaster()
+
+	// "Remote" echo service
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer echo.Close()
+	go func() {
+		for {
+			c, err := echo.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _, _ = io.Copy(c, c); _ = c.Close() }()
+		}
+	}()
+
+	free, _ := net.Listen("tcp", "127.0.0.1:0")
+	localPort := free.Addr().(*net.TCPAddr).Port
+	_ = free.Close()
+
+	forward, err := conn.ForwardPort(localPort, echo.Addr().(*net.TCPAddr).Port, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("ForwardPort: %v", err)
+	}
+	defer forward.Close()
+
+	c, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", localPort), 2*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	_ = c.SetDeadline(time.Now().Add(5 * time.Second))
+	if _, err := c.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 4)
+	if _, err := io.ReadFull(c, buf); err != nil || string(buf) != "ping" {
+		t.Errorf("forwarded echo = %q, %v", buf, err)
+	}
+}
+
+func TestNative_StreamAndInteractive(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	conn := NewConnection(nativeTestConfig(t, srv, identity))
+	defer conn.CloseControlMaster()
+
+	stream, err := conn.StartStream("cat")
+	if err != nil {
+		t.Fatalf("StartStream: %v", err)
+	}
+	if _, err := stream.Stdin.Write([]byte("round trip")); err != nil {
+		t.Fatal(err)
+	}
+	_ = stream.Stdin.Close()
+	out, _ := io.ReadAll(stream.Stdout)
+	if string(out) != "round trip" {
+		t.Errorf("stream output = %q", out)
+	}
+	if err := stream.Wait(); err != nil {
+		t.Errorf("stream Wait: %v", err)
+	}
+
+	session, err := conn.OpenInteractive("read key; echo attached:$key; exit 1", 120, 40)
+	if err != nil {
+		t.Fatalf("OpenInteractive: %v", err)
+	}
+	defer session.Close()
+	if err := session.Resize(100, 30); err != nil {
+		t.Errorf("Resize: %v", err)
+	}
+	if _, err := session.Write([]byte("q\n")); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var output strings.Builder
+	done := make(chan struct{})
+	go func() {
+		buf := make([]byte, 64)
+		for {
+			n, err := session.Read(buf)
+			mu.Lock()
+			output.Write(buf[:n])
+			mu.Unlock()
+			if err != nil {
+				close(done)
+				return
+			}
+		}
+	}()
+	err = session.Wait()
+	if code, ok := ExitCode(err); !ok || code != 1 {
+		t.Errorf("ExitCode(%v) = %d, %v; want 1", err, code, ok)
+	}
+	<-done
+	mu.Lock()
+	defer mu.Unlock()
+	if !strings.Contains(output.String(), "attached:q") {
+		t.Errorf("interactive output = %q", output.String())
+	}
+}
+
+func TestParseJumpHosts(t *testing.T) {
+	hops := ParseJumpHosts("admin@bastion.example.com:2222, inner")
+	if len(hops) != 2 {
+		t.Fatalf("got %d hops, want 2", len(hops))
+	}
+	if hops[0].User != "admin" || hops[0].Host != "bastion.example.com" || hops[0].Port != 2222 {
+		t.Errorf("hop 0 = %+v", hops[0])
+	}
+	if hops[1].Host != "inner" || hops[1].Port != 0 || hops[1].User != "" {
+		t.Errorf("hop 1 = %+v", hops[1])
+	}
+}
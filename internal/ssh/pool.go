@@ -12,13 +12,30 @@ type Pool struct {
 	mu          sync.RWMutex
 	connections map[string]*Connection // hostID -> connection
 	configs     map[string]Config      // hostID -> config for reconnection
+	health      map[string]*hostHealth // hostID -> health checker state
+	resources   map[string]*HostResources
+	onChange    func(hostID string, connected bool)
 }
 
+// hostHealth is the health checker's view of a host
+type hostHealth struct {
+	connected bool
+	lastCheck time.Time
+	lastError error
+	failures  int       // consecutive failed checks
+	nextCheck time.Time // backoff: no check before this
+}
+
+// maxReconnectBackoff caps the delay between checks of an unreachable host
+const maxReconnectBackoff = 5 * time.Minute
+
 // NewPool creates a new connection pool
 func NewPool() *Pool {
 	return &Pool{
 		connections: make(map[string]*Connection),
 		configs:     make(map[string]Config),
+		health:      make(map[string]*hostHealth),
+		resources:   make(map[string]*HostResources),
 	}
 }
 
@@ -43,6 +60,8 @@ func (p *Pool) Unregister(hostID string) {
 	conn, exists := p.connections[hostID]
 	delete(p.connections, hostID)
 	delete(p.configs, hostID)
+	delete(p.health, hostID)
+	delete(p.resources, hostID)
 	p.mu.Unlock()
 
 	// Clean up ControlMaster socket if connection existed
@@ -118,6 +137,7 @@ func (p *Pool) HealthCheck() map[string]error {
 	p.mu.RUnlock()
 
 	results := make(map[string]error)
+	var resultsMu sync.Mutex
 	var wg sync.WaitGroup
 
 	for _, hostID := range hostIDs {
@@ -125,7 +145,9 @@ func (p *Pool) HealthCheck() map[string]error {
 		go func(hid string) {
 			defer wg.Done()
 			_, err := p.Get(hid)
+			resultsMu.Lock()
 			results[hid] = err
+			resultsMu.Unlock()
 		}(hostID)
 	}
 
@@ -188,6 +210,13 @@ type Status struct {
 	Connected bool
 	LastError error
 	LastCheck time.Time
+	// Failures and NextRetry are set by the health checker while a host is
+	// unreachable: consecutive failed checks and when it retries next
+	Failures  int
+	NextRetry time.Time
+	// Resources is the last load/memory/disk snapshot of the host, collected
+	// by the health checker or CollectResources (nil if never collected)
+	Resources *HostResources
 }
 
 // statusCheckCacheDuration is how long a connection status check is considered fresh.
@@ -295,31 +324,181 @@ func (p *Pool) Status() []Status {
 
 	// Collect results
 	statuses := make([]Status, 0, len(hostIDs))
+	p.mu.RLock()
 	for result := range results {
 		statuses = append(statuses, Status{
 			HostID:    result.hostID,
 			Connected: result.connected,
 			LastError: result.lastError,
 			LastCheck: result.lastCheck,
+			Resources: p.resources[result.hostID],
 		})
 	}
+	p.mu.RUnlock()
 
 	return statuses
 }
 
-// StartHealthChecker starts a background goroutine that periodically checks connections
+// SetStateHandler registers fn to be called by the health checker when a
+// host goes from connected to unreachable or back. fn runs on the checker's
+// goroutine and must not block.
+func (p *Pool) SetStateHandler(fn func(hostID string, connected bool)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = fn
+}
+
+// CachedStatus returns the last known status of every registered host without
+// any network I/O. Hosts that were never checked are reported disconnected
+// with a zero LastCheck.
+func (p *Pool) CachedStatus() []Status {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(p.configs))
+	for hostID := range p.configs {
+		status := Status{HostID: hostID, Resources: p.resources[hostID]}
+		if conn, ok := p.connections[hostID]; ok {
+			status.Connected, status.LastCheck, status.LastError = conn.GetStatusSnapshot()
+		}
+		if h, ok := p.health[hostID]; ok && h.lastCheck.After(status.LastCheck) {
+			status.Connected, status.LastCheck, status.LastError = h.connected, h.lastCheck, h.lastError
+		}
+		if h, ok := p.health[hostID]; ok && !status.Connected {
+			status.Failures = h.failures
+			status.NextRetry = h.nextCheck
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// CollectResources reads a host's load, memory, disk and agent count and
+// keeps the result for Status and CachedStatus
+func (p *Pool) CollectResources(hostID string) (*HostResources, error) {
+	conn, err := p.Get(hostID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := conn.CollectResources()
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	p.resources[hostID] = r
+	p.mu.Unlock()
+	return r, nil
+}
+
+// reconnectBackoff returns the delay before the next check of a host that
+// failed the given number of consecutive checks: interval, doubling per
+// failure, capped at maxReconnectBackoff
+func reconnectBackoff(interval time.Duration, failures int) time.Duration {
+	delay := interval
+	for i := 1; i < failures && delay < maxReconnectBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxReconnectBackoff {
+		delay = maxReconnectBackoff
+	}
+	return delay
+}
+
+// checkHost tests one host and updates its health state. The next check is
+// scheduled from tick, the time the check was due, rather than from when it
+// finished, so a healthy host is due again on the next tick. Returns whether
+// the host changed between connected and unreachable.
+func (p *Pool) checkHost(hostID string, tick time.Time, interval time.Duration) (connected, changed bool) {
+	conn, err := p.Get(hostID)
+	if err == nil {
+		// Get reuses a connection that was fine at its last check; test it again
+		err = conn.TestConnection()
+	}
+	var resources *HostResources
+	if err == nil {
+		// Batched with the check so the host panel stays current
+		resources, _ = conn.CollectResources()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	h, known := p.health[hostID]
+	if !known {
+		h = &hostHealth{}
+		p.health[hostID] = h
+	}
+	if resources != nil {
+		p.resources[hostID] = resources
+	}
+	wasConnected := h.connected
+	h.connected = err == nil
+	h.lastCheck = time.Now()
+	h.lastError = err
+	if err == nil {
+		h.failures = 0
+		h.nextCheck = tick.Add(interval)
+	} else {
+		h.failures++
+		h.nextCheck = tick.Add(reconnectBackoff(interval, h.failures))
+	}
+	changed = wasConnected != h.connected
+	if !known {
+		// The first check only reports a change when the host is down
+		changed = !h.connected
+	}
+	return h.connected, changed
+}
+
+// StartHealthChecker starts a background goroutine that periodically checks
+// connections and collects each reachable host's resources. Hosts that fail
+// are retried with exponential backoff (interval, 2x, 4x ... up to 5 minutes)
+// instead of every tick, and the state handler is told when a host drops or
+// comes back.
 func (p *Pool) StartHealthChecker(interval time.Duration, stop <-chan struct{}) {
 	go func() {
 		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
+		now := time.Now()
 		for {
+			p.runHealthChecks(now, interval)
 			select {
-			case <-ticker.C:
-				p.HealthCheck()
+			case now = <-ticker.C:
 			case <-stop:
 				return
 			}
 		}
 	}()
 }
+
+// runHealthChecks checks every host whose backoff has expired at now (the
+// tick time), in parallel
+func (p *Pool) runHealthChecks(now time.Time, interval time.Duration) {
+	p.mu.RLock()
+	var due []string
+	for hostID := range p.configs {
+		if h, ok := p.health[hostID]; !ok || !now.Before(h.nextCheck) {
+			due = append(due, hostID)
+		}
+	}
+	p.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	for _, hostID := range due {
+		wg.Add(1)
+		go func(hid string) {
+			defer wg.Done()
+			connected, changed := p.checkHost(hid, now, interval)
+			if !changed {
+				return
+			}
+			p.mu.RLock()
+			fn := p.onChange
+			p.mu.RUnlock()
+			if fn != nil {
+				fn(hid, connected)
+			}
+		}(hostID)
+	}
+	wg.Wait()
+}
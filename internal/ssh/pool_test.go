@@ -1,6 +1,7 @@
 package ssh
 
 import (
+	"net"
 	"testing"
 	"time"
 )
@@ -421,3 +422,112 @@ func TestPoolUnregister_HostNotInListAfterRemoval(t *testing.T) {
 		t.Error("alpha and gamma should still be in ListHosts")
 	}
 }
+
+func TestReconnectBackoff(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{4, 4 * time.Minute},
+		{5, maxReconnectBackoff},
+		{50, maxReconnectBackoff},
+	}
+	for _, tt := range tests {
+		if got := reconnectBackoff(30*time.Second, tt.failures); got != tt.want {
+			t.Errorf("reconnectBackoff(30s, %d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestHealthChecker_BackoffAndStateChanges(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	good := nativeTestConfig(t, srv, identity)
+
+	// A closed port fails fast
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bad := good
+	bad.Port = ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+
+	pool := NewPool()
+	pool.Register("dev", bad)
+	var changes []bool
+	pool.SetStateHandler(func(hostID string, connected bool) {
+		changes = append(changes, connected)
+	})
+
+	const interval = time.Minute
+	pool.runHealthChecks(time.Now(), interval)
+	pool.runHealthChecks(time.Now(), interval) // backoff: not retried yet
+	status := pool.CachedStatus()
+	if len(status) != 1 || status[0].Connected || status[0].Failures != 1 {
+		t.Fatalf("after failed check: %+v", status)
+	}
+	if until := time.Until(status[0].NextRetry); until < interval-time.Second || until > interval {
+		t.Errorf("NextRetry in %v, want ~%v", until, interval)
+	}
+
+	// Host comes back once the backoff expires
+	pool.Register("dev", good)
+	pool.mu.Lock()
+	pool.health["dev"].nextCheck = time.Time{}
+	pool.mu.Unlock()
+	pool.runHealthChecks(time.Now(), interval)
+
+	status = pool.CachedStatus()
+	if !status[0].Connected || status[0].Failures != 0 {
+		t.Errorf("after reconnect: %+v", status)
+	}
+	if status[0].Resources == nil || status[0].Resources.CPUs == 0 {
+		t.Errorf("resources not collected with the check: %+v", status[0].Resources)
+	}
+	if len(changes) != 2 || changes[0] || !changes[1] {
+		t.Errorf("state changes = %v, want [false true]", changes)
+	}
+	pool.CloseAll()
+}
+
+func TestHealthChecker_HealthyHostDueEveryTick(t *testing.T) {
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+
+	pool := NewPool()
+	pool.Register("dev", nativeTestConfig(t, srv, identity))
+	defer pool.CloseAll()
+
+	// Fake clock: ticks are exactly one interval apart, and each check
+	// finishes some time after its tick
+	const interval = time.Minute
+	tick := time.Now()
+	lastCheck := func() time.Time {
+		pool.mu.RLock()
+		defer pool.mu.RUnlock()
+		return pool.health["dev"].lastCheck
+	}
+
+	pool.runHealthChecks(tick, interval)
+	first := lastCheck()
+	if first.IsZero() {
+		t.Fatal("first tick should check the host")
+	}
+
+	pool.runHealthChecks(tick.Add(interval/2), interval)
+	if !lastCheck().Equal(first) {
+		t.Error("host checked again before its interval passed")
+	}
+
+	for i := 1; i <= 3; i++ {
+		before := lastCheck()
+		pool.runHealthChecks(tick.Add(time.Duration(i)*interval), interval)
+		if !lastCheck().After(before) {
+			t.Fatalf("tick %d: healthy host skipped, want a check every interval", i)
+		}
+	}
+}
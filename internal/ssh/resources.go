@@ -0,0 +1,151 @@
+package ssh
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AgentProcessNames are the processes counted as running agents on a host
+var AgentProcessNames = []string{"claude", "gemini", "codex", "opencode"}
+
+// Overload thresholds used by HostResources.Warnings
+const (
+	overloadLoadPerCPU    = 1.0     // 1-minute load average per CPU
+	overloadMemAvailRatio = 0.10    // available / total memory
+	overloadDiskFreeRatio = 0.05    // free / total disk in $HOME
+	overloadDiskFreeBytes = 2 << 30 // free disk in $HOME
+)
+
+// HostResources is a snapshot of an SSH host's load, collected with one
+// remote command. Fields the host doesn't report are zero.
+type HostResources struct {
+	Load1  float64 `json:"load1"`
+	Load5  float64 `json:"load5"`
+	Load15 float64 `json:"load15"`
+	CPUs   int     `json:"cpus,omitempty"`
+	// Memory in bytes; MemAvailable includes reclaimable cache
+	MemTotal     uint64 `json:"mem_total,omitempty"`
+	MemAvailable uint64 `json:"mem_available,omitempty"`
+	// Disk of the filesystem holding $HOME, in bytes
+	DiskTotal uint64 `json:"disk_total,omitempty"`
+	DiskFree  uint64 `json:"disk_free,omitempty"`
+	// AgentProcesses counts running AgentProcessNames processes
+	AgentProcesses int       `json:"agent_processes"`
+	CollectedAt    time.Time `json:"collected_at"`
+}
+
+// hostResourcesScript prints key=value lines for parseHostResources. It
+// reads /proc on Linux and falls back to sysctl/vm_stat on macOS.
+var hostResourcesScript = strings.Join([]string{
+	`echo "cpus=$(getconf _NPROCESSORS_ONLN 2>/dev/null || sysctl -n hw.ncpu 2>/dev/null)"`,
+	`if [ -r /proc/loadavg ]; then echo "load=$(cut -d' ' -f1-3 /proc/loadavg)"; else echo "load=$(sysctl -n vm.loadavg 2>/dev/null | tr -d '{}')"; fi`,
+	`if [ -r /proc/meminfo ]; then awk '/^MemTotal:/{print "mem_total_kb=" $2} /^MemAvailable:/{print "mem_avail_kb=" $2}' /proc/meminfo; ` +
+		`else echo "mem_total=$(sysctl -n hw.memsize 2>/dev/null)"; vm_stat 2>/dev/null | awk '/page size of/{ps=$8} /^Pages (free|inactive|speculative):/{gsub(/\./,"",$NF); n+=$NF} END{if (ps) print "mem_avail=" n*ps}'; fi`,
+	`df -Pk "$HOME" 2>/dev/null | awk 'NR==2{print "disk_total_kb=" $2; print "disk_free_kb=" $4}'`,
+	`echo "agents=$(ps -A -o comm= 2>/dev/null | awk -F/ '{print $NF}' | grep -cxE '` + strings.Join(AgentProcessNames, "|") + `')"`,
+}, "\n")
+
+// CollectResources reads the host's load average, memory, free disk in
+// $HOME and number of running agent processes with a single command
+func (c *Connection) CollectResources() (*HostResources, error) {
+	out, err := c.RunCommand(remoteShell(hostResourcesScript))
+	if err != nil {
+		return nil, fmt.Errorf("collect resources: %w", err)
+	}
+	return parseHostResources(out), nil
+}
+
+// parseHostResources parses the output of hostResourcesScript
+func parseHostResources(out string) *HostResources {
+	r := &HostResources{CollectedAt: time.Now()}
+	for _, line := range strings.Split(out, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		num, _ := strconv.ParseUint(value, 10, 64)
+		switch key {
+		case "cpus":
+			r.CPUs = int(num)
+		case "load":
+			fields := strings.Fields(value)
+			loads := []*float64{&r.Load1, &r.Load5, &r.Load15}
+			for i := 0; i < len(fields) && i < len(loads); i++ {
+				*loads[i], _ = strconv.ParseFloat(fields[i], 64)
+			}
+		case "mem_total_kb":
+			r.MemTotal = num * 1024
+		case "mem_avail_kb":
+			r.MemAvailable = num * 1024
+		case "mem_total":
+			r.MemTotal = num
+		case "mem_avail":
+			r.MemAvailable = num
+		case "disk_total_kb":
+			r.DiskTotal = num * 1024
+		case "disk_free_kb":
+			r.DiskFree = num * 1024
+		case "agents":
+			r.AgentProcesses = int(num)
+		}
+	}
+	return r
+}
+
+// Warnings describes why the host is overloaded: load above the CPU count,
+// under 10% of memory available, or under 5% (or 2 GiB) of disk free.
+// Returns nil when the host looks fine.
+func (r *HostResources) Warnings() []string {
+	if r == nil {
+		return nil
+	}
+	var warnings []string
+	if r.CPUs > 0 && r.Load1 > float64(r.CPUs)*overloadLoadPerCPU {
+		warnings = append(warnings, fmt.Sprintf("load %.2f on %d CPUs", r.Load1, r.CPUs))
+	}
+	if r.MemTotal > 0 && float64(r.MemAvailable) < float64(r.MemTotal)*overloadMemAvailRatio {
+		warnings = append(warnings, fmt.Sprintf("only %s of %s memory available", FormatBytes(r.MemAvailable), FormatBytes(r.MemTotal)))
+	}
+	if r.DiskTotal > 0 && (float64(r.DiskFree) < float64(r.DiskTotal)*overloadDiskFreeRatio || r.DiskFree < overloadDiskFreeBytes) {
+		warnings = append(warnings, fmt.Sprintf("only %s disk free", FormatBytes(r.DiskFree)))
+	}
+	return warnings
+}
+
+// Summary is a one-line description like
+// "load 0.52 (8 CPUs), mem 12.1G/32.0G free, disk 210.4G free, 3 agents"
+func (r *HostResources) Summary() string {
+	if r == nil {
+		return ""
+	}
+	parts := []string{fmt.Sprintf("load %.2f", r.Load1)}
+	if r.CPUs > 0 {
+		parts[0] += fmt.Sprintf(" (%d CPUs)", r.CPUs)
+	}
+	if r.MemTotal > 0 {
+		parts = append(parts, fmt.Sprintf("mem %s/%s free", FormatBytes(r.MemAvailable), FormatBytes(r.MemTotal)))
+	}
+	if r.DiskTotal > 0 {
+		parts = append(parts, fmt.Sprintf("disk %s free", FormatBytes(r.DiskFree)))
+	}
+	parts = append(parts, fmt.Sprintf("%d agents", r.AgentProcesses))
+	return strings.Join(parts, ", ")
+}
+
+// FormatBytes formats a byte count with one decimal and a binary unit
+// suffix, like 12.3G
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%c", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,59 @@
+package ssh
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseHostResources(t *testing.T) {
+	linux := "cpus=8\nload=0.52 0.61 0.70\nmem_total_kb=32000000\nmem_avail_kb=2000000\n" +
+		"disk_total_kb=100000000\ndisk_free_kb=50000000\nagents=3\n"
+	r := parseHostResources(linux)
+	if r.CPUs != 8 || r.Load1 != 0.52 || r.Load15 != 0.70 || r.AgentProcesses != 3 {
+		t.Errorf("parsed %+v", r)
+	}
+	if r.MemTotal != 32000000*1024 || r.DiskFree != 50000000*1024 {
+		t.Errorf("memory/disk not converted from KiB: %+v", r)
+	}
+
+	mac := "cpus=10\nload= 1.91 2.03 2.10 \nmem_total=17179869184\nmem_avail=4294967296\nagents=0\n"
+	r = parseHostResources(mac)
+	if r.Load5 != 2.03 || r.MemTotal != 17179869184 || r.MemAvailable != 4294967296 {
+		t.Errorf("parsed macOS output %+v", r)
+	}
+}
+
+func TestHostResourcesWarnings(t *testing.T) {
+	ok := &HostResources{Load1: 3, CPUs: 8, MemTotal: 16 << 30, MemAvailable: 8 << 30, DiskTotal: 100 << 30, DiskFree: 50 << 30}
+	if w := ok.Warnings(); len(w) != 0 {
+		t.Errorf("Warnings() = %v, want none", w)
+	}
+
+	busy := &HostResources{Load1: 12.5, CPUs: 8, MemTotal: 16 << 30, MemAvailable: 1 << 30, DiskTotal: 100 << 30, DiskFree: 1 << 30}
+	w := busy.Warnings()
+	if len(w) != 3 {
+		t.Fatalf("Warnings() = %v, want load, memory and disk", w)
+	}
+	if !strings.Contains(w[0], "12.50") || !strings.Contains(w[1], "1.0G") || !strings.Contains(w[2], "1.0G") {
+		t.Errorf("Warnings() = %v", w)
+	}
+
+	var none *HostResources
+	if none.Warnings() != nil || none.Summary() != "" {
+		t.Error("nil HostResources should have no warnings or summary")
+	}
+}
+
+func TestCollectResources(t *testing.T) {
+	conn := newSyncTestConnection(t)
+	r, err := conn.CollectResources()
+	if err != nil {
+		t.Fatalf("CollectResources: %v", err)
+	}
+	if r.CPUs == 0 || r.CollectedAt.IsZero() {
+		t.Errorf("CollectResources() = %+v, want CPU count", r)
+	}
+	if !strings.Contains(r.Summary(), "agents") {
+		t.Errorf("Summary() = %q", r.Summary())
+	}
+}
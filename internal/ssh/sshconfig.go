@@ -0,0 +1,374 @@
+package ssh
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxSSHConfigIncludeDepth matches OpenSSH's READCONF_MAX_DEPTH
+const maxSSHConfigIncludeDepth = 16
+
+// SSHConfig is a parsed OpenSSH client config (~/.ssh/config).
+// Only the options agent-deck needs are kept: HostName, User, Port,
+// IdentityFile, ProxyJump and ProxyCommand. Match blocks are skipped.
+type SSHConfig struct {
+	// Path is the top-level config file that was loaded
+	Path string
+
+	entries []sshConfigEntry
+	// aliases lists literal Host patterns in file order
+	aliases []string
+}
+
+// sshConfigEntry is one option line together with the Host patterns it applies to
+type sshConfigEntry struct {
+	patterns []string // nil = applies to every host (before the first Host line)
+	match    bool     // inside a Match block (never applies)
+	key      string   // lower-cased keyword
+	value    string
+}
+
+// SSHConfigHost holds the effective settings ssh would use for an alias
+type SSHConfigHost struct {
+	Alias        string `json:"alias"`
+	HostName     string `json:"hostname"`
+	User         string `json:"user,omitempty"`
+	Port         int    `json:"port,omitempty"`
+	IdentityFile string `json:"identity_file,omitempty"`
+	ProxyJump    string `json:"proxy_jump,omitempty"`
+	ProxyCommand string `json:"proxy_command,omitempty"`
+}
+
+// DefaultSSHConfigPath returns ~/.ssh/config
+func DefaultSSHConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".ssh", "config")
+}
+
+// LoadSSHConfig parses an OpenSSH client config, following Include directives.
+// An empty path loads ~/.ssh/config. A missing file yields an empty config.
+func LoadSSHConfig(configPath string) (*SSHConfig, error) {
+	if configPath == "" {
+		configPath = DefaultSSHConfigPath()
+	}
+	cfg := &SSHConfig{Path: configPath}
+	if configPath == "" {
+		return cfg, nil
+	}
+	if err := cfg.parseFile(configPath, nil, false, 0); err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// parseFile reads one config file. patterns/match are the enclosing block of
+// an Include, so included lines before their own Host line stay conditional.
+func (c *SSHConfig) parseFile(file string, patterns []string, match bool, depth int) error {
+	if depth > maxSSHConfigIncludeDepth {
+		return fmt.Errorf("%s: Include nested too deeply", file)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		key, args := splitSSHConfigLine(scanner.Text())
+		if key == "" {
+			continue
+		}
+		switch key {
+		case "host":
+			patterns, match = args, false
+			for _, p := range args {
+				if !strings.ContainsAny(p, "*?!") && !containsString(c.aliases, p) {
+					c.aliases = append(c.aliases, p)
+				}
+			}
+		case "match":
+			patterns, match = nil, true
+		case "include":
+			for _, pattern := range args {
+				matches, err := filepath.Glob(c.includePath(pattern))
+				if err != nil {
+					return fmt.Errorf("%s:%d: bad Include pattern %q: %w", file, lineNo, pattern, err)
+				}
+				for _, inc := range matches {
+					if err := c.parseFile(inc, patterns, match, depth+1); err != nil && !os.IsNotExist(err) {
+						return err
+					}
+				}
+			}
+		default:
+			if len(args) == 0 {
+				continue
+			}
+			c.entries = append(c.entries, sshConfigEntry{
+				patterns: patterns,
+				match:    match,
+				key:      key,
+				value:    strings.Join(args, " "),
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", file, err)
+	}
+	return nil
+}
+
+// includePath resolves an Include argument: ~ is expanded and relative
+// paths are taken from the directory of the top-level config (~/.ssh)
+func (c *SSHConfig) includePath(p string) string {
+	if strings.HasPrefix(p, "~") {
+		return expandPath(p)
+	}
+	if filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(filepath.Dir(c.Path), p)
+}
+
+// splitSSHConfigLine returns the lower-cased keyword and its arguments.
+// Handles "Key value", "Key=value", comments and double-quoted arguments.
+func splitSSHConfigLine(line string) (string, []string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", nil
+	}
+	end := strings.IndexAny(line, " \t=")
+	if end < 0 {
+		return strings.ToLower(line), nil
+	}
+	key := strings.ToLower(line[:end])
+	rest := strings.TrimLeft(line[end:], " \t")
+	rest = strings.TrimLeft(strings.TrimPrefix(rest, "="), " \t")
+
+	var args []string
+	for rest != "" {
+		if strings.HasPrefix(rest, "#") {
+			break
+		}
+		var arg string
+		if rest[0] == '"' {
+			closing := strings.IndexByte(rest[1:], '"')
+			if closing < 0 {
+				arg, rest = rest[1:], ""
+			} else {
+				arg, rest = rest[1:closing+1], rest[closing+2:]
+			}
+		} else if sep := strings.IndexAny(rest, " \t"); sep >= 0 {
+			arg, rest = rest[:sep], rest[sep:]
+		} else {
+			arg, rest = rest, ""
+		}
+		args = append(args, arg)
+		rest = strings.TrimLeft(rest, " \t")
+	}
+	return key, args
+}
+
+// matchesSSHHost reports whether alias matches a Host pattern list.
+// A negated pattern that matches excludes the host outright.
+func matchesSSHHost(patterns []string, alias string) bool {
+	if patterns == nil {
+		return true
+	}
+	matched := false
+	for _, p := range patterns {
+		negate := strings.HasPrefix(p, "!")
+		p = strings.TrimPrefix(p, "!")
+		ok, _ := path.Match(strings.ToLower(p), strings.ToLower(alias))
+		if ok && negate {
+			return false
+		}
+		if ok {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// Aliases returns the literal (non-wildcard) Host names in file order
+func (c *SSHConfig) Aliases() []string {
+	return append([]string(nil), c.aliases...)
+}
+
+// HasAlias reports whether any Host block other than a bare "*" matches alias
+func (c *SSHConfig) HasAlias(alias string) bool {
+	if alias == "" {
+		return false
+	}
+	for _, e := range c.entries {
+		if e.match || e.patterns == nil {
+			continue
+		}
+		if len(e.patterns) == 1 && e.patterns[0] == "*" {
+			continue
+		}
+		if matchesSSHHost(e.patterns, alias) {
+			return true
+		}
+	}
+	return containsString(c.aliases, alias)
+}
+
+// Resolve returns the effective settings for alias the way ssh -G would:
+// the first value obtained for each option wins.
+func (c *SSHConfig) Resolve(alias string) SSHConfigHost {
+	h := SSHConfigHost{Alias: alias}
+	for _, e := range c.entries {
+		if e.match || !matchesSSHHost(e.patterns, alias) {
+			continue
+		}
+		switch e.key {
+		case "hostname":
+			if h.HostName == "" {
+				h.HostName = e.value
+			}
+		case "user":
+			if h.User == "" {
+				h.User = e.value
+			}
+		case "port":
+			if h.Port == 0 {
+				h.Port, _ = strconv.Atoi(e.value)
+			}
+		case "identityfile":
+			if h.IdentityFile == "" {
+				h.IdentityFile = e.value
+			}
+		case "proxyjump":
+			if h.ProxyJump == "" {
+				h.ProxyJump = e.value
+			}
+		case "proxycommand":
+			if h.ProxyCommand == "" {
+				h.ProxyCommand = e.value
+			}
+		}
+	}
+
+	if h.HostName == "" {
+		h.HostName = alias
+	} else {
+		h.HostName = strings.ReplaceAll(h.HostName, "%h", alias)
+	}
+	if strings.EqualFold(h.ProxyJump, "none") {
+		h.ProxyJump = ""
+	}
+	if strings.EqualFold(h.ProxyCommand, "none") {
+		h.ProxyCommand = ""
+	}
+	if h.IdentityFile != "" {
+		h.IdentityFile = expandSSHConfigTokens(h.IdentityFile, h)
+	}
+	return h
+}
+
+// JumpHosts resolves a ProxyJump list into a hop chain (outermost first).
+// Each hop name is looked up in the config, so hops may themselves be aliases
+// with their own ProxyJump; explicit user@ and :port in the spec win.
+func (c *SSHConfig) JumpHosts(spec string) []Config {
+	return c.jumpHosts(spec, map[string]bool{})
+}
+
+func (c *SSHConfig) jumpHosts(spec string, visiting map[string]bool) []Config {
+	var chain []Config
+	for _, hop := range ParseJumpHosts(spec) {
+		if visiting[hop.Host] {
+			chain = append(chain, hop)
+			continue
+		}
+		resolved := c.Resolve(hop.Host)
+		if resolved.ProxyJump != "" {
+			visiting[hop.Host] = true
+			chain = append(chain, c.jumpHosts(resolved.ProxyJump, visiting)...)
+			delete(visiting, hop.Host)
+		}
+		cfg := Config{
+			Host:         resolved.HostName,
+			User:         resolved.User,
+			Port:         resolved.Port,
+			IdentityFile: resolved.IdentityFile,
+		}
+		if hop.User != "" {
+			cfg.User = hop.User
+		}
+		if hop.Port != 0 {
+			cfg.Port = hop.Port
+		}
+		chain = append(chain, cfg)
+	}
+	return chain
+}
+
+// expandSSHConfigTokens expands the ssh_config(5) tokens agent-deck can know
+// (%d %h %n %p %r %u %%) and a leading ~
+func expandSSHConfigTokens(s string, h SSHConfigHost) string {
+	if !strings.Contains(s, "%") {
+		return expandPath(s)
+	}
+	home, _ := os.UserHomeDir()
+	localUser := os.Getenv("USER")
+	port := h.Port
+	if port == 0 {
+		port = 22
+	}
+	remoteUser := h.User
+	if remoteUser == "" {
+		remoteUser = localUser
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'd':
+			b.WriteString(home)
+		case 'h':
+			b.WriteString(h.HostName)
+		case 'n':
+			b.WriteString(h.Alias)
+		case 'p':
+			b.WriteString(strconv.Itoa(port))
+		case 'r':
+			b.WriteString(remoteUser)
+		case 'u':
+			b.WriteString(localUser)
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(s[i])
+		}
+	}
+	return expandPath(b.String())
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeSSHConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadSSHConfig_ResolveAndInclude(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	sshDir := filepath.Join(home, ".ssh")
+
+	writeSSHConfigFile(t, filepath.Join(sshDir, "config"), `
+# personal hosts
+Include config.d/*
+
+Host devbox dev
+    HostName 10.0.0.5
+    User alice
+    Port 2222
+    IdentityFile ~/.ssh/id_dev
+    ProxyJump bastion
+
+Host bastion
+    HostName bastion.example.com
+    User jump
+
+Host *.corp !legacy.corp
+    User corpuser
+    IdentityFile %d/.ssh/%h.key
+
+Match host foo
+    User ignored
+
+Host *
+    User fallback
+    Port 22
+`)
+	writeSSHConfigFile(t, filepath.Join(sshDir, "config.d", "work"), `
+Host gpu
+    HostName=gpu.internal
+    User "ml ops"
+    ProxyJump none
+`)
+
+	cfg, err := LoadSSHConfig("")
+	if err != nil {
+		t.Fatalf("LoadSSHConfig: %v", err)
+	}
+
+	if got, want := cfg.Aliases(), []string{"gpu", "devbox", "dev", "bastion"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Aliases() = %v, want %v", got, want)
+	}
+
+	dev := cfg.Resolve("dev")
+	want := SSHConfigHost{
+		Alias:        "dev",
+		HostName:     "10.0.0.5",
+		User:         "alice",
+		Port:         2222,
+		IdentityFile: filepath.Join(home, ".ssh", "id_dev"),
+		ProxyJump:    "bastion",
+	}
+	if dev != want {
+		t.Errorf("Resolve(dev) = %+v, want %+v", dev, want)
+	}
+
+	gpu := cfg.Resolve("gpu")
+	if gpu.HostName != "gpu.internal" || gpu.User != "ml ops" || gpu.ProxyJump != "" || gpu.Port != 22 {
+		t.Errorf("Resolve(gpu) = %+v", gpu)
+	}
+
+	corp := cfg.Resolve("build.corp")
+	if corp.User != "corpuser" || corp.IdentityFile != filepath.Join(home, ".ssh", "build.corp.key") {
+		t.Errorf("Resolve(build.corp) = %+v", corp)
+	}
+	if legacy := cfg.Resolve("legacy.corp"); legacy.User != "fallback" {
+		t.Errorf("negated pattern: Resolve(legacy.corp).User = %q, want fallback", legacy.User)
+	}
+	if foo := cfg.Resolve("foo"); foo.User != "fallback" || foo.HostName != "foo" {
+		t.Errorf("Match block should be skipped: Resolve(foo) = %+v", foo)
+	}
+
+	if !cfg.HasAlias("devbox") || !cfg.HasAlias("build.corp") {
+		t.Error("HasAlias should match literal and wildcard Host blocks")
+	}
+	if cfg.HasAlias("unknown") {
+		t.Error("HasAlias(unknown) = true, want false (only Host * matches)")
+	}
+}
+
+func TestLoadSSHConfig_MissingFile(t *testing.T) {
+	cfg, err := LoadSSHConfig(filepath.Join(t.TempDir(), "nope"))
+	if err != nil {
+		t.Fatalf("LoadSSHConfig: %v", err)
+	}
+	if len(cfg.Aliases()) != 0 || cfg.HasAlias("x") {
+		t.Error("missing config should be empty")
+	}
+	if h := cfg.Resolve("x"); h.HostName != "x" {
+		t.Errorf("Resolve on empty config = %+v", h)
+	}
+}
+
+func TestSSHConfig_JumpHosts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	writeSSHConfigFile(t, path, `
+Host inner
+    HostName 192.168.1.10
+    ProxyJump outer
+
+Host outer
+    HostName outer.example.com
+    User ops
+    Port 2200
+
+Host loop
+    ProxyJump loop
+`)
+	cfg, err := LoadSSHConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := cfg.JumpHosts("root@inner:2022")
+	want := []Config{
+		{Host: "outer.example.com", User: "ops", Port: 2200},
+		{Host: "192.168.1.10", User: "root", Port: 2022},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("JumpHosts = %+v, want %+v", got, want)
+	}
+
+	if got := cfg.JumpHosts("loop"); len(got) != 2 {
+		t.Errorf("self-referencing ProxyJump should stop after one repeat, got %+v", got)
+	}
+}
+
+func TestSplitSSHConfigLine(t *testing.T) {
+	tests := []struct {
+		line string
+		key  string
+		args []string
+	}{
+		{"  Host a b  ", "host", []string{"a", "b"}},
+		{"Port=2222", "port", []string{"2222"}},
+		{"User = bob # trailing", "user", []string{"bob"}},
+		{`IdentityFile "~/My Keys/id"`, "identityfile", []string{"~/My Keys/id"}},
+		{"# comment", "", nil},
+		{"", "", nil},
+	}
+	for _, tt := range tests {
+		key, args := splitSSHConfigLine(tt.line)
+		if key != tt.key || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("splitSSHConfigLine(%q) = %q %v, want %q %v", tt.line, key, args, tt.key, tt.args)
+		}
+	}
+}
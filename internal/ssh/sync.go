@@ -0,0 +1,505 @@
+package ssh
+
+import (
+	"archive/tar"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultSyncExclude is used when SyncOptions.Exclude is nil
+var DefaultSyncExclude = []string{".git"}
+
+// SyncOptions controls Push and Pull
+type SyncOptions struct {
+	// Exclude lists glob patterns matched against every path component
+	// (e.g. ".git", "node_modules", "*.log"). nil means DefaultSyncExclude.
+	Exclude []string
+	// Delete removes destination files that are missing from the source
+	Delete bool
+	// DryRun computes the changes without transferring or deleting anything
+	DryRun bool
+}
+
+func (o SyncOptions) exclude() []string {
+	if o.Exclude == nil {
+		return DefaultSyncExclude
+	}
+	return o.Exclude
+}
+
+// SyncResult describes what Push or Pull changed (or would change, for a
+// dry run). Paths are relative to the destination directory.
+type SyncResult struct {
+	Transferred []string `json:"transferred"`
+	Deleted     []string `json:"deleted,omitempty"`
+	Unchanged   int      `json:"unchanged"`
+	Bytes       int64    `json:"bytes"`
+}
+
+// syncFile is one regular file in a sync manifest
+type syncFile struct {
+	hash string
+	size int64
+	mode os.FileMode
+	// path is the local file, for local manifests
+	path string
+}
+
+// syncTree is one side of a sync: a directory, optionally narrowed to a
+// single file that may have a different name on the other side
+type syncTree struct {
+	dir  string
+	file string
+}
+
+// Push copies localPath to remotePath. A directory is synced recursively
+// into remotePath; a file is written to remotePath. Only files whose sha256
+// differs from the remote copy are sent, as one tar stream, and the remote
+// checksums are verified afterwards. remotePath may start with ~/.
+func (c *Connection) Push(localPath, remotePath string, opts SyncOptions) (*SyncResult, error) {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return nil, err
+	}
+	src := syncTree{dir: localPath}
+	dst := syncTree{dir: remotePath}
+	if !info.IsDir() {
+		src = syncTree{dir: filepath.Dir(localPath), file: filepath.Base(localPath)}
+		dst = syncTree{dir: path.Dir(remotePath), file: path.Base(remotePath)}
+		if kind, err := c.remoteKind(remotePath); err != nil {
+			return nil, err
+		} else if kind == "dir" {
+			// Like cp: pushing a file to a directory keeps its name
+			dst = syncTree{dir: remotePath, file: src.file}
+		}
+	}
+
+	local, err := localManifest(src, dst.file, opts.exclude())
+	if err != nil {
+		return nil, err
+	}
+	remote, err := c.remoteManifest(dst, opts.exclude())
+	if err != nil {
+		return nil, err
+	}
+	result, changed := diffManifests(local, remote, opts.Delete)
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if len(changed) > 0 {
+		if err := c.pushFiles(dst.dir, local, changed); err != nil {
+			return nil, err
+		}
+		// Verify what landed on the remote
+		after, err := c.remoteManifest(syncTree{dir: dst.dir}, nil, changed...)
+		if err != nil {
+			return nil, fmt.Errorf("checksum failed: %w", err)
+		}
+		for _, name := range changed {
+			if after[name].hash != local[name].hash {
+				return nil, fmt.Errorf("checksum mismatch after upload: %s", name)
+			}
+		}
+	}
+	if len(result.Deleted) > 0 {
+		script := fmt.Sprintf(`cd %s && while IFS= read -r f; do rm -f -- "$f"; done`, RemoteQuote(dst.dir))
+		if _, err := c.RunCommandWithStdin(remoteShell(script), strings.NewReader(strings.Join(result.Deleted, "\n")+"\n")); err != nil {
+			return nil, fmt.Errorf("delete failed: %w", err)
+		}
+	}
+	return result, nil
+}
+
+// Pull copies remotePath to localPath, the reverse of Push. Received files
+// are checked against the remote sha256 before they replace local ones.
+func (c *Connection) Pull(remotePath, localPath string, opts SyncOptions) (*SyncResult, error) {
+	kind, err := c.remoteKind(remotePath)
+	if err != nil {
+		return nil, err
+	}
+	src := syncTree{dir: remotePath}
+	dst := syncTree{dir: localPath}
+	switch kind {
+	case "":
+		return nil, fmt.Errorf("%s: no such file or directory on remote host", remotePath)
+	case "file":
+		src = syncTree{dir: path.Dir(remotePath), file: path.Base(remotePath)}
+		dst = syncTree{dir: filepath.Dir(localPath), file: filepath.Base(localPath)}
+		if info, err := os.Stat(localPath); err == nil && info.IsDir() {
+			dst = syncTree{dir: localPath, file: src.file}
+		}
+	}
+
+	remote, err := c.remoteManifest(src, opts.exclude())
+	if err != nil {
+		return nil, err
+	}
+	if dst.file != "" && dst.file != src.file {
+		// Key the remote file by its local name
+		remote = map[string]syncFile{dst.file: remote[src.file]}
+		if remote[dst.file].hash == "" {
+			delete(remote, dst.file)
+		}
+	}
+	local, err := localManifest(dst, "", opts.exclude())
+	if err != nil {
+		return nil, err
+	}
+	result, changed := diffManifests(remote, local, opts.Delete)
+	if opts.DryRun {
+		return result, nil
+	}
+
+	if len(changed) > 0 {
+		names := changed
+		if dst.file != "" {
+			names = []string{src.file}
+		}
+		if err := c.pullFiles(src.dir, names, dst, remote); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range result.Deleted {
+		if err := os.Remove(filepath.Join(dst.dir, filepath.FromSlash(name))); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// WriteFile streams r to remotePath, creating it with 0600 permissions
+func (c *Connection) WriteFile(remotePath string, r io.Reader) error {
+	script := "umask 077; cat > " + RemoteQuote(remotePath)
+	if _, err := c.RunCommandWithStdin(remoteShell(script), r); err != nil {
+		return fmt.Errorf("failed to write %s: %w", remotePath, err)
+	}
+	return nil
+}
+
+// diffManifests compares source and destination manifests and returns the
+// result plus the sorted names to transfer
+func diffManifests(src, dst map[string]syncFile, deleteExtra bool) (*SyncResult, []string) {
+	result := &SyncResult{Transferred: []string{}}
+	for name, f := range src {
+		if d, ok := dst[name]; ok && d.hash == f.hash {
+			result.Unchanged++
+			continue
+		}
+		result.Transferred = append(result.Transferred, name)
+		result.Bytes += f.size
+	}
+	sort.Strings(result.Transferred)
+	if deleteExtra {
+		for name := range dst {
+			if _, ok := src[name]; !ok {
+				result.Deleted = append(result.Deleted, name)
+			}
+		}
+		sort.Strings(result.Deleted)
+	}
+	return result, result.Transferred
+}
+
+// localManifest hashes the regular files under tree. Symlinks inside a
+// directory, excluded paths and names containing newlines are skipped. For a single file, the
+// entry is keyed by name (the destination name) when given.
+func localManifest(tree syncTree, name string, exclude []string) (map[string]syncFile, error) {
+	files := make(map[string]syncFile)
+	if tree.file != "" {
+		p := filepath.Join(tree.dir, tree.file)
+		info, err := os.Stat(p)
+		if os.IsNotExist(err) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s is not a regular file", p)
+		}
+		f, err := hashLocalFile(p, info)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			name = tree.file
+		}
+		files[name] = f
+		return files, nil
+	}
+
+	err := filepath.Walk(tree.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == tree.dir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if p == tree.dir {
+			return nil
+		}
+		if syncExcluded(info.Name(), exclude) || strings.ContainsRune(info.Name(), '\n') {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(tree.dir, p)
+		if err != nil {
+			return err
+		}
+		f, err := hashLocalFile(p, info)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = f
+		return nil
+	})
+	return files, err
+}
+
+func hashLocalFile(p string, info os.FileInfo) (syncFile, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return syncFile{}, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return syncFile{}, err
+	}
+	return syncFile{hash: hex.EncodeToString(h.Sum(nil)), size: info.Size(), mode: info.Mode().Perm(), path: p}, nil
+}
+
+func syncExcluded(name string, exclude []string) bool {
+	for _, pattern := range exclude {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sha256Script picks sha256sum (Linux) or shasum (macOS) as $h
+const sha256Script = `if command -v sha256sum >/dev/null 2>&1; then h=sha256sum; else h="shasum -a 256"; fi; `
+
+// remoteKind returns "dir", "file" or "" (missing) for a remote path
+func (c *Connection) remoteKind(remotePath string) (string, error) {
+	p := RemoteQuote(remotePath)
+	out, err := c.RunCommand(remoteShell(fmt.Sprintf(`if [ -d %[1]s ]; then echo dir; elif [ -f %[1]s ]; then echo file; fi`, p)))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// remoteManifest hashes the remote files under tree. With names, only those
+// files (relative to tree.dir) are hashed. A missing directory is empty.
+func (c *Connection) remoteManifest(tree syncTree, exclude []string, names ...string) (map[string]syncFile, error) {
+	if tree.file != "" {
+		names = []string{tree.file}
+	}
+	var script string
+	var stdin io.Reader = strings.NewReader("")
+	if len(names) > 0 {
+		script = `while IFS= read -r f; do if [ -f "$f" ]; then $h "$f" && wc -c < "$f"; fi; done`
+		stdin = strings.NewReader(strings.Join(names, "\n") + "\n")
+	} else {
+		prune := ""
+		if len(exclude) > 0 {
+			var parts []string
+			for _, pattern := range exclude {
+				parts = append(parts, "-name "+ShellQuote(pattern))
+			}
+			prune = `\( ` + strings.Join(parts, " -o ") + ` \) -prune -o `
+		}
+		script = `find . -mindepth 1 ` + prune + `-type f -print | while IFS= read -r f; do $h "$f" && wc -c < "$f"; done`
+	}
+	script = fmt.Sprintf("cd %s 2>/dev/null || exit 0; %s%s", RemoteQuote(tree.dir), sha256Script, script)
+	out, err := c.RunCommandWithStdin(remoteShell(script), stdin)
+	if err != nil {
+		return nil, err
+	}
+	return parseRemoteManifest(out), nil
+}
+
+// parseRemoteManifest parses pairs of "<sha256>  <path>" and "<size>" lines.
+// Paths that aren't local (absolute, or climbing out with ..) are dropped so
+// they are never requested or written.
+func parseRemoteManifest(out string) map[string]syncFile {
+	files := make(map[string]syncFile)
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		hash, name, ok := strings.Cut(lines[i], "  ")
+		// sha256sum prefixes escaped names with a backslash; skip them
+		if !ok || strings.HasPrefix(hash, `\`) {
+			continue
+		}
+		name = strings.TrimPrefix(name, "./")
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			continue
+		}
+		var size int64
+		fmt.Sscan(strings.TrimSpace(lines[i+1]), &size)
+		files[name] = syncFile{hash: hash, size: size}
+	}
+	return files
+}
+
+// pushFiles sends the named local files to remoteDir as a tar stream
+func (c *Connection) pushFiles(remoteDir string, local map[string]syncFile, names []string) error {
+	pr, pw := io.Pipe()
+	go func() {
+		tw := tar.NewWriter(pw)
+		for _, name := range names {
+			if err := writeTarFile(tw, name, local[name]); err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+		pw.CloseWithError(tw.Close())
+	}()
+	script := fmt.Sprintf("mkdir -p %[1]s && cd %[1]s && tar xf -", RemoteQuote(remoteDir))
+	_, err := c.RunCommandWithStdin(remoteShell(script), pr)
+	_ = pr.Close()
+	if err != nil {
+		return fmt.Errorf("upload failed: %w", err)
+	}
+	return nil
+}
+
+func writeTarFile(tw *tar.Writer, name string, file syncFile) error {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    int64(info.Mode().Perm()),
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// pullFiles receives the named remote files as a tar stream into dst,
+// checking each against the remote manifest before replacing the local file
+func (c *Connection) pullFiles(remoteDir string, names []string, dst syncTree, remote map[string]syncFile) error {
+	stream, err := c.StartStream(remoteShell(fmt.Sprintf("cd %s && tar cf - -T -", RemoteQuote(remoteDir))))
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(stream.Stdin, strings.Join(names, "\n")+"\n"); err != nil {
+		_ = stream.Kill()
+		return err
+	}
+	_ = stream.Stdin.Close()
+
+	tr := tar.NewReader(bufio.NewReader(stream.Stdout))
+	received := 0
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = stream.Kill()
+			return fmt.Errorf("download failed: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := strings.TrimPrefix(hdr.Name, "./")
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			_ = stream.Kill()
+			return fmt.Errorf("download failed: unsafe path %q", hdr.Name)
+		}
+		if dst.file != "" {
+			name = dst.file
+		}
+		want, ok := remote[name]
+		if !ok {
+			_ = stream.Kill()
+			return fmt.Errorf("download failed: unexpected file %q", hdr.Name)
+		}
+		target := filepath.Join(dst.dir, filepath.FromSlash(name))
+		if err := receiveFile(tr, target, os.FileMode(hdr.Mode).Perm(), want.hash); err != nil {
+			_ = stream.Kill()
+			return err
+		}
+		received++
+	}
+	if err := stream.Wait(); err != nil {
+		return fmt.Errorf("download failed: %w", err)
+	}
+	if received != len(names) {
+		return fmt.Errorf("download failed: received %d of %d files", received, len(names))
+	}
+	return nil
+}
+
+// receiveFile writes r to a temp file next to target and renames it into
+// place only if its sha256 matches want
+func receiveFile(r io.Reader, target string, mode os.FileMode, want string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != want {
+		return fmt.Errorf("checksum mismatch after download: %s", filepath.Base(target))
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), target)
+}
+
+// RemoteQuote quotes a remote path for sh, expanding a leading ~/ to "$HOME"
+func RemoteQuote(p string) string {
+	if p == "~" {
+		return `"$HOME"`
+	}
+	if strings.HasPrefix(p, "~/") {
+		return `"$HOME"/` + ShellQuote(p[2:])
+	}
+	return ShellQuote(p)
+}
+
+// remoteShell runs script under sh, whatever the remote login shell is
+func remoteShell(script string) string {
+	return "sh -c " + ShellQuote(script)
+}
@@ -0,0 +1,183 @@
+package ssh
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeSyncFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readSyncFile(t *testing.T, p string) string {
+	t.Helper()
+	data, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// newSyncTestConnection connects to an in-process server whose "remote"
+// filesystem is the local one
+func newSyncTestConnection(t *testing.T) *Connection {
+	t.Helper()
+	identity, pub := newTestClientKey(t)
+	srv := startTestSSHServer(t, pub)
+	conn := NewConnection(nativeTestConfig(t, srv, identity))
+	t.Cleanup(func() { _ = conn.CloseControlMaster() })
+	return conn
+}
+
+func TestPushPull_Incremental(t *testing.T) {
+	conn := newSyncTestConnection(t)
+	local := t.TempDir()
+	remote := filepath.Join(t.TempDir(), "project")
+
+	writeSyncFiles(t, local, map[string]string{
+		"main.go":        "package main\n",
+		"docs/README.md": "# readme\n",
+		".git/HEAD":      "ref: refs/heads/main\n",
+	})
+
+	result, err := conn.Push(local, remote, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if want := []string{"docs/README.md", "main.go"}; !reflect.DeepEqual(result.Transferred, want) {
+		t.Errorf("Transferred = %v, want %v", result.Transferred, want)
+	}
+	if got := readSyncFile(t, filepath.Join(remote, "docs", "README.md")); got != "# readme\n" {
+		t.Errorf("remote README = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(remote, ".git")); !os.IsNotExist(err) {
+		t.Error(".git should be excluded by default")
+	}
+
+	// Only the changed file is sent the second time
+	writeSyncFiles(t, local, map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	result, err = conn.Push(local, remote, SyncOptions{})
+	if err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if !reflect.DeepEqual(result.Transferred, []string{"main.go"}) || result.Unchanged != 1 {
+		t.Errorf("second push = %+v, want only main.go", result)
+	}
+
+	// Pull remote changes back, deleting local extras
+	writeSyncFiles(t, remote, map[string]string{"out/result.txt": "done\n"})
+	writeSyncFiles(t, local, map[string]string{"scratch.txt": "local only\n"})
+	dry, err := conn.Pull(remote, local, SyncOptions{Delete: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Pull dry run: %v", err)
+	}
+	if !reflect.DeepEqual(dry.Transferred, []string{"out/result.txt"}) || !reflect.DeepEqual(dry.Deleted, []string{"scratch.txt"}) {
+		t.Errorf("dry run = %+v", dry)
+	}
+	if _, err := os.Stat(filepath.Join(local, "out")); !os.IsNotExist(err) {
+		t.Error("dry run must not transfer")
+	}
+
+	if _, err := conn.Pull(remote, local, SyncOptions{Delete: true}); err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if got := readSyncFile(t, filepath.Join(local, "out", "result.txt")); got != "done\n" {
+		t.Errorf("pulled file = %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(local, "scratch.txt")); !os.IsNotExist(err) {
+		t.Error("--delete should remove local files missing on the remote")
+	}
+	if _, err := os.Stat(filepath.Join(local, ".git", "HEAD")); err != nil {
+		t.Error("excluded paths must not be deleted")
+	}
+}
+
+func TestPushPull_SingleFile(t *testing.T) {
+	conn := newSyncTestConnection(t)
+	local := t.TempDir()
+	remote := t.TempDir()
+	writeSyncFiles(t, local, map[string]string{"notes.txt": "hello\n"})
+
+	// Renamed on the way
+	if _, err := conn.Push(filepath.Join(local, "notes.txt"), filepath.Join(remote, "renamed.txt"), SyncOptions{}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+	if got := readSyncFile(t, filepath.Join(remote, "renamed.txt")); got != "hello\n" {
+		t.Errorf("remote file = %q", got)
+	}
+
+	// Into a directory keeps the name
+	if _, err := conn.Push(filepath.Join(local, "notes.txt"), remote, SyncOptions{}); err != nil {
+		t.Fatalf("Push to dir: %v", err)
+	}
+	if got := readSyncFile(t, filepath.Join(remote, "notes.txt")); got != "hello\n" {
+		t.Errorf("remote file = %q", got)
+	}
+
+	writeSyncFiles(t, remote, map[string]string{"renamed.txt": "changed\n"})
+	result, err := conn.Pull(filepath.Join(remote, "renamed.txt"), filepath.Join(local, "notes.txt"), SyncOptions{})
+	if err != nil {
+		t.Fatalf("Pull: %v", err)
+	}
+	if !reflect.DeepEqual(result.Transferred, []string{"notes.txt"}) {
+		t.Errorf("Transferred = %v", result.Transferred)
+	}
+	if got := readSyncFile(t, filepath.Join(local, "notes.txt")); got != "changed\n" {
+		t.Errorf("local file = %q", got)
+	}
+
+	if _, err := conn.Pull(filepath.Join(remote, "missing.txt"), local, SyncOptions{}); err == nil || !strings.Contains(err.Error(), "no such file") {
+		t.Errorf("Pull missing = %v", err)
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	conn := newSyncTestConnection(t)
+	p := filepath.Join(t.TempDir(), "img.png")
+	if err := conn.WriteFile(p, strings.NewReader("data")); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	info, err := os.Stat(p)
+	if err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("WriteFile mode = %v, %v", info, err)
+	}
+}
+
+func TestRemoteQuote(t *testing.T) {
+	tests := map[string]string{
+		"~":             `"$HOME"`,
+		"~/my dir":      `"$HOME"/'my dir'`,
+		"/srv/it's":     `'/srv/it'\''s'`,
+		"~user/project": `'~user/project'`,
+	}
+	for in, want := range tests {
+		if got := RemoteQuote(in); got != want {
+			t.Errorf("RemoteQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestParseRemoteManifest_DropsNonLocalPaths(t *testing.T) {
+	out := strings.Join([]string{
+		"aaa  ./src/main.go", "12",
+		"bbb  ./../outside", "3",
+		"ccc  /etc/passwd", "4",
+		"ddd  ./docs/../../up", "5",
+	}, "\n") + "\n"
+	files := parseRemoteManifest(out)
+	if len(files) != 1 || files["src/main.go"].hash != "aaa" || files["src/main.go"].size != 12 {
+		t.Errorf("parseRemoteManifest = %+v, want only src/main.go", files)
+	}
+}
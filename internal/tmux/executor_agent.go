@@ -0,0 +1,270 @@
+//go:build !windows
+// +build !windows
+
+package tmux
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/ssh"
+)
+
+// Remote agent lifecycle
+//
+// SSHExecutor upgrades to the remote agent transparently: the first call on a
+// host starts "agent-deck remote-agent" over ssh and keeps it running. If the
+// binary is missing or the handshake fails, calls fall back to one ssh command
+// per operation and the agent is retried after remoteAgentRetryInterval.
+
+const (
+	// remoteAgentHelloTimeout covers ssh connection setup plus agent startup
+	remoteAgentHelloTimeout = 10 * time.Second
+	// remoteAgentRetryInterval is how long a host without a usable agent uses plain ssh
+	remoteAgentRetryInterval = 5 * time.Minute
+	// remotePaneCacheTTL is how long StatusBatch pane content serves CapturePane
+	remotePaneCacheTTL = 1 * time.Second
+)
+
+// remoteAgentHost tracks the agent connection for one SSH host
+type remoteAgentHost struct {
+	mu       sync.Mutex
+	client   *RemoteAgentClient
+	starting bool
+	retryAt  time.Time
+}
+
+var (
+	remoteAgentsMu sync.Mutex
+	remoteAgents   = make(map[string]*remoteAgentHost) // hostID -> agent
+
+	remotePaneCacheMu sync.Mutex
+	remotePaneCache   = make(map[remotePaneKey]remotePaneEntry)
+)
+
+type remotePaneKey struct {
+	hostID  string
+	session string
+}
+
+type remotePaneEntry struct {
+	content   string
+	fetchedAt time.Time
+}
+
+func getRemoteAgentHost(hostID string) *remoteAgentHost {
+	remoteAgentsMu.Lock()
+	defer remoteAgentsMu.Unlock()
+	host, ok := remoteAgents[hostID]
+	if !ok {
+		host = &remoteAgentHost{}
+		remoteAgents[hostID] = host
+	}
+	return host
+}
+
+// agentProcess closes the agent's stdin and reaps the remote command
+type agentProcess struct {
+	io.WriteCloser
+	stream *ssh.Stream
+}
+
+func (p *agentProcess) Close() error {
+	err := p.WriteCloser.Close()
+	go func() {
+		// The agent exits on stdin EOF; don't wait forever on a wedged connection
+		timer := time.AfterFunc(5*time.Second, func() { _ = p.stream.Kill() })
+		_ = p.stream.Wait()
+		timer.Stop()
+	}()
+	return err
+}
+
+// startRemoteAgent launches agent-deck remote-agent on the host and handshakes
+func startRemoteAgent(conn *ssh.Connection, tmuxCmd string) (*RemoteAgentClient, error) {
+	command := "agent-deck remote-agent"
+	if tmuxCmd != "" && tmuxCmd != "tmux" {
+		command += " --tmux-path " + ssh.ShellQuote(tmuxCmd)
+	}
+	stream, err := conn.StartStream(command)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewRemoteAgentClient(stream.Stdout, &agentProcess{WriteCloser: stream.Stdin, stream: stream}, remoteAgentHelloTimeout)
+	if err != nil {
+		_ = stream.Kill()
+		return nil, err
+	}
+	return client, nil
+}
+
+// agent returns the host's remote agent, starting it if needed.
+// Returns nil when the caller should fall back to plain ssh commands.
+func (e *SSHExecutor) agent() *RemoteAgentClient {
+	host := getRemoteAgentHost(e.hostID)
+
+	host.mu.Lock()
+	if host.client != nil {
+		select {
+		case <-host.client.Done():
+			log.Printf("[REMOTE-AGENT] %s: connection lost, reconnecting", e.hostID)
+			host.client = nil
+		default:
+			client := host.client
+			host.mu.Unlock()
+			return client
+		}
+	}
+	// Concurrent callers use ssh while the agent starts instead of blocking
+	if host.starting || time.Now().Before(host.retryAt) {
+		host.mu.Unlock()
+		return nil
+	}
+	host.starting = true
+	host.mu.Unlock()
+
+	client, err := startRemoteAgent(e.conn, e.tmuxCmd)
+
+	host.mu.Lock()
+	defer host.mu.Unlock()
+	host.starting = false
+	if err != nil {
+		log.Printf("[REMOTE-AGENT] %s: agent unavailable, using ssh commands: %v", e.hostID, err)
+		host.retryAt = time.Now().Add(remoteAgentRetryInterval)
+		return nil
+	}
+	log.Printf("[REMOTE-AGENT] %s: connected to agent-deck %s", e.hostID, client.Version())
+	host.client = client
+	return client
+}
+
+// UsingRemoteAgent reports whether calls to this host go through the remote agent
+func (e *SSHExecutor) UsingRemoteAgent() bool {
+	host := getRemoteAgentHost(e.hostID)
+	host.mu.Lock()
+	defer host.mu.Unlock()
+	if host.client == nil {
+		return false
+	}
+	select {
+	case <-host.client.Done():
+		return false
+	default:
+		return true
+	}
+}
+
+// CloseRemoteAgents shuts down all remote agent connections
+func CloseRemoteAgents() {
+	remoteAgentsMu.Lock()
+	defer remoteAgentsMu.Unlock()
+	for hostID, host := range remoteAgents {
+		host.mu.Lock()
+		if host.client != nil {
+			_ = host.client.Close()
+		}
+		host.mu.Unlock()
+		delete(remoteAgents, hostID)
+	}
+}
+
+// StatusBatch fetches existence, activity and pane content for many sessions
+// on this host in a single agent round trip. Results also refresh the remote
+// session cache and serve CapturePane for a short time, so a status tick
+// costs one round trip per host rather than several ssh commands per session.
+// Returns an error when no agent is available; callers then poll per session.
+func (e *SSHExecutor) StatusBatch(sessions []string) ([]AgentSessionStatus, error) {
+	client := e.agent()
+	if client == nil {
+		return nil, fmt.Errorf("remote agent not available on %s", e.hostID)
+	}
+	statuses, err := client.StatusBatch(sessions, true)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	remoteSessionCacheMu.Lock()
+	cached := make(map[string]int64)
+	if cache, ok := remoteSessionCaches[e.hostID]; ok {
+		for name, activity := range cache.sessions {
+			cached[name] = activity
+		}
+	}
+	for _, st := range statuses {
+		if st.Exists {
+			cached[st.Name] = st.Activity
+		} else {
+			delete(cached, st.Name)
+		}
+	}
+	remoteSessionCaches[e.hostID] = &remoteSessionCache{sessions: cached, updatedAt: now}
+	remoteSessionCacheMu.Unlock()
+
+	remotePaneCacheMu.Lock()
+	for _, st := range statuses {
+		key := remotePaneKey{hostID: e.hostID, session: st.Name}
+		if st.Exists && st.Error == "" {
+			remotePaneCache[key] = remotePaneEntry{content: st.Content, fetchedAt: now}
+		} else {
+			delete(remotePaneCache, key)
+		}
+	}
+	remotePaneCacheMu.Unlock()
+
+	return statuses, nil
+}
+
+// cachedPane returns pane content prefetched by StatusBatch, if still fresh
+func (e *SSHExecutor) cachedPane(session string) (string, bool) {
+	remotePaneCacheMu.Lock()
+	defer remotePaneCacheMu.Unlock()
+	entry, ok := remotePaneCache[remotePaneKey{hostID: e.hostID, session: session}]
+	if !ok || time.Since(entry.fetchedAt) > remotePaneCacheTTL {
+		return "", false
+	}
+	return entry.content, true
+}
+
+// PrefetchRemoteStatus batches status queries for remote sessions, grouped by
+// host, ahead of a status update pass. Hosts without an agent are skipped.
+func PrefetchRemoteStatus(sessionsByHost map[string][]string) {
+	var wg sync.WaitGroup
+	for hostID, names := range sessionsByHost {
+		if len(names) == 0 {
+			continue
+		}
+		e, err := NewSSHExecutorFromPool(hostID)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(e *SSHExecutor, names []string) {
+			defer wg.Done()
+			_, _ = e.StatusBatch(names)
+		}(e, names)
+	}
+	wg.Wait()
+}
+
+// viaAgent runs fn against the host's remote agent. handled is false when no
+// agent is available or the request never reached it (the channel was
+// already closed or the write failed); the caller then uses ssh commands.
+// Any other error is returned as is: after a timeout or a channel failure
+// mid-call the agent may have run the request, and replaying SendKeys,
+// NewSession or RespawnPane over ssh would type or start things twice.
+func (e *SSHExecutor) viaAgent(fn func(*RemoteAgentClient) error) (handled bool, err error) {
+	client := e.agent()
+	if client == nil {
+		return false, nil
+	}
+	err = fn(client)
+	if err != nil && IsRemoteAgentNotSent(err) {
+		log.Printf("[REMOTE-AGENT] %s: %v; falling back to ssh", e.hostID, err)
+		return false, nil
+	}
+	return true, err
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"os/signal"
 	"strings"
 	"sync"
@@ -129,6 +128,12 @@ func (e *SSHExecutor) NewSession(name, workDir string) error {
 	if workDir == "" {
 		workDir = "~"
 	}
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.NewSession(name, workDir) }); ok {
+		if err != nil {
+			return fmt.Errorf("failed to create remote tmux session: %w", err)
+		}
+		return nil
+	}
 	cmd := fmt.Sprintf("%s new-session -d -s %q -c %q", e.tmuxCmd, name, workDir)
 	_, err := e.runRemote(cmd)
 	if err != nil {
@@ -139,6 +144,9 @@ func (e *SSHExecutor) NewSession(name, workDir string) error {
 
 // KillSession terminates a tmux session on the remote host
 func (e *SSHExecutor) KillSession(name string) error {
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.KillSession(name) }); ok {
+		return err
+	}
 	cmd := fmt.Sprintf("%s kill-session -t %q", e.tmuxCmd, name)
 	_, err := e.runRemote(cmd)
 	return err
@@ -152,7 +160,14 @@ func (e *SSHExecutor) SessionExists(name string) (bool, error) {
 		return true, nil
 	}
 
-	// Cache miss - make SSH call
+	// Cache miss - ask the agent, or make an SSH call
+	var exists bool
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) (err error) {
+		exists, err = a.SessionExists(name)
+		return err
+	}); ok {
+		return exists, err
+	}
 	cmd := fmt.Sprintf("%s has-session -t %q 2>/dev/null && echo exists || echo notfound", e.tmuxCmd, name)
 	output, err := e.runRemote(cmd)
 	if err != nil {
@@ -163,13 +178,20 @@ func (e *SSHExecutor) SessionExists(name string) (bool, error) {
 
 // ListSessions returns all sessions with their window activity timestamps
 func (e *SSHExecutor) ListSessions() (map[string]int64, error) {
+	var sessions map[string]int64
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) (err error) {
+		sessions, err = a.ListSessions()
+		return err
+	}); ok {
+		return sessions, err
+	}
 	cmd := fmt.Sprintf("%s list-windows -a -F '#{session_name}\t#{window_activity}' 2>/dev/null || echo ''", e.tmuxCmd)
 	output, err := e.runRemote(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	sessions := make(map[string]int64)
+	sessions = make(map[string]int64)
 	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
 		if line == "" {
 			continue
@@ -190,6 +212,9 @@ func (e *SSHExecutor) ListSessions() (map[string]int64, error) {
 
 // SendKeys sends keys to a session on the remote host
 func (e *SSHExecutor) SendKeys(session, keys string, literal bool) error {
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.SendKeys(session, keys, literal) }); ok {
+		return err
+	}
 	var cmd string
 	if literal {
 		// Escape the keys for shell and tmux
@@ -204,6 +229,22 @@ func (e *SSHExecutor) SendKeys(session, keys string, literal bool) error {
 
 // CapturePane captures the visible content of a pane on the remote host
 func (e *SSHExecutor) CapturePane(session string, joinWrapped bool) (string, error) {
+	// Status polling captures with -J; serve it from a recent StatusBatch
+	if joinWrapped {
+		if content, ok := e.cachedPane(session); ok {
+			return content, nil
+		}
+	}
+	var content string
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) (err error) {
+		content, err = a.CapturePane(session, joinWrapped)
+		return err
+	}); ok {
+		if err != nil {
+			return "", fmt.Errorf("failed to capture remote pane: %w", err)
+		}
+		return content, nil
+	}
 	joinFlag := ""
 	if joinWrapped {
 		joinFlag = " -J"
@@ -218,6 +259,16 @@ func (e *SSHExecutor) CapturePane(session string, joinWrapped bool) (string, err
 
 // CapturePaneHistory captures scrollback history from the remote host
 func (e *SSHExecutor) CapturePaneHistory(session string, lines int) (string, error) {
+	var content string
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) (err error) {
+		content, err = a.CapturePaneHistory(session, lines)
+		return err
+	}); ok {
+		if err != nil {
+			return "", fmt.Errorf("failed to capture remote history: %w", err)
+		}
+		return content, nil
+	}
 	cmd := fmt.Sprintf("%s capture-pane -t %q -p -J -S -%d", e.tmuxCmd, session, lines)
 	output, err := e.runRemote(cmd)
 	if err != nil {
@@ -228,6 +279,12 @@ func (e *SSHExecutor) CapturePaneHistory(session string, lines int) (string, err
 
 // RespawnPane kills the current process and starts a new command on the remote host
 func (e *SSHExecutor) RespawnPane(session, command string) error {
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.RespawnPane(session, command) }); ok {
+		if err != nil {
+			return fmt.Errorf("failed to respawn remote pane: %w", err)
+		}
+		return nil
+	}
 	target := session + ":"
 	var cmd string
 	if command != "" {
@@ -246,6 +303,9 @@ func (e *SSHExecutor) RespawnPane(session, command string) error {
 
 // SetOption sets a tmux option for a session on the remote host
 func (e *SSHExecutor) SetOption(session, option, value string) error {
+	if ok, _ := e.viaAgent(func(a *RemoteAgentClient) error { return a.SetOption(session, option, value) }); ok {
+		return nil
+	}
 	cmd := fmt.Sprintf("%s set-option -t %q %s %q 2>/dev/null || true", e.tmuxCmd, session, option, value)
 	e.runRemoteIgnoreError(cmd)
 	return nil
@@ -253,6 +313,9 @@ func (e *SSHExecutor) SetOption(session, option, value string) error {
 
 // SetServerOption sets a server-wide tmux option on the remote host
 func (e *SSHExecutor) SetServerOption(option, value string) error {
+	if ok, _ := e.viaAgent(func(a *RemoteAgentClient) error { return a.SetServerOption(option, value) }); ok {
+		return nil
+	}
 	cmd := fmt.Sprintf("%s set -asq %s %q 2>/dev/null || true", e.tmuxCmd, option, value)
 	e.runRemoteIgnoreError(cmd)
 	return nil
@@ -260,6 +323,9 @@ func (e *SSHExecutor) SetServerOption(option, value string) error {
 
 // SetEnvironment sets an environment variable for a session on the remote host
 func (e *SSHExecutor) SetEnvironment(session, key, value string) error {
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.SetEnvironment(session, key, value) }); ok {
+		return err
+	}
 	cmd := fmt.Sprintf("%s set-environment -t %q %s %q", e.tmuxCmd, session, key, value)
 	_, err := e.runRemote(cmd)
 	return err
@@ -267,6 +333,16 @@ func (e *SSHExecutor) SetEnvironment(session, key, value string) error {
 
 // GetEnvironment gets an environment variable from a session on the remote host
 func (e *SSHExecutor) GetEnvironment(session, key string) (string, error) {
+	var value string
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) (err error) {
+		value, err = a.GetEnvironment(session, key)
+		return err
+	}); ok {
+		if err != nil {
+			return "", fmt.Errorf("variable not found: %s", key)
+		}
+		return value, nil
+	}
 	cmd := fmt.Sprintf("%s show-environment -t %q %s 2>/dev/null", e.tmuxCmd, session, key)
 	output, err := e.runRemote(cmd)
 	if err != nil {
@@ -282,6 +358,16 @@ func (e *SSHExecutor) GetEnvironment(session, key string) (string, error) {
 
 // DisplayMessage runs tmux display-message on the remote host
 func (e *SSHExecutor) DisplayMessage(session, format string) (string, error) {
+	var output string
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) (err error) {
+		output, err = a.DisplayMessage(session, format)
+		return err
+	}); ok {
+		if err != nil {
+			return "", fmt.Errorf("failed to display message: %w", err)
+		}
+		return strings.TrimSpace(output), nil
+	}
 	cmd := fmt.Sprintf("%s display-message -t %q -p %q", e.tmuxCmd, session, format)
 	output, err := e.runRemote(cmd)
 	if err != nil {
@@ -293,6 +379,9 @@ func (e *SSHExecutor) DisplayMessage(session, format string) (string, error) {
 // EnablePipePane enables pipe-pane on the remote host
 // Note: This logs to a file on the REMOTE host, not locally
 func (e *SSHExecutor) EnablePipePane(session, outputFile string) error {
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.EnablePipePane(session, outputFile) }); ok {
+		return err
+	}
 	// Create log directory on remote
 	logDir := "~/.agent-deck/logs"
 	e.runRemoteIgnoreError(fmt.Sprintf("mkdir -p %s", logDir))
@@ -304,6 +393,9 @@ func (e *SSHExecutor) EnablePipePane(session, outputFile string) error {
 
 // DisablePipePane disables pipe-pane on the remote host
 func (e *SSHExecutor) DisablePipePane(session string) error {
+	if ok, err := e.viaAgent(func(a *RemoteAgentClient) error { return a.DisablePipePane(session) }); ok {
+		return err
+	}
 	cmd := fmt.Sprintf("%s pipe-pane -t %q", e.tmuxCmd, session)
 	_, err := e.runRemote(cmd)
 	return err
@@ -330,26 +422,24 @@ func (e *SSHExecutor) Attach(ctx context.Context, session string, stdin io.Reade
 	// Disable tmux status bar to prevent UI leakage
 	e.disableStatusBar(session)
 
-	// Build SSH command for interactive attach
-	// ssh -t user@host tmux attach-session -t session
-	sshCmd, err := e.conn.StartInteractiveSession(fmt.Sprintf("%s attach-session -t %q", e.tmuxCmd, session))
-	if err != nil {
-		return fmt.Errorf("failed to start SSH session: %w", err)
-	}
-
-	// Start command with PTY
-	ptmx, err := pty.Start(sshCmd)
-	if err != nil {
-		return fmt.Errorf("failed to start pty: %w", err)
-	}
-	defer func() { _ = ptmx.Close() }()
-
 	// Get stdin as file for raw mode
 	stdinFile, ok := stdin.(*os.File)
 	if !ok {
 		stdinFile = os.Stdin
 	}
 
+	// Start an interactive attach with a PTY sized like the local terminal
+	// (ssh -t user@host tmux attach-session -t session, or the native equivalent)
+	cols, rows := 80, 24
+	if ws, err := pty.GetsizeFull(stdinFile); err == nil {
+		cols, rows = int(ws.Cols), int(ws.Rows)
+	}
+	ptmx, err := e.conn.OpenInteractive(fmt.Sprintf("%s attach-session -t %q", e.tmuxCmd, session), cols, rows)
+	if err != nil {
+		return fmt.Errorf("failed to start SSH session: %w", err)
+	}
+	defer func() { _ = ptmx.Close() }()
+
 	// Save original terminal state and set raw mode
 	oldState, err := term.MakeRaw(int(stdinFile.Fd()))
 	if err != nil {
@@ -381,7 +471,7 @@ func (e *SSHExecutor) Attach(ctx context.Context, session string, stdin io.Reade
 					return
 				}
 				if ws, err := pty.GetsizeFull(stdinFile); err == nil {
-					_ = pty.Setsize(ptmx, ws)
+					_ = ptmx.Resize(int(ws.Cols), int(ws.Rows))
 				}
 			}
 		}
@@ -456,7 +546,7 @@ func (e *SSHExecutor) Attach(ctx context.Context, session string, stdin io.Reade
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		cmdDone <- sshCmd.Wait()
+		cmdDone <- ptmx.Wait()
 	}()
 
 	// Wait for detach or completion
@@ -465,10 +555,8 @@ func (e *SSHExecutor) Attach(ctx context.Context, session string, stdin io.Reade
 		return nil
 	case err := <-cmdDone:
 		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				if exitErr.ExitCode() == 0 || exitErr.ExitCode() == 1 {
-					return nil
-				}
+			if code, ok := ssh.ExitCode(err); ok && (code == 0 || code == 1) {
+				return nil
 			}
 			if ctx.Err() != nil {
 				return nil
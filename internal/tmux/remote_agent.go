@@ -0,0 +1,555 @@
+package tmux
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Remote agent protocol
+//
+// "agent-deck remote-agent" runs on an SSH host and serves TmuxExecutor calls
+// over its stdin/stdout, so a single long-lived SSH channel replaces one ssh
+// process per tmux command. Every message is a frame: a 4-byte big-endian
+// length followed by that many bytes of JSON. The agent first sends an
+// AgentHello frame, then answers AgentRequest frames with AgentResponse
+// frames carrying the same ID. Requests may be answered out of order.
+
+// RemoteAgentProtocolVersion is bumped on incompatible protocol changes
+const RemoteAgentProtocolVersion = 1
+
+// maxAgentFrameSize bounds a single frame (pane captures with deep history can be large)
+const maxAgentFrameSize = 32 << 20
+
+// Remote agent methods, one per TmuxExecutor call plus batched status
+const (
+	AgentMethodNewSession         = "new_session"
+	AgentMethodKillSession        = "kill_session"
+	AgentMethodSessionExists      = "session_exists"
+	AgentMethodListSessions       = "list_sessions"
+	AgentMethodSendKeys           = "send_keys"
+	AgentMethodCapturePane        = "capture_pane"
+	AgentMethodCapturePaneHistory = "capture_pane_history"
+	AgentMethodRespawnPane        = "respawn_pane"
+	AgentMethodSetOption          = "set_option"
+	AgentMethodSetServerOption    = "set_server_option"
+	AgentMethodSetEnvironment     = "set_environment"
+	AgentMethodGetEnvironment     = "get_environment"
+	AgentMethodDisplayMessage     = "display_message"
+	AgentMethodEnablePipePane     = "enable_pipe_pane"
+	AgentMethodDisablePipePane    = "disable_pipe_pane"
+	AgentMethodStatusBatch        = "status_batch"
+)
+
+// AgentHello is the first frame sent by the agent
+type AgentHello struct {
+	Protocol int    `json:"protocol"`
+	Version  string `json:"version"`
+}
+
+// AgentRequest is a call from agent-deck to the remote agent
+type AgentRequest struct {
+	ID     uint64      `json:"id"`
+	Method string      `json:"method"`
+	Params AgentParams `json:"params"`
+}
+
+// AgentParams carries the arguments of every method; unused fields are omitted
+type AgentParams struct {
+	Session     string   `json:"session,omitempty"`
+	Sessions    []string `json:"sessions,omitempty"`
+	WorkDir     string   `json:"work_dir,omitempty"`
+	Keys        string   `json:"keys,omitempty"`
+	Literal     bool     `json:"literal,omitempty"`
+	JoinWrapped bool     `json:"join_wrapped,omitempty"`
+	Lines       int      `json:"lines,omitempty"`
+	Command     string   `json:"command,omitempty"`
+	Option      string   `json:"option,omitempty"`
+	Key         string   `json:"key,omitempty"`
+	Value       string   `json:"value,omitempty"`
+	Format      string   `json:"format,omitempty"`
+	OutputFile  string   `json:"output_file,omitempty"`
+}
+
+// AgentResponse answers the request with the same ID
+type AgentResponse struct {
+	ID     uint64          `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// AgentSessionStatus is one entry of a status_batch result: everything the
+// status poller needs for a session in a single round trip
+type AgentSessionStatus struct {
+	Name     string `json:"name"`
+	Exists   bool   `json:"exists"`
+	Activity int64  `json:"activity,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// writeAgentFrame writes one length-prefixed JSON frame
+func writeAgentFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxAgentFrameSize {
+		return fmt.Errorf("frame too large: %d bytes", len(data))
+	}
+	var header [4]byte
+	binary.BigEndian.PutUint32(header[:], uint32(len(data)))
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// readAgentFrame reads one length-prefixed JSON frame into v
+func readAgentFrame(r io.Reader, v interface{}) error {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > maxAgentFrameSize {
+		return fmt.Errorf("frame too large: %d bytes", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// ServeRemoteAgent serves the remote agent protocol on r/w using exec for
+// tmux calls, until r is closed. Requests are handled concurrently.
+func ServeRemoteAgent(r io.Reader, w io.Writer, exec TmuxExecutor, version string) error {
+	reader := bufio.NewReader(r)
+	writer := bufio.NewWriter(w)
+	var writeMu sync.Mutex
+
+	send := func(v interface{}) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		if err := writeAgentFrame(writer, v); err != nil {
+			return err
+		}
+		return writer.Flush()
+	}
+
+	if err := send(AgentHello{Protocol: RemoteAgentProtocolVersion, Version: version}); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		var req AgentRequest
+		if err := readAgentFrame(reader, &req); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func(req AgentRequest) {
+			defer wg.Done()
+			resp := AgentResponse{ID: req.ID}
+			result, err := handleAgentRequest(exec, req)
+			if err != nil {
+				resp.Error = err.Error()
+			} else if result != nil {
+				resp.Result, err = json.Marshal(result)
+				if err != nil {
+					resp.Error = err.Error()
+				}
+			}
+			_ = send(resp)
+		}(req)
+	}
+}
+
+// handleAgentRequest dispatches one request to the executor
+func handleAgentRequest(e TmuxExecutor, req AgentRequest) (interface{}, error) {
+	p := req.Params
+	p.WorkDir = expandAgentHome(p.WorkDir)
+	p.OutputFile = expandAgentHome(p.OutputFile)
+	switch req.Method {
+	case AgentMethodNewSession:
+		return nil, e.NewSession(p.Session, p.WorkDir)
+	case AgentMethodKillSession:
+		return nil, e.KillSession(p.Session)
+	case AgentMethodSessionExists:
+		return e.SessionExists(p.Session)
+	case AgentMethodListSessions:
+		sessions, err := e.ListSessions()
+		if err != nil {
+			// No tmux server running is an empty list, not an error
+			return map[string]int64{}, nil
+		}
+		return sessions, nil
+	case AgentMethodSendKeys:
+		return nil, e.SendKeys(p.Session, p.Keys, p.Literal)
+	case AgentMethodCapturePane:
+		return e.CapturePane(p.Session, p.JoinWrapped)
+	case AgentMethodCapturePaneHistory:
+		return e.CapturePaneHistory(p.Session, p.Lines)
+	case AgentMethodRespawnPane:
+		return nil, e.RespawnPane(p.Session, p.Command)
+	case AgentMethodSetOption:
+		return nil, e.SetOption(p.Session, p.Option, p.Value)
+	case AgentMethodSetServerOption:
+		return nil, e.SetServerOption(p.Option, p.Value)
+	case AgentMethodSetEnvironment:
+		return nil, e.SetEnvironment(p.Session, p.Key, p.Value)
+	case AgentMethodGetEnvironment:
+		return e.GetEnvironment(p.Session, p.Key)
+	case AgentMethodDisplayMessage:
+		return e.DisplayMessage(p.Session, p.Format)
+	case AgentMethodEnablePipePane:
+		if dir := filepath.Dir(p.OutputFile); dir != "." {
+			_ = os.MkdirAll(dir, 0755)
+		}
+		return nil, e.EnablePipePane(p.Session, p.OutputFile)
+	case AgentMethodDisablePipePane:
+		return nil, e.DisablePipePane(p.Session)
+	case AgentMethodStatusBatch:
+		return agentStatusBatch(e, p.Sessions, p.JoinWrapped), nil
+	}
+	return nil, fmt.Errorf("unknown method %q", req.Method)
+}
+
+// expandAgentHome expands a leading ~ the way the remote shell would for
+// SSHExecutor's command strings, since the agent runs tmux without a shell
+func expandAgentHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// agentStatusBatch collects existence, activity and pane content for many
+// sessions with one list-windows call plus one capture per live session
+func agentStatusBatch(e TmuxExecutor, names []string, joinWrapped bool) []AgentSessionStatus {
+	activity, _ := e.ListSessions()
+	statuses := make([]AgentSessionStatus, len(names))
+	for i, name := range names {
+		st := AgentSessionStatus{Name: name}
+		if act, ok := activity[name]; ok {
+			st.Exists = true
+			st.Activity = act
+			content, err := e.CapturePane(name, joinWrapped)
+			if err != nil {
+				st.Error = err.Error()
+			} else {
+				st.Content = content
+			}
+		}
+		statuses[i] = st
+	}
+	return statuses
+}
+
+// agentCallTimeout bounds a single request so a wedged channel can't hang the UI
+const agentCallTimeout = 30 * time.Second
+
+// ErrRemoteAgentClosed is returned for calls on a client whose channel has closed
+var ErrRemoteAgentClosed = errors.New("remote agent connection closed")
+
+// RemoteAgentError is an error reported by the agent (typically a failed tmux
+// command), as opposed to a transport failure talking to it
+type RemoteAgentError struct {
+	Method  string
+	Message string
+}
+
+func (e *RemoteAgentError) Error() string {
+	return e.Message
+}
+
+// IsRemoteAgentError reports whether err came from the agent rather than the channel
+func IsRemoteAgentError(err error) bool {
+	var agentErr *RemoteAgentError
+	return errors.As(err, &agentErr)
+}
+
+// remoteAgentNotSentError wraps a transport failure that happened before the
+// request was written to the channel, so the agent never ran it
+type remoteAgentNotSentError struct {
+	err error
+}
+
+func (e *remoteAgentNotSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *remoteAgentNotSentError) Unwrap() error {
+	return e.err
+}
+
+// IsRemoteAgentNotSent reports whether err means the request never reached
+// the agent, so it is safe to retry another way
+func IsRemoteAgentNotSent(err error) bool {
+	var notSent *remoteAgentNotSentError
+	return errors.As(err, &notSent)
+}
+
+// agentResult delivers a response, or the transport error that ended the call
+type agentResult struct {
+	resp AgentResponse
+	err  error
+}
+
+// RemoteAgentClient multiplexes TmuxExecutor calls over one agent channel
+type RemoteAgentClient struct {
+	w       io.WriteCloser
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	pending map[uint64]chan agentResult
+	nextID  uint64
+	err     error // set once the channel is closed
+
+	hello AgentHello
+	done  chan struct{}
+}
+
+// NewRemoteAgentClient performs the hello handshake on r/w and starts reading
+// responses. w is closed when the client is closed.
+func NewRemoteAgentClient(r io.Reader, w io.WriteCloser, helloTimeout time.Duration) (*RemoteAgentClient, error) {
+	reader := bufio.NewReader(r)
+
+	helloCh := make(chan error, 1)
+	var hello AgentHello
+	go func() { helloCh <- readAgentFrame(reader, &hello) }()
+	select {
+	case err := <-helloCh:
+		if err != nil {
+			_ = w.Close()
+			return nil, fmt.Errorf("remote agent handshake failed: %w", err)
+		}
+	case <-time.After(helloTimeout):
+		_ = w.Close()
+		return nil, fmt.Errorf("remote agent handshake timed out after %v", helloTimeout)
+	}
+	if hello.Protocol != RemoteAgentProtocolVersion {
+		_ = w.Close()
+		return nil, fmt.Errorf("remote agent speaks protocol %d, want %d (version %s)",
+			hello.Protocol, RemoteAgentProtocolVersion, hello.Version)
+	}
+
+	c := &RemoteAgentClient{
+		w:       w,
+		pending: make(map[uint64]chan agentResult),
+		hello:   hello,
+		done:    make(chan struct{}),
+	}
+	go c.readLoop(reader)
+	return c, nil
+}
+
+// Version returns the agent-deck version reported by the remote agent
+func (c *RemoteAgentClient) Version() string {
+	return c.hello.Version
+}
+
+// Done is closed when the channel to the agent is gone
+func (c *RemoteAgentClient) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close shuts down the channel; pending calls fail with ErrRemoteAgentClosed
+func (c *RemoteAgentClient) Close() error {
+	c.fail(ErrRemoteAgentClosed)
+	return c.w.Close()
+}
+
+func (c *RemoteAgentClient) readLoop(r io.Reader) {
+	for {
+		var resp AgentResponse
+		if err := readAgentFrame(r, &resp); err != nil {
+			if err == io.EOF {
+				err = ErrRemoteAgentClosed
+			}
+			c.fail(err)
+			return
+		}
+		c.mu.Lock()
+		ch, ok := c.pending[resp.ID]
+		delete(c.pending, resp.ID)
+		c.mu.Unlock()
+		if ok {
+			ch <- agentResult{resp: resp}
+		}
+	}
+}
+
+// fail marks the client closed and releases every pending call
+func (c *RemoteAgentClient) fail(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	for id, ch := range c.pending {
+		ch <- agentResult{err: err}
+		delete(c.pending, id)
+	}
+	close(c.done)
+}
+
+// call sends one request and decodes the result into result (if non-nil)
+func (c *RemoteAgentClient) call(method string, params AgentParams, result interface{}) error {
+	ch := make(chan agentResult, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return &remoteAgentNotSentError{err: err}
+	}
+	c.nextID++
+	id := c.nextID
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	c.writeMu.Lock()
+	err := writeAgentFrame(c.w, AgentRequest{ID: id, Method: method, Params: params})
+	c.writeMu.Unlock()
+	if err != nil {
+		// A partial frame is never decoded by the agent
+		c.fail(err)
+		return &remoteAgentNotSentError{err: err}
+	}
+
+	timer := time.NewTimer(agentCallTimeout)
+	defer timer.Stop()
+	select {
+	case res := <-ch:
+		if res.err != nil {
+			return res.err
+		}
+		resp := res.resp
+		if resp.Error != "" {
+			return &RemoteAgentError{Method: method, Message: resp.Error}
+		}
+		if result != nil && len(resp.Result) > 0 {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	case <-timer.C:
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return fmt.Errorf("remote agent %s timed out after %v", method, agentCallTimeout)
+	}
+}
+
+// NewSession creates a tmux session on the agent's host
+func (c *RemoteAgentClient) NewSession(name, workDir string) error {
+	return c.call(AgentMethodNewSession, AgentParams{Session: name, WorkDir: workDir}, nil)
+}
+
+// KillSession terminates a tmux session
+func (c *RemoteAgentClient) KillSession(name string) error {
+	return c.call(AgentMethodKillSession, AgentParams{Session: name}, nil)
+}
+
+// SessionExists checks if a tmux session exists
+func (c *RemoteAgentClient) SessionExists(name string) (bool, error) {
+	var exists bool
+	err := c.call(AgentMethodSessionExists, AgentParams{Session: name}, &exists)
+	return exists, err
+}
+
+// ListSessions returns all sessions with their window activity timestamps
+func (c *RemoteAgentClient) ListSessions() (map[string]int64, error) {
+	sessions := make(map[string]int64)
+	err := c.call(AgentMethodListSessions, AgentParams{}, &sessions)
+	return sessions, err
+}
+
+// SendKeys sends keys to a session
+func (c *RemoteAgentClient) SendKeys(session, keys string, literal bool) error {
+	return c.call(AgentMethodSendKeys, AgentParams{Session: session, Keys: keys, Literal: literal}, nil)
+}
+
+// CapturePane captures the visible content of a pane
+func (c *RemoteAgentClient) CapturePane(session string, joinWrapped bool) (string, error) {
+	var content string
+	err := c.call(AgentMethodCapturePane, AgentParams{Session: session, JoinWrapped: joinWrapped}, &content)
+	return content, err
+}
+
+// CapturePaneHistory captures scrollback history
+func (c *RemoteAgentClient) CapturePaneHistory(session string, lines int) (string, error) {
+	var content string
+	err := c.call(AgentMethodCapturePaneHistory, AgentParams{Session: session, Lines: lines}, &content)
+	return content, err
+}
+
+// RespawnPane kills the pane's process and starts command
+func (c *RemoteAgentClient) RespawnPane(session, command string) error {
+	return c.call(AgentMethodRespawnPane, AgentParams{Session: session, Command: command}, nil)
+}
+
+// SetOption sets a tmux option for a session
+func (c *RemoteAgentClient) SetOption(session, option, value string) error {
+	return c.call(AgentMethodSetOption, AgentParams{Session: session, Option: option, Value: value}, nil)
+}
+
+// SetServerOption sets a server-wide tmux option
+func (c *RemoteAgentClient) SetServerOption(option, value string) error {
+	return c.call(AgentMethodSetServerOption, AgentParams{Option: option, Value: value}, nil)
+}
+
+// SetEnvironment sets an environment variable for a session
+func (c *RemoteAgentClient) SetEnvironment(session, key, value string) error {
+	return c.call(AgentMethodSetEnvironment, AgentParams{Session: session, Key: key, Value: value}, nil)
+}
+
+// GetEnvironment gets an environment variable from a session
+func (c *RemoteAgentClient) GetEnvironment(session, key string) (string, error) {
+	var value string
+	err := c.call(AgentMethodGetEnvironment, AgentParams{Session: session, Key: key}, &value)
+	return value, err
+}
+
+// DisplayMessage runs tmux display-message
+func (c *RemoteAgentClient) DisplayMessage(session, format string) (string, error) {
+	var output string
+	err := c.call(AgentMethodDisplayMessage, AgentParams{Session: session, Format: format}, &output)
+	return output, err
+}
+
+// EnablePipePane pipes pane output to a file on the agent's host
+func (c *RemoteAgentClient) EnablePipePane(session, outputFile string) error {
+	return c.call(AgentMethodEnablePipePane, AgentParams{Session: session, OutputFile: outputFile}, nil)
+}
+
+// DisablePipePane disables pipe-pane
+func (c *RemoteAgentClient) DisablePipePane(session string) error {
+	return c.call(AgentMethodDisablePipePane, AgentParams{Session: session}, nil)
+}
+
+// StatusBatch returns existence, activity and pane content for many sessions
+// in one round trip
+func (c *RemoteAgentClient) StatusBatch(sessions []string, joinWrapped bool) ([]AgentSessionStatus, error) {
+	var statuses []AgentSessionStatus
+	err := c.call(AgentMethodStatusBatch, AgentParams{Sessions: sessions, JoinWrapped: joinWrapped}, &statuses)
+	return statuses, err
+}
@@ -0,0 +1,336 @@
+package tmux
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeExecutor is an in-memory TmuxExecutor for protocol tests
+type fakeExecutor struct {
+	mu       sync.Mutex
+	sessions map[string]int64
+	panes    map[string]string
+	env      map[string]string
+	keys     []string
+}
+
+func newFakeExecutor() *fakeExecutor {
+	return &fakeExecutor{
+		sessions: make(map[string]int64),
+		panes:    make(map[string]string),
+		env:      make(map[string]string),
+	}
+}
+
+func (f *fakeExecutor) NewSession(name, workDir string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.sessions[name]; ok {
+		return fmt.Errorf("duplicate session: %s", name)
+	}
+	f.sessions[name] = 100
+	f.panes[name] = "$ cd " + workDir
+	return nil
+}
+
+func (f *fakeExecutor) KillSession(name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.sessions, name)
+	return nil
+}
+
+func (f *fakeExecutor) SessionExists(name string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, ok := f.sessions[name]
+	return ok, nil
+}
+
+func (f *fakeExecutor) ListSessions() (map[string]int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]int64, len(f.sessions))
+	for k, v := range f.sessions {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (f *fakeExecutor) SendKeys(session, keys string, literal bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, fmt.Sprintf("%s:%s:%v", session, keys, literal))
+	return nil
+}
+
+func (f *fakeExecutor) CapturePane(session string, joinWrapped bool) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	content, ok := f.panes[session]
+	if !ok {
+		return "", fmt.Errorf("can't find session: %s", session)
+	}
+	return content, nil
+}
+
+func (f *fakeExecutor) CapturePaneHistory(session string, lines int) (string, error) {
+	return f.CapturePane(session, true)
+}
+
+func (f *fakeExecutor) RespawnPane(session, command string) error { return nil }
+
+func (f *fakeExecutor) SetOption(session, option, value string) error { return nil }
+
+func (f *fakeExecutor) SetServerOption(option, value string) error { return nil }
+
+func (f *fakeExecutor) SetEnvironment(session, key, value string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.env[session+"/"+key] = value
+	return nil
+}
+
+func (f *fakeExecutor) GetEnvironment(session, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	value, ok := f.env[session+"/"+key]
+	if !ok {
+		return "", fmt.Errorf("variable not found: %s", key)
+	}
+	return value, nil
+}
+
+func (f *fakeExecutor) DisplayMessage(session, format string) (string, error) {
+	return session + ":" + format, nil
+}
+
+func (f *fakeExecutor) EnablePipePane(session, outputFile string) error { return nil }
+
+func (f *fakeExecutor) DisablePipePane(session string) error { return nil }
+
+func (f *fakeExecutor) Attach(ctx context.Context, session string, stdin io.Reader, stdout, stderr io.Writer) error {
+	return errors.New("not supported")
+}
+
+func (f *fakeExecutor) IsRemote() bool { return false }
+
+func (f *fakeExecutor) HostID() string { return "" }
+
+// startTestAgent connects a client to ServeRemoteAgent over in-memory pipes
+func startTestAgent(t *testing.T, exec TmuxExecutor) *RemoteAgentClient {
+	t.Helper()
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+
+	served := make(chan error, 1)
+	go func() {
+		err := ServeRemoteAgent(reqR, respW, exec, "test")
+		_ = respW.Close()
+		served <- err
+	}()
+
+	client, err := NewRemoteAgentClient(respR, reqW, time.Second)
+	if err != nil {
+		t.Fatalf("NewRemoteAgentClient: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = client.Close()
+		if err := <-served; err != nil {
+			t.Errorf("ServeRemoteAgent: %v", err)
+		}
+	})
+	return client
+}
+
+func TestRemoteAgent_ExecutorCalls(t *testing.T) {
+	fake := newFakeExecutor()
+	client := startTestAgent(t, fake)
+
+	if client.Version() != "test" {
+		t.Errorf("Version() = %q, want test", client.Version())
+	}
+
+	if err := client.NewSession("alpha", "/work"); err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	err := client.NewSession("alpha", "/work")
+	if !IsRemoteAgentError(err) {
+		t.Errorf("duplicate NewSession error = %v, want RemoteAgentError", err)
+	}
+
+	exists, err := client.SessionExists("alpha")
+	if err != nil || !exists {
+		t.Errorf("SessionExists(alpha) = %v, %v", exists, err)
+	}
+	if exists, _ := client.SessionExists("missing"); exists {
+		t.Error("SessionExists(missing) = true")
+	}
+
+	if err := client.SendKeys("alpha", "ls -la", true); err != nil {
+		t.Fatalf("SendKeys: %v", err)
+	}
+	if len(fake.keys) != 1 || fake.keys[0] != "alpha:ls -la:true" {
+		t.Errorf("keys = %v", fake.keys)
+	}
+
+	content, err := client.CapturePane("alpha", true)
+	if err != nil || content != "$ cd /work" {
+		t.Errorf("CapturePane = %q, %v", content, err)
+	}
+
+	if err := client.SetEnvironment("alpha", "CLAUDE_SESSION_ID", "abc"); err != nil {
+		t.Fatalf("SetEnvironment: %v", err)
+	}
+	if v, err := client.GetEnvironment("alpha", "CLAUDE_SESSION_ID"); err != nil || v != "abc" {
+		t.Errorf("GetEnvironment = %q, %v", v, err)
+	}
+
+	if out, _ := client.DisplayMessage("alpha", "#{pane_pid}"); out != "alpha:#{pane_pid}" {
+		t.Errorf("DisplayMessage = %q", out)
+	}
+
+	sessions, err := client.ListSessions()
+	if err != nil || len(sessions) != 1 || sessions["alpha"] != 100 {
+		t.Errorf("ListSessions = %v, %v", sessions, err)
+	}
+
+	if err := client.KillSession("alpha"); err != nil {
+		t.Fatalf("KillSession: %v", err)
+	}
+	if exists, _ := client.SessionExists("alpha"); exists {
+		t.Error("session still exists after KillSession")
+	}
+}
+
+func TestRemoteAgent_StatusBatch(t *testing.T) {
+	fake := newFakeExecutor()
+	_ = fake.NewSession("a", "/a")
+	_ = fake.NewSession("b", "/b")
+	client := startTestAgent(t, fake)
+
+	statuses, err := client.StatusBatch([]string{"a", "gone", "b"}, true)
+	if err != nil {
+		t.Fatalf("StatusBatch: %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("got %d statuses, want 3", len(statuses))
+	}
+	if !statuses[0].Exists || statuses[0].Content != "$ cd /a" || statuses[0].Activity != 100 {
+		t.Errorf("statuses[0] = %+v", statuses[0])
+	}
+	if statuses[1].Exists {
+		t.Errorf("statuses[1] = %+v, want missing", statuses[1])
+	}
+	if statuses[2].Name != "b" || statuses[2].Content != "$ cd /b" {
+		t.Errorf("statuses[2] = %+v", statuses[2])
+	}
+}
+
+func TestRemoteAgent_ConcurrentCalls(t *testing.T) {
+	fake := newFakeExecutor()
+	client := startTestAgent(t, fake)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("s%d", i)
+			if err := client.NewSession(name, "/w"); err != nil {
+				errs <- err
+				return
+			}
+			if exists, err := client.SessionExists(name); err != nil || !exists {
+				errs <- fmt.Errorf("%s: exists=%v err=%v", name, exists, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestRemoteAgent_ClosedChannel(t *testing.T) {
+	client := startTestAgent(t, newFakeExecutor())
+	_ = client.Close()
+
+	select {
+	case <-client.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Done() not closed after Close()")
+	}
+	err := client.NewSession("x", "")
+	if err == nil || IsRemoteAgentError(err) {
+		t.Errorf("call on closed client = %v, want transport error", err)
+	}
+	if !IsRemoteAgentNotSent(err) {
+		t.Errorf("call on closed client = %v, want a not-sent error (safe to retry over ssh)", err)
+	}
+}
+
+func TestRemoteAgent_LostAfterSend(t *testing.T) {
+	// The agent reads the request, then the channel dies before it answers:
+	// the request may have run, so it must not count as never sent
+	reqR, reqW := io.Pipe()
+	respR, respW := io.Pipe()
+	go func() {
+		_ = writeAgentFrame(respW, AgentHello{Protocol: RemoteAgentProtocolVersion, Version: "test"})
+		var req AgentRequest
+		_ = readAgentFrame(bufio.NewReader(reqR), &req)
+		_ = respW.Close()
+	}()
+
+	client, err := NewRemoteAgentClient(respR, reqW, time.Second)
+	if err != nil {
+		t.Fatalf("NewRemoteAgentClient: %v", err)
+	}
+	defer client.Close()
+
+	err = client.SendKeys("x", "hello", true)
+	if err == nil || IsRemoteAgentError(err) {
+		t.Fatalf("SendKeys = %v, want transport error", err)
+	}
+	if IsRemoteAgentNotSent(err) {
+		t.Errorf("SendKeys = %v, must not be retried: the agent received it", err)
+	}
+}
+
+func TestRemoteAgent_HandshakeFailure(t *testing.T) {
+	// A host without agent-deck: ssh exits and stdout closes before hello
+	r, w := io.Pipe()
+	_ = w.Close()
+	_, reqW := io.Pipe()
+	if _, err := NewRemoteAgentClient(r, reqW, time.Second); err == nil {
+		t.Fatal("expected handshake error")
+	}
+}
+
+func TestExpandAgentHome(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	tests := map[string]string{
+		"~":                        home,
+		"~/.agent-deck/logs/x.log": filepath.Join(home, ".agent-deck/logs/x.log"),
+		"/tmp/x":                   "/tmp/x",
+		"~other/x":                 "~other/x",
+	}
+	for in, want := range tests {
+		if got := expandAgentHome(in); got != want {
+			t.Errorf("expandAgentHome(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// archiveDialogRows is how many archived sessions are listed at once
+const archiveDialogRows = 10
+
+// ArchiveDialog lists the profile's deleted sessions so they can be restored
+// or purged (agent-deck archive)
+type ArchiveDialog struct {
+	visible  bool
+	width    int
+	height   int
+	sessions []*session.ArchivedSession
+	cursor   int
+	offset   int
+	status   string
+	failed   bool
+}
+
+// NewArchiveDialog creates a new archive dialog
+func NewArchiveDialog() *ArchiveDialog {
+	return &ArchiveDialog{}
+}
+
+// Show opens the dialog with the archived sessions (newest first)
+func (d *ArchiveDialog) Show(sessions []*session.ArchivedSession) {
+	d.visible = true
+	d.sessions = sessions
+	d.cursor = 0
+	d.offset = 0
+	d.status = ""
+	d.failed = false
+}
+
+// Hide hides the dialog
+func (d *ArchiveDialog) Hide() {
+	d.visible = false
+	d.sessions = nil
+}
+
+// IsVisible returns whether the dialog is visible
+func (d *ArchiveDialog) IsVisible() bool {
+	return d.visible
+}
+
+// Selected returns the archived session under the cursor, or nil
+func (d *ArchiveDialog) Selected() *session.ArchivedSession {
+	if d.cursor < 0 || d.cursor >= len(d.sessions) {
+		return nil
+	}
+	return d.sessions[d.cursor]
+}
+
+// Remove drops a restored or purged session from the list
+func (d *ArchiveDialog) Remove(id string) {
+	for i, s := range d.sessions {
+		if s.Session.ID == id {
+			d.sessions = append(d.sessions[:i], d.sessions[i+1:]...)
+			break
+		}
+	}
+	if d.cursor >= len(d.sessions) && d.cursor > 0 {
+		d.cursor = len(d.sessions) - 1
+	}
+	d.clampOffset()
+}
+
+// SetStatus shows a message below the list
+func (d *ArchiveDialog) SetStatus(status string, failed bool) {
+	d.status = status
+	d.failed = failed
+}
+
+// SetSize sets the dialog dimensions
+func (d *ArchiveDialog) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// Update handles navigation keys
+func (d *ArchiveDialog) Update(msg tea.KeyMsg) (*ArchiveDialog, tea.Cmd) {
+	switch msg.String() {
+	case "down", "j":
+		if d.cursor < len(d.sessions)-1 {
+			d.cursor++
+		}
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	case "home", "g":
+		d.cursor = 0
+	case "end", "G":
+		d.cursor = len(d.sessions) - 1
+		if d.cursor < 0 {
+			d.cursor = 0
+		}
+	}
+	d.clampOffset()
+	return d, nil
+}
+
+// clampOffset keeps the cursor inside the visible window
+func (d *ArchiveDialog) clampOffset() {
+	if d.cursor < d.offset {
+		d.offset = d.cursor
+	}
+	if d.cursor >= d.offset+archiveDialogRows {
+		d.offset = d.cursor - archiveDialogRows + 1
+	}
+	if d.offset < 0 {
+		d.offset = 0
+	}
+}
+
+// View renders the dialog
+func (d *ArchiveDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorCyan)
+
+	textStyle := lipgloss.NewStyle().
+		Foreground(ColorText)
+
+	selectedStyle := lipgloss.NewStyle().
+		Foreground(ColorAccent).
+		Bold(true)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(ColorComment)
+
+	// Responsive dialog width
+	dialogWidth := 72
+	if d.width > 0 && d.width < dialogWidth+10 {
+		dialogWidth = d.width - 10
+		if dialogWidth < 40 {
+			dialogWidth = 40
+		}
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(1, 2).
+		Width(dialogWidth)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Archive (%d)", len(d.sessions))))
+	b.WriteString("\n")
+	if retention := session.GetArchiveSettings().Retention(); retention > 0 {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("Deleted sessions are purged after %d days", int(retention.Hours()/24))))
+	} else {
+		b.WriteString(dimStyle.Render("Deleted sessions are kept until purged"))
+	}
+	b.WriteString("\n\n")
+
+	if len(d.sessions) == 0 {
+		b.WriteString(dimStyle.Render("  No archived sessions"))
+		b.WriteString("\n")
+	}
+
+	end := d.offset + archiveDialogRows
+	if end > len(d.sessions) {
+		end = len(d.sessions)
+	}
+	for i := d.offset; i < end; i++ {
+		s := d.sessions[i]
+		prefix, style := "  ", textStyle
+		if i == d.cursor {
+			prefix, style = "▶ ", selectedStyle
+		}
+		title := s.Session.Title
+		if s.Session.RemoteHost != "" {
+			title += " @" + s.Session.RemoteHost
+		}
+		b.WriteString(style.Render(prefix + truncatePath(title, dialogWidth-24)))
+		b.WriteString(dimStyle.Render("  " + formatRelativeTime(s.ArchivedAt)))
+		b.WriteString("\n")
+		if i == d.cursor {
+			details := s.Session.ProjectPath
+			if s.Reason != "" {
+				details = s.Reason + " · " + details
+			}
+			b.WriteString(dimStyle.Render("    " + truncatePath(details, dialogWidth-10)))
+			b.WriteString("\n")
+		}
+	}
+	if len(d.sessions) > archiveDialogRows {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %d-%d of %d", d.offset+1, end, len(d.sessions))))
+		b.WriteString("\n")
+	}
+
+	if d.status != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(ColorGreen)
+		if d.failed {
+			statusStyle = lipgloss.NewStyle().Foreground(ColorRed)
+		}
+		b.WriteString("\n")
+		b.WriteString(statusStyle.Width(dialogWidth - 4).Render(d.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Enter restore │ x purge │ Esc close │ ↑↓ select"))
+
+	dialog := boxStyle.Render(b.String())
+
+	// Center the dialog on screen
+	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, dialog)
+}
@@ -0,0 +1,43 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestArchiveDialog_SelectAndRemove(t *testing.T) {
+	d := NewArchiveDialog()
+	if d.IsVisible() || d.Selected() != nil {
+		t.Fatal("Dialog should start hidden and empty")
+	}
+
+	d.Show([]*session.ArchivedSession{
+		{Session: &session.InstanceData{ID: "a", Title: "api"}, ArchivedAt: time.Now()},
+		{Session: &session.InstanceData{ID: "b", Title: "web"}, ArchivedAt: time.Now().Add(-time.Hour)},
+	})
+	if !d.IsVisible() || d.Selected().Session.ID != "a" {
+		t.Fatalf("after Show: visible=%v selected=%+v", d.IsVisible(), d.Selected())
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if d.Selected().Session.ID != "b" {
+		t.Errorf("cursor should stop on the last session, got %s", d.Selected().Session.ID)
+	}
+
+	// Removing the last row moves the cursor up
+	d.Remove("b")
+	if d.Selected() == nil || d.Selected().Session.ID != "a" {
+		t.Errorf("after Remove: selected=%+v", d.Selected())
+	}
+	d.Remove("a")
+	if d.Selected() != nil {
+		t.Error("empty archive should have no selection")
+	}
+	if d.View() == "" {
+		t.Error("View should render the empty archive")
+	}
+}
@@ -111,8 +111,8 @@ func (c *ConfirmDialog) View() string {
 	switch c.confirmType {
 	case ConfirmDeleteSession:
 		title = "⚠️  Delete Session?"
-		warning = fmt.Sprintf("This will PERMANENTLY KILL the tmux session:\n\n  \"%s\"", c.targetName)
-		details = "• The tmux session will be terminated\n• Any running processes will be killed\n• Terminal history will be lost\n• This cannot be undone"
+		warning = fmt.Sprintf("This will KILL the tmux session:\n\n  \"%s\"", c.targetName)
+		details = "• The tmux session will be terminated\n• Any running processes will be killed\n• Terminal history will be lost\n• The session moves to the archive (A to restore)"
 		borderColor = ColorRed
 
 		buttonYes := lipgloss.NewStyle().
@@ -48,6 +48,17 @@ type GlobalSearchResult struct {
 	MatchCount  int       // Number of query matches in content
 	InAgentDeck bool      // True if this session is already in Agent Deck
 	InstanceID  string    // Agent Deck instance ID if exists
+	FromNotes   bool      // Matched the notes of an Agent Deck session
+}
+
+// notesSource is an Agent Deck session's notes searched alongside
+// conversations
+type notesSource struct {
+	instanceID string
+	title      string
+	cwd        string
+	notes      string
+	modTime    time.Time
 }
 
 // GlobalSearch represents the global session search overlay
@@ -67,12 +78,15 @@ type GlobalSearch struct {
 
 	// Index reference (set by Home)
 	index *session.GlobalSearchIndex
+
+	// Session notes (set by Home when the overlay opens)
+	notes []notesSource
 }
 
 // NewGlobalSearch creates a new global search overlay
 func NewGlobalSearch() *GlobalSearch {
 	ti := textinput.New()
-	ti.Placeholder = "Search all Claude conversations..."
+	ti.Placeholder = "Search Claude conversations and session notes..."
 	ti.Focus()
 	ti.CharLimit = 100
 	ti.Width = 60
@@ -94,6 +108,22 @@ func (gs *GlobalSearch) SetIndex(index *session.GlobalSearchIndex) {
 	}
 }
 
+// SetNotes sets the session notes to search, keyed by instance ID
+func (gs *GlobalSearch) SetNotes(instances []*session.Instance, notes map[string]string) {
+	gs.notes = gs.notes[:0]
+	for _, inst := range instances {
+		if text, ok := notes[inst.ID]; ok {
+			gs.notes = append(gs.notes, notesSource{
+				instanceID: inst.ID,
+				title:      inst.Title,
+				cwd:        inst.ProjectPath,
+				notes:      text,
+				modTime:    inst.GetLastActivityTime(),
+			})
+		}
+	}
+}
+
 // RefreshStats updates the stats from the index
 func (gs *GlobalSearch) RefreshStats() {
 	if gs.index != nil {
@@ -234,17 +264,23 @@ func (gs *GlobalSearch) Update(msg tea.Msg) (*GlobalSearch, tea.Cmd) {
 func (gs *GlobalSearch) updateResults() {
 	gs.query = gs.input.Value() // Store for highlighting
 	query := gs.query
-	if gs.index == nil || query == "" {
+	if query == "" {
 		gs.results = nil
 		return
 	}
 
+	// Session notes matches are listed first
+	notesResults := gs.searchNotes(query)
+
 	// Perform full-content search first (more comprehensive)
-	searchResults := gs.index.Search(query)
+	var searchResults []*session.SearchResult
+	if gs.index != nil {
+		searchResults = gs.index.Search(query)
 
-	// If no substring matches, fall back to fuzzy search (typo tolerance)
-	if len(searchResults) == 0 {
-		searchResults = gs.index.FuzzySearch(query)
+		// If no substring matches, fall back to fuzzy search (typo tolerance)
+		if len(searchResults) == 0 {
+			searchResults = gs.index.FuzzySearch(query)
+		}
 	}
 
 	// Convert to UI results (limit to 15 for split view)
@@ -283,10 +319,41 @@ func (gs *GlobalSearch) updateResults() {
 		return scoreI > scoreJ
 	})
 
+	gs.results = append(notesResults, gs.results...)
+	if len(gs.results) > 15 {
+		gs.results = gs.results[:15]
+	}
+
 	gs.cursor = 0
 	gs.previewScroll = 0
 }
 
+// searchNotes returns the sessions whose notes contain query
+func (gs *GlobalSearch) searchNotes(query string) []*GlobalSearchResult {
+	notes := make(map[string]string, len(gs.notes))
+	sources := make(map[string]notesSource, len(gs.notes))
+	for _, n := range gs.notes {
+		notes[n.instanceID] = n.notes
+		sources[n.instanceID] = n
+	}
+
+	var results []*GlobalSearchResult
+	for _, m := range session.SearchNotes(notes, query) {
+		src := sources[m.SessionID]
+		results = append(results, &GlobalSearchResult{
+			Summary:     "📝 " + src.title,
+			Content:     src.notes,
+			CWD:         src.cwd,
+			ModTime:     src.modTime,
+			MatchCount:  m.Count,
+			InAgentDeck: true,
+			InstanceID:  src.instanceID,
+			FromNotes:   true,
+		})
+	}
+	return results
+}
+
 // View renders the overlay with split-pane layout
 func (gs *GlobalSearch) View() string {
 	if !gs.visible {
@@ -392,10 +459,14 @@ func (gs *GlobalSearch) View() string {
 		result := gs.results[gs.cursor]
 
 		// Preview header
+		headerLabel := "📄 Preview"
+		if result.FromNotes {
+			headerLabel = "📝 Session notes"
+		}
 		previewHeader := lipgloss.NewStyle().
 			Foreground(ColorCyan).
 			Bold(true).
-			Render("📄 Preview")
+			Render(headerLabel)
 		rightPane.WriteString(previewHeader + "\n")
 
 		// Show CWD
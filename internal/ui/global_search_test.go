@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/asheshgoplani/agent-deck/internal/session"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -247,3 +248,33 @@ func TestGlobalSearchMatchCount(t *testing.T) {
 		t.Error("Expected non-empty view output")
 	}
 }
+
+func TestGlobalSearchNotesResults(t *testing.T) {
+	gs := NewGlobalSearch()
+	gs.Show()
+
+	api := session.NewInstance("api", "/srv/api")
+	web := session.NewInstance("web", "/srv/web")
+	gs.SetNotes([]*session.Instance{api, web}, map[string]string{
+		api.ID: "Asked it to migrate the DB.\nGotcha: migrations need VPN",
+		web.ID: "Nothing about that",
+		"gone": "migrate (session no longer exists)",
+	})
+
+	// No index: notes are still searched
+	gs.input.SetValue("migrat")
+	gs.updateResults()
+
+	if len(gs.results) != 1 {
+		t.Fatalf("Expected 1 notes result, got %d", len(gs.results))
+	}
+	r := gs.results[0]
+	if !r.FromNotes || r.InstanceID != api.ID || r.MatchCount != 2 || r.CWD != "/srv/api" {
+		t.Errorf("Unexpected notes result: %+v", r)
+	}
+
+	gs.SetSize(160, 40)
+	if view := gs.View(); !strings.Contains(view, "Session notes") {
+		t.Error("Expected notes preview header")
+	}
+}
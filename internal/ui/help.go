@@ -113,14 +113,19 @@ func (h *HelpOverlay) View() string {
 				{"n", "New session"},
 				{"r", "Rename session"},
 				{"Shift+R", "Restart session"},
-				{"d", "Delete session"},
+				{"d", "Delete session (to archive)"},
+				{"Shift+A", "Archive: restore deleted sessions"},
 				{"m", "Move to group"},
+				{"Shift+P", "Move/copy to another profile"},
+				{"Ctrl+P", "All profiles view"},
 				{"Shift+M", "MCP Manager (Claude)"},
 				{"v", "Toggle preview mode (output/stats/both)"},
 				{"u", "Mark unread"},
 				{"K / J", "Reorder up/down"},
 				{"f", "Quick fork (Claude only)"},
 				{"F", "Fork with options (Claude only)"},
+				{"x", "Push/pull files (remote only)"},
+				{"Shift+E", "Edit session notes ($EDITOR)"},
 			},
 		},
 		{
@@ -146,6 +151,7 @@ func (h *HelpOverlay) View() string {
 			items: [][2]string{
 				{"S", "Settings"},
 				{"T", "Toggle light/dark theme"},
+				{"Ctrl+Z/Y", "Undo / redo"},
 				{"Ctrl+R", "Reload from disk"},
 				{"Shift+D", "Discover remote"},
 				{"i", "Import tmux sessions"},
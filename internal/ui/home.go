@@ -142,14 +142,18 @@ type Home struct {
 	globalSearch      *GlobalSearch              // Global session search across all Claude conversations
 	globalSearchIndex *session.GlobalSearchIndex // Search index (nil if disabled)
 	newDialog         *NewDialog
-	groupDialog       *GroupDialog    // For creating/renaming groups
-	forkDialog        *ForkDialog     // For forking sessions
-	confirmDialog     *ConfirmDialog  // For confirming destructive actions
-	helpOverlay       *HelpOverlay    // For showing keyboard shortcuts
-	mcpDialog         *MCPDialog      // For managing MCPs
-	setupWizard       *SetupWizard    // For first-run setup
-	settingsPanel     *SettingsPanel  // For editing settings
-	analyticsPanel    *AnalyticsPanel // For displaying session analytics
+	groupDialog       *GroupDialog           // For creating/renaming groups
+	forkDialog        *ForkDialog            // For forking sessions
+	remoteSyncDialog  *RemoteSyncDialog      // For copying files to/from remote sessions
+	archiveDialog     *ArchiveDialog         // For restoring deleted sessions
+	transferDialog    *ProfileTransferDialog // For moving sessions to another profile
+	profilesView      *ProfilesView          // Sessions of all profiles
+	confirmDialog     *ConfirmDialog         // For confirming destructive actions
+	helpOverlay       *HelpOverlay           // For showing keyboard shortcuts
+	mcpDialog         *MCPDialog             // For managing MCPs
+	setupWizard       *SetupWizard           // For first-run setup
+	settingsPanel     *SettingsPanel         // For editing settings
+	analyticsPanel    *AnalyticsPanel        // For displaying session analytics
 
 	// Analytics cache (async fetching with TTL)
 	currentAnalytics       *session.SessionAnalytics                  // Current analytics for selected session (Claude)
@@ -174,6 +178,9 @@ type Home struct {
 	reloadVersion  uint64     // Incremented on each reload to prevent stale background saves
 	reloadMu       sync.Mutex // Protects reloadVersion and isReloading for thread-safe access
 
+	// Undo/redo of moves, renames, reorders and deletions (ctrl+z / ctrl+y)
+	undo undoLog
+
 	// Remote session attach request (from Ctrl+b N shortcut)
 	// Background worker sets this when it detects "attach:" signal, main loop processes it
 	pendingRemoteAttach   string     // Session ID to attach to (set by background)
@@ -211,7 +218,11 @@ type Home struct {
 	storageWatcher *StorageWatcher
 
 	// SSH host connectivity cache (refreshed on tick from SSH pool)
-	sshHostConnected map[string]bool // hostID -> connected
+	sshHostConnected map[string]bool                  // hostID -> connected
+	sshHostResources map[string]*sshpkg.HostResources // hostID -> last load/memory/disk snapshot
+
+	// MCP pool health cache (refreshed on tick from the global MCP pool)
+	poolHealth []session.MCPHealth
 
 	// Storage warning (shown if storage initialization failed)
 	storageWarning string
@@ -272,6 +283,23 @@ type Home struct {
 	remoteDiscoveryRunning      atomic.Bool      // Prevents concurrent discoveries
 	remoteDiscoveryStarted      atomic.Bool      // True once worker goroutine has been started
 	remoteDiscoveryNeedsRebuild atomic.Bool      // Signal main loop to rebuild flatItems after discovery
+	remoteNotice                string           // Reconciliation message for the main loop to show
+	remoteNoticeMu              sync.Mutex       // Protects remoteNotice (written by discovery worker)
+
+	// Session notes by instance ID (session notes, E to edit)
+	notes map[string]string
+
+	// Git-backed profile sync ([sync] in config.toml)
+	syncDirtyAt  atomic.Int64 // UnixNano of the first save not yet pushed (0 = none)
+	syncRunning  atomic.Bool  // Prevents concurrent syncs
+	lastSync     time.Time
+	syncNotice   string     // Sync conflicts or error for the main loop to show
+	syncNoticeMu sync.Mutex // Protects syncNotice (written by the sync goroutine)
+
+	// Port forwards of running remote sessions (held by the primary instance)
+	portForwards        *session.PortForwardManager
+	portForwardSyncing  atomic.Bool // Prevents concurrent Sync calls
+	lastPortForwardSync time.Time
 
 	// Multi-instance support
 	// When AllowMultiple is enabled, only the primary instance (first to start) manages
@@ -347,6 +375,13 @@ type previewFetchedMsg struct {
 	err       error
 }
 
+// portForwardsResolvedMsg carries the forwards picked for localhost URLs
+// detected in a remote session's preview
+type portForwardsResolvedMsg struct {
+	sessionID string
+	forwards  []session.PortForward
+}
+
 // previewDebounceMsg signals debounce period elapsed for preview fetch
 // PERFORMANCE: Delays preview fetch during rapid navigation
 type previewDebounceMsg struct {
@@ -413,6 +448,10 @@ func NewHomeWithProfileAndMode(profile string, isPrimary bool) *Home {
 		newDialog:            NewNewDialog(),
 		groupDialog:          NewGroupDialog(),
 		forkDialog:           NewForkDialog(),
+		remoteSyncDialog:     NewRemoteSyncDialog(),
+		archiveDialog:        NewArchiveDialog(),
+		transferDialog:       NewProfileTransferDialog(),
+		profilesView:         NewProfilesView(),
 		confirmDialog:        NewConfirmDialog(),
 		helpOverlay:          NewHelpOverlay(),
 		mcpDialog:            NewMCPDialog(),
@@ -437,6 +476,7 @@ func NewHomeWithProfileAndMode(profile string, isPrimary bool) *Home {
 		mcpLoadingSessions:     make(map[string]time.Time),
 		forkingSessions:        make(map[string]time.Time),
 		sshHostConnected:       make(map[string]bool),
+		portForwards:           session.NewPortForwardManager(),
 		lastLogActivity:        make(map[string]time.Time),
 		statusTrigger:          make(chan statusUpdateRequest, 1), // Buffered to avoid blocking
 		statusWorkerDone:       make(chan struct{}),
@@ -1075,6 +1115,12 @@ func (h *Home) loadSessions() tea.Msg {
 		return loadSessionsMsg{instances: []*session.Instance{}, err: fmt.Errorf("storage not initialized")}
 	}
 
+	// Pull changes from other machines before the first load
+	if syncEnabled() && h.syncRunning.CompareAndSwap(false, true) {
+		h.runSync()
+		h.syncRunning.Store(false)
+	}
+
 	instances, groups, err := h.storage.LoadWithGroups()
 	msg := loadSessionsMsg{instances: instances, groups: groups, err: err}
 
@@ -1454,6 +1500,8 @@ func (h *Home) startRemoteDiscoveryCmd() tea.Cmd {
 const (
 	remoteDiscoveryIntervalBackground = 60 * time.Second // When viewing local sessions
 	remoteDiscoveryIntervalForeground = 10 * time.Second // When viewing remote sessions
+	sshHealthCheckInterval            = 30 * time.Second // Base interval (backs off while a host is down)
+	portForwardSyncInterval           = 2 * time.Second  // How often forwards follow session state
 )
 
 // remoteDiscoveryWorker runs periodic remote session discovery in the background
@@ -1511,6 +1559,16 @@ func (h *Home) getDiscoveryInterval() time.Duration {
 	return remoteDiscoveryIntervalBackground
 }
 
+// isRemoteHostOffline returns true if inst is a remote session whose host
+// the SSH health checker found unreachable
+func (h *Home) isRemoteHostOffline(inst *session.Instance) bool {
+	if inst == nil || !inst.IsRemote() {
+		return false
+	}
+	connected, checked := h.sshHostConnected[inst.RemoteHost]
+	return checked && !connected
+}
+
 // isViewingRemoteSession returns true if the currently selected session is remote
 func (h *Home) isViewingRemoteSession() bool {
 	selected := h.getSelectedSession()
@@ -1536,6 +1594,10 @@ func (h *Home) runRemoteDiscovery() {
 	// Discover remote sessions
 	discovered, updated, staleIDs, remoteGroups, errors := session.DiscoverRemoteTmuxSessions(existing)
 
+	// Compare with each host's last-known snapshot: sessions that are gone
+	// are reported, sessions on unreachable hosts keep their cached state
+	h.reconcileRemoteHosts(append(existing, discovered...), staleIDs, errors)
+
 	// Note: We don't send a message to the TUI here because that would require
 	// access to the tea.Program which we don't have in the background worker.
 	// Instead, we directly merge the results and trigger a save.
@@ -1679,6 +1741,23 @@ func (h *Home) runRemoteDiscovery() {
 	}
 }
 
+// reconcileRemoteHosts updates the offline snapshot of every auto-discovered
+// host and queues a notice for sessions that disappeared while away
+func (h *Home) reconcileRemoteHosts(instances []*session.Instance, staleIDs []string, errors map[string]error) {
+	var notices []string
+	for _, hostID := range session.AutoDiscoverHostIDs() {
+		report := session.ReconcileRemoteHost(h.profile, hostID, instances, staleIDs, errors[hostID])
+		if report.Reachable && len(report.Disappeared) > 0 && !report.LastContact.IsZero() {
+			notices = append(notices, report.Summary())
+		}
+	}
+	if len(notices) > 0 {
+		h.remoteNoticeMu.Lock()
+		h.remoteNotice = strings.Join(notices, "; ")
+		h.remoteNoticeMu.Unlock()
+	}
+}
+
 // triggerRemoteDiscovery signals the remote discovery worker to run immediately
 func (h *Home) triggerRemoteDiscovery() {
 	select {
@@ -1983,6 +2062,21 @@ func (h *Home) processStatusUpdate(req statusUpdateRequest) {
 		visibleIDs[req.flatItemIDs[i]] = true
 	}
 
+	// Batch status queries for remote sessions: one agent round trip per host
+	// instead of several ssh commands per session (no-op without a remote agent)
+	remoteByHost := make(map[string][]string)
+	for _, inst := range instancesCopy {
+		if !inst.IsRemote() || (!visibleIDs[inst.ID] && inst.Status == "idle") {
+			continue
+		}
+		if ts := inst.GetTmuxSession(); ts != nil {
+			remoteByHost[inst.RemoteHost] = append(remoteByHost[inst.RemoteHost], ts.Name)
+		}
+	}
+	if len(remoteByHost) > 0 {
+		tmux.PrefetchRemoteStatus(remoteByHost)
+	}
+
 	// Track which sessions we've updated this tick
 	updated := make(map[string]bool)
 	// Track if any status actually changed (for cache invalidation)
@@ -2084,6 +2178,9 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 			}
 			h.instancesMu.Unlock()
+			// The undo history's snapshots predate the reloaded sessions
+			h.undo.clear()
+			h.reloadNotes()
 			// Invalidate status counts cache
 			h.cachedStatusCounts.valid.Store(false)
 			// Sync group tree with loaded data
@@ -2147,6 +2244,18 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if h.remoteDiscoveryStarted.CompareAndSwap(false, true) {
 			go h.remoteDiscoveryWorker()
 			log.Printf("[REMOTE-DISCOVERY] Worker started (deferred after local sessions loaded)")
+			// Check SSH hosts in the background (retrying unreachable ones with
+			// backoff) and rediscover as soon as one comes back
+			pool := sshpkg.DefaultPool()
+			pool.SetStateHandler(func(hostID string, connected bool) {
+				if connected {
+					log.Printf("[SSH] %s reconnected, reconciling sessions", hostID)
+					h.triggerRemoteDiscovery()
+				} else {
+					log.Printf("[SSH] %s unreachable, showing last-known sessions", hostID)
+				}
+			})
+			pool.StartHealthChecker(sshHealthCheckInterval, h.ctx.Done())
 		}
 		return h, nil
 
@@ -2196,6 +2305,7 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// CRITICAL: Save to storage BEFORE starting tmux session to prevent orphans
 			// If save fails, the tmux session won't be created
 			h.saveInstances()
+			session.RecordAudit(h.profile, session.AuditCreate, msg.instance, "")
 
 			// Start the session after save completes
 			// This happens asynchronously - if it fails, the session will show as "stopped"
@@ -2256,6 +2366,7 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// CRITICAL: Save to storage BEFORE starting tmux session to prevent orphans
 			// If save fails, the tmux session won't be created
 			h.saveInstances()
+			session.RecordAudit(h.profile, session.AuditFork, msg.instance, "from "+msg.sourceID)
 
 			// Start the forked session after save completes
 			// This happens asynchronously - if it fails, the session will show as "stopped"
@@ -2273,12 +2384,18 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return h, nil
 		}
 
+		if msg.archiveErr != nil {
+			h.setError(fmt.Errorf("session not deleted, failed to archive it: %w", msg.archiveErr))
+			return h, nil
+		}
+
 		// Report kill error if any (session may still be running in tmux)
 		if msg.killErr != nil {
 			h.setError(fmt.Errorf("warning: tmux session may still be running: %w", msg.killErr))
 		}
 
 		// Find and remove from list
+		before := captureLayout(h.groupTree)
 		var deletedInstance *session.Instance
 		h.instancesMu.Lock()
 		for i, s := range h.instances {
@@ -2297,6 +2414,12 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Remove from group tree (preserves empty groups)
 		if deletedInstance != nil {
 			h.groupTree.RemoveSession(deletedInstance)
+			op := undoOp{label: "delete session", before: before, after: captureLayout(h.groupTree), deleted: []string{msg.deletedID}}
+			if msg.redo {
+				h.undo.push(op)
+			} else {
+				h.undo.record(op)
+			}
 		}
 		h.rebuildFlatItems()
 		// Update search items
@@ -2339,6 +2462,22 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		h.forceSaveInstances()
 		return h, nil
 
+	case remoteSyncDoneMsg:
+		if h.remoteSyncDialog.IsVisible() && h.remoteSyncDialog.SessionID() == msg.sessionID {
+			h.remoteSyncDialog.SetResult(msg.result, msg.err)
+		} else if msg.err != nil {
+			direction := "pull"
+			if msg.push {
+				direction = "push"
+			}
+			h.setError(fmt.Errorf("remote %s failed: %w", direction, msg.err))
+		}
+		return h, nil
+
+	case notesEditedMsg:
+		h.handleNotesEdited(msg)
+		return h, nil
+
 	case sessionRestartedMsg:
 		if msg.err != nil {
 			h.setError(fmt.Errorf("failed to restart session: %w", msg.err))
@@ -2531,6 +2670,28 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			h.previewCacheTime[msg.sessionID] = time.Now()
 		}
 		h.previewCacheMu.Unlock()
+		// Keep the last output of remote sessions for when the host is offline
+		if msg.err == nil {
+			if inst := h.getInstanceByID(msg.sessionID); inst != nil && inst.IsRemote() {
+				session.SetRemoteSessionPreview(h.profile, inst.RemoteHost, inst.ID, msg.content)
+				// Forward dev servers the agent started on the host. Picking
+				// free local ports dials them, so it runs in a command.
+				if plan := inst.PlanPortForwards(msg.content, h.instances); len(plan.RemotePorts) > 0 {
+					sessionID := inst.ID
+					return h, func() tea.Msg {
+						return portForwardsResolvedMsg{sessionID: sessionID, forwards: plan.Resolve()}
+					}
+				}
+			}
+		}
+		return h, nil
+
+	case portForwardsResolvedMsg:
+		if inst := h.getInstanceByID(msg.sessionID); inst != nil {
+			if added := inst.AddDetectedPortForwards(msg.forwards, h.instances); len(added) > 0 {
+				h.saveInstances()
+			}
+		}
 		return h, nil
 
 	case analyticsFetchedMsg:
@@ -2618,13 +2779,52 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update animation frame for launching spinner (8 frames, cycles every tick)
 		h.animationFrame = (h.animationFrame + 1) % 8
 
-		// Refresh SSH host connectivity cache from the global pool
-		if statuses := sshpkg.DefaultPool().Status(); len(statuses) > 0 {
+		// Refresh SSH host connectivity cache from the global pool (last results
+		// of the health checker, no I/O). Hosts not checked yet are left out.
+		if statuses := sshpkg.DefaultPool().CachedStatus(); len(statuses) > 0 {
 			connected := make(map[string]bool, len(statuses))
+			resources := make(map[string]*sshpkg.HostResources, len(statuses))
 			for _, s := range statuses {
-				connected[s.HostID] = s.Connected
+				if !s.LastCheck.IsZero() {
+					connected[s.HostID] = s.Connected
+				}
+				if s.Resources != nil {
+					resources[s.HostID] = s.Resources
+				}
 			}
 			h.sshHostConnected = connected
+			h.sshHostResources = resources
+		}
+
+		// Open forwards of running remote sessions, close those of stopped or
+		// deleted ones. Only the primary instance holds the local ports.
+		if h.isPrimaryInstance && time.Since(h.lastPortForwardSync) >= portForwardSyncInterval &&
+			h.portForwardSyncing.CompareAndSwap(false, true) {
+			h.lastPortForwardSync = time.Now()
+			h.instancesMu.RLock()
+			targets := session.PortForwardTargets(h.instances)
+			h.instancesMu.RUnlock()
+			go func() {
+				defer h.portForwardSyncing.Store(false)
+				h.portForwards.Sync(targets)
+			}()
+		}
+
+		// Show sessions that disappeared from a host since it was last reached
+		h.remoteNoticeMu.Lock()
+		if h.remoteNotice != "" {
+			h.setError(fmt.Errorf("%s", h.remoteNotice))
+			h.remoteNotice = ""
+		}
+		h.remoteNoticeMu.Unlock()
+
+		// Push saves and pull other machines' changes, show conflicts
+		h.maybeSync()
+		h.showSyncNotice()
+
+		// Refresh MCP pool health (last results of the pool's health monitor, no I/O)
+		if session.GetGlobalPool() != nil {
+			h.poolHealth = session.GetMCPPoolHealth()
 		}
 
 		// Fast log size check every 10 seconds (catches runaway logs before they cause issues)
@@ -2752,6 +2952,18 @@ func (h *Home) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if h.forkDialog.IsVisible() {
 			return h.handleForkDialogKey(msg)
 		}
+		if h.remoteSyncDialog.IsVisible() {
+			return h.handleRemoteSyncDialogKey(msg)
+		}
+		if h.archiveDialog.IsVisible() {
+			return h.handleArchiveDialogKey(msg)
+		}
+		if h.transferDialog.IsVisible() {
+			return h.handleTransferDialogKey(msg)
+		}
+		if h.profilesView.IsVisible() {
+			return h.handleProfilesViewKey(msg)
+		}
 		if h.confirmDialog.IsVisible() {
 			return h.handleConfirmDialogKey(msg)
 		}
@@ -2800,6 +3012,7 @@ func (h *Home) handleSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	// Check if user wants to switch to global search
 	if h.search.WantsSwitchToGlobal() && h.globalSearchIndex != nil {
 		h.globalSearch.SetSize(h.width, h.height)
+		h.globalSearch.SetNotes(h.instances, h.notes)
 		h.globalSearch.Show()
 	}
 
@@ -2836,6 +3049,14 @@ func (h *Home) handleGlobalSearchKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleGlobalSearchSelection handles selection from global search
 func (h *Home) handleGlobalSearchSelection(result *GlobalSearchResult) tea.Cmd {
+	// Notes matches belong to an existing session
+	if result.FromNotes {
+		if inst := h.getInstanceByID(result.InstanceID); inst != nil {
+			h.jumpToSession(inst)
+		}
+		return nil
+	}
+
 	// Check if session already exists in Agent Deck
 	h.instancesMu.RLock()
 	for _, inst := range h.instances {
@@ -3140,6 +3361,10 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					h.setError(fmt.Errorf("session is starting, please wait"))
 					return h, nil
 				}
+				if h.isRemoteHostOffline(item.Session) {
+					h.setError(fmt.Errorf("%s is unreachable, showing last-known snapshot (retrying in background)", item.Session.RemoteHost))
+					return h, nil
+				}
 				if item.Session.Exists() {
 					h.isAttaching.Store(true) // Prevent View() output during transition (atomic)
 					return h, h.attachSession(item.Session)
@@ -3189,12 +3414,14 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Move item up
 		if h.cursor < len(h.flatItems) {
 			item := h.flatItems[h.cursor]
+			before := captureLayout(h.groupTree)
 			switch item.Type {
 			case session.ItemTypeGroup:
 				h.groupTree.MoveGroupUp(item.Path)
 			case session.ItemTypeSession:
 				h.groupTree.MoveSessionUp(item.Session)
 			}
+			h.recordLayoutChange("reorder", before)
 			h.rebuildFlatItems()
 			if h.cursor > 0 {
 				h.cursor--
@@ -3207,12 +3434,14 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Move item down
 		if h.cursor < len(h.flatItems) {
 			item := h.flatItems[h.cursor]
+			before := captureLayout(h.groupTree)
 			switch item.Type {
 			case session.ItemTypeGroup:
 				h.groupTree.MoveGroupDown(item.Path)
 			case session.ItemTypeSession:
 				h.groupTree.MoveSessionDown(item.Session)
 			}
+			h.recordLayoutChange("reorder", before)
 			h.rebuildFlatItems()
 			if h.cursor < len(h.flatItems)-1 {
 				h.cursor++
@@ -3341,6 +3570,7 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Open global search first if available, otherwise local search
 		if h.globalSearchIndex != nil {
 			h.globalSearch.SetSize(h.width, h.height)
+			h.globalSearch.SetNotes(h.instances, h.notes)
 			h.globalSearch.Show()
 		} else {
 			h.search.Show()
@@ -3451,6 +3681,11 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		defaultPath := h.getDefaultPathForGroup(groupPath)
 		h.newDialog.ShowInGroup(groupPath, groupName, defaultPath)
+		if hostID, isRemote := session.GetSSHHostIDFromGroupPath(groupPath); isRemote {
+			if warnings := h.sshHostResources[hostID].Warnings(); len(warnings) > 0 {
+				h.newDialog.SetHostWarning(fmt.Sprintf("%s is overloaded: %s", hostID, strings.Join(warnings, ", ")))
+			}
+		}
 		return h, nil
 
 	case "d":
@@ -3468,6 +3703,66 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "i":
 		return h, h.importSessions
 
+	case "A":
+		// Browse deleted sessions to restore or purge them
+		archive, err := session.OpenArchive(h.profile)
+		if err != nil {
+			h.setError(err)
+			return h, nil
+		}
+		archived, err := archive.List()
+		if err != nil {
+			h.setError(fmt.Errorf("failed to read archive: %w", err))
+			return h, nil
+		}
+		h.archiveDialog.SetSize(h.width, h.height)
+		h.archiveDialog.Show(archived)
+		return h, nil
+
+	case "P":
+		// Move or copy the session to another profile
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			if item.Type == session.ItemTypeSession && item.Session != nil {
+				profiles, err := session.ListProfiles()
+				if err != nil {
+					h.setError(err)
+					return h, nil
+				}
+				var others []string
+				for _, p := range profiles {
+					if p != h.profile {
+						others = append(others, p)
+					}
+				}
+				h.transferDialog.SetSize(h.width, h.height)
+				h.transferDialog.Show(item.Session, others)
+			}
+		}
+		return h, nil
+
+	case "ctrl+p":
+		// Browse the sessions of all profiles
+		sessions, err := h.loadAllProfileSessions()
+		if err != nil {
+			h.setError(err)
+			return h, nil
+		}
+		h.profilesView.SetSize(h.width, h.height)
+		h.profilesView.Show(h.profile, sessions)
+		return h, nil
+
+	case "x":
+		// Copy files to/from a remote session's host
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			if item.Type == session.ItemTypeSession && item.Session != nil && item.Session.IsRemote() {
+				h.remoteSyncDialog.SetSize(h.width, h.height)
+				h.remoteSyncDialog.Show(item.Session)
+			}
+		}
+		return h, nil
+
 	case "u":
 		// Mark session as unread (change idle → waiting)
 		if h.cursor < len(h.flatItems) {
@@ -3532,6 +3827,22 @@ func (h *Home) handleMainKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return h, nil
 
+	case "E":
+		// Edit the selected session's notes in $EDITOR
+		if h.cursor < len(h.flatItems) {
+			item := h.flatItems[h.cursor]
+			if item.Type == session.ItemTypeSession && item.Session != nil {
+				return h, h.editNotes(item.Session)
+			}
+		}
+		return h, nil
+
+	case "ctrl+z":
+		return h.undoLast()
+
+	case "ctrl+y":
+		return h.redoLast()
+
 	case "ctrl+r":
 		// Manual refresh (useful if watcher fails or for user preference)
 		state := h.preserveState()
@@ -3658,7 +3969,9 @@ func (h *Home) handleConfirmDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 			case ConfirmDeleteGroup:
 				groupPath := h.confirmDialog.GetTargetID()
+				before := captureLayout(h.groupTree)
 				h.groupTree.DeleteGroup(groupPath)
+				h.recordLayoutChange("delete group", before)
 				h.instancesMu.Lock()
 				h.instances = h.groupTree.GetAllInstances()
 				h.instancesMu.Unlock()
@@ -3712,6 +4025,7 @@ func (h *Home) performQuit(shutdownPool bool) tea.Cmd {
 		if h.logWatcher != nil {
 			_ = h.logWatcher.Close()
 		}
+		h.portForwards.CloseAll()
 		// Close storage watcher
 		if h.storageWatcher != nil {
 			_ = h.storageWatcher.Close()
@@ -3760,6 +4074,13 @@ func (h *Home) handleMCPDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 			// O(1) lookup - no lock needed as Update() runs on main goroutine
 			targetInst := h.getInstanceByID(sessionID)
+			attached, detached := h.mcpDialog.AttachChanges()
+			if len(attached) > 0 {
+				session.RecordAudit(h.profile, session.AuditMCPAttach, targetInst, strings.Join(attached, ", "))
+			}
+			if len(detached) > 0 {
+				session.RecordAudit(h.profile, session.AuditMCPDetach, targetInst, strings.Join(detached, ", "))
+			}
 			if targetInst != nil {
 				log.Printf("[MCP-DEBUG] Found session by ID: %s, Title=%s", targetInst.ID, targetInst.Title)
 			}
@@ -3820,7 +4141,9 @@ func (h *Home) handleGroupDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case GroupDialogRename:
 			name := h.groupDialog.GetValue()
 			if name != "" {
+				before := captureLayout(h.groupTree)
 				h.groupTree.RenameGroup(h.groupDialog.GetGroupPath(), name)
+				h.recordLayoutChange("rename group", before)
 				h.instancesMu.Lock()
 				h.instances = h.groupTree.GetAllInstances()
 				h.instancesMu.Unlock()
@@ -3835,7 +4158,11 @@ func (h *Home) handleGroupDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 					// Find the group path from name
 					for _, g := range h.groupTree.GroupList {
 						if g.Name == groupName {
+							before := captureLayout(h.groupTree)
+							from := item.Session.GroupPath
 							h.groupTree.MoveSessionToGroup(item.Session, g.Path)
+							h.recordLayoutChange("move session", before)
+							session.RecordAudit(h.profile, session.AuditMove, item.Session, from+" -> "+g.Path)
 							h.instancesMu.Lock()
 							h.instances = h.groupTree.GetAllInstances()
 							h.instancesMu.Unlock()
@@ -3852,7 +4179,9 @@ func (h *Home) handleGroupDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				sessionID := h.groupDialog.GetSessionID()
 				// Find and rename the session (O(1) lookup)
 				if inst := h.getInstanceByID(sessionID); inst != nil {
+					before := captureLayout(h.groupTree)
 					inst.Title = newName
+					h.recordLayoutChange("rename session", before)
 				}
 				// Invalidate preview cache since title changed
 				h.invalidatePreviewCache(sessionID)
@@ -3908,6 +4237,304 @@ func (h *Home) handleForkDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return h, cmd
 }
 
+// handleRemoteSyncDialogKey handles keyboard input for the remote sync dialog
+func (h *Home) handleRemoteSyncDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		if h.remoteSyncDialog.IsRunning() {
+			return h, nil
+		}
+		if validationErr := h.remoteSyncDialog.Validate(); validationErr != "" {
+			h.setError(fmt.Errorf("validation error: %s", validationErr))
+			return h, nil
+		}
+		h.clearError()
+		inst := h.getInstanceByID(h.remoteSyncDialog.SessionID())
+		if inst == nil {
+			h.remoteSyncDialog.Hide()
+			return h, nil
+		}
+		h.remoteSyncDialog.SetRunning()
+		local, remote := h.remoteSyncDialog.GetPaths()
+		return h, h.remoteSyncCmd(inst, h.remoteSyncDialog.IsPush(), local, remote)
+
+	case "esc":
+		// A running transfer finishes in the background
+		h.remoteSyncDialog.Hide()
+		h.clearError()
+		return h, nil
+	}
+
+	var cmd tea.Cmd
+	h.remoteSyncDialog, cmd = h.remoteSyncDialog.Update(msg)
+	return h, cmd
+}
+
+// remoteSyncCmd pushes or pulls files for a remote session in the background
+func (h *Home) remoteSyncCmd(inst *session.Instance, push bool, local, remote string) tea.Cmd {
+	id := inst.ID
+	return func() tea.Msg {
+		var result *sshpkg.SyncResult
+		var err error
+		if push {
+			result, err = session.PushToRemoteSession(inst, local, remote, sshpkg.SyncOptions{})
+		} else {
+			result, err = session.PullFromRemoteSession(inst, remote, local, sshpkg.SyncOptions{})
+		}
+		return remoteSyncDoneMsg{sessionID: id, push: push, result: result, err: err}
+	}
+}
+
+// handleArchiveDialogKey handles keyboard input for the archive dialog
+func (h *Home) handleArchiveDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "r":
+		entry := h.archiveDialog.Selected()
+		if entry == nil {
+			return h, nil
+		}
+		inst, err := h.restoreArchivedSession(entry)
+		if err != nil {
+			h.archiveDialog.SetStatus(err.Error(), true)
+			return h, nil
+		}
+		h.archiveDialog.Hide()
+		return h, tea.Batch(h.reviveRestoredSession(inst), h.fetchPreview(inst))
+
+	case "x", "d":
+		entry := h.archiveDialog.Selected()
+		if entry == nil {
+			return h, nil
+		}
+		archive, err := session.OpenArchive(h.profile)
+		if err == nil {
+			_, err = archive.Purge([]string{entry.Session.ID})
+		}
+		if err != nil {
+			h.archiveDialog.SetStatus(fmt.Sprintf("failed to purge: %v", err), true)
+			return h, nil
+		}
+		h.archiveDialog.Remove(entry.Session.ID)
+		h.archiveDialog.SetStatus(fmt.Sprintf("Purged %s", entry.Session.Title), false)
+		return h, nil
+
+	case "esc", "q", "A":
+		h.archiveDialog.Hide()
+		return h, nil
+	}
+
+	var cmd tea.Cmd
+	h.archiveDialog, cmd = h.archiveDialog.Update(msg)
+	return h, cmd
+}
+
+// handleTransferDialogKey handles keyboard input for the move-to-profile dialog
+func (h *Home) handleTransferDialogKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter", "c":
+		inst, target := h.transferDialog.Session(), h.transferDialog.Selected()
+		if inst == nil || target == "" {
+			return h, nil
+		}
+		copyMode := msg.String() == "c"
+		// Persist in-memory changes first; the transfer reads the file
+		h.saveInstances()
+		result, err := session.TransferSession(h.profile, target, inst.ID, session.TransferOptions{Copy: copyMode})
+		if err != nil {
+			h.transferDialog.SetError(err)
+			return h, nil
+		}
+		h.transferDialog.Hide()
+		if !copyMode {
+			h.removeTransferredSessions(result)
+		}
+		return h, nil
+
+	case "esc", "q":
+		h.transferDialog.Hide()
+		return h, nil
+	}
+
+	var cmd tea.Cmd
+	h.transferDialog, cmd = h.transferDialog.Update(msg)
+	return h, cmd
+}
+
+// removeTransferredSessions drops sessions moved to another profile from the
+// list. They keep running; the other profile now owns them.
+func (h *Home) removeTransferredSessions(result *session.TransferResult) {
+	moved := make(map[string]bool, len(result.Sessions))
+	for _, s := range result.Sessions {
+		moved[s.ID] = true
+	}
+
+	h.instancesMu.Lock()
+	var removed []*session.Instance
+	kept := h.instances[:0]
+	for _, inst := range h.instances {
+		if moved[inst.ID] {
+			removed = append(removed, inst)
+			delete(h.instanceByID, inst.ID)
+		} else {
+			kept = append(kept, inst)
+		}
+	}
+	h.instances = kept
+	h.instancesMu.Unlock()
+
+	h.cachedStatusCounts.valid.Store(false)
+	for _, inst := range removed {
+		h.invalidatePreviewCache(inst.ID)
+		h.groupTree.RemoveSession(inst)
+	}
+	h.rebuildFlatItems()
+	h.search.SetItems(h.instances)
+	h.saveInstances()
+}
+
+// loadAllProfileSessions returns the sessions of every profile, sorted by
+// profile. The current profile's come from memory so their status is live.
+func (h *Home) loadAllProfileSessions() ([]ProfileSession, error) {
+	profiles, err := session.ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(profiles)
+
+	var sessions []ProfileSession
+	for _, p := range profiles {
+		if p == h.profile {
+			h.instancesMu.RLock()
+			for _, inst := range h.instances {
+				sessions = append(sessions, ProfileSession{Profile: p, Session: inst})
+			}
+			h.instancesMu.RUnlock()
+			continue
+		}
+		storage, err := session.NewStorageWithProfile(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open profile '%s': %w", p, err)
+		}
+		instances, _, err := storage.LoadWithGroups()
+		_ = storage.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load profile '%s': %w", p, err)
+		}
+		for _, inst := range instances {
+			sessions = append(sessions, ProfileSession{Profile: p, Session: inst})
+		}
+	}
+	return sessions, nil
+}
+
+// handleProfilesViewKey handles keyboard input for the all-profiles view
+func (h *Home) handleProfilesViewKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		entry := h.profilesView.Selected()
+		if entry == nil {
+			return h, nil
+		}
+		if !entry.Session.Exists() {
+			h.profilesView.SetStatus(fmt.Sprintf("%s is not running (start it from profile '%s')", entry.Session.Title, entry.Profile), true)
+			return h, nil
+		}
+		h.profilesView.Hide()
+		h.isAttaching.Store(true)
+		return h, h.attachSession(entry.Session)
+
+	case "m", "c":
+		entry := h.profilesView.Selected()
+		if entry == nil {
+			return h, nil
+		}
+		if entry.Profile == h.profile {
+			h.profilesView.SetStatus(fmt.Sprintf("%s is already in this profile", entry.Session.Title), true)
+			return h, nil
+		}
+		copyMode := msg.String() == "c"
+		// Persist in-memory changes first; the transfer rewrites this
+		// profile and the reload below replaces h.instances
+		h.saveInstances()
+		if _, err := session.TransferSession(entry.Profile, h.profile, entry.Session.ID, session.TransferOptions{Copy: copyMode}); err != nil {
+			h.profilesView.SetStatus(err.Error(), true)
+			return h, nil
+		}
+		h.profilesView.Hide()
+		state := h.preserveState()
+		return h, func() tea.Msg {
+			instances, groups, err := h.storage.LoadWithGroups()
+			return loadSessionsMsg{instances: instances, groups: groups, err: err, restoreState: &state}
+		}
+
+	case "esc", "q", "ctrl+p":
+		h.profilesView.Hide()
+		return h, nil
+	}
+
+	var cmd tea.Cmd
+	h.profilesView, cmd = h.profilesView.Update(msg)
+	return h, cmd
+}
+
+// restoreArchivedSession adds an archived session back to the list and saves
+// it. The tmux session is recreated afterwards by reviveRestoredSession.
+func (h *Home) restoreArchivedSession(entry *session.ArchivedSession) (*session.Instance, error) {
+	if h.isReloading {
+		return nil, fmt.Errorf("sessions are reloading, try again")
+	}
+
+	h.instancesMu.Lock()
+	inst, err := session.RestoreArchivedSession(entry, h.instances)
+	if err == nil {
+		h.instances = append(h.instances, inst)
+		h.instanceByID[inst.ID] = inst
+	}
+	h.instancesMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	h.cachedStatusCounts.valid.Store(false)
+
+	if inst.GroupPath != "" {
+		h.groupTree.ExpandGroupWithParents(inst.GroupPath)
+	}
+	h.groupTree.AddSession(inst)
+	h.rebuildFlatItems()
+	h.search.SetItems(h.instances)
+	for i, item := range h.flatItems {
+		if item.Type == session.ItemTypeSession && item.Session != nil && item.Session.ID == inst.ID {
+			h.cursor = i
+			h.syncViewport()
+			break
+		}
+	}
+
+	// Save before dropping it from the archive so it can't be lost
+	h.saveInstances()
+	return inst, nil
+}
+
+// reviveRestoredSession removes a restored session from the archive and
+// recreates its tmux session, resuming the conversation when possible
+func (h *Home) reviveRestoredSession(inst *session.Instance) tea.Cmd {
+	id := inst.ID
+	profile := h.profile
+	return func() tea.Msg {
+		if archive, err := session.OpenArchive(profile); err == nil {
+			if _, err := archive.Take(id); err != nil {
+				log.Printf("[ARCHIVE] Restored session %s is still archived: %v", id, err)
+			}
+		}
+		session.RecordAudit(profile, session.AuditCreate, inst, "restored from archive")
+		err := inst.Revive()
+		if err == nil {
+			session.RecordAudit(profile, session.AuditStart, inst, "")
+		}
+		return sessionRestartedMsg{sessionID: id, err: err}
+	}
+}
+
 // saveInstances saves instances to storage
 func (h *Home) saveInstances() {
 	h.saveInstancesWithForce(false)
@@ -3979,6 +4606,8 @@ func (h *Home) saveInstancesWithForce(force bool) {
 		// Save both instances and groups (including empty ones)
 		if err := h.storage.SaveWithGroups(instancesCopy, groupTreeCopy); err != nil {
 			h.setError(fmt.Errorf("failed to save: %w", err))
+		} else {
+			h.markSyncDirty()
 		}
 	}
 }
@@ -4115,6 +4744,7 @@ func (h *Home) startSession(inst *session.Instance) tea.Cmd {
 			// User can manually start it later
 			return nil
 		}
+		session.RecordAudit(h.profile, session.AuditStart, inst, "")
 
 		// Return nil - no message needed, status will be updated via normal polling
 		return nil
@@ -4132,6 +4762,7 @@ func (h *Home) startForkedSession(inst *session.Instance) tea.Cmd {
 			// User can manually start it later
 			return nil
 		}
+		session.RecordAudit(h.profile, session.AuditStart, inst, "")
 
 		// Wait for Claude to create the new session file (fork creates new UUID)
 		// Give Claude up to 5 seconds to initialize and write the session file
@@ -4156,15 +4787,26 @@ func (h *Home) startForkedSession(inst *session.Instance) tea.Cmd {
 
 // sessionDeletedMsg signals that a session was deleted
 type sessionDeletedMsg struct {
-	deletedID string
-	killErr   error // Error from Kill() if any
+	deletedID  string
+	killErr    error // Error from Kill() if any
+	archiveErr error // Session could not be archived and was left alone
+	redo       bool  // Deleted again by redo (keeps the redo history)
 }
 
-// deleteSession deletes a session
+// deleteSession moves a session to the archive and kills its tmux session
 func (h *Home) deleteSession(inst *session.Instance) tea.Cmd {
 	id := inst.ID
+	profile := h.profile
 	return func() tea.Msg {
+		archive, err := session.OpenArchive(profile)
+		if err == nil {
+			err = archive.Add(inst, "deleted in TUI")
+		}
+		if err != nil {
+			return sessionDeletedMsg{deletedID: id, archiveErr: err}
+		}
 		killErr := inst.Kill()
+		session.RecordAudit(profile, session.AuditDelete, inst, "")
 		return sessionDeletedMsg{deletedID: id, killErr: killErr}
 	}
 }
@@ -4184,11 +4826,15 @@ type mcpRestartedMsg struct {
 // restartSession restarts a dead/errored session by creating a new tmux session
 func (h *Home) restartSession(inst *session.Instance) tea.Cmd {
 	id := inst.ID
+	profile := h.profile
 	log.Printf("[MCP-DEBUG] restartSession() called for ID=%s, Title=%s, Tool=%s", inst.ID, inst.Title, inst.Tool)
 	return func() tea.Msg {
 		log.Printf("[MCP-DEBUG] restartSession() cmd executing - calling inst.Restart()")
 		err := inst.Restart()
 		log.Printf("[MCP-DEBUG] restartSession() inst.Restart() returned err=%v", err)
+		if err == nil {
+			session.RecordAudit(profile, session.AuditRestart, inst, "")
+		}
 		return sessionRestartedMsg{sessionID: id, err: err}
 	}
 }
@@ -4504,6 +5150,11 @@ func (h *Home) renderFilterBar() string {
 		}
 	}
 
+	// MCP pool status (pooled count, unresponsive and quarantined MCPs)
+	if poolPill := h.renderPoolStatus(); poolPill != "" {
+		pills = append(pills, lipgloss.NewStyle().Foreground(ColorBorder).Render("|"), poolPill)
+	}
+
 	// Hint for keyboard shortcuts (shift+number to filter, 0 to clear)
 	hintStyle := lipgloss.NewStyle().Foreground(ColorComment).Faint(true)
 	hint := hintStyle.Render("  !@#$ status • % tool • 0 all")
@@ -4517,6 +5168,42 @@ func (h *Home) renderFilterBar() string {
 		Render(filterRow)
 }
 
+// renderPoolStatus renders the MCP pool pill for the filter bar:
+// healthy/pooled count, then unresponsive (⚠) and quarantined (⛔) MCP names
+func (h *Home) renderPoolStatus() string {
+	var pooled, healthy int
+	var unresponsive, quarantined []string
+	for _, mh := range h.poolHealth {
+		switch {
+		case mh.Status == session.MCPPoolStatusNotPooled:
+			continue
+		case mh.Status == session.MCPPoolStatusQuarantined:
+			quarantined = append(quarantined, mh.Name)
+		case mh.Health == "unresponsive" || mh.Status == "failed":
+			unresponsive = append(unresponsive, mh.Name)
+		case mh.Health == "healthy":
+			healthy++
+		}
+		pooled++
+	}
+	if pooled == 0 {
+		return ""
+	}
+
+	label := fmt.Sprintf("🔌 %d/%d", healthy, pooled)
+	color := ColorGreen
+	if len(unresponsive) > 0 {
+		label += " ⚠ " + strings.Join(unresponsive, ",")
+		color = ColorYellow
+	}
+	if len(quarantined) > 0 {
+		label += " ⛔ " + strings.Join(quarantined, ",")
+		color = ColorRed
+	}
+	pillStyle := lipgloss.NewStyle().Foreground(color).Background(ColorSurface).Padding(0, 1)
+	return pillStyle.Render(label)
+}
+
 // updateSizes updates component sizes
 func (h *Home) updateSizes() {
 	h.search.SetSize(h.width, h.height)
@@ -4586,6 +5273,18 @@ func (h *Home) View() string {
 	if h.forkDialog.IsVisible() {
 		return h.forkDialog.View()
 	}
+	if h.remoteSyncDialog.IsVisible() {
+		return h.remoteSyncDialog.View()
+	}
+	if h.archiveDialog.IsVisible() {
+		return h.archiveDialog.View()
+	}
+	if h.transferDialog.IsVisible() {
+		return h.transferDialog.View()
+	}
+	if h.profilesView.IsVisible() {
+		return h.profilesView.View()
+	}
 	if h.confirmDialog.IsVisible() {
 		return h.confirmDialog.View()
 	}
@@ -5419,6 +6118,9 @@ func (h *Home) renderHelpBarFull() string {
 				primaryHints = append(primaryHints, h.helpKey("M", "MCP"))
 				primaryHints = append(primaryHints, h.helpKey("v", h.previewModeShort()))
 			}
+			if item.Session != nil && item.Session.IsRemote() {
+				primaryHints = append(primaryHints, h.helpKey("x", "Sync"))
+			}
 			secondaryHints = []string{
 				h.helpKey("r", "Rename"),
 				h.helpKey("m", "Move"),
@@ -6178,6 +6880,13 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	b.WriteString(groupBadge)
 	b.WriteString("\n")
 
+	if len(selected.PortForwards) > 0 {
+		b.WriteString(h.renderPortForwards(selected, width))
+	}
+	if notes := h.notes[selected.ID]; notes != "" {
+		b.WriteString(renderNotes(notes, width))
+	}
+
 	// Claude-specific info (session ID and MCPs)
 	if selected.Tool == "claude" {
 		// Section divider for Claude info
@@ -6656,6 +7365,20 @@ func (h *Home) renderPreviewPane(width, height int) string {
 	preview, hasCached := h.previewCache[selected.ID]
 	h.previewCacheMu.RUnlock()
 
+	// Host unreachable: show the last-known snapshot, read-only
+	if h.isRemoteHostOffline(selected) {
+		since := "no snapshot yet"
+		if snap := session.GetRemoteSessionSnapshot(h.profile, selected.RemoteHost, selected.ID); snap != nil {
+			since = "last-known output from " + snap.LastSeen.Format("Jan 2 15:04")
+			if !hasCached && snap.Preview != "" {
+				preview, hasCached = snap.Preview, true
+			}
+		}
+		offlineStyle := lipgloss.NewStyle().Foreground(ColorYellow).Bold(true)
+		b.WriteString(offlineStyle.Render(fmt.Sprintf("⊘ %s unreachable: %s (read-only)", selected.RemoteHost, since)))
+		b.WriteString("\n\n")
+	}
+
 	// Show forking animation when fork is in progress (highest priority)
 	if showForkingAnimation {
 		b.WriteString("\n")
@@ -6850,6 +7573,90 @@ func formatRelativeTime(t time.Time) string {
 	}
 }
 
+// renderHostResources renders the load, memory, disk and agent count of an
+// SSH host, as last collected by the pool's health checker
+func (h *Home) renderHostResources(hostID string, width int) string {
+	var b strings.Builder
+	b.WriteString(renderSectionDivider("Host", width-4))
+	b.WriteString("\n")
+
+	labelStyle := lipgloss.NewStyle().Foreground(ColorTextDim)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorText)
+	warnStyle := lipgloss.NewStyle().Foreground(ColorYellow)
+
+	if connected, checked := h.sshHostConnected[hostID]; checked && !connected {
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorRed).Render("  ⊘ " + hostID + " unreachable"))
+		b.WriteString("\n\n")
+		return b.String()
+	}
+	r := h.sshHostResources[hostID]
+	if r == nil {
+		b.WriteString(DimStyle.Render("  Collecting host stats..."))
+		b.WriteString("\n\n")
+		return b.String()
+	}
+
+	load := fmt.Sprintf("%.2f %.2f %.2f", r.Load1, r.Load5, r.Load15)
+	if r.CPUs > 0 {
+		load += fmt.Sprintf(" (%d CPUs)", r.CPUs)
+	}
+	b.WriteString(fmt.Sprintf("  %s %s\n", labelStyle.Render("Load:  "), valueStyle.Render(load)))
+	if r.MemTotal > 0 {
+		b.WriteString(fmt.Sprintf("  %s %s\n", labelStyle.Render("Memory:"),
+			valueStyle.Render(fmt.Sprintf("%s free of %s", sshpkg.FormatBytes(r.MemAvailable), sshpkg.FormatBytes(r.MemTotal)))))
+	}
+	if r.DiskTotal > 0 {
+		b.WriteString(fmt.Sprintf("  %s %s\n", labelStyle.Render("Disk:  "),
+			valueStyle.Render(fmt.Sprintf("%s free of %s", sshpkg.FormatBytes(r.DiskFree), sshpkg.FormatBytes(r.DiskTotal)))))
+	}
+	b.WriteString(fmt.Sprintf("  %s %s\n", labelStyle.Render("Agents:"), valueStyle.Render(fmt.Sprintf("%d running", r.AgentProcesses))))
+	for _, w := range r.Warnings() {
+		b.WriteString(warnStyle.Render("  ⚠ " + w))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderPortForwards renders a remote session's port forwards and whether
+// each is open
+func (h *Home) renderPortForwards(inst *session.Instance, width int) string {
+	var b strings.Builder
+	b.WriteString(renderSectionDivider("Ports", width-4))
+	b.WriteString("\n")
+
+	states := make(map[int]session.PortForwardState)
+	for _, st := range h.portForwards.States(inst.ID) {
+		states[st.LocalPort] = st
+	}
+	openStyle := lipgloss.NewStyle().Foreground(ColorGreen)
+	errStyle := lipgloss.NewStyle().Foreground(ColorRed)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorText)
+	for _, f := range inst.PortForwards {
+		line := fmt.Sprintf("localhost:%d → %s:%d", f.LocalPort, inst.RemoteHost, f.RemotePort)
+		if f.Auto {
+			line += " (auto)"
+		}
+		b.WriteString("  ")
+		b.WriteString(valueStyle.Render(truncatePath(line, width-8)))
+		st, held := states[f.LocalPort]
+		switch {
+		case !h.isPrimaryInstance:
+			b.WriteString(DimStyle.Render("  held by primary instance"))
+		case inst.Status == session.StatusError:
+			b.WriteString(DimStyle.Render("  ○ session stopped"))
+		case held && st.Open:
+			b.WriteString(openStyle.Render("  ● open"))
+		case held && st.Error != "":
+			b.WriteString(errStyle.Render("  ✕ " + st.Error))
+		default:
+			b.WriteString(DimStyle.Render("  ◌ opening"))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
 // renderGroupPreview renders the preview pane for a group
 func (h *Home) renderGroupPreview(group *session.Group, width, height int) string {
 	var b strings.Builder
@@ -6903,6 +7710,11 @@ func (h *Home) renderGroupPreview(group *session.Group, width, height int) strin
 		b.WriteString("\n\n")
 	}
 
+	// Host panel for remote host groups
+	if hostID, isRemote := session.GetSSHHostIDFromGroupPath(group.Path); isRemote {
+		b.WriteString(h.renderHostResources(hostID, width))
+	}
+
 	// Sessions divider
 	b.WriteString(renderSectionDivider("Sessions", width-4))
 	b.WriteString("\n")
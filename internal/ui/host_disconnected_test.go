@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
 )
 
 // setupSSHHostConfigForUI creates a temp HOME with a config.toml containing SSH host
@@ -201,3 +202,80 @@ group_name = "Jeeves"
 		t.Errorf("Local groups should never show disconnected indicator.\nGot: %q", output)
 	}
 }
+
+// TestRenderGroupPreview_ShowsHostResources verifies the host panel of a
+// remote group shows the host's load and flags an overloaded host.
+func TestRenderGroupPreview_ShowsHostResources(t *testing.T) {
+	setupSSHHostConfigForUI(t, `
+[ssh_hosts.jeeves]
+host = "192.168.1.100"
+group_name = "Jeeves"
+`)
+
+	group := &session.Group{
+		Name:     "Jeeves",
+		Path:     "remote/Jeeves",
+		Expanded: true,
+		Sessions: []*session.Instance{},
+	}
+	h := newMinimalHomeWithGroup(group, map[string]bool{"jeeves": true})
+	h.sshHostResources = map[string]*sshpkg.HostResources{
+		"jeeves": {Load1: 9.5, Load5: 8, Load15: 7, CPUs: 4, MemTotal: 8 << 30, MemAvailable: 4 << 30, AgentProcesses: 5},
+	}
+
+	output := h.renderGroupPreview(group, 80, 40)
+	for _, want := range []string{"9.50 8.00 7.00 (4 CPUs)", "4.0G free of 8.0G", "5 running", "load 9.50 on 4 CPUs"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("host panel missing %q.\nGot: %s", want, output)
+		}
+	}
+
+	// Local groups have no host panel
+	local := &session.Group{Name: "work", Path: "work", Sessions: []*session.Instance{}}
+	if output := h.renderGroupPreview(local, 80, 40); strings.Contains(output, "Load:") {
+		t.Errorf("local group should not show host stats.\nGot: %s", output)
+	}
+}
+
+// TestNewDialog_HostWarning verifies the overload warning is shown and
+// cleared when the dialog is reopened.
+func TestNewDialog_HostWarning(t *testing.T) {
+	d := NewNewDialog()
+	d.SetSize(120, 50)
+	d.ShowInGroup("remote/Jeeves", "Jeeves", "")
+	d.SetHostWarning("jeeves is overloaded: load 9.50 on 4 CPUs")
+	if !strings.Contains(d.View(), "jeeves is overloaded") {
+		t.Error("expected host warning in the dialog")
+	}
+
+	d.ShowInGroup("work", "work", "")
+	if strings.Contains(d.View(), "overloaded") {
+		t.Error("ShowInGroup should clear the host warning")
+	}
+}
+
+// TestRenderPortForwards verifies a remote session's forwards are listed
+// with their state in the preview.
+func TestRenderPortForwards(t *testing.T) {
+	inst := session.NewInstance("web", "/srv/web")
+	inst.RemoteHost = "jeeves"
+	inst.Status = session.StatusRunning
+	inst.AddPortForward(session.PortForward{LocalPort: 8080, RemotePort: 3000})
+	inst.AddPortForward(session.PortForward{LocalPort: 5173, RemotePort: 5173, Auto: true})
+
+	h := newMinimalHomeWithGroup(&session.Group{Name: "Jeeves", Path: "remote/Jeeves"}, nil)
+	h.portForwards = session.NewPortForwardManager()
+	h.isPrimaryInstance = true
+
+	output := h.renderPortForwards(inst, 80)
+	for _, want := range []string{"localhost:8080 → jeeves:3000", "localhost:5173 → jeeves:5173 (auto)", "opening"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("ports panel missing %q.\nGot: %s", want, output)
+		}
+	}
+
+	inst.Status = session.StatusError
+	if output := h.renderPortForwards(inst, 80); !strings.Contains(output, "session stopped") {
+		t.Errorf("stopped session should show its forwards as stopped.\nGot: %s", output)
+	}
+}
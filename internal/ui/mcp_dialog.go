@@ -2,6 +2,7 @@ package ui
 
 import (
 	"log"
+	"strings"
 
 	"github.com/asheshgoplani/agent-deck/internal/session"
 	tea "github.com/charmbracelet/bubbletea"
@@ -31,6 +32,8 @@ type MCPItem struct {
 	Description string
 	IsOrphan    bool // True if MCP is attached but not in config.toml pool
 	IsPooled    bool // True if this MCP uses socket pool
+	IsBundle    bool // True if this item is an [mcp_bundles] entry, not a single MCP
+	Members     []string
 }
 
 // MCPDialog handles MCP management for Claude and Gemini sessions
@@ -67,6 +70,11 @@ type MCPDialog struct {
 	globalChanged bool
 	userChanged   bool // USER scope changed
 
+	// Attached MCPs per scope when the dialog was shown (for the audit log)
+	shownLocal  []string
+	shownGlobal []string
+	shownUser   []string
+
 	err         error
 	configError string // Error message from config parsing
 }
@@ -237,6 +245,8 @@ func (m *MCPDialog) Show(projectPath string, sessionID string, tool string) erro
 		}
 	}
 
+	m.refreshBundleItems()
+
 	m.visible = true
 	m.projectPath = projectPath
 	// Gemini only has global scope, Claude starts with local
@@ -255,6 +265,9 @@ func (m *MCPDialog) Show(projectPath string, sessionID string, tool string) erro
 	m.localChanged = false
 	m.globalChanged = false
 	m.userChanged = false
+	m.shownLocal = mcpItemNames(m.localAttached)
+	m.shownGlobal = mcpItemNames(m.globalAttached)
+	m.shownUser = mcpItemNames(m.userAttached)
 	m.err = nil
 
 	return nil
@@ -282,6 +295,33 @@ func (m *MCPDialog) HasItems() bool {
 	return len(m.localAttached)+len(m.localAvailable)+len(m.globalAttached)+len(m.globalAvailable) > 0
 }
 
+// AttachChanges returns the MCPs attached and detached since the dialog was
+// shown, each with its scope, e.g. "exa (local)"
+func (m *MCPDialog) AttachChanges() (attached, detached []string) {
+	for _, scope := range []struct {
+		name    string
+		changed bool
+		shown   []string
+		current []MCPItem
+	}{
+		{"local", m.localChanged, m.shownLocal, m.localAttached},
+		{"global", m.globalChanged, m.shownGlobal, m.globalAttached},
+		{"user", m.userChanged, m.shownUser, m.userAttached},
+	} {
+		if !scope.changed {
+			continue
+		}
+		current := mcpItemNames(scope.current)
+		for _, name := range session.SubtractMCPNames(current, scope.shown) {
+			attached = append(attached, name+" ("+scope.name+")")
+		}
+		for _, name := range session.SubtractMCPNames(scope.shown, current) {
+			detached = append(detached, name+" ("+scope.name+")")
+		}
+	}
+	return attached, detached
+}
+
 // HasChanged returns true if any MCPs were changed (any scope)
 func (m *MCPDialog) HasChanged() bool {
 	result := m.localChanged || m.globalChanged || m.userChanged
@@ -342,6 +382,14 @@ func (m *MCPDialog) Move() {
 	item := (*list)[*idx]
 	log.Printf("[MCP-DEBUG] Moving item: %q", item.Name)
 
+	if item.IsBundle {
+		m.moveBundle(item)
+		if *idx >= len(*list) && len(*list) > 0 {
+			*idx = len(*list) - 1
+		}
+		return
+	}
+
 	// Remove from current list
 	*list = append((*list)[:*idx], (*list)[*idx+1:]...)
 
@@ -383,6 +431,9 @@ func (m *MCPDialog) Move() {
 	log.Printf("[MCP-DEBUG] After Move: localChanged=%v, globalChanged=%v, userChanged=%v",
 		m.localChanged, m.globalChanged, m.userChanged)
 
+	// Bundle items may need to switch columns now that a member moved
+	m.refreshBundleItems()
+
 	// Adjust index if needed
 	if *idx >= len(*list) && len(*list) > 0 {
 		*idx = len(*list) - 1
@@ -397,10 +448,7 @@ func (m *MCPDialog) Apply() error {
 	if m.tool == "gemini" {
 		// Gemini: Only global scope, write to settings.json
 		if m.globalChanged {
-			enabledNames := make([]string, len(m.globalAttached))
-			for i, item := range m.globalAttached {
-				enabledNames[i] = item.Name
-			}
+			enabledNames := mcpItemNames(m.globalAttached)
 
 			if err := session.WriteGeminiMCPSettings(enabledNames); err != nil {
 				m.err = err
@@ -415,10 +463,7 @@ func (m *MCPDialog) Apply() error {
 	// Claude: Apply LOCAL changes
 	if m.localChanged {
 		// Get names of attached MCPs
-		enabledNames := make([]string, len(m.localAttached))
-		for i, item := range m.localAttached {
-			enabledNames[i] = item.Name
-		}
+		enabledNames := mcpItemNames(m.localAttached)
 
 		// Write to .mcp.json
 		if err := session.WriteMCPJsonFromConfig(m.projectPath, enabledNames); err != nil {
@@ -433,10 +478,7 @@ func (m *MCPDialog) Apply() error {
 	// Claude: Apply GLOBAL changes
 	if m.globalChanged {
 		// Get names of attached MCPs
-		enabledNames := make([]string, len(m.globalAttached))
-		for i, item := range m.globalAttached {
-			enabledNames[i] = item.Name
-		}
+		enabledNames := mcpItemNames(m.globalAttached)
 
 		// Write to Claude's global config
 		if err := session.WriteGlobalMCP(enabledNames); err != nil {
@@ -458,10 +500,7 @@ func (m *MCPDialog) Apply() error {
 	// Claude: Apply USER changes (affects ALL sessions!)
 	if m.userChanged {
 		// Get names of attached MCPs
-		enabledNames := make([]string, len(m.userAttached))
-		for i, item := range m.userAttached {
-			enabledNames[i] = item.Name
-		}
+		enabledNames := mcpItemNames(m.userAttached)
 
 		// Write to ~/.claude.json (ROOT config)
 		if err := session.WriteUserMCP(enabledNames); err != nil {
@@ -759,6 +798,10 @@ func (m *MCPDialog) renderColumn(title string, items []MCPItem, selectedIdx int,
 	} else {
 		for i, item := range items {
 			name := item.Name
+			// Bundles attach/detach all of their MCPs at once
+			if item.IsBundle {
+				name = "📦 " + name
+			}
 			// Add pool indicator for MCPs in socket pool
 			if item.IsPooled {
 				name = name + " 🔌"
@@ -798,6 +841,138 @@ func (m *MCPDialog) renderColumn(title string, items []MCPItem, selectedIdx int,
 	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
+// refreshBundleItems rebuilds bundle entries in every scope. A bundle is shown as
+// Attached when all of its MCPs are attached, and as Available when at least one
+// member can still be attached from that scope.
+func (m *MCPDialog) refreshBundleItems() {
+	bundles := session.GetMCPBundles()
+	var valid []string
+	for _, name := range session.GetMCPBundleNames() {
+		if len(bundles[name]) == 0 {
+			continue
+		}
+		// Skip bundles referencing MCPs missing from config.toml
+		if _, err := session.ResolveMCPBundles([]string{name}); err != nil {
+			continue
+		}
+		valid = append(valid, name)
+	}
+
+	refresh := func(attached, available *[]MCPItem) {
+		*attached = withoutBundles(*attached)
+		*available = withoutBundles(*available)
+
+		attachedNames := make(map[string]bool)
+		for _, item := range *attached {
+			attachedNames[item.Name] = true
+		}
+		availableNames := make(map[string]bool)
+		for _, item := range *available {
+			availableNames[item.Name] = true
+		}
+
+		var attachedBundles, availableBundles []MCPItem
+		for _, name := range valid {
+			members := bundles[name]
+			allAttached, anyAvailable := true, false
+			for _, member := range members {
+				if !attachedNames[member] {
+					allAttached = false
+				}
+				if availableNames[member] {
+					anyAvailable = true
+				}
+			}
+			item := MCPItem{
+				Name:        name,
+				Description: strings.Join(members, ", "),
+				IsBundle:    true,
+				Members:     members,
+			}
+			if allAttached {
+				attachedBundles = append(attachedBundles, item)
+			} else if anyAvailable {
+				availableBundles = append(availableBundles, item)
+			}
+		}
+
+		// Bundles are listed first in each column
+		*attached = append(attachedBundles, *attached...)
+		*available = append(availableBundles, *available...)
+	}
+
+	if m.tool == "gemini" {
+		refresh(&m.globalAttached, &m.globalAvailable)
+		return
+	}
+	refresh(&m.localAttached, &m.localAvailable)
+	refresh(&m.globalAttached, &m.globalAvailable)
+	refresh(&m.userAttached, &m.userAvailable)
+}
+
+// moveBundle moves every member of a bundle between the Attached and Available
+// columns of the current scope in one step
+func (m *MCPDialog) moveBundle(bundle MCPItem) {
+	var attached, available *[]MCPItem
+	var changed *bool
+	switch m.scope {
+	case MCPScopeLocal:
+		attached, available, changed = &m.localAttached, &m.localAvailable, &m.localChanged
+	case MCPScopeGlobal:
+		attached, available, changed = &m.globalAttached, &m.globalAvailable, &m.globalChanged
+	case MCPScopeUser:
+		attached, available, changed = &m.userAttached, &m.userAvailable, &m.userChanged
+	default:
+		return
+	}
+
+	from, to := available, attached
+	if m.column == MCPColumnAttached {
+		from, to = attached, available
+	}
+
+	members := make(map[string]bool, len(bundle.Members))
+	for _, name := range bundle.Members {
+		members[name] = true
+	}
+
+	kept := (*from)[:0]
+	for _, item := range *from {
+		if !item.IsBundle && members[item.Name] {
+			*to = append(*to, item)
+			*changed = true
+			continue
+		}
+		kept = append(kept, item)
+	}
+	*from = kept
+
+	log.Printf("[MCP-DEBUG] Moved bundle %q (%d MCPs), changed=%v", bundle.Name, len(bundle.Members), *changed)
+	m.refreshBundleItems()
+}
+
+// withoutBundles returns items with bundle entries removed
+func withoutBundles(items []MCPItem) []MCPItem {
+	result := make([]MCPItem, 0, len(items))
+	for _, item := range items {
+		if !item.IsBundle {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// mcpItemNames returns the MCP names in items, excluding bundle entries
+func mcpItemNames(items []MCPItem) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		if !item.IsBundle {
+			names = append(names, item.Name)
+		}
+	}
+	return names
+}
+
 // repeatStr repeats a string n times
 func repeatStr(s string, n int) string {
 	result := ""
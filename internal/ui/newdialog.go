@@ -35,6 +35,8 @@ type NewDialog struct {
 	branchInput     textinput.Model
 	// Gemini YOLO mode
 	geminiYoloMode bool
+	// Shown when the target SSH host is overloaded (remote groups only)
+	hostWarning string
 }
 
 // NewNewDialog creates a new NewDialog instance
@@ -95,6 +97,7 @@ func (d *NewDialog) ShowInGroup(groupPath, groupName, defaultPath string) {
 	}
 	d.parentGroupPath = groupPath
 	d.parentGroupName = groupName
+	d.hostWarning = ""
 	d.visible = true
 	d.focusIndex = 0
 	d.nameInput.SetValue("")
@@ -145,6 +148,12 @@ func (d *NewDialog) SetDefaultTool(tool string) {
 	d.commandCursor = 0
 }
 
+// SetHostWarning shows a warning about the SSH host the session will be
+// created on. Call after ShowInGroup, which clears it.
+func (d *NewDialog) SetHostWarning(warning string) {
+	d.hostWarning = warning
+}
+
 // GetSelectedGroup returns the parent group path
 func (d *NewDialog) GetSelectedGroup() string {
 	return d.parentGroupPath
@@ -567,6 +576,11 @@ func (d *NewDialog) View() string {
 	groupInfoStyle := lipgloss.NewStyle().Foreground(ColorPurple) // Purple for group context
 	content.WriteString(groupInfoStyle.Render("  in group: " + d.parentGroupName))
 	content.WriteString("\n\n")
+	if d.hostWarning != "" {
+		warnStyle := lipgloss.NewStyle().Foreground(ColorYellow).Width(dialogWidth - 8)
+		content.WriteString(warnStyle.Render("  ⚠ " + d.hostWarning))
+		content.WriteString("\n\n")
+	}
 
 	// Name input
 	if d.focusIndex == 0 {
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+// notesPreviewLines is how many lines of a session's notes the preview pane
+// shows
+const notesPreviewLines = 6
+
+// notesEditedMsg signals that the notes editor exited
+type notesEditedMsg struct {
+	sessionID string
+	err       error
+}
+
+// reloadNotes reads the notes of the profile's sessions
+func (h *Home) reloadNotes() {
+	notes, err := session.LoadAllNotes(h.profile)
+	if err != nil {
+		log.Printf("[NOTES] %v", err)
+		return
+	}
+	h.notes = notes
+}
+
+// editNotes suspends the TUI and opens a session's notes in $EDITOR
+func (h *Home) editNotes(inst *session.Instance) tea.Cmd {
+	path, err := session.NotesPath(h.profile, inst.ID)
+	if err != nil {
+		h.setError(fmt.Errorf("cannot edit notes: %w", err))
+		return nil
+	}
+	cmd, err := session.NotesEditorCommand(path)
+	if err != nil {
+		h.setError(fmt.Errorf("cannot edit notes: %w", err))
+		return nil
+	}
+	id := inst.ID
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return notesEditedMsg{sessionID: id, err: err}
+	})
+}
+
+// handleNotesEdited picks up the edited notes
+func (h *Home) handleNotesEdited(msg notesEditedMsg) {
+	if msg.err != nil {
+		h.setError(fmt.Errorf("notes editor failed: %w", msg.err))
+	}
+	notes, err := session.LoadNotes(h.profile, msg.sessionID)
+	if err == nil {
+		// Removes the file if the notes were left blank
+		err = session.SaveNotes(h.profile, msg.sessionID, notes)
+	}
+	if err != nil {
+		h.setError(err)
+		return
+	}
+	if h.notes == nil {
+		h.notes = make(map[string]string)
+	}
+	if strings.TrimSpace(notes) == "" {
+		delete(h.notes, msg.sessionID)
+	} else {
+		h.notes[msg.sessionID] = notes
+	}
+}
+
+// renderNotes renders the first lines of a session's notes for the preview
+// pane
+func renderNotes(notes string, width int) string {
+	var b strings.Builder
+	b.WriteString(renderSectionDivider("Notes", width-4))
+	b.WriteString("\n")
+
+	lines := strings.Split(strings.TrimRight(notes, "\n"), "\n")
+	shown := lines
+	if len(shown) > notesPreviewLines {
+		shown = shown[:notesPreviewLines]
+	}
+	textStyle := lipgloss.NewStyle().Foreground(ColorText)
+	for _, line := range shown {
+		b.WriteString("  ")
+		b.WriteString(textStyle.Render(runewidth.Truncate(line, max(width-6, 10), "...")))
+		b.WriteString("\n")
+	}
+	if more := len(lines) - len(shown); more > 0 {
+		b.WriteString(DimStyle.Render(fmt.Sprintf("  … %d more lines (E to edit)", more)))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,338 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// profilesViewRows is how many sessions the all-profiles view lists at once
+const profilesViewRows = 14
+
+// ProfileTransferDialog picks the profile to move or copy a session to
+// (agent-deck session move-profile)
+type ProfileTransferDialog struct {
+	visible  bool
+	width    int
+	height   int
+	session  *session.Instance
+	profiles []string
+	cursor   int
+	status   string
+}
+
+// NewProfileTransferDialog creates a new profile transfer dialog
+func NewProfileTransferDialog() *ProfileTransferDialog {
+	return &ProfileTransferDialog{}
+}
+
+// Show opens the dialog for inst with the profiles it can go to
+func (d *ProfileTransferDialog) Show(inst *session.Instance, profiles []string) {
+	d.visible = true
+	d.session = inst
+	d.profiles = profiles
+	d.cursor = 0
+	d.status = ""
+}
+
+// Hide hides the dialog
+func (d *ProfileTransferDialog) Hide() {
+	d.visible = false
+	d.session = nil
+}
+
+// IsVisible returns whether the dialog is visible
+func (d *ProfileTransferDialog) IsVisible() bool {
+	return d.visible
+}
+
+// Session returns the session being transferred
+func (d *ProfileTransferDialog) Session() *session.Instance {
+	return d.session
+}
+
+// Selected returns the profile under the cursor, or ""
+func (d *ProfileTransferDialog) Selected() string {
+	if d.cursor < 0 || d.cursor >= len(d.profiles) {
+		return ""
+	}
+	return d.profiles[d.cursor]
+}
+
+// SetError shows a failed transfer below the list
+func (d *ProfileTransferDialog) SetError(err error) {
+	d.status = err.Error()
+}
+
+// SetSize sets the dialog dimensions
+func (d *ProfileTransferDialog) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// Update handles navigation keys
+func (d *ProfileTransferDialog) Update(msg tea.KeyMsg) (*ProfileTransferDialog, tea.Cmd) {
+	switch msg.String() {
+	case "down", "j":
+		if d.cursor < len(d.profiles)-1 {
+			d.cursor++
+		}
+	case "up", "k":
+		if d.cursor > 0 {
+			d.cursor--
+		}
+	}
+	return d, nil
+}
+
+// View renders the dialog
+func (d *ProfileTransferDialog) View() string {
+	if !d.visible || d.session == nil {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorCyan)
+	textStyle := lipgloss.NewStyle().Foreground(ColorText)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+
+	dialogWidth := 56
+	if d.width > 0 && d.width < dialogWidth+10 {
+		dialogWidth = d.width - 10
+		if dialogWidth < 36 {
+			dialogWidth = 36
+		}
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(1, 2).
+		Width(dialogWidth)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render("Move to Profile"))
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render(truncatePath(d.session.Title, dialogWidth-6) + " and its sub-sessions"))
+	b.WriteString("\n\n")
+
+	if len(d.profiles) == 0 {
+		b.WriteString(dimStyle.Render("  No other profiles (agent-deck profile create <name>)"))
+		b.WriteString("\n")
+	}
+	for i, p := range d.profiles {
+		if i == d.cursor {
+			b.WriteString(selectedStyle.Render("▶ " + p))
+		} else {
+			b.WriteString(textStyle.Render("  " + p))
+		}
+		b.WriteString("\n")
+	}
+
+	if d.status != "" {
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorRed).Width(dialogWidth - 4).Render(d.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Enter move │ c copy │ Esc cancel │ ↑↓ select"))
+
+	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
+
+// ProfileSession is a session listed in the all-profiles view
+type ProfileSession struct {
+	Profile string
+	Session *session.Instance
+}
+
+// ProfilesView lists the sessions of every profile, grouped by profile, so
+// they can be attached to or pulled into the current profile
+type ProfilesView struct {
+	visible  bool
+	width    int
+	height   int
+	current  string
+	sessions []ProfileSession // Sorted by profile
+	cursor   int
+	offset   int
+	status   string
+	failed   bool
+}
+
+// NewProfilesView creates a new all-profiles view
+func NewProfilesView() *ProfilesView {
+	return &ProfilesView{}
+}
+
+// Show opens the view. current is the TUI's profile; sessions must be
+// sorted by profile.
+func (v *ProfilesView) Show(current string, sessions []ProfileSession) {
+	v.visible = true
+	v.current = current
+	v.sessions = sessions
+	v.cursor = 0
+	v.offset = 0
+	v.status = ""
+	v.failed = false
+}
+
+// Hide hides the view
+func (v *ProfilesView) Hide() {
+	v.visible = false
+	v.sessions = nil
+}
+
+// IsVisible returns whether the view is visible
+func (v *ProfilesView) IsVisible() bool {
+	return v.visible
+}
+
+// Selected returns the session under the cursor, or nil
+func (v *ProfilesView) Selected() *ProfileSession {
+	if v.cursor < 0 || v.cursor >= len(v.sessions) {
+		return nil
+	}
+	return &v.sessions[v.cursor]
+}
+
+// SetStatus shows a message below the list
+func (v *ProfilesView) SetStatus(status string, failed bool) {
+	v.status = status
+	v.failed = failed
+}
+
+// SetSize sets the view dimensions
+func (v *ProfilesView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+}
+
+// Update handles navigation keys
+func (v *ProfilesView) Update(msg tea.KeyMsg) (*ProfilesView, tea.Cmd) {
+	switch msg.String() {
+	case "down", "j":
+		if v.cursor < len(v.sessions)-1 {
+			v.cursor++
+		}
+	case "up", "k":
+		if v.cursor > 0 {
+			v.cursor--
+		}
+	case "home", "g":
+		v.cursor = 0
+	case "end", "G":
+		v.cursor = len(v.sessions) - 1
+		if v.cursor < 0 {
+			v.cursor = 0
+		}
+	}
+	if v.cursor < v.offset {
+		v.offset = v.cursor
+	}
+	if v.cursor >= v.offset+profilesViewRows {
+		v.offset = v.cursor - profilesViewRows + 1
+	}
+	return v, nil
+}
+
+// View renders the view
+func (v *ProfilesView) View() string {
+	if !v.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorCyan)
+	headerStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorPurple)
+	textStyle := lipgloss.NewStyle().Foreground(ColorText)
+	selectedStyle := lipgloss.NewStyle().Foreground(ColorAccent).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorComment)
+
+	dialogWidth := 80
+	if v.width > 0 && v.width < dialogWidth+10 {
+		dialogWidth = v.width - 10
+		if dialogWidth < 40 {
+			dialogWidth = 40
+		}
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(1, 2).
+		Width(dialogWidth)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("All Profiles (%d sessions)", len(v.sessions))))
+	b.WriteString("\n\n")
+
+	if len(v.sessions) == 0 {
+		b.WriteString(dimStyle.Render("  No sessions"))
+		b.WriteString("\n")
+	}
+
+	end := v.offset + profilesViewRows
+	if end > len(v.sessions) {
+		end = len(v.sessions)
+	}
+	for i := v.offset; i < end; i++ {
+		s := v.sessions[i]
+		if i == v.offset || v.sessions[i-1].Profile != s.Profile {
+			header := s.Profile
+			if s.Profile == v.current {
+				header += " (current)"
+			}
+			b.WriteString(headerStyle.Render(header))
+			b.WriteString("\n")
+		}
+
+		statusIcon, statusColor := "○", ColorTextDim
+		switch s.Session.Status {
+		case session.StatusRunning:
+			statusIcon, statusColor = "●", ColorGreen
+		case session.StatusWaiting:
+			statusIcon, statusColor = "◐", ColorYellow
+		case session.StatusError:
+			statusIcon, statusColor = "✕", ColorRed
+		}
+
+		prefix, style := "  ", textStyle
+		if i == v.cursor {
+			prefix, style = "▶ ", selectedStyle
+		}
+		title := s.Session.Title
+		if s.Session.RemoteHost != "" {
+			title += " @" + s.Session.RemoteHost
+		}
+		b.WriteString(style.Render(prefix))
+		b.WriteString(lipgloss.NewStyle().Foreground(statusColor).Render(statusIcon))
+		b.WriteString(style.Render(" " + truncatePath(title, dialogWidth/2)))
+		if s.Session.GroupPath != "" {
+			b.WriteString(dimStyle.Render("  " + s.Session.GroupPath))
+		}
+		b.WriteString("\n")
+	}
+	if len(v.sessions) > profilesViewRows {
+		b.WriteString(dimStyle.Render(fmt.Sprintf("  %d-%d of %d", v.offset+1, end, len(v.sessions))))
+		b.WriteString("\n")
+	}
+
+	if v.status != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(ColorGreen)
+		if v.failed {
+			statusStyle = lipgloss.NewStyle().Foreground(ColorRed)
+		}
+		b.WriteString("\n")
+		b.WriteString(statusStyle.Width(dialogWidth - 4).Render(v.status))
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(dimStyle.Render("Enter attach │ m move here │ c copy here │ Esc close │ ↑↓ select"))
+
+	return lipgloss.Place(v.width, v.height, lipgloss.Center, lipgloss.Center, boxStyle.Render(b.String()))
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestProfileTransferDialog_Select(t *testing.T) {
+	d := NewProfileTransferDialog()
+	inst := &session.Instance{ID: "a", Title: "api"}
+	d.Show(inst, []string{"personal", "work"})
+	if !d.IsVisible() || d.Session() != inst || d.Selected() != "personal" {
+		t.Fatalf("after Show: visible=%v selected=%q", d.IsVisible(), d.Selected())
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if d.Selected() != "work" {
+		t.Errorf("cursor should stop on the last profile, got %q", d.Selected())
+	}
+
+	d.Show(inst, nil)
+	if d.Selected() != "" {
+		t.Errorf("no profiles should mean no selection, got %q", d.Selected())
+	}
+	if !strings.Contains(d.View(), "No other profiles") {
+		t.Error("View should explain there's nowhere to move to")
+	}
+}
+
+func TestProfilesView_GroupsByProfile(t *testing.T) {
+	v := NewProfilesView()
+	v.SetSize(120, 40)
+	v.Show("work", []ProfileSession{
+		{Profile: "personal", Session: &session.Instance{ID: "a", Title: "blog"}},
+		{Profile: "work", Session: &session.Instance{ID: "b", Title: "api", Status: session.StatusRunning}},
+		{Profile: "work", Session: &session.Instance{ID: "c", Title: "web"}},
+	})
+
+	view := v.View()
+	for _, want := range []string{"personal", "work (current)", "blog", "api", "web"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View missing %q", want)
+		}
+	}
+	if strings.Count(view, "work (current)") != 1 {
+		t.Error("each profile should get one header")
+	}
+
+	v.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if s := v.Selected(); s == nil || s.Profile != "work" || s.Session.ID != "b" {
+		t.Errorf("after down: selected=%+v", s)
+	}
+}
@@ -0,0 +1,271 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// remoteSyncDoneMsg reports the result of a push or pull started from the
+// remote sync dialog
+type remoteSyncDoneMsg struct {
+	sessionID string
+	push      bool
+	result    *sshpkg.SyncResult
+	err       error
+}
+
+// RemoteSyncDialog copies files between this machine and a remote session's
+// project (agent-deck remote push/pull). It stays open to show the result.
+type RemoteSyncDialog struct {
+	visible     bool
+	width       int
+	height      int
+	sessionID   string
+	title       string
+	host        string
+	projectPath string
+	push        bool
+	focusIndex  int // 0=direction, 1=local path, 2=remote path
+	localInput  textinput.Model
+	remoteInput textinput.Model
+	running     bool
+	status      string
+	failed      bool
+}
+
+// NewRemoteSyncDialog creates a new remote sync dialog
+func NewRemoteSyncDialog() *RemoteSyncDialog {
+	localInput := textinput.New()
+	localInput.Placeholder = "Local file or directory"
+	localInput.CharLimit = 512
+	localInput.Width = 44
+
+	remoteInput := textinput.New()
+	remoteInput.Placeholder = "Project directory"
+	remoteInput.CharLimit = 512
+	remoteInput.Width = 44
+
+	return &RemoteSyncDialog{
+		localInput:  localInput,
+		remoteInput: remoteInput,
+		push:        true,
+	}
+}
+
+// Show opens the dialog for a remote session. The local path defaults to
+// the working directory and the remote path to the session's project.
+func (d *RemoteSyncDialog) Show(inst *session.Instance) {
+	d.visible = true
+	d.sessionID = inst.ID
+	d.title = inst.Title
+	d.host = inst.RemoteHost
+	d.projectPath = inst.ProjectPath
+	d.running = false
+	d.status = ""
+	d.failed = false
+	if cwd, err := os.Getwd(); err == nil {
+		d.localInput.SetValue(cwd)
+	}
+	d.remoteInput.SetValue("")
+	d.focusIndex = 1
+	d.updateFocus()
+}
+
+// Hide hides the dialog
+func (d *RemoteSyncDialog) Hide() {
+	d.visible = false
+	d.localInput.Blur()
+	d.remoteInput.Blur()
+}
+
+// IsVisible returns whether the dialog is visible
+func (d *RemoteSyncDialog) IsVisible() bool {
+	return d.visible
+}
+
+// IsRunning returns whether a transfer is in progress
+func (d *RemoteSyncDialog) IsRunning() bool {
+	return d.running
+}
+
+// SessionID returns the session the dialog was opened for
+func (d *RemoteSyncDialog) SessionID() string {
+	return d.sessionID
+}
+
+// IsPush returns true for push (local → remote), false for pull
+func (d *RemoteSyncDialog) IsPush() bool {
+	return d.push
+}
+
+// GetPaths returns the local and remote paths. An empty remote path means
+// the session's project directory.
+func (d *RemoteSyncDialog) GetPaths() (local, remote string) {
+	remote = strings.TrimSpace(d.remoteInput.Value())
+	if remote == "" {
+		remote = "."
+	}
+	return strings.TrimSpace(d.localInput.Value()), remote
+}
+
+// Validate returns an error message if the dialog can't start a transfer
+func (d *RemoteSyncDialog) Validate() string {
+	if local, _ := d.GetPaths(); local == "" {
+		return "Local path cannot be empty"
+	}
+	return ""
+}
+
+// SetRunning marks a transfer as started
+func (d *RemoteSyncDialog) SetRunning() {
+	d.running = true
+	d.failed = false
+	if d.push {
+		d.status = "Pushing..."
+	} else {
+		d.status = "Pulling..."
+	}
+}
+
+// SetResult shows the outcome of a transfer
+func (d *RemoteSyncDialog) SetResult(result *sshpkg.SyncResult, err error) {
+	d.running = false
+	if err != nil {
+		d.failed = true
+		d.status = err.Error()
+		return
+	}
+	d.failed = false
+	d.status = fmt.Sprintf("✓ %d copied, %d unchanged", len(result.Transferred), result.Unchanged)
+}
+
+// SetSize sets the dialog dimensions
+func (d *RemoteSyncDialog) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+}
+
+// Update handles input events
+func (d *RemoteSyncDialog) Update(msg tea.KeyMsg) (*RemoteSyncDialog, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "down":
+		d.focusIndex = (d.focusIndex + 1) % 3
+		d.updateFocus()
+		return d, nil
+	case "shift+tab", "up":
+		d.focusIndex = (d.focusIndex + 2) % 3
+		d.updateFocus()
+		return d, nil
+	}
+
+	var cmd tea.Cmd
+	switch d.focusIndex {
+	case 0:
+		switch msg.String() {
+		case " ", "left", "right", "h", "l":
+			d.push = !d.push
+		}
+	case 1:
+		d.localInput, cmd = d.localInput.Update(msg)
+	case 2:
+		d.remoteInput, cmd = d.remoteInput.Update(msg)
+	}
+	return d, cmd
+}
+
+func (d *RemoteSyncDialog) updateFocus() {
+	d.localInput.Blur()
+	d.remoteInput.Blur()
+	switch d.focusIndex {
+	case 1:
+		d.localInput.Focus()
+	case 2:
+		d.remoteInput.Focus()
+	}
+}
+
+// View renders the dialog
+func (d *RemoteSyncDialog) View() string {
+	if !d.visible {
+		return ""
+	}
+
+	titleStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(ColorCyan)
+
+	labelStyle := lipgloss.NewStyle().
+		Foreground(ColorText)
+
+	activeLabelStyle := lipgloss.NewStyle().
+		Foreground(ColorAccent).
+		Bold(true)
+
+	dimStyle := lipgloss.NewStyle().
+		Foreground(ColorComment)
+
+	// Responsive dialog width
+	dialogWidth := 56
+	if d.width > 0 && d.width < dialogWidth+10 {
+		dialogWidth = d.width - 10
+		if dialogWidth < 35 {
+			dialogWidth = 35
+		}
+	}
+
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorAccent).
+		Padding(1, 2).
+		Width(dialogWidth)
+
+	label := func(index int, text string) string {
+		if d.focusIndex == index {
+			return activeLabelStyle.Render("▶ " + text)
+		}
+		return labelStyle.Render("  " + text)
+	}
+
+	pushOpt, pullOpt := "Push (local → remote)", "Pull (remote → local)"
+	selected := lipgloss.NewStyle().Foreground(ColorBg).Background(ColorAccent).Bold(true).Padding(0, 1)
+	unselected := lipgloss.NewStyle().Foreground(ColorText).Padding(0, 1)
+	var direction string
+	if d.push {
+		direction = selected.Render(pushOpt) + " " + unselected.Render(pullOpt)
+	} else {
+		direction = unselected.Render(pushOpt) + " " + selected.Render(pullOpt)
+	}
+
+	content := titleStyle.Render("Sync Files: "+d.title) + "\n" +
+		dimStyle.Render(d.host+":"+d.projectPath) + "\n\n" +
+		label(0, "Direction:") + "\n" +
+		"  " + direction + "\n\n" +
+		label(1, "Local path:") + "\n" +
+		"  " + d.localInput.View() + "\n\n" +
+		label(2, "Remote path (relative to project):") + "\n" +
+		"  " + d.remoteInput.View() + "\n"
+
+	if d.status != "" {
+		statusStyle := lipgloss.NewStyle().Foreground(ColorGreen)
+		if d.failed {
+			statusStyle = lipgloss.NewStyle().Foreground(ColorRed)
+		} else if d.running {
+			statusStyle = lipgloss.NewStyle().Foreground(ColorYellow)
+		}
+		content += "\n" + statusStyle.Width(dialogWidth-4).Render(d.status) + "\n"
+	}
+
+	content += "\n" + dimStyle.Render("Enter sync │ Esc close │ Tab next │ Space toggle")
+
+	dialog := boxStyle.Render(content)
+
+	// Center the dialog on screen
+	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, dialog)
+}
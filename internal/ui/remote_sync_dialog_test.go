@@ -0,0 +1,55 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	sshpkg "github.com/asheshgoplani/agent-deck/internal/ssh"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRemoteSyncDialog_ShowAndPaths(t *testing.T) {
+	d := NewRemoteSyncDialog()
+	if d.IsVisible() {
+		t.Error("Dialog should not be visible initially")
+	}
+
+	d.Show(&session.Instance{ID: "abc", Title: "api", RemoteHost: "dev", ProjectPath: "/srv/api"})
+	if !d.IsVisible() || d.SessionID() != "abc" || !d.IsPush() {
+		t.Errorf("after Show: visible=%v id=%q push=%v", d.IsVisible(), d.SessionID(), d.IsPush())
+	}
+	if local, remote := d.GetPaths(); local == "" || remote != "." {
+		t.Errorf("GetPaths() = %q, %q; want cwd and project", local, remote)
+	}
+
+	// Focus the direction selector and toggle it
+	d.Update(tea.KeyMsg{Type: tea.KeyShiftTab})
+	d.Update(tea.KeyMsg{Type: tea.KeySpace})
+	if d.IsPush() {
+		t.Error("space on the direction selector should switch to pull")
+	}
+
+	d.localInput.SetValue("  ")
+	if d.Validate() == "" {
+		t.Error("empty local path should not validate")
+	}
+}
+
+func TestRemoteSyncDialog_Result(t *testing.T) {
+	d := NewRemoteSyncDialog()
+	d.Show(&session.Instance{ID: "abc", Title: "api", RemoteHost: "dev"})
+
+	d.SetRunning()
+	if !d.IsRunning() {
+		t.Error("SetRunning should mark the dialog running")
+	}
+	d.SetResult(&sshpkg.SyncResult{Transferred: []string{"a", "b"}, Unchanged: 3}, nil)
+	if d.IsRunning() || d.status != "✓ 2 copied, 3 unchanged" {
+		t.Errorf("status = %q", d.status)
+	}
+	d.SetResult(nil, errors.New("boom"))
+	if !d.failed || d.status != "boom" {
+		t.Errorf("error status = %q failed=%v", d.status, d.failed)
+	}
+}
@@ -189,11 +189,15 @@ func (s *Search) View() string {
 	}
 
 	for i, item := range s.results {
+		label := item.Title + " (" + item.Tool + ")"
+		if len(item.Tags) > 0 {
+			label += " " + session.FormatTags(item.Tags)
+		}
 		var line string
 		if i == s.cursor {
-			line = selectedResultStyle.Render("› " + item.Title + " (" + item.Tool + ")")
+			line = selectedResultStyle.Render("› " + label)
 		} else {
-			line = resultItemStyle.Render("  " + item.Title + " (" + item.Tool + ")")
+			line = resultItemStyle.Render("  " + label)
 		}
 		resultsStr.WriteString(line)
 		if i < len(s.results)-1 {
@@ -212,7 +216,7 @@ func (s *Search) View() string {
 		hintStr = lipgloss.NewStyle().
 			Foreground(ColorComment).
 			Italic(true).
-			Render("  Tip: waiting / running / idle to filter by status")
+			Render("  Tip: waiting / running / idle, tag:<tag>, meta:<key>=<value>")
 	}
 
 	// Keyboard shortcuts hint
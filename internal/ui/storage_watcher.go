@@ -15,7 +15,8 @@ import (
 // This prevents the watcher from triggering reload when the TUI itself saves.
 const ignoreWindow = 500 * time.Millisecond
 
-// StorageWatcher monitors sessions.json for external changes
+// StorageWatcher monitors sessions.json (or sessions.db and its WAL) for
+// external changes
 type StorageWatcher struct {
 	watcher     *fsnotify.Watcher
 	storagePath string
@@ -63,17 +64,10 @@ func NewStorageWatcher(storagePath string) (*StorageWatcher, error) {
 		return nil, fmt.Errorf("failed to watch directory %s: %w", dir, err)
 	}
 
-	// Get initial mod time
-	info, _ := os.Stat(resolvedPath)
-	lastMod := time.Time{}
-	if info != nil {
-		lastMod = info.ModTime()
-	}
-
 	return &StorageWatcher{
 		watcher:      w,
 		storagePath:  resolvedPath, // Use pre-resolved path
-		lastModified: lastMod,
+		lastModified: storageModTime(resolvedPath),
 		reloadCh:     make(chan struct{}, 1), // Buffered to prevent blocking
 		closeCh:      make(chan struct{}),
 	}, nil
@@ -115,7 +109,8 @@ func (sw *StorageWatcher) watchLoop() {
 				}
 			}
 
-			if eventPath != sw.storagePath {
+			// SQLite commits land in the -wal file before being checkpointed
+			if eventPath != sw.storagePath && eventPath != sw.storagePath+"-wal" {
 				continue
 			}
 
@@ -151,9 +146,9 @@ func (sw *StorageWatcher) checkAndNotify() {
 	if time.Since(lastSave) < ignoreWindow {
 		// This change was likely caused by TUI's own save, ignore it
 		// Still update lastModified to avoid re-triggering later
-		if info, err := os.Stat(sw.storagePath); err == nil {
+		if modTime := storageModTime(sw.storagePath); !modTime.IsZero() {
 			sw.modMu.Lock()
-			sw.lastModified = info.ModTime()
+			sw.lastModified = modTime
 			sw.modMu.Unlock()
 		}
 		log.Printf("[WATCHER-DEBUG] Ignoring own save (path=%s, within %v window)", sw.storagePath, ignoreWindow)
@@ -166,7 +161,7 @@ func (sw *StorageWatcher) checkAndNotify() {
 		return // File might be temporarily gone during atomic rename
 	}
 
-	modTime := info.ModTime()
+	modTime := storageModTime(sw.storagePath)
 	sw.modMu.Lock()
 	if modTime.After(sw.lastModified) {
 		sw.lastModified = modTime
@@ -185,6 +180,18 @@ func (sw *StorageWatcher) checkAndNotify() {
 	}
 }
 
+// storageModTime returns the newest modification time of the storage file and
+// its SQLite WAL, or zero if neither exists
+func storageModTime(path string) time.Time {
+	var newest time.Time
+	for _, p := range []string{path, path + "-wal"} {
+		if info, err := os.Stat(p); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+		}
+	}
+	return newest
+}
+
 // ReloadChannel returns the channel that signals when reload is needed
 func (sw *StorageWatcher) ReloadChannel() <-chan struct{} {
 	return sw.reloadCh
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+)
+
+// syncPushDelay batches saves made in quick succession into one push
+const syncPushDelay = 3 * time.Second
+
+// syncEnabled reports whether the profile is synced through [sync]
+func syncEnabled() bool {
+	settings := session.GetSyncSettings()
+	return settings.Enabled && settings.Remote != ""
+}
+
+// markSyncDirty schedules a push after a save
+func (h *Home) markSyncDirty() {
+	h.syncDirtyAt.CompareAndSwap(0, time.Now().UnixNano())
+}
+
+// maybeSync starts a background sync when saves are waiting to be pushed or
+// the pull interval has passed. Called from the tick handler.
+func (h *Home) maybeSync() {
+	if !syncEnabled() {
+		return
+	}
+	if h.lastSync.IsZero() {
+		// Synced before the first load
+		h.lastSync = time.Now()
+	}
+	dirtyAt := h.syncDirtyAt.Load()
+	due := dirtyAt != 0 && time.Since(time.Unix(0, dirtyAt)) >= syncPushDelay
+	if interval := session.GetSyncSettings().Interval(); interval > 0 && time.Since(h.lastSync) >= interval {
+		due = true
+	}
+	if !due || !h.syncRunning.CompareAndSwap(false, true) {
+		return
+	}
+	h.lastSync = time.Now()
+	h.syncDirtyAt.Store(0)
+	go func() {
+		defer h.syncRunning.Store(false)
+		h.runSync()
+	}()
+}
+
+// runSync syncs the profile and leaves conflicts and errors for the main
+// loop to show. Pulled changes reach the TUI through the storage watcher.
+func (h *Home) runSync() {
+	result, err := session.SyncProfile(h.profile)
+	notice := ""
+	switch {
+	case err != nil:
+		log.Printf("[SYNC] %v", err)
+		notice = fmt.Sprintf("sync failed: %v", err)
+	case len(result.Conflicts) > 0:
+		log.Printf("[SYNC] %s", result.ConflictSummary())
+		notice = result.ConflictSummary()
+	default:
+		log.Printf("[SYNC] profile=%s sessions=%+v groups=%+v pushed=%v", result.Profile, result.Sessions, result.Groups, result.Pushed)
+	}
+	if notice != "" {
+		h.syncNoticeMu.Lock()
+		h.syncNotice = notice
+		h.syncNoticeMu.Unlock()
+	}
+}
+
+// showSyncNotice shows the last sync's conflicts or error
+func (h *Home) showSyncNotice() {
+	h.syncNoticeMu.Lock()
+	defer h.syncNoticeMu.Unlock()
+	if h.syncNotice != "" {
+		h.setError(fmt.Errorf("%s", h.syncNotice))
+		h.syncNotice = ""
+	}
+}
@@ -0,0 +1,268 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndoOps bounds the operation log
+const maxUndoOps = 100
+
+// layoutSnapshot is the structure of the group tree at one point: groups,
+// the order of sessions within them, and session titles. Restoring it reverts
+// moves, renames, reorders and group deletions.
+type layoutSnapshot struct {
+	groups   []*session.GroupData
+	sessions map[string][]string // group path -> session IDs in order
+	titles   map[string]string   // session ID -> title
+}
+
+// captureLayout snapshots the group tree
+func captureLayout(tree *session.GroupTree) *layoutSnapshot {
+	snap := &layoutSnapshot{
+		sessions: make(map[string][]string),
+		titles:   make(map[string]string),
+	}
+	if tree == nil {
+		return snap
+	}
+	for _, g := range tree.GroupList {
+		snap.groups = append(snap.groups, &session.GroupData{
+			Name:        g.Name,
+			Path:        g.Path,
+			Expanded:    g.Expanded,
+			Order:       g.Order,
+			DefaultPath: g.DefaultPath,
+		})
+		ids := make([]string, 0, len(g.Sessions))
+		for _, inst := range g.Sessions {
+			ids = append(ids, inst.ID)
+			snap.titles[inst.ID] = inst.Title
+		}
+		snap.sessions[g.Path] = ids
+	}
+	return snap
+}
+
+// restore puts instances back into the snapshot's layout and returns the
+// rebuilt tree. Sessions the snapshot doesn't know (created since) keep
+// their group; sessions it knows that no longer exist are skipped. Groups
+// keep their current expanded state.
+func (s *layoutSnapshot) restore(current *session.GroupTree, instances []*session.Instance) *session.GroupTree {
+	byID := make(map[string]*session.Instance, len(instances))
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+
+	ordered := make([]*session.Instance, 0, len(instances))
+	placed := make(map[string]bool, len(instances))
+	groups := make([]*session.GroupData, 0, len(s.groups))
+	for _, gd := range s.groups {
+		g := *gd
+		if current != nil {
+			if expanded, ok := current.Expanded[g.Path]; ok {
+				g.Expanded = expanded
+			}
+		}
+		groups = append(groups, &g)
+
+		for _, id := range s.sessions[g.Path] {
+			inst, ok := byID[id]
+			if !ok || placed[id] {
+				continue
+			}
+			inst.GroupPath = g.Path
+			if title, ok := s.titles[id]; ok {
+				inst.Title = title
+			}
+			ordered = append(ordered, inst)
+			placed[id] = true
+		}
+	}
+	for _, inst := range instances {
+		if !placed[inst.ID] {
+			ordered = append(ordered, inst)
+		}
+	}
+	return session.NewGroupTreeWithGroups(ordered, groups)
+}
+
+// undoOp is one reversible TUI operation
+type undoOp struct {
+	label   string
+	before  *layoutSnapshot
+	after   *layoutSnapshot
+	deleted []string // Sessions the operation deleted (restored from the archive on undo)
+}
+
+// undoLog is the undo/redo history of structural operations for the current
+// TUI run
+type undoLog struct {
+	done   []undoOp
+	undone []undoOp
+}
+
+// record adds an operation performed by the user, dropping the redo history
+func (l *undoLog) record(op undoOp) {
+	l.push(op)
+	l.undone = nil
+}
+
+// push adds an operation without touching the redo history (used by redo)
+func (l *undoLog) push(op undoOp) {
+	l.done = append(l.done, op)
+	if len(l.done) > maxUndoOps {
+		l.done = l.done[len(l.done)-maxUndoOps:]
+	}
+}
+
+// popUndo returns the last operation to undo and moves it to the redo list
+func (l *undoLog) popUndo() (undoOp, bool) {
+	if len(l.done) == 0 {
+		return undoOp{}, false
+	}
+	op := l.done[len(l.done)-1]
+	l.done = l.done[:len(l.done)-1]
+	l.undone = append(l.undone, op)
+	return op, true
+}
+
+// popRedo returns the last undone operation. Redo re-records it with push
+// once it has been performed again.
+func (l *undoLog) popRedo() (undoOp, bool) {
+	if len(l.undone) == 0 {
+		return undoOp{}, false
+	}
+	op := l.undone[len(l.undone)-1]
+	l.undone = l.undone[:len(l.undone)-1]
+	return op, true
+}
+
+// clear drops the whole history. Snapshots cover every session and group,
+// so undoing one taken before a reload would revert changes made elsewhere.
+func (l *undoLog) clear() {
+	l.done = nil
+	l.undone = nil
+}
+
+// cancelUndo reverts a popUndo whose operation could not be undone
+func (l *undoLog) cancelUndo() {
+	if len(l.undone) == 0 {
+		return
+	}
+	l.done = append(l.done, l.undone[len(l.undone)-1])
+	l.undone = l.undone[:len(l.undone)-1]
+}
+
+// recordLayoutChange adds a structural change made since before to the undo
+// log
+func (h *Home) recordLayoutChange(label string, before *layoutSnapshot) {
+	h.undo.record(undoOp{label: label, before: before, after: captureLayout(h.groupTree)})
+}
+
+// applyLayout restores a snapshot of the group tree and saves it
+func (h *Home) applyLayout(snap *layoutSnapshot) {
+	h.instancesMu.Lock()
+	titles := make(map[string]string, len(h.instances))
+	for _, inst := range h.instances {
+		titles[inst.ID] = inst.Title
+	}
+	h.groupTree = snap.restore(h.groupTree, h.instances)
+	h.instances = h.groupTree.GetAllInstances()
+	h.instancesMu.Unlock()
+
+	for _, inst := range h.instances {
+		if titles[inst.ID] != inst.Title {
+			h.invalidatePreviewCache(inst.ID)
+		}
+	}
+	h.cachedStatusCounts.valid.Store(false)
+	h.rebuildFlatItems()
+	h.search.SetItems(h.instances)
+	h.saveInstances()
+}
+
+// undoLast reverts the last structural operation. Deleted sessions are
+// restored from the archive and restarted.
+func (h *Home) undoLast() (tea.Model, tea.Cmd) {
+	if h.isReloading {
+		h.setError(fmt.Errorf("sessions are reloading, try again"))
+		return h, nil
+	}
+	op, ok := h.undo.popUndo()
+	if !ok {
+		return h, nil
+	}
+
+	var cmds []tea.Cmd
+	if len(op.deleted) > 0 {
+		archive, err := session.OpenArchive(h.profile)
+		if err != nil {
+			h.undo.cancelUndo()
+			h.setError(fmt.Errorf("cannot undo %s: %w", op.label, err))
+			return h, nil
+		}
+		for _, id := range op.deleted {
+			if h.getInstanceByID(id) != nil {
+				continue
+			}
+			entry, err := archive.Find(id)
+			if err == nil {
+				var inst *session.Instance
+				if inst, err = h.restoreArchivedSession(entry); err == nil {
+					cmds = append(cmds, h.reviveRestoredSession(inst), h.fetchPreview(inst))
+				}
+			}
+			if err != nil {
+				h.undo.cancelUndo()
+				h.setError(fmt.Errorf("cannot undo %s: %w", op.label, err))
+				return h, tea.Batch(cmds...)
+			}
+		}
+	}
+
+	h.applyLayout(op.before)
+	return h, tea.Batch(cmds...)
+}
+
+// redoLast performs the last undone operation again
+func (h *Home) redoLast() (tea.Model, tea.Cmd) {
+	if h.isReloading {
+		h.setError(fmt.Errorf("sessions are reloading, try again"))
+		return h, nil
+	}
+	op, ok := h.undo.popRedo()
+	if !ok {
+		return h, nil
+	}
+
+	// Deletions are re-recorded when sessionDeletedMsg arrives
+	if len(op.deleted) > 0 {
+		var cmds []tea.Cmd
+		for _, id := range op.deleted {
+			if inst := h.getInstanceByID(id); inst != nil {
+				cmds = append(cmds, h.redoDeleteSession(inst))
+			}
+		}
+		return h, tea.Batch(cmds...)
+	}
+
+	h.applyLayout(op.after)
+	h.undo.push(op)
+	return h, nil
+}
+
+// redoDeleteSession deletes a session again without dropping the redo history
+func (h *Home) redoDeleteSession(inst *session.Instance) tea.Cmd {
+	deleteCmd := h.deleteSession(inst)
+	return func() tea.Msg {
+		msg, ok := deleteCmd().(sessionDeletedMsg)
+		if !ok {
+			return nil
+		}
+		msg.redo = true
+		return msg
+	}
+}
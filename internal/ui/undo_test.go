@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/asheshgoplani/agent-deck/internal/session"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLayoutSnapshot_RestoresDeletedGroup(t *testing.T) {
+	api := session.NewInstance("api", "/srv/api")
+	api.GroupPath = "work"
+	web := session.NewInstance("web", "/srv/web")
+	web.GroupPath = "work"
+	tree := session.NewGroupTree([]*session.Instance{api, web})
+	tree.MoveSessionDown(api)
+
+	before := captureLayout(tree)
+	tree.DeleteGroup("work")
+	if api.GroupPath != session.DefaultGroupPath {
+		t.Fatalf("DeleteGroup left api in %q", api.GroupPath)
+	}
+
+	// A session created after the snapshot keeps its group
+	newer := session.NewInstance("newer", "/srv/newer")
+	newer.GroupPath = "scratch"
+	tree.AddSession(newer)
+
+	restored := before.restore(tree, tree.GetAllInstances())
+	group, ok := restored.Groups["work"]
+	if !ok || len(group.Sessions) != 2 || group.Sessions[0].ID != web.ID || group.Sessions[1].ID != api.ID {
+		t.Fatalf("work group not restored in order: %+v", group)
+	}
+	if api.GroupPath != "work" || web.GroupPath != "work" {
+		t.Errorf("group paths = %q, %q", api.GroupPath, web.GroupPath)
+	}
+	if restored.SessionCount() != 3 || newer.GroupPath != "scratch" {
+		t.Errorf("newer session lost: count %d, group %q", restored.SessionCount(), newer.GroupPath)
+	}
+}
+
+func TestUndoLog_RedoDroppedByNewOperation(t *testing.T) {
+	var l undoLog
+	l.record(undoOp{label: "a"})
+	l.record(undoOp{label: "b"})
+
+	if op, ok := l.popUndo(); !ok || op.label != "b" {
+		t.Fatalf("popUndo = %q, %v", op.label, ok)
+	}
+	if op, ok := l.popRedo(); !ok || op.label != "b" {
+		t.Fatalf("popRedo = %q, %v", op.label, ok)
+	}
+	l.push(undoOp{label: "b"})
+
+	l.popUndo()
+	l.cancelUndo()
+	if len(l.done) != 2 || len(l.undone) != 0 {
+		t.Errorf("cancelUndo: done %d, undone %d", len(l.done), len(l.undone))
+	}
+
+	l.popUndo()
+	l.record(undoOp{label: "c"})
+	if _, ok := l.popRedo(); ok {
+		t.Error("a new operation should drop the redo history")
+	}
+}
+
+func TestHomeUndoRedoRenameSession(t *testing.T) {
+	home := NewHome()
+	home.width = 100
+	home.height = 30
+
+	inst := session.NewInstance("original-name", "/tmp/project")
+	home.instancesMu.Lock()
+	home.instances = []*session.Instance{inst}
+	home.instanceByID[inst.ID] = inst
+	home.instancesMu.Unlock()
+	home.groupTree = session.NewGroupTree(home.instances)
+	home.rebuildFlatItems()
+	for i, item := range home.flatItems {
+		if item.Type == session.ItemTypeSession {
+			home.cursor = i
+		}
+	}
+
+	home.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	home.groupDialog.nameInput.SetValue("new-name")
+	home.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if inst.Title != "new-name" {
+		t.Fatalf("rename failed: %q", inst.Title)
+	}
+
+	home.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if home.instances[0].Title != "original-name" {
+		t.Errorf("after ctrl+z title = %q, want original-name", home.instances[0].Title)
+	}
+	home.Update(tea.KeyMsg{Type: tea.KeyCtrlY})
+	if home.instances[0].Title != "new-name" {
+		t.Errorf("after ctrl+y title = %q, want new-name", home.instances[0].Title)
+	}
+}
+
+func TestHomeUndoClearedByReload(t *testing.T) {
+	home := NewHome()
+	home.width = 100
+	home.height = 30
+
+	inst := session.NewInstance("original-name", "/tmp/project")
+	home.instancesMu.Lock()
+	home.instances = []*session.Instance{inst}
+	home.instanceByID[inst.ID] = inst
+	home.instancesMu.Unlock()
+	home.groupTree = session.NewGroupTree(home.instances)
+	home.rebuildFlatItems()
+	for i, item := range home.flatItems {
+		if item.Type == session.ItemTypeSession {
+			home.cursor = i
+		}
+	}
+
+	home.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	home.groupDialog.nameInput.SetValue("new-name")
+	home.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	// Another process renames the session; the TUI reloads it
+	reloaded := session.NewInstance("renamed-elsewhere", "/tmp/project")
+	reloaded.ID = inst.ID
+	home.Update(loadSessionsMsg{instances: []*session.Instance{reloaded}})
+
+	home.Update(tea.KeyMsg{Type: tea.KeyCtrlZ})
+	if home.instances[0].Title != "renamed-elsewhere" {
+		t.Errorf("after reload and ctrl+z title = %q, want renamed-elsewhere", home.instances[0].Title)
+	}
+	if len(home.undo.done) != 0 || len(home.undo.undone) != 0 {
+		t.Errorf("undo history not cleared: done %d, undone %d", len(home.undo.done), len(home.undo.undone))
+	}
+}